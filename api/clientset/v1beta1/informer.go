@@ -0,0 +1,103 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+)
+
+// TopologyInformer provides access to a shared informer and lister of the
+// Topology CRD.
+type TopologyInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() TopologyLister
+}
+
+type topologyInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+// NewTopologyInformer returns an informer of the Topology resources in
+// namespace, all namespaces if namespace is empty. The informer caches the
+// resources as *topologyv1.Topology and is indexed by namespace. The cache is
+// resynced every resyncPeriod, never if zero.
+func NewTopologyInformer(client Interface, namespace string, resyncPeriod time.Duration) TopologyInformer {
+	return NewFilteredTopologyInformer(client, namespace, resyncPeriod, nil)
+}
+
+// NewFilteredTopologyInformer returns an informer like NewTopologyInformer with
+// the list and watch options modified by tweakListOptions, e.g. to select
+// resources by label.
+func NewFilteredTopologyInformer(client Interface, namespace string, resyncPeriod time.Duration, tweakListOptions func(*metav1.ListOptions)) TopologyInformer {
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&opts)
+			}
+			return client.Topology(namespace).List(context.Background(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&opts)
+			}
+			w, err := client.Topology(namespace).Watch(context.Background(), opts)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, toTopologyEvent), nil
+		},
+	}
+	return &topologyInformer{
+		informer: cache.NewSharedIndexInformer(lw, &topologyv1.Topology{}, resyncPeriod, cache.Indexers{
+			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		}),
+	}
+}
+
+func (i *topologyInformer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *topologyInformer) Lister() TopologyLister {
+	return NewTopologyLister(i.informer.GetIndexer())
+}
+
+// toTopologyEvent converts the unstructured object of a watch event of the
+// dynamic client to a Topology. Events of other objects, such as errors, are
+// passed unchanged.
+func toTopologyEvent(e watch.Event) (watch.Event, bool) {
+	u, ok := e.Object.(*unstructured.Unstructured)
+	if !ok {
+		return e, true
+	}
+	t := &topologyv1.Topology{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), t); err != nil {
+		return watch.Event{Type: watch.Error, Object: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: "failed to type assert watch event to Topology: " + err.Error(),
+		}}, true
+	}
+	e.Object = t
+	return e, true
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package v1beta1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/h-fam/errdiff"
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+func setUpInformer(t *testing.T, objs ...runtime.Object) *Clientset {
+	t.Helper()
+	cs, err := NewForConfig(&rest.Config{})
+	if err != nil {
+		t.Fatalf("failed to create client set")
+	}
+	cs.dInterface = dynamicfake.NewSimpleDynamicClient(topologyv1.Scheme, objs...).Resource(gvr)
+	return cs
+}
+
+func startInformer(t *testing.T, i TopologyInformer) {
+	t.Helper()
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	go i.Informer().Run(stop)
+	if !cache.WaitForCacheSync(stop, i.Informer().HasSynced) {
+		t.Fatalf("failed to sync informer")
+	}
+}
+
+func names(ts []*topologyv1.Topology) []string {
+	var n []string
+	for _, t := range ts {
+		n = append(n, t.Namespace+"/"+t.Name)
+	}
+	return n
+}
+
+func TestTopologyInformer(t *testing.T) {
+	other := obj1.DeepCopy()
+	other.Namespace = "other"
+	cs := setUpInformer(t, obj1.DeepCopy(), obj2.DeepCopy(), other)
+	i := NewTopologyInformer(cs, "", 0)
+	startInformer(t, i)
+	l := i.Lister()
+
+	got, err := l.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if s := cmp.Diff([]string{"other/obj1", "test/obj1", "test/obj2"}, names(got), cmpopts.SortSlices(func(a, b string) bool { return a < b })); s != "" {
+		t.Errorf("List() unexpected topologies diff (-want +got):\n%s", s)
+	}
+	got, err = l.Topologies("test").List(labels.Everything())
+	if err != nil {
+		t.Fatalf("Topologies(%q).List() failed: %v", "test", err)
+	}
+	if s := cmp.Diff([]string{"test/obj1", "test/obj2"}, names(got), cmpopts.SortSlices(func(a, b string) bool { return a < b })); s != "" {
+		t.Errorf("Topologies(%q).List() unexpected topologies diff (-want +got):\n%s", "test", s)
+	}
+
+	tests := []struct {
+		desc    string
+		ns      string
+		name    string
+		want    *topologyv1.Topology
+		wantErr string
+	}{{
+		desc: "cached",
+		ns:   "test",
+		name: "obj1",
+		want: obj1,
+	}, {
+		desc:    "not found",
+		ns:      "test",
+		name:    "missing",
+		wantErr: "not found",
+	}, {
+		desc:    "other namespace",
+		ns:      "missing",
+		name:    "obj1",
+		wantErr: "not found",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := l.Topologies(tt.ns).Get(tt.name)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("Get(%q) unexpected topology diff (-want +got):\n%s", tt.name, s)
+			}
+		})
+	}
+
+	// Resources created later are added to the cache from the watch.
+	if _, err := cs.Topology("test").Create(context.Background(), objNew.DeepCopy(), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := l.Topologies("test").Get(objNew.Name)
+		if err == nil {
+			if s := cmp.Diff(objNew, got); s != "" {
+				t.Errorf("Get(%q) unexpected topology diff (-want +got):\n%s", objNew.Name, s)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Get(%q) failed to get created topology: %v", objNew.Name, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFilteredTopologyInformer(t *testing.T) {
+	labeled := obj1.DeepCopy()
+	labeled.Labels = map[string]string{"app": "kne"}
+	cs := setUpInformer(t, labeled, obj2.DeepCopy())
+	i := NewFilteredTopologyInformer(cs, "test", 0, func(opts *metav1.ListOptions) {
+		opts.LabelSelector = "app=kne"
+	})
+	startInformer(t, i)
+	got, err := i.Lister().List(labels.Everything())
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if s := cmp.Diff([]string{"test/obj1"}, names(got)); s != "" {
+		t.Errorf("List() unexpected topologies diff (-want +got):\n%s", s)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+)
+
+// TopologyLister lists Topology resources from the cache of an informer. The
+// returned objects are shared with the cache and must not be modified.
+type TopologyLister interface {
+	// List lists the topologies of all namespaces matching selector.
+	List(selector labels.Selector) ([]*topologyv1.Topology, error)
+	// Topologies returns a lister of the topologies of namespace.
+	Topologies(namespace string) TopologyNamespaceLister
+}
+
+// TopologyNamespaceLister lists the Topology resources of a namespace from
+// the cache of an informer.
+type TopologyNamespaceLister interface {
+	// List lists the topologies of the namespace matching selector.
+	List(selector labels.Selector) ([]*topologyv1.Topology, error)
+	// Get returns the topology name of the namespace.
+	Get(name string) (*topologyv1.Topology, error)
+}
+
+// NewTopologyLister returns a lister of the Topology resources in indexer.
+func NewTopologyLister(indexer cache.Indexer) TopologyLister {
+	return &topologyLister{indexer: indexer}
+}
+
+type topologyLister struct {
+	indexer cache.Indexer
+}
+
+func (l *topologyLister) List(selector labels.Selector) ([]*topologyv1.Topology, error) {
+	var ret []*topologyv1.Topology
+	err := cache.ListAll(l.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*topologyv1.Topology))
+	})
+	return ret, err
+}
+
+func (l *topologyLister) Topologies(namespace string) TopologyNamespaceLister {
+	return &topologyNamespaceLister{indexer: l.indexer, ns: namespace}
+}
+
+type topologyNamespaceLister struct {
+	indexer cache.Indexer
+	ns      string
+}
+
+func (l *topologyNamespaceLister) List(selector labels.Selector) ([]*topologyv1.Topology, error) {
+	var ret []*topologyv1.Topology
+	err := cache.ListAllByNamespace(l.indexer, l.ns, selector, func(m interface{}) {
+		ret = append(ret, m.(*topologyv1.Topology))
+	})
+	return ret, err
+}
+
+func (l *topologyNamespaceLister) Get(name string) (*topologyv1.Topology, error) {
+	obj, exists, err := l.indexer.GetByKey(l.ns + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(GVR().GroupResource(), name)
+	}
+	return obj.(*topologyv1.Topology), nil
+}
@@ -16,12 +16,15 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -34,14 +37,23 @@ import (
 // TopologyInterface provides access to the Topology CRD.
 type TopologyInterface interface {
 	List(ctx context.Context, opts metav1.ListOptions) (*topologyv1.TopologyList, error)
+	ListByLabels(ctx context.Context, selector labels.Selector) (*topologyv1.TopologyList, error)
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*topologyv1.Topology, error)
 	Create(ctx context.Context, topology *topologyv1.Topology, opts metav1.CreateOptions) (*topologyv1.Topology, error)
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	WatchByLabels(ctx context.Context, selector labels.Selector) (watch.Interface, error)
 	Unstructured(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error)
-	Update(ctx context.Context, obj *unstructured.Unstructured, opts metav1.UpdateOptions) (*topologyv1.Topology, error)
+	Update(ctx context.Context, topology *topologyv1.Topology, opts metav1.UpdateOptions) (*topologyv1.Topology, error)
+	UpdateStatus(ctx context.Context, topology *topologyv1.Topology, opts metav1.UpdateOptions) (*topologyv1.Topology, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*topologyv1.Topology, error)
+	Apply(ctx context.Context, topology *topologyv1.Topology, opts metav1.ApplyOptions) (*topologyv1.Topology, error)
 }
 
+// FieldManager is the field manager of the fields applied by kne with Apply,
+// unless another one is set in the options.
+const FieldManager = "kne"
+
 // Interface is the clientset interface for topology.
 type Interface interface {
 	Topology(namespace string) TopologyInterface
@@ -117,6 +129,12 @@ func (t *topologyClient) List(ctx context.Context, opts metav1.ListOptions) (*to
 	return &result, nil
 }
 
+// ListByLabels lists the topologies with labels matching selector, e.g. the
+// topologies of a kne topology with labels.SelectorFromSet.
+func (t *topologyClient) ListByLabels(ctx context.Context, selector labels.Selector) (*topologyv1.TopologyList, error) {
+	return t.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+}
+
 func (t *topologyClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*topologyv1.Topology, error) {
 	u, err := t.dInterface.Namespace(t.ns).Get(ctx, name, opts)
 	if err != nil {
@@ -158,12 +176,55 @@ func (t *topologyClient) Watch(ctx context.Context, opts metav1.ListOptions) (wa
 	return t.dInterface.Namespace(t.ns).Watch(ctx, opts)
 }
 
+// WatchByLabels watches the topologies with labels matching selector.
+func (t *topologyClient) WatchByLabels(ctx context.Context, selector labels.Selector) (watch.Interface, error) {
+	return t.Watch(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+}
+
 func (t *topologyClient) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return t.dInterface.Namespace(t.ns).Delete(ctx, name, opts)
 }
 
-func (t *topologyClient) Update(ctx context.Context, obj *unstructured.Unstructured, opts metav1.UpdateOptions) (*topologyv1.Topology, error) {
-	obj, err := t.dInterface.Namespace(t.ns).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+// Update updates the topology. Use UpdateStatus to update its status.
+func (t *topologyClient) Update(ctx context.Context, topology *topologyv1.Topology, opts metav1.UpdateOptions) (*topologyv1.Topology, error) {
+	return t.update(ctx, topology, opts)
+}
+
+// UpdateStatus updates the status of the topology. The status is also written
+// by meshnet, callers updating only some fields should use Patch with the
+// "status" subresource instead.
+func (t *topologyClient) UpdateStatus(ctx context.Context, topology *topologyv1.Topology, opts metav1.UpdateOptions) (*topologyv1.Topology, error) {
+	return t.update(ctx, topology, opts, "status")
+}
+
+func (t *topologyClient) update(ctx context.Context, topology *topologyv1.Topology, opts metav1.UpdateOptions, subresources ...string) (*topologyv1.Topology, error) {
+	gvk, err := apiutil.GVKForObject(topology, topologyv1.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gvk for Topology: %w", err)
+	}
+	topology.TypeMeta = metav1.TypeMeta{
+		Kind:       gvk.Kind,
+		APIVersion: gvk.GroupVersion().String(),
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(topology)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Topology to unstructured: %w", err)
+	}
+	u, err := t.dInterface.Namespace(t.ns).Update(ctx, &unstructured.Unstructured{Object: obj}, opts, subresources...)
+	if err != nil {
+		return nil, err
+	}
+	result := topologyv1.Topology{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &result); err != nil {
+		return nil, fmt.Errorf("failed to type assert return to Topology: %w", err)
+	}
+	return &result, nil
+}
+
+// Patch patches the topology name with data of type pt, e.g. a merge patch
+// of the link status. Pass "status" as subresource to patch the status.
+func (t *topologyClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*topologyv1.Topology, error) {
+	obj, err := t.dInterface.Namespace(t.ns).Patch(ctx, name, pt, data, opts, subresources...)
 	if err != nil {
 		return nil, err
 	}
@@ -174,6 +235,34 @@ func (t *topologyClient) Update(ctx context.Context, obj *unstructured.Unstructu
 	return &result, nil
 }
 
+// Apply applies the topology with server-side apply, creating it if it does
+// not exist. Only the fields set in topology are owned by the field manager,
+// so the topology can be co-owned with other controllers such as meshnet. The
+// status is not applied, use Patch with the "status" subresource instead.
+func (t *topologyClient) Apply(ctx context.Context, topology *topologyv1.Topology, opts metav1.ApplyOptions) (*topologyv1.Topology, error) {
+	gvk, err := apiutil.GVKForObject(topology, topologyv1.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gvk for Topology: %w", err)
+	}
+	topology.TypeMeta = metav1.TypeMeta{
+		Kind:       gvk.Kind,
+		APIVersion: gvk.GroupVersion().String(),
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(topology)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Topology to unstructured: %w", err)
+	}
+	delete(obj, "status")
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Topology: %w", err)
+	}
+	if opts.FieldManager == "" {
+		opts.FieldManager = FieldManager
+	}
+	return t.Patch(ctx, topology.Name, types.ApplyPatchType, data, opts.ToPatchOptions())
+}
+
 func (t *topologyClient) Unstructured(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
 	return t.dInterface.Namespace(t.ns).Get(ctx, name, opts, subresources...)
 }
@@ -15,7 +15,12 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -23,8 +28,9 @@ import (
 	"github.com/h-fam/errdiff"
 	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/rest"
@@ -225,6 +231,70 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListByLabels(t *testing.T) {
+	labeled1 := obj1.DeepCopy()
+	labeled1.Labels = map[string]string{"topo": "t1"}
+	labeled2 := obj2.DeepCopy()
+	labeled2.Labels = map[string]string{"topo": "t2"}
+	cs := setUpInformer(t, labeled1, labeled2, objNew.DeepCopy())
+	tests := []struct {
+		desc     string
+		selector labels.Selector
+		want     []string
+	}{{
+		desc:     "match",
+		selector: labels.SelectorFromSet(labels.Set{"topo": "t1"}),
+		want:     []string{"obj1"},
+	}, {
+		desc:     "no match",
+		selector: labels.SelectorFromSet(labels.Set{"topo": "t3"}),
+	}, {
+		desc:     "everything",
+		selector: labels.Everything(),
+		want:     []string{"newObj", "obj1", "obj2"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := cs.Topology("test").ListByLabels(context.Background(), tt.selector)
+			if err != nil {
+				t.Fatalf("ListByLabels() failed: %v", err)
+			}
+			var gotNames []string
+			for _, item := range got.Items {
+				gotNames = append(gotNames, item.Name)
+			}
+			if s := cmp.Diff(tt.want, gotNames, cmpopts.SortSlices(func(a, b string) bool { return a < b })); s != "" {
+				t.Errorf("ListByLabels(%q) unexpected topologies diff (-want +got):\n%s", tt.selector, s)
+			}
+		})
+	}
+}
+
+func TestWatchByLabels(t *testing.T) {
+	cs, err := NewForConfig(&rest.Config{})
+	if err != nil {
+		t.Fatalf("failed to create client set")
+	}
+	f := dynamicfake.NewSimpleDynamicClient(topologyv1.Scheme)
+	var gotSelector string
+	f.PrependWatchReactor("*", func(action ktest.Action) (bool, watch.Interface, error) {
+		gotSelector = action.(ktest.WatchAction).GetWatchRestrictions().Labels.String()
+		return true, newFakeWatch([]watch.Event{{Type: watch.Added, Object: obj1}}), nil
+	})
+	cs.dInterface = f.Resource(gvr)
+	w, err := cs.Topology("test").WatchByLabels(context.Background(), labels.SelectorFromSet(labels.Set{"topo": "t1"}))
+	if err != nil {
+		t.Fatalf("WatchByLabels() failed: %v", err)
+	}
+	defer w.Stop()
+	if want := "topo=t1"; gotSelector != want {
+		t.Errorf("WatchByLabels() got label selector %q, want %q", gotSelector, want)
+	}
+	if e := <-w.ResultChan(); e.Type != watch.Added {
+		t.Errorf("WatchByLabels() got event %v, want %v", e.Type, watch.Added)
+	}
+}
+
 func TestGet(t *testing.T) {
 	cs := setUp(t)
 	tests := []struct {
@@ -329,9 +399,10 @@ func TestWatch(t *testing.T) {
 func TestUpdate(t *testing.T) {
 	cs := setUp(t)
 	tests := []struct {
-		desc    string
-		want    *topologyv1.Topology
-		wantErr string
+		desc         string
+		want         *topologyv1.Topology
+		wantErr      string
+		skipTypeMeta bool
 	}{{
 		desc: "Error",
 		want: &topologyv1.Topology{
@@ -348,17 +419,21 @@ func TestUpdate(t *testing.T) {
 	}, {
 		desc: "Valid Topology",
 		want: obj1,
+	}, {
+		desc:         "Valid Topology without typemeta",
+		want:         obj2,
+		skipTypeMeta: true,
 	}}
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
 			tc := cs.Topology("test")
 			updateObj := tt.want.DeepCopy()
 			updateObj.Spec.Links = append(updateObj.Spec.Links, topologyv1.Link{UID: 1000})
-			update, err := runtime.DefaultUnstructuredConverter.ToUnstructured(updateObj)
-			if err != nil {
-				t.Fatalf("failed to generate update: %v", err)
+			in := updateObj.DeepCopy()
+			if tt.skipTypeMeta {
+				in.TypeMeta.Reset()
 			}
-			got, err := tc.Update(context.Background(), &unstructured.Unstructured{Object: update}, metav1.UpdateOptions{})
+			got, err := tc.Update(context.Background(), in, metav1.UpdateOptions{})
 			if s := errdiff.Substring(err, tt.wantErr); s != "" {
 				t.Fatalf("unexpected error: %s", s)
 			}
@@ -372,6 +447,184 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		desc    string
+		in      *topologyv1.Topology
+		wantErr string
+	}{{
+		desc: "not found",
+		in: &topologyv1.Topology{
+			ObjectMeta: metav1.ObjectMeta{Name: "doesnotexist", Namespace: "test"},
+		},
+		wantErr: "doesnotexist",
+	}, {
+		desc: "success",
+		in:   obj1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cs, err := NewForConfig(&rest.Config{})
+			if err != nil {
+				t.Fatalf("failed to create client set")
+			}
+			f := dynamicfake.NewSimpleDynamicClient(topologyv1.Scheme, obj1.DeepCopy())
+			var gotSubresource string
+			f.PrependReactor("update", "topologies", func(action ktest.Action) (bool, runtime.Object, error) {
+				gotSubresource = action.GetSubresource()
+				return false, nil, nil
+			})
+			cs.dInterface = f.Resource(gvr)
+			in := tt.in.DeepCopy()
+			in.Status.Links = []topologyv1.LinkStatus{{UID: 0, LocalIntf: "int1", PeerPod: "obj2", PeerIntf: "int1", State: topologyv1.LinkEstablished}}
+			in.Status.EstablishedLinks = 1
+			got, err := cs.Topology("test").UpdateStatus(context.Background(), in, metav1.UpdateOptions{})
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if gotSubresource != "status" {
+				t.Errorf("UpdateStatus() got subresource %q, want %q", gotSubresource, "status")
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if s := cmp.Diff(in, got); s != "" {
+				t.Errorf("UpdateStatus() unexpected topology diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	cs := setUp(t)
+	mergePatched := obj1.DeepCopy()
+	mergePatched.Status.Skipped = []string{"obj2"}
+	jsonPatched := obj2.DeepCopy()
+	jsonPatched.Spec.Links[0].PeerIntf = "int2"
+	tests := []struct {
+		desc    string
+		name    string
+		pt      types.PatchType
+		data    string
+		want    *topologyv1.Topology
+		wantErr string
+	}{{
+		desc:    "not found",
+		name:    "doesnotexist",
+		pt:      types.MergePatchType,
+		data:    `{"status":{}}`,
+		wantErr: "not found",
+	}, {
+		desc:    "invalid patch",
+		name:    "obj1",
+		pt:      types.JSONPatchType,
+		data:    `{`,
+		wantErr: "unexpected end of JSON input",
+	}, {
+		desc: "merge patch",
+		name: "obj1",
+		pt:   types.MergePatchType,
+		data: `{"status":{"skipped":["obj2"]}}`,
+		want: mergePatched,
+	}, {
+		desc: "json patch",
+		name: "obj2",
+		pt:   types.JSONPatchType,
+		data: `[{"op":"replace","path":"/spec/links/0/peer_intf","value":"int2"}]`,
+		want: jsonPatched,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tc := cs.Topology("test")
+			got, err := tc.Patch(context.Background(), tt.name, tt.pt, []byte(tt.data), metav1.PatchOptions{})
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Fatalf("Patch() failed: %s", s)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		desc      string
+		opts      metav1.ApplyOptions
+		code      int
+		wantQuery url.Values
+		wantErr   string
+	}{{
+		desc:      "default field manager",
+		code:      http.StatusOK,
+		wantQuery: url.Values{"fieldManager": {FieldManager}, "force": {"false"}},
+	}, {
+		desc:      "field manager and force",
+		opts:      metav1.ApplyOptions{FieldManager: "meshnet", Force: true},
+		code:      http.StatusOK,
+		wantQuery: url.Values{"fieldManager": {"meshnet"}, "force": {"true"}},
+	}, {
+		desc:    "conflict",
+		code:    http.StatusConflict,
+		wantErr: "conflict",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var gotMethod, gotPath, gotType string
+			var gotQuery url.Values
+			var gotBody map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotMethod, gotPath, gotType, gotQuery = r.Method, r.URL.Path, r.Header.Get("Content-Type"), r.URL.Query()
+				b, err := io.ReadAll(r.Body)
+				if err != nil || json.Unmarshal(b, &gotBody) != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if tt.code != http.StatusOK {
+					w.WriteHeader(tt.code)
+					fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"Apply failed with 1 conflict","reason":"Conflict","code":409}`)
+					return
+				}
+				w.Write(b)
+			}))
+			defer srv.Close()
+			cs, err := NewForConfig(&rest.Config{Host: srv.URL})
+			if err != nil {
+				t.Fatalf("failed to create client set: %v", err)
+			}
+			in := obj1.DeepCopy()
+			in.TypeMeta.Reset()
+			in.Status.SrcIP = "10.0.0.1"
+			got, err := cs.Topology("test").Apply(context.Background(), in, tt.opts)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if want := "/apis/networkop.co.uk/v1beta1/namespaces/test/topologies/obj1"; gotMethod != http.MethodPatch || gotPath != want {
+				t.Errorf("Apply() got request %s %s, want %s %s", gotMethod, gotPath, http.MethodPatch, want)
+			}
+			if gotType != string(types.ApplyPatchType) {
+				t.Errorf("Apply() got content type %q, want %q", gotType, types.ApplyPatchType)
+			}
+			if s := cmp.Diff(tt.wantQuery, gotQuery); s != "" {
+				t.Errorf("Apply() unexpected query diff (-want +got):\n%s", s)
+			}
+			if _, ok := gotBody["status"]; ok {
+				t.Errorf("Apply() unexpectedly applied the status: %v", gotBody["status"])
+			}
+			if s := cmp.Diff(obj1, got); s != "" {
+				t.Errorf("Apply() unexpected topology diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
 func TestUnstructured(t *testing.T) {
 	cs := setUp(t)
 	tests := []struct {
@@ -0,0 +1,47 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1alpha1 contains the types of the KneTopology CRD reconciled by the
+// kne operator.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	GroupName    = "kne.openconfig.net"
+	GroupVersion = "v1alpha1"
+)
+
+var (
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: GroupVersion}
+	Scheme             = runtime.NewScheme()
+)
+
+// GVR returns the group version resource of the KneTopology CRD.
+func GVR() schema.GroupVersionResource {
+	return SchemeGroupVersion.WithResource("knetopologies")
+}
+
+func init() {
+	Scheme.AddKnownTypes(SchemeGroupVersion,
+		&KneTopology{},
+		&KneTopologyList{},
+	)
+	metav1.AddToGroupVersion(Scheme, SchemeGroupVersion)
+	metav1.AddMetaToScheme(Scheme)
+}
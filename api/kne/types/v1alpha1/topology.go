@@ -0,0 +1,70 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+//go:generate controller-gen object paths=$GOFILE
+
+// Finalizer is the finalizer set by the operator on KneTopology resources
+// until their topology is deleted.
+const Finalizer = "kne.openconfig.net/topology"
+
+// Phases of a KneTopology.
+const (
+	PhaseCreating = "Creating"
+	PhaseReady    = "Ready"
+	PhaseFailed   = "Failed"
+	PhaseDeleting = "Deleting"
+)
+
+type KneTopologySpec struct {
+	// Topology is the topology in prototext format, like a topology file.
+	// The name of the topology is the name of the resource. Files are not
+	// supported, configs must be set inline.
+	Topology string `json:"topology"`
+	// Timeout is the timeout for the nodes to become ready, e.g. 10m. The
+	// status of the nodes is not waited for if unset.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type KneTopologyStatus struct {
+	// Phase is the phase of the topology.
+	Phase string `json:"phase,omitempty"`
+	// Message is the reason of a failure.
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the spec last reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KneTopology is a topology created by the kne operator. The topology is
+// created in the namespace of its name and deleted along with the resource.
+type KneTopology struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KneTopologySpec   `json:"spec"`
+	Status KneTopologyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KneTopologyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KneTopology `json:"items"`
+}
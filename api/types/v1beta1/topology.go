@@ -32,6 +32,39 @@ type TopologyStatus struct {
 	Skipped []string `json:"skipped"`
 	SrcIP   string   `json:"src_ip"`
 	NetNS   string   `json:"net_ns"`
+	// State is the lifecycle state of the node recorded by kne.
+	State string `json:"state,omitempty"`
+	// Reason is the reason of a node failure recorded by kne.
+	Reason string `json:"reason,omitempty"`
+	// ConfigPush is the result of the last config push to the node recorded
+	// by kne, Pushed or Failed.
+	ConfigPush string `json:"config_push,omitempty"`
+	// ConfigPushReason is the reason of the last config push failure.
+	ConfigPushReason string `json:"config_push_reason,omitempty"`
+	// Links is the operational state of the links of the node recorded by
+	// kne.
+	Links []LinkStatus `json:"links,omitempty"`
+	// EstablishedLinks is the number of links established.
+	EstablishedLinks int `json:"established_links,omitempty"`
+	// SkippedLinks is the number of links not established yet, e.g. as the
+	// peer pod is not running.
+	SkippedLinks int `json:"skipped_links,omitempty"`
+}
+
+// Operational states of a link in LinkStatus.
+const (
+	LinkEstablished = "Established"
+	LinkSkipped     = "Skipped"
+)
+
+// LinkStatus is the operational state of a link.
+// +kubebuilder:object:generate=true
+type LinkStatus struct {
+	UID       int    `json:"uid"`
+	LocalIntf string `json:"local_intf"`
+	PeerPod   string `json:"peer_pod"`
+	PeerIntf  string `json:"peer_intf"`
+	State     string `json:"state"`
 }
 
 type Link struct {
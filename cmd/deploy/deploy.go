@@ -32,6 +32,9 @@ func New() *cobra.Command {
 		Short: "Deploy cluster.",
 		RunE:  deployFn,
 	}
+	deployCmd.Flags().StringSlice("image-archive", nil, "Image archives, as written by docker save, to load into the cluster before deploying, in addition to the imageArchives of the deployment")
+	deployCmd.Flags().Bool("dry-run", false, "Print the manifests of the ingress, CNI and controllers instead of deploying them")
+	deployCmd.Flags().StringP("output", "o", "yaml", "Output format of the manifests printed with --dry-run, only yaml is supported")
 	return deployCmd
 }
 
@@ -60,6 +63,12 @@ type DeploymentConfig struct {
 	Ingress     IngressSpec       `yaml:"ingress"`
 	CNI         CNISpec           `yaml:"cni"`
 	Controllers []*ControllerSpec `yaml:"controllers"`
+	// ImageArchives are image archives, as written by docker save, loaded
+	// into the cluster to deploy without access to a registry.
+	ImageArchives []string `yaml:"imageArchives"`
+	// Context is the kubeconfig context the cluster components are deployed
+	// with, the context of the cluster by default.
+	Context string `yaml:"context"`
 }
 
 func newDeployment(cfgPath string) (*deploy.Deployment, error) {
@@ -80,7 +89,10 @@ func newDeployment(cfgPath string) (*deploy.Deployment, error) {
 		return nil, err
 	}
 
-	d := &deploy.Deployment{}
+	d := &deploy.Deployment{Context: cfg.Context}
+	for _, s := range cfg.ImageArchives {
+		d.ImageArchives = append(d.ImageArchives, cleanPath(s, basePath))
+	}
 	switch cfg.Cluster.Kind {
 	case "Kind":
 		log.Infof("Using kind scenario")
@@ -98,6 +110,46 @@ func newDeployment(cfgPath string) (*deploy.Deployment, error) {
 			v.KindConfigFile = cleanPath(v.KindConfigFile, basePath)
 		}
 
+		d.Cluster = v
+	case "K3s":
+		log.Infof("Using k3s scenario")
+		v := &deploy.K3sSpec{}
+		if err := cfg.Cluster.Spec.Decode(v); err != nil {
+			return nil, err
+		}
+		for i, s := range v.AdditionalManifests {
+			v.AdditionalManifests[i] = cleanPath(s, basePath)
+		}
+		d.Cluster = v
+	case "Minikube":
+		log.Infof("Using minikube scenario")
+		v := &deploy.MinikubeSpec{}
+		if err := cfg.Cluster.Spec.Decode(v); err != nil {
+			return nil, err
+		}
+		for i, s := range v.AdditionalManifests {
+			v.AdditionalManifests[i] = cleanPath(s, basePath)
+		}
+		d.Cluster = v
+	case "GKE":
+		log.Infof("Using GKE scenario")
+		v := &deploy.GKESpec{}
+		if err := cfg.Cluster.Spec.Decode(v); err != nil {
+			return nil, err
+		}
+		if v.NodePool.Topology != "" {
+			v.NodePool.Topology = cleanPath(v.NodePool.Topology, basePath)
+		}
+		d.Cluster = v
+	case "EKS":
+		log.Infof("Using EKS scenario")
+		v := &deploy.EKSSpec{}
+		if err := cfg.Cluster.Spec.Decode(v); err != nil {
+			return nil, err
+		}
+		if v.NodePool.Topology != "" {
+			v.NodePool.Topology = cleanPath(v.NodePool.Topology, basePath)
+		}
 		d.Cluster = v
 	default:
 		return nil, fmt.Errorf("cluster type not supported: %s", cfg.Cluster.Kind)
@@ -121,6 +173,8 @@ func newDeployment(cfgPath string) (*deploy.Deployment, error) {
 		}
 		v.ManifestDir = cleanPath(v.ManifestDir, basePath)
 		d.Ingress = v
+	case "CloudLB":
+		d.Ingress = &deploy.CloudLBSpec{}
 	default:
 		return nil, fmt.Errorf("ingress type not supported: %s", cfg.Ingress.Kind)
 	}
@@ -150,6 +204,13 @@ func newDeployment(cfgPath string) (*deploy.Deployment, error) {
 			}
 			v.ManifestDir = cleanPath(v.ManifestDir, basePath)
 			d.Controllers = append(d.Controllers, v)
+		case "KNE":
+			v := &deploy.KNESpec{}
+			if err := c.Spec.Decode(v); err != nil {
+				return nil, err
+			}
+			v.ManifestDir = cleanPath(v.ManifestDir, basePath)
+			d.Controllers = append(d.Controllers, v)
 		default:
 			return nil, fmt.Errorf("controller type not supported: %s", c.Kind)
 		}
@@ -168,6 +229,13 @@ func deployFn(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("%s: missing args", cmd.Use)
 	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return manifestsFn(cmd, args[0])
+	}
 	if _, err := exec.LookPath("kubectl"); err != nil {
 		return fmt.Errorf("install kubectl before running deploy: %v", err)
 	}
@@ -175,13 +243,37 @@ func deployFn(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	archives, err := cmd.Flags().GetStringSlice("image-archive")
+	if err != nil {
+		return err
+	}
 	d, err := newDeployment(args[0])
 	if err != nil {
 		return err
 	}
+	d.ImageArchives = append(d.ImageArchives, archives...)
+	if f := cmd.Flags().Lookup("context"); f != nil && f.Value.String() != "" {
+		d.Context = f.Value.String()
+	}
 	if err := d.Deploy(cmd.Context(), kubecfg); err != nil {
 		return err
 	}
 	log.Infof("Deployment complete, ready for topology")
 	return nil
 }
+
+// manifestsFn prints the manifests of the deployment in cfgPath.
+func manifestsFn(cmd *cobra.Command, cfgPath string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output != "yaml" {
+		return fmt.Errorf("invalid output format %q, must be yaml", output)
+	}
+	d, err := newDeployment(cfgPath)
+	if err != nil {
+		return err
+	}
+	return d.Manifests(cmd.Context(), cmd.OutOrStdout())
+}
@@ -127,6 +127,9 @@ controllers:
     spec:
       manifests: path/to/manifest
   - kind: CEOSLab
+    spec:
+      manifests: path/to/manifest
+  - kind: KNE
     spec:
       manifests: path/to/manifest`
 )
@@ -138,6 +141,34 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		desc    string
+		args    []string
+		wantErr string
+	}{{
+		desc:    "invalid output",
+		args:    []string{"--dry-run", "-o", "json", "../../deploy/kne/kind-bridge.yaml"},
+		wantErr: "invalid output format",
+	}, {
+		desc:    "invalid config",
+		args:    []string{"--dry-run", "missing.yaml"},
+		wantErr: "no such file",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := New()
+			c.SetArgs(tt.args)
+			c.SilenceUsage = true
+			c.SilenceErrors = true
+			err := c.Execute()
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+		})
+	}
+}
+
 func TestNewDeployment(t *testing.T) {
 	tests := []struct {
 		desc    string
@@ -167,6 +198,18 @@ func TestNewDeployment(t *testing.T) {
 		desc: "kind example",
 		cfg:  "",
 		path: "../../deploy/kne/kind-bridge.yaml",
+	}, {
+		desc: "k3s example",
+		path: "../../deploy/kne/k3s.yaml",
+	}, {
+		desc: "minikube example",
+		path: "../../deploy/kne/minikube.yaml",
+	}, {
+		desc: "gke example",
+		path: "../../deploy/kne/gke.yaml",
+	}, {
+		desc: "eks example",
+		path: "../../deploy/kne/eks.yaml",
 	}}
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
@@ -0,0 +1,108 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openconfig/kne/topo"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+// progressView prints the progress of the nodes of a topology being created.
+type progressView struct {
+	mu    sync.Mutex
+	w     io.Writer
+	live  bool
+	nodes map[string]*topo.NodeProgress
+	lines int
+}
+
+// newProgress returns a function printing the progress of the nodes to w in
+// format. The text format redraws a table of all nodes if w is a terminal,
+// otherwise a line is printed per update. The json format prints an object
+// per update.
+func newProgress(w io.Writer, format string) (topo.ProgressFunc, error) {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		var mu sync.Mutex
+		return func(p *topo.NodeProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err := enc.Encode(p); err != nil {
+				log.Warnf("Failed to write progress of node %q: %v", p.Node, err)
+			}
+		}, nil
+	case "text":
+		v := &progressView{w: w, nodes: map[string]*topo.NodeProgress{}}
+		if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			v.live = true
+		}
+		return v.update, nil
+	}
+	return nil, fmt.Errorf("invalid progress format %q, must be text or json", format)
+}
+
+func (v *progressView) update(p *topo.NodeProgress) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.live {
+		fmt.Fprintf(v.w, "[%7s] %s: %s%s\n", p.Elapsed.Truncate(100*time.Millisecond), p.Node, p.Phase, progressDetail(p))
+		return
+	}
+	v.nodes[p.Node] = p
+	if v.lines > 0 {
+		// Move the cursor to the start of the previous table and clear it.
+		fmt.Fprintf(v.w, "\033[%dA\033[J", v.lines)
+	}
+	var names []string
+	for n := range v.nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(v.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tPHASE\tIMAGE\tELAPSED\tREASON")
+	for _, n := range names {
+		np := v.nodes[n]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", np.Node, np.Phase, np.ImagePull, np.Elapsed.Truncate(100*time.Millisecond), np.Reason)
+	}
+	tw.Flush()
+	v.lines = len(names) + 1
+}
+
+// progressDetail returns the image pull state and reason of p, if set.
+func progressDetail(p *topo.NodeProgress) string {
+	var d []string
+	if p.ImagePull != "" {
+		d = append(d, "image "+p.ImagePull)
+	}
+	if p.Reason != "" {
+		d = append(d, p.Reason)
+	}
+	if len(d) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(d, ", ") + ")"
+}
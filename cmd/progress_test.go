@@ -0,0 +1,76 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	"github.com/openconfig/kne/topo"
+	"github.com/openconfig/kne/topo/node"
+)
+
+func TestNewProgress(t *testing.T) {
+	updates := []*topo.NodeProgress{
+		{Node: "r1", Phase: node.StatusCreating, ImagePull: topo.ImagePullPulling, Elapsed: 1500 * time.Millisecond},
+		{Node: "r1", Phase: node.StatusFailed, ImagePull: "ErrImagePull", Reason: "pull access denied", Elapsed: 3 * time.Second},
+		{Node: "r2", Phase: node.StatusReady, ImagePull: topo.ImagePullPulled, Elapsed: 42 * time.Second},
+	}
+	tests := []struct {
+		desc    string
+		format  string
+		want    string
+		wantErr string
+	}{{
+		desc:   "text",
+		format: "text",
+		want: `[   1.5s] r1: CREATING (image Pulling)
+[     3s] r1: FAILED (image ErrImagePull, pull access denied)
+[    42s] r2: READY (image Pulled)
+`,
+	}, {
+		desc:   "json",
+		format: "json",
+		want: `{"node":"r1","phase":"CREATING","image_pull":"Pulling","elapsed_seconds":1.5}
+{"node":"r1","phase":"FAILED","image_pull":"ErrImagePull","reason":"pull access denied","elapsed_seconds":3}
+{"node":"r2","phase":"READY","image_pull":"Pulled","elapsed_seconds":42}
+`,
+	}, {
+		desc:    "invalid",
+		format:  "yaml",
+		wantErr: "invalid progress format",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			f, err := newProgress(&buf, tt.format)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("newProgress() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			for _, u := range updates {
+				f(u)
+			}
+			if s := cmp.Diff(tt.want, buf.String()); s != "" {
+				t.Errorf("newProgress() unexpected output diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
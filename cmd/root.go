@@ -16,25 +16,45 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kr/pretty"
 	"github.com/openconfig/kne/cmd/deploy"
 	"github.com/openconfig/kne/cmd/topology"
+	kdeploy "github.com/openconfig/kne/deploy"
 	"github.com/openconfig/kne/topo"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 )
 
 var (
-	kubecfg  string
-	dryrun   bool
-	timeout  time.Duration
-	logLevel = "info"
+	kubecfg   string
+	kubeCtx   string
+	dryrun    bool
+	timeout   time.Duration
+	logLevel  = "info"
+	logFormat = "text"
+	progress  string
+	workers   int
+	qps       float32
+	burst     int
+	imageMap  map[string]string
+	archives  []string
+	prepull   int
+	replicas  int
+	manifest  string
+
+	skipCapacity bool
+
+	cleanupNamespaces []string
+	topoFormat        string
 
 	rootCmd = &cobra.Command{
 		Use:   "kne",
@@ -53,6 +73,14 @@ func rootFn(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	log.SetLevel(l)
+	switch logFormat {
+	case "text":
+		log.SetFormatter(&log.TextFormatter{})
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return fmt.Errorf("invalid log format %q, must be text or json", logFormat)
+	}
 	return nil
 }
 
@@ -74,12 +102,32 @@ func defaultKubeCfg() string {
 func init() {
 	rootCmd.SetOut(os.Stdout)
 	rootCmd.PersistentFlags().StringVar(&kubecfg, "kubecfg", defaultKubeCfg(), "kubeconfig file")
+	rootCmd.PersistentFlags().StringVar(&kubecfg, "kubeconfig", defaultKubeCfg(), "kubeconfig file, alias of --kubecfg")
+	rootCmd.PersistentFlags().StringVar(&kubeCtx, "context", "", "context of the kubeconfig to use, the current context if empty")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "verbosity", "v", logLevel, "log level")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormat, "log format, text or json")
+	rootCmd.PersistentFlags().StringVar(&topoFormat, "topology-format", "", "format of the topology file, text, json or yaml (default by extension, text if unknown)")
 	createCmd.Flags().BoolVar(&dryrun, "dryrun", false, "Generate topology but do not push to k8s")
 	createCmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for pod status enquiry")
+	createCmd.Flags().IntVar(&workers, "workers", 0, "maximum number of nodes created concurrently, 0 for the default")
+	createCmd.Flags().Float32Var(&qps, "qps", 0, "maximum requests per second to the API server, 0 for the client default")
+	createCmd.Flags().IntVar(&burst, "burst", 0, "maximum burst of requests to the API server, 0 for the client default")
+	deleteCmd.Flags().Float32Var(&qps, "qps", 0, "maximum requests per second to the API server, 0 for the client default")
+	deleteCmd.Flags().IntVar(&burst, "burst", 0, "maximum burst of requests to the API server, 0 for the client default")
+	createCmd.Flags().StringToStringVar(&imageMap, "image-map", nil, "rewrite node images, e.g. to images preloaded into the cluster, as source=destination pairs")
+	createCmd.Flags().StringSliceVar(&archives, "image-archive", nil, "image archives, as written by docker save, to load into the kind cluster of the current context before creating the topology")
+	createCmd.Flags().BoolVar(&skipCapacity, "skip-capacity-check", false, "do not check the cluster has the capacity for the constraints of the nodes")
+	createCmd.Flags().IntVar(&prepull, "prepull", 0, "pull the images of the nodes onto the cluster nodes before creating the topology, at most this many at a time, disabled if 0")
+	createCmd.Flags().IntVar(&replicas, "replicas", 0, "create this many isolated copies of the topology, named <topology>-0 to <topology>-<replicas-1>, and print a manifest of their endpoints")
+	createCmd.Flags().StringVar(&manifest, "manifest", "", "path of the manifest of the endpoints of the replicas to write (default stdout)")
+	deleteCmd.Flags().IntVar(&replicas, "replicas", 0, "delete this many copies of the topology created by create --replicas")
+	createCmd.Flags().StringVar(&progress, "progress", "", "print the progress of the nodes instead of info logs, text or json")
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(deleteCmd)
+	cleanupCmd.Flags().StringSliceVar(&cleanupNamespaces, "namespace", nil, "namespaces to clean up, all namespaces with KNE resources if empty")
+	cleanupCmd.Flags().BoolVar(&dryrun, "dryrun", false, "list the left over resources but do not delete them")
 	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(cleanupCmd)
 	rootCmd.AddCommand(topology.New())
 	rootCmd.AddCommand(deploy.New())
 }
@@ -106,6 +154,17 @@ var (
 		RunE:      showFn,
 		ValidArgs: []string{"topology"},
 	}
+	cleanupCmd = &cobra.Command{
+		Use:   "cleanup",
+		Short: "Clean up left over resources of topologies",
+		Long: `Clean up the resources left over by interrupted creates and deletes of
+topologies: the meshnet topologies, services and config maps of nodes without
+a pod, and the namespaces created by KNE without node pods. The left over
+resources found are printed. Do not clean up while a topology is being
+created.`,
+		Args: cobra.NoArgs,
+		RunE: cleanupFn,
+	}
 )
 
 func validateTopology(cmd *cobra.Command, args []string) error {
@@ -129,38 +188,150 @@ func createFn(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	log.Infof(bp)
-	topopb, err := topo.Load(args[0])
+	topopb, err := topo.LoadFormat(args[0], topoFormat)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	tm, err := topo.New(topopb, topo.WithKubecfg(kubecfg), topo.WithBasePath(bp))
+	opts := []topo.Option{topo.WithKubecfg(kubecfg), topo.WithContext(kubeCtx), topo.WithBasePath(bp), topo.WithWorkers(workers), topo.WithRateLimit(qps, burst), topo.WithImageMap(imageMap), topo.WithSkipCapacityCheck(skipCapacity), topo.WithPrepull(prepull)}
+	if progress != "" {
+		f, err := newProgress(cmd.OutOrStdout(), progress)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cmd.Use, err)
+		}
+		opts = append(opts, topo.WithProgress(f))
+		// Only warnings are logged so they do not interleave with the
+		// progress, unless a log level is explicitly requested.
+		if !cmd.Flags().Changed("verbosity") {
+			log.SetLevel(log.WarnLevel)
+		}
+	}
+	var (
+		tm *topo.Manager
+		rs *topo.Replicas
+	)
+	if replicas > 0 {
+		rs, err = topo.NewReplicas(topopb, replicas, opts...)
+	} else {
+		tm, err = topo.New(topopb, opts...)
+	}
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
 	if dryrun {
 		return nil
 	}
-	return tm.Create(cmd.Context(), timeout)
+	if len(archives) != 0 {
+		if err := loadImageArchives(archives); err != nil {
+			return fmt.Errorf("%s: %w", cmd.Use, err)
+		}
+	}
+	if rs == nil {
+		return tm.Create(cmd.Context(), timeout)
+	}
+	endpoints, err := rs.Create(cmd.Context(), timeout)
+	if err != nil {
+		return err
+	}
+	if err := writeManifest(cmd, endpoints); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	return nil
+}
+
+// writeManifest writes the endpoints of the replicas as JSON to the file set
+// by --manifest, or to the output of cmd if not set.
+func writeManifest(cmd *cobra.Command, endpoints []*topo.TopologyEndpoints) error {
+	b, err := json.MarshalIndent(endpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if manifest == "" {
+		_, err := cmd.OutOrStdout().Write(b)
+		return err
+	}
+	if err := os.WriteFile(manifest, b, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	log.Infof("Wrote manifest of %d replicas to %q", len(endpoints), manifest)
+	return nil
+}
+
+// kindClusterName returns the name of the kind cluster of context, or of the
+// current context of the kubeconfig at path if empty.
+func kindClusterName(path, context string) (string, error) {
+	if context == "" {
+		cfg, err := clientcmd.LoadFromFile(path)
+		if err != nil {
+			return "", err
+		}
+		context = cfg.CurrentContext
+	}
+	name := strings.TrimPrefix(context, "kind-")
+	if name == context || name == "" {
+		return "", fmt.Errorf("context %q is not a kind cluster, load image archives into other clusters with kne deploy", context)
+	}
+	return name, nil
+}
+
+// loadImageArchives loads the image archives into the kind cluster of the
+// current context.
+func loadImageArchives(paths []string) error {
+	name, err := kindClusterName(kubecfg, kubeCtx)
+	if err != nil {
+		return err
+	}
+	k := &kdeploy.KindSpec{Name: name}
+	for _, p := range paths {
+		log.Infof("Loading image archive %q into kind cluster %q", p, name)
+		if err := k.LoadImageArchive(p); err != nil {
+			return fmt.Errorf("failed to load image archive %q: %w", p, err)
+		}
+	}
+	return nil
 }
 
 func deleteFn(cmd *cobra.Command, args []string) error {
-	topopb, err := topo.Load(args[0])
+	topopb, err := topo.LoadFormat(args[0], topoFormat)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	tm, err := topo.New(topopb, topo.WithKubecfg(kubecfg))
+	opts := []topo.Option{topo.WithKubecfg(kubecfg), topo.WithContext(kubeCtx), topo.WithRateLimit(qps, burst)}
+	if replicas > 0 {
+		rs, err := topo.NewReplicas(topopb, replicas, opts...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cmd.Use, err)
+		}
+		return rs.Delete(cmd.Context())
+	}
+	tm, err := topo.New(topopb, opts...)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
 	return tm.Delete(cmd.Context())
 }
 
+func cleanupFn(cmd *cobra.Command, args []string) error {
+	leftovers, err := topo.Cleanup(cmd.Context(), cleanupNamespaces, dryrun, topo.WithKubecfg(kubecfg), topo.WithContext(kubeCtx))
+	out := cmd.OutOrStdout()
+	for _, l := range leftovers {
+		fmt.Fprintln(out, l)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	if len(leftovers) == 0 {
+		fmt.Fprintln(out, "No left over resources found")
+	}
+	return nil
+}
+
 func showFn(cmd *cobra.Command, args []string) error {
-	topopb, err := topo.Load(args[0])
+	topopb, err := topo.LoadFormat(args[0], topoFormat)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	tm, err := topo.New(topopb, topo.WithKubecfg(kubecfg))
+	tm, err := topo.New(topopb, topo.WithKubecfg(kubecfg), topo.WithContext(kubeCtx))
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
@@ -2,7 +2,12 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/h-fam/errdiff"
+	log "github.com/sirupsen/logrus"
 )
 
 func TestGetKubeCfg(t *testing.T) {
@@ -55,3 +60,95 @@ func TestGetKubeCfg(t *testing.T) {
 		})
 	}
 }
+
+func TestRootFn(t *testing.T) {
+	origLevel, origFormat := logLevel, logFormat
+	defer func() {
+		logLevel, logFormat = origLevel, origFormat
+		log.SetLevel(log.InfoLevel)
+		log.SetFormatter(&log.TextFormatter{})
+	}()
+	tests := []struct {
+		desc          string
+		level         string
+		format        string
+		wantFormatter log.Formatter
+		wantErr       string
+	}{{
+		desc:          "text",
+		level:         "info",
+		format:        "text",
+		wantFormatter: &log.TextFormatter{},
+	}, {
+		desc:          "json",
+		level:         "debug",
+		format:        "json",
+		wantFormatter: &log.JSONFormatter{},
+	}, {
+		desc:    "invalid format",
+		level:   "info",
+		format:  "xml",
+		wantErr: "invalid log format",
+	}, {
+		desc:    "invalid level",
+		level:   "loud",
+		format:  "text",
+		wantErr: "not a valid logrus Level",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			logLevel, logFormat = tt.level, tt.format
+			err := rootFn(rootCmd, nil)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("rootFn() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if got, want := reflect.TypeOf(log.StandardLogger().Formatter), reflect.TypeOf(tt.wantFormatter); got != want {
+				t.Errorf("rootFn() set formatter %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestKindClusterName(t *testing.T) {
+	tests := []struct {
+		desc     string
+		current  string
+		override string
+		want     string
+		wantErr  string
+	}{{
+		desc:    "kind",
+		current: "kind-kne",
+		want:    "kne",
+	}, {
+		desc:     "context",
+		current:  "minikube",
+		override: "kind-other",
+		want:     "other",
+	}, {
+		desc:    "not kind",
+		current: "minikube",
+		wantErr: "is not a kind cluster",
+	}, {
+		desc:    "no context",
+		wantErr: "is not a kind cluster",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if err := os.WriteFile(path, []byte("apiVersion: v1\nkind: Config\ncurrent-context: "+tt.current+"\n"), 0600); err != nil {
+				t.Fatalf("failed to write kubeconfig: %v", err)
+			}
+			got, err := kindClusterName(path, tt.override)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if got != tt.want {
+				t.Errorf("kindClusterName() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,33 +16,49 @@ package topology
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/openconfig/gnmi/errlist"
+	bpb "github.com/openconfig/kne/proto/bind"
 	cpb "github.com/openconfig/kne/proto/controller"
 	tpb "github.com/openconfig/kne/proto/topo"
 	"github.com/openconfig/kne/topo"
 	"github.com/openconfig/kne/topo/node"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/prototext"
+	"k8s.io/client-go/rest"
 )
 
 func New() *cobra.Command {
 	pushCmd := &cobra.Command{
-		Use:   "push <topology> <device> <config file>",
-		Short: "push config to device",
+		Use:   "push <topology> [<device>] <config file>",
+		Short: "push config to device (or to the devices selected by --nodes and --label)",
 		RunE:  pushFn,
 	}
 	watchCmd := &cobra.Command{
 		Use:   "watch <topology>",
-		Short: "watch will watch the current topologies",
+		Short: "watch the changes of the phases, service IPs and links of the nodes of the topology",
 		RunE:  watchFn,
 	}
+	statusCmd := &cobra.Command{
+		Use:   "status <topology>",
+		Short: "show the health of the nodes of the topology, failing if any node is not healthy",
+		RunE:  statusFn,
+	}
 	serviceCmd := &cobra.Command{
 		Use:   "service <topology>",
 		Short: "service returns the current topology with service endpoints defined.",
@@ -53,31 +69,210 @@ func New() *cobra.Command {
 		Short: "push or generate certs for nodes in topology",
 		RunE:  certFn,
 	}
+	rotateCertsCmd := &cobra.Command{
+		Use:   "rotate-certs <topology>",
+		Short: "re-generate and install the certs of the running nodes of the topology, one node at a time",
+		RunE:  rotateCertsFn,
+	}
+	prepullCmd := &cobra.Command{
+		Use:   "prepull <topology>",
+		Short: "pull the images of the nodes of the topology onto the cluster nodes, a few at a time",
+		RunE:  prepullFn,
+	}
+	resourcesCmd := &cobra.Command{
+		Use:   "resources <topology>",
+		Short: "summarize the resources the topology requests, optionally compared with the cluster",
+		RunE:  resourcesFn,
+	}
 	resetCfgCmd := &cobra.Command{
 		Use:   "reset <topology> <device>",
-		Short: "reset configuration of device to vendor default (if device not provide reset all nodes)",
+		Short: "reset configuration of device to vendor default (if device not provided reset all nodes selected by --nodes and --label)",
 		RunE:  resetCfgFn,
 	}
+	consoleCmd := &cobra.Command{
+		Use:   "console <topology> <device>",
+		Short: "open an interactive session on device using its entry command",
+		RunE:  consoleFn,
+	}
+	logsCmd := &cobra.Command{
+		Use:   "logs <topology> [<device>]",
+		Short: "print the logs of device (if device not provided print logs of all nodes)",
+		RunE:  logsFn,
+	}
+	collectCmd := &cobra.Command{
+		Use:   "collect <topology>",
+		Short: "collect debug information for topology into an archive",
+		RunE:  collectFn,
+	}
+	captureCmd := &cobra.Command{
+		Use:   "capture <topology> <device>:<interface>",
+		Short: "capture packets on interface of device to a pcap file until interrupted",
+		RunE:  captureFn,
+	}
+	verifyCmd := &cobra.Command{
+		Use:   "verify <topology>",
+		Short: "verify every link of the topology is functional",
+		RunE:  verifyFn,
+	}
+	bindCmd := &cobra.Command{
+		Use:   "bind <topology> <testbed>",
+		Short: "bind the devices of an Ondatra testbed to the nodes of the topology and write the binding",
+		RunE:  bindFn,
+	}
+	checkCmd := &cobra.Command{
+		Use:   "check <topology> <testbed>",
+		Short: "check the topology can satisfy an Ondatra testbed, without creating it",
+		RunE:  checkFn,
+	}
+	convertCmd := &cobra.Command{
+		Use:   "convert <topology>",
+		Short: "convert the topology file between the text, json and yaml formats",
+		RunE:  convertFn,
+	}
+	interfacesCmd := &cobra.Command{
+		Use:   "interfaces <topology> <device>",
+		Short: "show the vendor interface names of the interfaces of device",
+		RunE:  interfacesFn,
+	}
+	migrateCmd := &cobra.Command{
+		Use:   "migrate <topology>",
+		Short: "upgrade the topology file to the current topology schema version",
+		RunE:  migrateFn,
+	}
+	chaosCmd := &cobra.Command{
+		Use:   "chaos",
+		Short: "kill or restart nodes to test the resiliency of the topology",
+	}
+	chaosCmd.AddCommand(&cobra.Command{
+		Use:   "kill <topology> <device>",
+		Short: "abruptly delete the pods of device, keeping its links and services",
+		RunE:  chaosFn,
+	})
+	chaosCmd.AddCommand(&cobra.Command{
+		Use:   "restart <topology> <device>",
+		Short: "delete and recreate the pods of device, keeping its links and services",
+		RunE:  chaosFn,
+	})
 	topoCmd := &cobra.Command{
 		Use:   "topology",
 		Short: "Topology commands.",
 	}
+	captureCmd.Flags().StringVarP(&captureOutput, "output", "o", "", `path of the pcap file to write, "-" for stdout (default <device>-<interface>.pcap)`)
+	captureCmd.Flags().StringVar(&captureStream, "stream", "", "serve the capture live on tcp:<address> or fifo:<path> instead of writing a file (e.g. for wireshark -k -i)")
+	captureCmd.Flags().StringVar(&captureImage, "image", topo.DefaultCaptureImage, "image providing tcpdump used if the device image does not")
+	bindCmd.Flags().StringVarP(&bindOutput, "output", "o", "", "path of the binding file to write (default stdout)")
+	bindCmd.Flags().StringVar(&bindOpts.Username, "username", "", "default username of the devices in the binding")
+	bindCmd.Flags().StringVar(&bindOpts.Password, "password", "", "default password of the devices in the binding")
+	bindCmd.Flags().BoolVar(&bindOpts.SkipVerify, "skip-verify", false, "skip the verification of the certificates of the devices")
+	topoCmd.AddCommand(bindCmd)
+	topoCmd.AddCommand(captureCmd)
 	topoCmd.AddCommand(certCmd)
+	topoCmd.AddCommand(chaosCmd)
+	topoCmd.AddCommand(checkCmd)
+	collectCmd.Flags().StringVarP(&collectOutput, "output", "o", "", "path of the archive to write (default <topology name>-debug.tgz)")
+	topoCmd.AddCommand(collectCmd)
+	topoCmd.AddCommand(consoleCmd)
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "path of the topology file to write (default stdout)")
+	convertCmd.Flags().StringVar(&convertFormat, "to", "", "format to convert to, text, json or yaml (default by extension of --output, text if unknown)")
+	topoCmd.AddCommand(convertCmd)
+	topoCmd.AddCommand(interfacesCmd)
+	migrateCmd.Flags().StringVarP(&migrateOutput, "output", "o", "", "path of the topology file to write, in the format of its extension (default stdout, in the format of the topology)")
+	topoCmd.AddCommand(migrateCmd)
+	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "stream logs until interrupted")
+	logsCmd.Flags().BoolVar(&allContainers, "all-containers", false, "stream the logs of all containers of the nodes, not only the NOS container")
+	topoCmd.AddCommand(logsCmd)
+	pushCmd.Flags().BoolVar(&pushStream, "stream", false, "stream the output of the devices to stdout during the push")
+	addSelectorFlags(pushCmd)
 	topoCmd.AddCommand(pushCmd)
+	serviceCmd.Flags().StringVarP(&serviceFormat, "output", "o", "text", "output format, text for the topology with its services, json, yaml or env for the service endpoints")
+	serviceCmd.Flags().StringSliceVar(&serviceNames, "service", nil, "comma separated list of names of the services to show (default all)")
+	addSelectorFlags(serviceCmd)
 	topoCmd.AddCommand(serviceCmd)
+	statusCmd.Flags().StringVarP(&statusFormat, "output", "o", "text", "output format, text or json")
+	topoCmd.AddCommand(statusCmd)
+	watchCmd.Flags().StringVarP(&watchFormat, "output", "o", "text", "output format of the events, text or json")
 	topoCmd.AddCommand(watchCmd)
 	resetCfgCmd.Flags().BoolVar(&skipReset, "skip", skipReset, "skip nodes if they are not resetable")
 	resetCfgCmd.Flags().BoolVar(&pushConfig, "push", pushConfig, "additionally push orginal topology configuration")
+	addSelectorFlags(resetCfgCmd)
 	topoCmd.AddCommand(resetCfgCmd)
+	addSelectorFlags(rotateCertsCmd)
+	topoCmd.AddCommand(rotateCertsCmd)
+	prepullCmd.Flags().IntVar(&prepullConcurrency, "concurrency", topo.DefaultPrepullConcurrency, "maximum number of images pulled concurrently")
+	topoCmd.AddCommand(prepullCmd)
+	resourcesCmd.Flags().BoolVar(&resourcesCluster, "cluster", false, "compare the requests with the schedulable capacity of the cluster, failing if the nodes do not fit")
+	resourcesCmd.Flags().StringVarP(&resourcesFormat, "output", "o", "text", "output format, text or json")
+	topoCmd.AddCommand(resourcesCmd)
+	topoCmd.AddCommand(verifyCmd)
 	return topoCmd
 }
 
 var (
-	skipReset  bool
-	pushConfig bool
-	opts       []topo.Option
+	skipReset          bool
+	followLogs         bool
+	allContainers      bool
+	collectOutput      string
+	bindOutput         string
+	convertOutput      string
+	convertFormat      string
+	migrateOutput      string
+	serviceFormat      string
+	serviceNames       []string
+	watchFormat        string
+	statusFormat       string
+	bindOpts           topo.BindOptions
+	captureOutput      string
+	captureImage       string
+	captureStream      string
+	pushConfig         bool
+	pushStream         bool
+	prepullConcurrency int
+	resourcesCluster   bool
+	resourcesFormat    string
+	nodePatterns       []string
+	labelSelector      []string
+	opts               []topo.Option
 )
 
+func addSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&nodePatterns, "nodes", nil, "comma separated list of node names or glob patterns to target")
+	cmd.Flags().StringArrayVar(&labelSelector, "label", nil, "label selector nodes must match to be targeted (e.g. vendor=CISCO), may be repeated")
+}
+
+// clusterOpts returns the options of the topology manager with the kubeconfig
+// and context of the cluster set by the --kubecfg and --context flags.
+func clusterOpts(cmd *cobra.Command) ([]topo.Option, error) {
+	s, err := cmd.Flags().GetString("kubecfg")
+	if err != nil {
+		return nil, err
+	}
+	tOpts := append(opts, topo.WithKubecfg(s))
+	if f := cmd.Flags().Lookup("context"); f != nil {
+		tOpts = append(tOpts, topo.WithContext(f.Value.String()))
+	}
+	return tOpts, nil
+}
+
+// loadTopology loads the topology at path in the format set by the
+// --topology-format flag, or in the format of its extension if not set.
+func loadTopology(cmd *cobra.Command, path string) (*tpb.Topology, error) {
+	var format string
+	if f := cmd.Flags().Lookup("topology-format"); f != nil {
+		format = f.Value.String()
+	}
+	return topo.LoadFormat(path, format)
+}
+
+// hasSelectors returns true if --nodes or --label were provided.
+func hasSelectors() bool {
+	return len(nodePatterns) != 0 || len(labelSelector) != 0
+}
+
+// selectNodes returns the nodes of the topology selected by --nodes and --label.
+func selectNodes(tm *topo.Manager) (map[string]node.Node, error) {
+	return tm.SelectNodes(nodePatterns, strings.Join(labelSelector, ","))
+}
+
 func fileRelative(p string) (string, error) {
 	bp, err := filepath.Abs(p)
 	if err != nil {
@@ -87,25 +282,29 @@ func fileRelative(p string) (string, error) {
 }
 
 func resetCfgFn(cmd *cobra.Command, args []string) error {
-	if len(args) < 1 || len(args) > 2 {
+	if len(args) < 1 || len(args) > 2 || (len(args) == 2 && hasSelectors()) {
 		return fmt.Errorf("%s: invalid args", cmd.Use)
 	}
-	topopb, err := topo.Load(args[0])
+	topopb, err := loadTopology(cmd, args[0])
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	s, err := cmd.Flags().GetString("kubecfg")
+	tOpts, err := clusterOpts(cmd)
 	if err != nil {
 		return err
 	}
-	tOpts := append(opts, topo.WithKubecfg(s))
 	tm, err := topo.New(topopb, tOpts...)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
 	nodes := tm.Nodes()
-	if len(args) > 1 {
+	switch {
+	case len(args) > 1:
 		nodes = map[string]node.Node{args[1]: nodes[args[1]]}
+	case hasSelectors():
+		if nodes, err = selectNodes(tm); err != nil {
+			return fmt.Errorf("%s: %w", cmd.Use, err)
+		}
 	}
 	for name := range nodes {
 		err := tm.ResetCfg(cmd.Context(), name)
@@ -169,68 +368,111 @@ func resetCfgFn(cmd *cobra.Command, args []string) error {
 }
 
 func pushFn(cmd *cobra.Command, args []string) error {
-	if len(args) != 3 {
+	want := 3
+	if hasSelectors() {
+		want = 2
+	}
+	if len(args) != want {
 		return fmt.Errorf("%s: invalid args", cmd.Use)
 	}
-	topopb, err := topo.Load(args[0])
+	topopb, err := loadTopology(cmd, args[0])
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	s, err := cmd.Flags().GetString("kubecfg")
+	tOpts, err := clusterOpts(cmd)
 	if err != nil {
 		return err
 	}
-	tOpts := append(opts, topo.WithKubecfg(s))
 	tm, err := topo.New(topopb, tOpts...)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-
-	fp, err := os.Open(args[2])
+	cfgPath := args[len(args)-1]
+	// The push is canceled on interrupt so the CLI connections are closed.
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer cancel()
+	var w io.Writer
+	if pushStream {
+		w = cmd.OutOrStdout()
+	}
+	if !hasSelectors() {
+		fp, err := os.Open(cfgPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := fp.Close(); err != nil {
+				log.Warnf("failed to close config file %q", cfgPath)
+			}
+		}()
+		return tm.ConfigPushStream(ctx, args[1], fp, w)
+	}
+	nodes, err := selectNodes(tm)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	b, err := os.ReadFile(cfgPath)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := fp.Close(); err != nil {
-			log.Warnf("failed to close config file %q", args[2])
+	var errList errlist.List
+	for name := range nodes {
+		log.Infof("Pushing configuration %q to %q", cfgPath, name)
+		if err := tm.ConfigPushStream(ctx, name, bytes.NewReader(b), w); err != nil {
+			errList.Add(err)
 		}
-	}()
-	return tm.ConfigPush(cmd.Context(), args[1], fp)
+	}
+	return errList.Err()
 }
 
 func watchFn(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("%s: missing topology", cmd.Use)
 	}
-	topopb, err := topo.Load(args[0])
+	if watchFormat != "text" && watchFormat != "json" {
+		return fmt.Errorf("%s: invalid output format %q, must be text or json", cmd.Use, watchFormat)
+	}
+	topopb, err := loadTopology(cmd, args[0])
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	s, err := cmd.Flags().GetString("kubecfg")
+	tOpts, err := clusterOpts(cmd)
 	if err != nil {
 		return err
 	}
-	tOpts := append(opts, topo.WithKubecfg(s))
 	tm, err := topo.New(topopb, tOpts...)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	return tm.Watch(cmd.Context())
+	ch, err := tm.Subscribe(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	for e := range ch {
+		if watchFormat == "json" {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", e.Time.Format("15:04:05"), e)
+	}
+	return nil
 }
 
 func certFn(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
 		return fmt.Errorf("%s: missing args", cmd.Use)
 	}
-	topopb, err := topo.Load(args[0])
+	topopb, err := loadTopology(cmd, args[0])
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	s, err := cmd.Flags().GetString("kubecfg")
+	tOpts, err := clusterOpts(cmd)
 	if err != nil {
 		return err
 	}
-	tOpts := append(opts, topo.WithKubecfg(s))
 	tm, err := topo.New(topopb, tOpts...)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
@@ -238,6 +480,377 @@ func certFn(cmd *cobra.Command, args []string) error {
 	return tm.GenerateSelfSigned(cmd.Context(), args[1])
 }
 
+// rotateCertsFn rotates the certs of the nodes of a topology. The nodes are
+// rotated one at a time so the other nodes keep serving while a node is
+// rotated. Nodes without cert info or not supporting certs are skipped.
+func rotateCertsFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	nodes := tm.Nodes()
+	if hasSelectors() {
+		if nodes, err = selectNodes(tm); err != nil {
+			return fmt.Errorf("%s: %w", cmd.Use, err)
+		}
+	}
+	var names []string
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errList errlist.List
+	for _, name := range names {
+		if nodes[name].GetProto().GetConfig().GetCert() == nil {
+			log.Infof("Skipping node %q no cert info", name)
+			continue
+		}
+		err := tm.RotateCert(cmd.Context(), name)
+		switch {
+		default:
+			errList.Add(fmt.Errorf("node %q: %w", name, err))
+		case err == nil:
+			fmt.Fprintf(cmd.OutOrStdout(), "Rotated cert of node %q\n", name)
+		case status.Code(err) == codes.Unimplemented:
+			log.Infof("Skipping node %q not a Certer", name)
+		}
+	}
+	return errList.Err()
+}
+
+// prepullFn pulls the images of the nodes of a topology onto the cluster
+// nodes.
+func prepullFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	pulls, err := tm.Prepull(cmd.Context(), prepullConcurrency)
+	for _, p := range pulls {
+		switch {
+		case p.Present:
+			fmt.Fprintf(cmd.OutOrStdout(), "Image %q already on cluster node %q\n", p.Image, p.ClusterNode)
+		case p.Err == nil:
+			fmt.Fprintf(cmd.OutOrStdout(), "Pulled image %q onto cluster node %q in %s\n", p.Image, p.ClusterNode, p.Duration.Truncate(time.Second))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	return nil
+}
+
+// resourcesFn prints the resources requested by the nodes of a topology. The
+// cluster is only accessed if the requests are compared with it, an error is
+// returned if the nodes do not fit into it.
+func resourcesFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	if resourcesFormat != "text" && resourcesFormat != "json" {
+		return fmt.Errorf("%s: invalid output format %q, must be text or json", cmd.Use, resourcesFormat)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	if !resourcesCluster {
+		// The topology can be summarized without a kubeconfig.
+		tOpts = append([]topo.Option{topo.WithClusterConfig(&rest.Config{})}, tOpts...)
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	r, err := tm.ResourceReport(cmd.Context(), resourcesCluster)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	if resourcesFormat == "json" {
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(b))
+	} else {
+		writeResourceReport(cmd.OutOrStdout(), r)
+	}
+	if r.Cluster == nil || len(r.Cluster.Unschedulable) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %d of %d nodes of topology %q do not fit into the cluster: %s", cmd.Use, len(r.Cluster.Unschedulable), r.Nodes, r.Name, strings.Join(r.Cluster.Unschedulable, ", "))
+}
+
+// writeResourceReport writes the totals and tables of the vendors and images
+// of r to w.
+func writeResourceReport(w io.Writer, r *topo.ResourceReport) {
+	fmt.Fprintf(w, "Topology %q: %d nodes, requests %s\n\n", r.Name, r.Nodes, dash(topo.FormatResources(r.Requests)))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VENDOR\tNODES\tREQUESTS")
+	for _, v := range r.Vendors {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", v.Vendor, v.Nodes, dash(topo.FormatResources(v.Requests)))
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "IMAGE\tNODES\tSIZE")
+	for _, i := range r.Images {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", i.Image, i.Nodes, formatBytes(i.SizeBytes))
+	}
+	tw.Flush()
+	if r.Cluster == nil {
+		return
+	}
+	fmt.Fprintf(w, "\nCluster: %d schedulable nodes, free %s\n", r.Cluster.Nodes, dash(topo.FormatResources(r.Cluster.Free)))
+	if len(r.Cluster.Unschedulable) == 0 {
+		fmt.Fprintln(w, "All nodes fit into the cluster")
+		return
+	}
+	fmt.Fprintf(w, "Nodes not fitting into the cluster: %s\n", strings.Join(r.Cluster.Unschedulable, ", "))
+}
+
+// formatBytes returns n in binary units, e.g. 1.5GiB, or "-" if n is 0.
+func formatBytes(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// chaosFn kills or restarts a node, as named by the subcommand.
+func chaosFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%s: missing args", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	if cmd.Name() == "restart" {
+		err = tm.RestartNode(cmd.Context(), args[1])
+	} else {
+		err = tm.KillNode(cmd.Context(), args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	return nil
+}
+
+func consoleFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%s: missing args", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	// Put the local terminal in raw mode so that input is passed through to
+	// the remote session unmodified.
+	if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+		defer func() {
+			if err := term.Restore(int(f.Fd()), state); err != nil {
+				log.Warnf("failed to restore terminal: %v", err)
+			}
+		}()
+	}
+	return tm.Console(cmd.Context(), args[1], cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+}
+
+func logsFn(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("%s: invalid args", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	return tm.Logs(cmd.Context(), cmd.OutOrStdout(), followLogs, allContainers, args[1:]...)
+}
+
+func collectFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	out := collectOutput
+	if out == "" {
+		out = fmt.Sprintf("%s-debug.tgz", topopb.GetName())
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := tm.Collect(cmd.Context(), f); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	log.Infof("Wrote debug bundle for topology %q to %q", topopb.GetName(), out)
+	return nil
+}
+
+func verifyFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	results, err := tm.Verify(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	var failed int
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(cmd.OutOrStdout(), "FAIL %s: %v\n", r, r.Err)
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "OK   %s\n", r)
+	}
+	if failed != 0 {
+		return fmt.Errorf("%s: %d of %d links failed verification", cmd.Use, failed, len(results))
+	}
+	return nil
+}
+
+func captureFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%s: missing args", cmd.Use)
+	}
+	nodeName, intf, ok := strings.Cut(args[1], ":")
+	if !ok || nodeName == "" || intf == "" {
+		return fmt.Errorf("%s: invalid interface %q, must be <device>:<interface>", cmd.Use, args[1])
+	}
+	if captureStream != "" && captureOutput != "" {
+		return fmt.Errorf("%s: --stream and --output are mutually exclusive", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer cancel()
+	cOpts := topo.CaptureOptions{Image: captureImage}
+	if captureStream != "" {
+		return tm.StreamCapture(ctx, nodeName, intf, captureStream, cOpts)
+	}
+	out := captureOutput
+	if out == "" {
+		out = fmt.Sprintf("%s-%s.pcap", nodeName, strings.ReplaceAll(intf, "/", "_"))
+	}
+	w := cmd.OutOrStdout()
+	if out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.Warnf("failed to close capture file %q", out)
+			}
+		}()
+		w = f
+		log.Infof("Writing capture to %q, interrupt to stop", out)
+	}
+	return tm.Capture(ctx, nodeName, intf, w, cOpts)
+}
+
 var newTopologyManager = func(topopb *tpb.Topology, opts ...topo.Option) (TopologyManager, error) {
 	return topo.New(topopb, opts...)
 }
@@ -250,15 +863,19 @@ func serviceFn(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("%s: missing topology", cmd.Use)
 	}
-	topopb, err := topo.Load(args[0])
+	switch serviceFormat {
+	case "text", "json", "yaml", "env":
+	default:
+		return fmt.Errorf("%s: invalid output format %q, must be text, json, yaml or env", cmd.Use, serviceFormat)
+	}
+	topopb, err := loadTopology(cmd, args[0])
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
 	}
-	s, err := cmd.Flags().GetString("kubecfg")
+	tOpts, err := clusterOpts(cmd)
 	if err != nil {
 		return err
 	}
-	tOpts := append(opts, topo.WithKubecfg(s))
 	tm, err := newTopologyManager(topopb, tOpts...)
 	if err != nil {
 		return fmt.Errorf("%s: %w", cmd.Use, err)
@@ -267,7 +884,394 @@ func serviceFn(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if hasSelectors() || len(serviceNames) != 0 {
+		if err := filterServices(ts.Topology); err != nil {
+			return fmt.Errorf("%s: %w", cmd.Use, err)
+		}
+	}
+	if serviceFormat == "text" {
+		fmt.Fprintln(cmd.OutOrStdout(), prototext.Format(ts.Topology))
+		return nil
+	}
+	eps := serviceEndpoints(ts.Topology)
+	switch serviceFormat {
+	case "json":
+		b, err := json.MarshalIndent(eps, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(b))
+	case "yaml":
+		b, err := yaml.Marshal(eps)
+		if err != nil {
+			return err
+		}
+		if _, err := cmd.OutOrStdout().Write(b); err != nil {
+			return err
+		}
+	case "env":
+		for _, ep := range eps {
+			p := envName(ep.Node + "_" + ep.Name)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s_INSIDE=%d\n", p, ep.Inside)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s_OUTSIDE=%d\n", p, ep.Outside)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s_INSIDE_IP=%s\n", p, ep.InsideIP)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s_OUTSIDE_IP=%s\n", p, ep.OutsideIP)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s_NODE_PORT=%d\n", p, ep.NodePort)
+		}
+	}
+	return nil
+}
 
-	fmt.Fprintln(cmd.OutOrStdout(), prototext.Format(ts.Topology))
+// statusFn prints the health of the nodes of a topology and returns an error
+// if any node is not healthy, so scripts can wait for a healthy topology.
+func statusFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	if statusFormat != "text" && statusFormat != "json" {
+		return fmt.Errorf("%s: invalid output format %q, must be text or json", cmd.Use, statusFormat)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := topo.New(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	h, err := tm.Health(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	if statusFormat == "json" {
+		b, err := json.MarshalIndent(h, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(b))
+	} else {
+		writeHealth(cmd.OutOrStdout(), h)
+	}
+	if h.Healthy {
+		return nil
+	}
+	var unhealthy []string
+	for _, n := range h.Nodes {
+		if !n.Healthy {
+			unhealthy = append(unhealthy, n.Node)
+		}
+	}
+	return fmt.Errorf("%s: topology %q is not healthy, unhealthy nodes: %s", cmd.Use, h.Name, strings.Join(unhealthy, ", "))
+}
+
+// writeHealth writes a table of the health of the nodes of h to w.
+func writeHealth(w io.Writer, h *topo.TopologyHealth) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tPHASE\tRESTARTS\tCONFIG PUSH\tSERVICES\tHEALTHY\tREASON")
+	for _, n := range h.Nodes {
+		var services []string
+		for _, s := range n.Services {
+			ip := s.OutsideIP
+			if ip == "" {
+				ip = "<pending>"
+			}
+			services = append(services, fmt.Sprintf("%s=%s:%d", s.Name, ip, s.Outside))
+		}
+		reason := n.Reason
+		if n.ConfigPushReason != "" {
+			reason = strings.TrimSpace(reason + " config push: " + n.ConfigPushReason)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%v\t%s\n", n.Node, n.Phase, n.Restarts, dash(n.ConfigPush), dash(strings.Join(services, ",")), n.Healthy, reason)
+	}
+	tw.Flush()
+}
+
+// dash returns s, or "-" if s is empty.
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// serviceEndpoint is a service of a node in the machine readable output of
+// the service command.
+type serviceEndpoint struct {
+	Node string `json:"node"`
+	Name string `json:"name"`
+	// Inside is the port of the service on the node.
+	Inside uint32 `json:"inside"`
+	// Outside is the port of the service on its external IP, the inside port
+	// if not set.
+	Outside   uint32 `json:"outside"`
+	InsideIP  string `json:"inside_ip,omitempty"`
+	OutsideIP string `json:"outside_ip,omitempty"`
+	NodePort  uint32 `json:"node_port,omitempty"`
+}
+
+// serviceEndpoints returns the services of the nodes of t in the order of the
+// nodes and by port.
+func serviceEndpoints(t *tpb.Topology) []*serviceEndpoint {
+	eps := []*serviceEndpoint{}
+	for _, n := range t.GetNodes() {
+		var ports []uint32
+		for k := range n.GetServices() {
+			ports = append(ports, k)
+		}
+		sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+		for _, k := range ports {
+			svc := n.Services[k]
+			ep := &serviceEndpoint{
+				Node:      n.GetName(),
+				Name:      serviceName(k, svc),
+				Inside:    svc.GetInside(),
+				Outside:   svc.GetOutside(),
+				InsideIP:  svc.GetInsideIp(),
+				OutsideIP: svc.GetOutsideIp(),
+				NodePort:  svc.GetNodePort(),
+			}
+			if ep.Outside == 0 {
+				ep.Outside = ep.Inside
+			}
+			eps = append(eps, ep)
+		}
+	}
+	return eps
+}
+
+// serviceName returns the name of the service svc of port k as named in the
+// cluster.
+func serviceName(k uint32, svc *tpb.Service) string {
+	if name := svc.GetName(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("port-%d", k)
+}
+
+// filterServices removes the nodes not selected by --nodes and --label and
+// the services not named by --service from t.
+func filterServices(t *tpb.Topology) error {
+	selected, err := topo.SelectNodeProtos(t, nodePatterns, strings.Join(labelSelector, ","))
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, name := range serviceNames {
+		names[name] = true
+	}
+	var nodes []*tpb.Node
+	for _, n := range t.GetNodes() {
+		if selected[n.GetName()] == nil {
+			continue
+		}
+		if len(names) != 0 {
+			for k, svc := range n.GetServices() {
+				if !names[serviceName(k, svc)] {
+					delete(n.Services, k)
+				}
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	t.Nodes = nodes
+	return nil
+}
+
+// envName returns s in upper case with all characters not valid in the name
+// of an environment variable replaced by underscores.
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func bindFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%s: missing args", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tb, err := loadTestbed(args[1])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tOpts, err := clusterOpts(cmd)
+	if err != nil {
+		return err
+	}
+	tm, err := newTopologyManager(topopb, tOpts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	ts, err := tm.Show(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	binding, err := topo.Bind(ts.Topology, tb, bindOpts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	out := prototext.MarshalOptions{Multiline: true}.Format(binding)
+	if bindOutput == "" {
+		fmt.Fprint(cmd.OutOrStdout(), out)
+		return nil
+	}
+	if err := os.WriteFile(bindOutput, []byte(out), 0644); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	log.Infof("Wrote binding of %d devices to %q", len(binding.GetDuts())+len(binding.GetAtes()), bindOutput)
+	return nil
+}
+
+// loadTestbed loads the Ondatra testbed at path.
+func loadTestbed(path string) (*bpb.Testbed, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tb := &bpb.Testbed{}
+	// The testbed may set fields of the Ondatra testbed not used by KNE.
+	if err := (prototext.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, tb); err != nil {
+		return nil, fmt.Errorf("invalid testbed %q: %w", path, err)
+	}
+	return tb, nil
+}
+
+func checkFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%s: missing args", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	tb, err := loadTestbed(args[1])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	failures, err := topo.CheckTestbed(topopb, tb)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	out := cmd.OutOrStdout()
+	if len(failures) == 0 {
+		fmt.Fprintf(out, "Topology %q can satisfy the testbed\n", topopb.GetName())
+		return nil
+	}
+	for _, f := range failures {
+		fmt.Fprintf(out, "FAIL %v\n", f)
+	}
+	return fmt.Errorf("%s: topology %q cannot satisfy the testbed: %d requirements failed", cmd.Use, topopb.GetName(), len(failures))
+}
+
+func interfacesFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%s: missing args", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	names, err := topo.InterfaceNames(topopb, args[1])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	var ifcs []string
+	for k := range names {
+		ifcs = append(ifcs, k)
+	}
+	// Sort eth2 before eth10.
+	sort.Slice(ifcs, func(i, j int) bool {
+		if len(ifcs[i]) != len(ifcs[j]) {
+			return len(ifcs[i]) < len(ifcs[j])
+		}
+		return ifcs[i] < ifcs[j]
+	})
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INTERFACE\tNAME")
+	for _, k := range ifcs {
+		fmt.Fprintf(tw, "%s\t%s\n", k, names[k])
+	}
+	return tw.Flush()
+}
+
+func migrateFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	from := topopb.GetVersion()
+	changes, err := topo.Migrate(topopb)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	for _, c := range changes {
+		fmt.Fprintln(cmd.ErrOrStderr(), c)
+	}
+	if from != topopb.GetVersion() {
+		fmt.Fprintf(cmd.ErrOrStderr(), "migrated topology %q from version %d to %d\n", topopb.GetName(), from, topopb.GetVersion())
+	}
+	format := ""
+	if f := cmd.Flags().Lookup("topology-format"); f != nil {
+		format = f.Value.String()
+	}
+	switch {
+	case migrateOutput != "":
+		format = topo.FormatOf(migrateOutput)
+	case format == "":
+		format = topo.FormatOf(args[0])
+	}
+	b, err := topo.Marshal(topopb, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	if migrateOutput == "" {
+		_, err := cmd.OutOrStdout().Write(b)
+		return err
+	}
+	if err := os.WriteFile(migrateOutput, b, 0644); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	return nil
+}
+
+func convertFn(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s: missing topology", cmd.Use)
+	}
+	topopb, err := loadTopology(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	format := convertFormat
+	if format == "" {
+		format = topo.FormatOf(convertOutput)
+	}
+	b, err := topo.Marshal(topopb, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
+	if convertOutput == "" {
+		_, err := cmd.OutOrStdout().Write(b)
+		return err
+	}
+	if err := os.WriteFile(convertOutput, b, 0644); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Use, err)
+	}
 	return nil
 }
@@ -8,19 +8,30 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/h-fam/errdiff"
 	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	bpb "github.com/openconfig/kne/proto/bind"
 	cpb "github.com/openconfig/kne/proto/controller"
 	tpb "github.com/openconfig/kne/proto/topo"
 	"github.com/openconfig/kne/topo"
 	"github.com/openconfig/kne/topo/node"
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	ktest "k8s.io/client-go/testing"
 )
 
 func NewNC(impl *node.Impl) (node.Node, error) {
@@ -59,6 +70,27 @@ func (r *resettable) ResetCfg(ctx context.Context) error {
 	return nil
 }
 
+// ConfigPushStream echoes the config as the device output.
+func (r *resettable) ConfigPushStream(ctx context.Context, cfg io.Reader, w io.Writer) error {
+	return r.ConfigPush(ctx, io.TeeReader(cfg, w))
+}
+
+// certable is a node whose cert generation fails for the config "error".
+type certable struct {
+	*node.Impl
+}
+
+func (c *certable) GenerateSelfSigned(context.Context) error {
+	if c.Proto.GetConfig().GetConfigFile() == "error" {
+		return fmt.Errorf("cert generation failed")
+	}
+	return nil
+}
+
+func NewCertable(impl *node.Impl) (node.Node, error) {
+	return &certable{Impl: impl}, nil
+}
+
 func NewR(impl *node.Impl) (node.Node, error) {
 	return &resettable{&notResettable{&notConfigable{Impl: impl}}}, nil
 }
@@ -214,9 +246,33 @@ func TestReset(t *testing.T) {
 		desc:    "valid topology push with config DNE single device invalid",
 		args:    []string{"reset", fConfigDNE.Name(), "--skip", "--push", "dne"},
 		wantErr: "not found",
+	}, {
+		desc: "valid topology node pattern",
+		args: []string{"reset", fNoConfig.Name(), "--skip=false", "--nodes=resettable*"},
+	}, {
+		desc: "valid topology node list",
+		args: []string{"reset", fNoConfig.Name(), "--skip=false", "--nodes=resettable1,resettable2"},
+	}, {
+		desc:    "valid topology label selects not resettable",
+		args:    []string{"reset", fNoConfig.Name(), "--skip=false", "--label=type=1002"},
+		wantErr: `node "notresettable1" is not a Resetter`,
+	}, {
+		desc: "valid topology label and pattern",
+		args: []string{"reset", fNoConfig.Name(), "--skip=false", "--label=type=1001", "--nodes=*1"},
+	}, {
+		desc:    "valid topology node pattern not found",
+		args:    []string{"reset", fNoConfig.Name(), "--nodes=dne"},
+		wantErr: `node "dne" not found`,
+	}, {
+		desc:    "valid topology no nodes selected",
+		args:    []string{"reset", fNoConfig.Name(), "--label=vendor=CISCO"},
+		wantErr: "no nodes matched",
+	}, {
+		desc:    "device and selector",
+		args:    []string{"reset", fNoConfig.Name(), "resettable1", "--nodes=resettable2"},
+		wantErr: "invalid args",
 	}}
 
-	rCmd := New()
 	origOpts := opts
 	tf, err := tfake.NewSimpleClientset()
 	if err != nil {
@@ -230,11 +286,13 @@ func TestReset(t *testing.T) {
 	defer func() {
 		opts = origOpts
 	}()
-	rCmd.PersistentFlags().String("kubecfg", "", "")
-	buf := bytes.NewBuffer([]byte{})
-	rCmd.SetOut(buf)
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
+			// Use a new command for each test so selector flags do not carry over.
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
 			rCmd.SetArgs(tt.args)
 			err := rCmd.ExecuteContext(context.Background())
 			if s := errdiff.Check(err, tt.wantErr); s != "" {
@@ -382,6 +440,102 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestServiceOutput(t *testing.T) {
+	validProto := &tpb.Topology{}
+	if err := prototext.Unmarshal([]byte(validPbTxt), validProto); err != nil {
+		t.Fatalf("failed to build a valid Topology protobuf for testing: %v", err)
+	}
+	tests := []struct {
+		desc    string
+		args    []string
+		want    string
+		wantErr string
+	}{{
+		desc:    "invalid format",
+		args:    []string{"service", "testdata/valid_topo.pb.txt", "-o", "xml"},
+		wantErr: `invalid output format "xml"`,
+	}, {
+		desc:    "missing node",
+		args:    []string{"service", "testdata/valid_topo.pb.txt", "--nodes", "r3"},
+		wantErr: `node "r3" not found`,
+	}, {
+		desc: "json",
+		args: []string{"service", "testdata/valid_topo.pb.txt", "-o", "json", "--service", "ssh,gnmi"},
+		want: `[
+  {
+    "node": "r1",
+    "name": "ssh",
+    "inside": 1002,
+    "outside": 22,
+    "inside_ip": "1.1.1.2",
+    "outside_ip": "100.100.100.101",
+    "node_port": 22
+  },
+  {
+    "node": "otg",
+    "name": "gnmi",
+    "inside": 50051,
+    "outside": 50051,
+    "inside_ip": "1.1.1.1",
+    "outside_ip": "100.100.100.100",
+    "node_port": 20000
+  }
+]
+`,
+	}, {
+		desc: "yaml",
+		args: []string{"service", "testdata/valid_topo.pb.txt", "-o", "yaml", "--nodes", "otg", "--service", "grpc"},
+		want: `- inside: 40051
+  inside_ip: 1.1.1.1
+  name: grpc
+  node: otg
+  node_port: 20001
+  outside: 40051
+  outside_ip: 100.100.100.100
+`,
+	}, {
+		desc: "env",
+		args: []string{"service", "testdata/valid_topo.pb.txt", "-o", "env", "--label", "type=ARISTA_CEOS"},
+		want: `R1_SSH_INSIDE=1002
+R1_SSH_OUTSIDE=22
+R1_SSH_INSIDE_IP=1.1.1.2
+R1_SSH_OUTSIDE_IP=100.100.100.101
+R1_SSH_NODE_PORT=22
+`,
+	}, {
+		desc: "no matching services",
+		args: []string{"service", "testdata/valid_topo.pb.txt", "-o", "json", "--service", "dne"},
+		want: "[]\n",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			origNewTopologyManager := newTopologyManager
+			newTopologyManager = func(_ *tpb.Topology, _ ...topo.Option) (TopologyManager, error) {
+				return &fakeTopologyManager{topo: proto.Clone(validProto).(*tpb.Topology)}, nil
+			}
+			defer func() {
+				newTopologyManager = origNewTopologyManager
+			}()
+			sCmd := New()
+			sCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			sCmd.SetOut(buf)
+			sCmd.SetErr(io.Discard)
+			sCmd.SetArgs(tt.args)
+			err := sCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("serviceCmd failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if s := cmp.Diff(tt.want, buf.String()); s != "" {
+				t.Errorf("serviceCmd unexpected output (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
 func TestPush(t *testing.T) {
 	confFile, err := os.CreateTemp("", "push")
 	if err != nil {
@@ -406,6 +560,7 @@ func TestPush(t *testing.T) {
 		desc    string
 		args    []string
 		tFile   string
+		want    string
 		wantErr string
 	}{{
 		desc:    "no args",
@@ -430,9 +585,35 @@ func TestPush(t *testing.T) {
 	}, {
 		desc: "valid file",
 		args: []string{"push", fConfig.Name(), "configable", confFile.Name()},
+	}, {
+		desc: "valid file stream",
+		args: []string{"push", fConfig.Name(), "configable", confFile.Name(), "--stream"},
+		want: "some bytes\n",
+	}, {
+		desc: "valid file node pattern",
+		args: []string{"push", fConfig.Name(), confFile.Name(), "--nodes=conf*"},
+	}, {
+		desc: "valid file node pattern stream",
+		args: []string{"push", fConfig.Name(), confFile.Name(), "--nodes=conf*", "--stream"},
+		want: "some bytes\n",
+	}, {
+		desc:    "valid file label selects notconfigable device",
+		args:    []string{"push", fConfig.Name(), confFile.Name(), "--label=type=1004"},
+		wantErr: "does not implement ConfigPusher",
+	}, {
+		desc:    "valid file node pattern invalid device",
+		args:    []string{"push", fConfig.Name(), confFile.Name(), "--nodes=foo"},
+		wantErr: `node "foo" not found`,
+	}, {
+		desc:    "no file node pattern",
+		args:    []string{"push", fConfig.Name(), "filedne", "--nodes=configable"},
+		wantErr: "no such file",
+	}, {
+		desc:    "device and selector",
+		args:    []string{"push", fConfig.Name(), "configable", confFile.Name(), "--nodes=configable"},
+		wantErr: "invalid args",
 	}}
 
-	rCmd := New()
 	origOpts := opts
 	tf, err := tfake.NewSimpleClientset()
 	if err != nil {
@@ -446,11 +627,13 @@ func TestPush(t *testing.T) {
 	defer func() {
 		opts = origOpts
 	}()
-	rCmd.PersistentFlags().String("kubecfg", "", "")
-	buf := bytes.NewBuffer([]byte{})
-	rCmd.SetOut(buf)
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
+			// Use a new command for each test so selector flags do not carry over.
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
 			rCmd.SetArgs(tt.args)
 			err := rCmd.ExecuteContext(context.Background())
 			if s := errdiff.Check(err, tt.wantErr); s != "" {
@@ -459,6 +642,1207 @@ func TestPush(t *testing.T) {
 			if tt.wantErr != "" {
 				return
 			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("pushFn got output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateCerts(t *testing.T) {
+	cert := func() *tpb.CertificateCfg {
+		return &tpb.CertificateCfg{Config: &tpb.CertificateCfg_SelfSigned{SelfSigned: &tpb.SelfSignedCertCfg{CertName: "gnmi"}}}
+	}
+	tInstance := &tpb.Topology{
+		Nodes: []*tpb.Node{{
+			Name:   "r1",
+			Type:   tpb.Node_Type(1010),
+			Config: &tpb.Config{Cert: cert()},
+		}, {
+			Name:   "r2",
+			Type:   tpb.Node_Type(1010),
+			Config: &tpb.Config{Cert: cert(), ConfigFile: "error"},
+		}, {
+			Name: "r3",
+			Type: tpb.Node_Type(1010),
+		}, {
+			Name:   "r4",
+			Type:   tpb.Node_Type(1011),
+			Config: &tpb.Config{Cert: cert()},
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1010), NewCertable)
+	node.Register(tpb.Node_Type(1011), NewNC)
+	tests := []struct {
+		desc    string
+		args    []string
+		want    string
+		wantErr string
+	}{{
+		desc:    "no topology",
+		args:    []string{"rotate-certs"},
+		wantErr: "missing topology",
+	}, {
+		desc:    "all nodes",
+		args:    []string{"rotate-certs", fTopo.Name()},
+		want:    "Rotated cert of node \"r1\"\n",
+		wantErr: `node "r2": cert generation failed`,
+	}, {
+		desc: "selected nodes",
+		args: []string{"rotate-certs", fTopo.Name(), "--nodes=r1,r3,r4"},
+		want: "Rotated cert of node \"r1\"\n",
+	}, {
+		desc:    "unknown node",
+		args:    []string{"rotate-certs", fTopo.Name(), "--nodes=r5"},
+		wantErr: `node "r5" not found`,
+	}}
+
+	origOpts := opts
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset")
+	}
+	opts = []topo.Option{
+		topo.WithClusterConfig(&rest.Config{}),
+		topo.WithKubeClient(kfake.NewSimpleClientset()),
+		topo.WithTopoClient(tf),
+	}
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SetArgs(tt.args)
+			err := rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("rotateCertsFn failed: %s", s)
+			}
+			if tt.want == "" {
+				return
+			}
+			if got := buf.String(); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("rotateCertsFn got output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tInstance := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name: "r1",
+			Type: tpb.Node_Type(1012),
+		}, {
+			Name: "r2",
+			Type: tpb.Node_Type(1012),
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1012), NewNC)
+	pod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: name, RestartCount: 1}},
+			},
+		}
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-r1", Namespace: "test"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "ssh", Port: 22, TargetPort: intstr.FromInt(22)}},
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "192.168.18.100"}}},
+		},
+	}
+	tests := []struct {
+		desc    string
+		args    []string
+		objs    []runtime.Object
+		want    string
+		wantErr string
+	}{{
+		desc:    "no topology",
+		args:    []string{"status"},
+		wantErr: "missing topology",
+	}, {
+		desc:    "invalid format",
+		args:    []string{"status", fTopo.Name(), "-o", "yaml"},
+		wantErr: "invalid output format",
+	}, {
+		desc: "healthy",
+		args: []string{"status", fTopo.Name()},
+		objs: []runtime.Object{pod("r1", corev1.ConditionTrue), pod("r2", corev1.ConditionTrue), service},
+		want: `NODE  PHASE  RESTARTS  CONFIG PUSH  SERVICES               HEALTHY  REASON
+r1    READY  1         -            ssh=192.168.18.100:22  true     
+r2    READY  1         -            -                      true     
+`,
+	}, {
+		desc:    "unhealthy",
+		args:    []string{"status", fTopo.Name()},
+		objs:    []runtime.Object{pod("r1", corev1.ConditionTrue), pod("r2", corev1.ConditionFalse), service},
+		wantErr: `topology "test" is not healthy, unhealthy nodes: r2`,
+		want: `NODE  PHASE    RESTARTS  CONFIG PUSH  SERVICES               HEALTHY  REASON
+r1    READY    1         -            ssh=192.168.18.100:22  true     
+r2    BOOTING  1         -            -                      false    
+`,
+	}, {
+		desc: "json",
+		args: []string{"status", fTopo.Name(), "-o", "json"},
+		objs: []runtime.Object{pod("r1", corev1.ConditionTrue), pod("r2", corev1.ConditionTrue)},
+		want: `{
+  "name": "test",
+  "healthy": true,
+  "nodes": [
+    {
+      "node": "r1",
+      "phase": "READY",
+      "restarts": 1,
+      "healthy": true
+    },
+    {
+      "node": "r2",
+      "phase": "READY",
+      "restarts": 1,
+      "healthy": true
+    }
+  ]
+}
+`,
+	}}
+
+	origOpts := opts
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset")
+			}
+			opts = []topo.Option{
+				topo.WithClusterConfig(&rest.Config{}),
+				topo.WithKubeClient(kfake.NewSimpleClientset(tt.objs...)),
+				topo.WithTopoClient(tf),
+			}
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SilenceUsage = true
+			rCmd.SilenceErrors = true
+			rCmd.SetArgs(tt.args)
+			err = rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("statusFn failed: %s", s)
+			}
+			if s := cmp.Diff(tt.want, buf.String()); s != "" {
+				t.Errorf("statusFn unexpected output (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestConsole(t *testing.T) {
+	tInstance := &tpb.Topology{
+		Nodes: []*tpb.Node{{
+			Name: "r1",
+			Type: tpb.Node_Type(1005),
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1005), NewNC)
+	tests := []struct {
+		desc    string
+		args    []string
+		wantErr string
+	}{{
+		desc:    "no args",
+		args:    []string{"console"},
+		wantErr: "missing args",
+	}, {
+		desc:    "missing device",
+		args:    []string{"console", fTopo.Name()},
+		wantErr: "missing args",
+	}, {
+		desc:    "no file",
+		args:    []string{"console", "filedne", "r1"},
+		wantErr: "no such file",
+	}, {
+		desc:    "device not found",
+		args:    []string{"console", fTopo.Name(), "dne"},
+		wantErr: `node "dne" not found`,
+	}, {
+		desc:    "no entry command",
+		args:    []string{"console", fTopo.Name(), "r1"},
+		wantErr: `node "r1" has no entry command`,
+	}}
+	origOpts := opts
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset")
+	}
+	opts = []topo.Option{
+		topo.WithClusterConfig(&rest.Config{}),
+		topo.WithKubeClient(kfake.NewSimpleClientset()),
+		topo.WithTopoClient(tf),
+	}
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SetIn(bytes.NewBuffer([]byte{}))
+			rCmd.SetArgs(tt.args)
+			err := rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("consoleFn failed: %s", s)
+			}
+		})
+	}
+}
+
+func TestLogs(t *testing.T) {
+	tInstance := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name: "r1",
+			Type: tpb.Node_Type(1006),
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1006), NewNC)
+	tests := []struct {
+		desc    string
+		args    []string
+		want    string
+		wantErr string
+	}{{
+		desc:    "no args",
+		args:    []string{"logs"},
+		wantErr: "invalid args",
+	}, {
+		desc:    "too many args",
+		args:    []string{"logs", fTopo.Name(), "r1", "r2"},
+		wantErr: "invalid args",
+	}, {
+		desc:    "no file",
+		args:    []string{"logs", "filedne"},
+		wantErr: "no such file",
+	}, {
+		desc:    "device not found",
+		args:    []string{"logs", fTopo.Name(), "dne"},
+		wantErr: `node "dne" not found`,
+	}, {
+		desc: "device",
+		args: []string{"logs", fTopo.Name(), "r1"},
+		want: "[r1] fake logs\n",
+	}, {
+		desc: "all devices follow",
+		args: []string{"logs", fTopo.Name(), "-f"},
+		want: "[r1] fake logs\n",
+	}}
+	origOpts := opts
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset")
+	}
+	opts = []topo.Option{
+		topo.WithClusterConfig(&rest.Config{}),
+		topo.WithKubeClient(kfake.NewSimpleClientset(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "r1"}},
+			},
+		})),
+		topo.WithTopoClient(tf),
+	}
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SetArgs(tt.args)
+			err := rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("logsFn failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("logsFn unexpected output: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	tInstance := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name: "r1",
+			Type: tpb.Node_Type(1007),
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1007), NewNC)
+	out := filepath.Join(t.TempDir(), "bundle.tgz")
+	tests := []struct {
+		desc    string
+		args    []string
+		wantErr string
+	}{{
+		desc:    "no args",
+		args:    []string{"collect"},
+		wantErr: "missing topology",
+	}, {
+		desc:    "no file",
+		args:    []string{"collect", "filedne"},
+		wantErr: "no such file",
+	}, {
+		desc:    "invalid output",
+		args:    []string{"collect", fTopo.Name(), "-o", filepath.Join(t.TempDir(), "dne", "bundle.tgz")},
+		wantErr: "no such file",
+	}, {
+		desc: "valid topology",
+		args: []string{"collect", fTopo.Name(), "-o", out},
+	}}
+	origOpts := opts
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset")
+	}
+	opts = []topo.Option{
+		topo.WithClusterConfig(&rest.Config{}),
+		topo.WithKubeClient(kfake.NewSimpleClientset()),
+		topo.WithTopoClient(tf),
+	}
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SetArgs(tt.args)
+			err := rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("collectFn failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if _, err := os.Stat(out); err != nil {
+				t.Errorf("collectFn did not write bundle: %v", err)
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	tests := []struct {
+		desc    string
+		args    []string
+		wantErr string
+	}{{
+		desc:    "no args",
+		args:    []string{"capture"},
+		wantErr: "missing args",
+	}, {
+		desc:    "missing interface",
+		args:    []string{"capture", "topology", "r1"},
+		wantErr: "must be <device>:<interface>",
+	}, {
+		desc:    "empty interface",
+		args:    []string{"capture", "topology", "r1:"},
+		wantErr: "must be <device>:<interface>",
+	}, {
+		desc:    "no file",
+		args:    []string{"capture", "filedne", "r1:eth1"},
+		wantErr: "no such file",
+	}, {
+		desc:    "stream and output",
+		args:    []string{"capture", "filedne", "r1:eth1", "--stream", "tcp::5555", "-o", "r1.pcap"},
+		wantErr: "mutually exclusive",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SetArgs(tt.args)
+			err := rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("captureFn failed: %s", s)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	nodes := []*tpb.Node{{
+		Name: "r1",
+		Type: tpb.Node_Type(1008),
+	}, {
+		Name: "r2",
+		Type: tpb.Node_Type(1008),
+	}}
+	noLinks, closer := writeTopology(t, &tpb.Topology{Name: "test", Nodes: nodes})
+	defer closer()
+	node.Register(tpb.Node_Type(1008), NewNC)
+	tests := []struct {
+		desc    string
+		args    []string
+		wantErr string
+	}{{
+		desc:    "no args",
+		args:    []string{"verify"},
+		wantErr: "missing topology",
+	}, {
+		desc:    "no file",
+		args:    []string{"verify", "filedne"},
+		wantErr: "no such file",
+	}, {
+		desc:    "no links",
+		args:    []string{"verify", noLinks.Name()},
+		wantErr: "has no links",
+	}}
+	origOpts := opts
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset")
+	}
+	opts = []topo.Option{
+		topo.WithClusterConfig(&rest.Config{}),
+		topo.WithKubeClient(kfake.NewSimpleClientset()),
+		topo.WithTopoClient(tf),
+	}
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SetArgs(tt.args)
+			err := rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("verifyFn failed: %s", s)
+			}
+		})
+	}
+}
+
+func TestChaos(t *testing.T) {
+	tInstance := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name: "r1",
+			Type: tpb.Node_Type(1009),
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1009), NewNC)
+	tests := []struct {
+		desc    string
+		args    []string
+		wantPod bool
+		wantErr string
+	}{{
+		desc:    "missing device",
+		args:    []string{"chaos", "kill", fTopo.Name()},
+		wantErr: "missing args",
+	}, {
+		desc:    "no file",
+		args:    []string{"chaos", "restart", "filedne", "r1"},
+		wantErr: "no such file",
+	}, {
+		desc:    "device not found",
+		args:    []string{"chaos", "kill", fTopo.Name(), "dne"},
+		wantErr: `node "dne" not found`,
+	}, {
+		desc: "kill",
+		args: []string{"chaos", "kill", fTopo.Name(), "r1"},
+	}, {
+		desc:    "restart",
+		args:    []string{"chaos", "restart", fTopo.Name(), "r1"},
+		wantPod: true,
+	}}
+	origOpts := opts
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset")
+			}
+			kClient := kfake.NewSimpleClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "r1"}},
+				},
+			})
+			opts = []topo.Option{
+				topo.WithClusterConfig(&rest.Config{}),
+				topo.WithKubeClient(kClient),
+				topo.WithTopoClient(tf),
+			}
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			rCmd.SetOut(bytes.NewBuffer([]byte{}))
+			rCmd.SetArgs(tt.args)
+			err = rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("chaosFn failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			_, err = kClient.CoreV1().Pods("test").Get(context.Background(), "r1", metav1.GetOptions{})
+			if gotPod := err == nil; gotPod != tt.wantPod {
+				t.Errorf("chaosFn pod exists: %v, want %v", gotPod, tt.wantPod)
+			}
+		})
+	}
+}
+
+func TestBind(t *testing.T) {
+	validProto := &tpb.Topology{}
+	if err := prototext.Unmarshal([]byte(validPbTxt), validProto); err != nil {
+		t.Fatalf("failed to build a valid Topology protobuf for testing: %v", err)
+	}
+	want := &bpb.Binding{
+		Duts: []*bpb.BoundDevice{{
+			Id:    "dut",
+			Name:  "r1",
+			Ssh:   &bpb.Options{Target: "100.100.100.101:22"},
+			Ports: []*bpb.BoundPort{{Id: "port1", Name: "eth9"}},
+		}},
+		Ates: []*bpb.BoundDevice{{
+			Id:    "ate",
+			Name:  "otg",
+			Gnmi:  &bpb.Options{Target: "100.100.100.100:50051"},
+			Ports: []*bpb.BoundPort{{Id: "port1", Name: "eth1"}},
+		}},
+		Options: &bpb.Options{Username: "admin"},
+	}
+	output := filepath.Join(t.TempDir(), "binding.textproto")
+	invalid := filepath.Join(t.TempDir(), "invalid.textproto")
+	if err := os.WriteFile(invalid, []byte("duts: {"), 0644); err != nil {
+		t.Fatalf("cannot write testbed: %v", err)
+	}
+	tests := []struct {
+		desc        string
+		args        []string
+		topoManager *fakeTopologyManager
+		output      string
+		wantErr     string
+	}{{
+		desc:    "missing testbed",
+		args:    []string{"bind", "testdata/valid_topo.pb.txt"},
+		wantErr: "missing args",
+	}, {
+		desc:    "invalid testbed",
+		args:    []string{"bind", "testdata/valid_topo.pb.txt", invalid},
+		wantErr: "invalid testbed",
+	}, {
+		desc:    "no testbed file",
+		args:    []string{"bind", "testdata/valid_topo.pb.txt", "filedne"},
+		wantErr: "no such file",
+	}, {
+		desc:        "fail to get topology services",
+		args:        []string{"bind", "testdata/valid_topo.pb.txt", "testdata/testbed.textproto"},
+		topoManager: &fakeTopologyManager{showErr: fmt.Errorf("some error")},
+		wantErr:     "some error",
+	}, {
+		desc:        "stdout",
+		args:        []string{"bind", "testdata/valid_topo.pb.txt", "testdata/testbed.textproto", "--username", "admin"},
+		topoManager: &fakeTopologyManager{topo: validProto},
+	}, {
+		desc:        "file",
+		args:        []string{"bind", "testdata/valid_topo.pb.txt", "testdata/testbed.textproto", "--username", "admin", "-o", output},
+		topoManager: &fakeTopologyManager{topo: validProto},
+		output:      output,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			origNewTopologyManager := newTopologyManager
+			newTopologyManager = func(_ *tpb.Topology, _ ...topo.Option) (TopologyManager, error) {
+				return tt.topoManager, nil
+			}
+			defer func() {
+				newTopologyManager = origNewTopologyManager
+			}()
+			bCmd := New()
+			bCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			bCmd.SetOut(buf)
+			bCmd.SetArgs(tt.args)
+			err := bCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("bindCmd failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			out := buf.Bytes()
+			if tt.output != "" {
+				if out, err = os.ReadFile(tt.output); err != nil {
+					t.Fatalf("cannot read binding: %v", err)
+				}
+			}
+			got := &bpb.Binding{}
+			if err := prototext.Unmarshal(out, got); err != nil {
+				t.Fatalf("invalid binding %q: %v", out, err)
+			}
+			if s := cmp.Diff(want, got, protocmp.Transform()); s != "" {
+				t.Errorf("bindCmd unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	unsatisfied := filepath.Join(t.TempDir(), "testbed.textproto")
+	if err := os.WriteFile(unsatisfied, []byte(`duts: { id: "dut" vendor: NOKIA }`), 0644); err != nil {
+		t.Fatalf("cannot write testbed: %v", err)
+	}
+	tests := []struct {
+		desc    string
+		args    []string
+		want    string
+		wantErr string
+	}{{
+		desc:    "missing testbed",
+		args:    []string{"check", "testdata/valid_topo.pb.txt"},
+		wantErr: "missing args",
+	}, {
+		desc:    "no topology file",
+		args:    []string{"check", "filedne", "testdata/testbed.textproto"},
+		wantErr: "no such file",
+	}, {
+		desc: "satisfied",
+		args: []string{"check", "testdata/valid_topo.pb.txt", "testdata/testbed.textproto"},
+		want: "Topology \"test-data-topology\" can satisfy the testbed\n",
+	}, {
+		desc:    "unsatisfied",
+		args:    []string{"check", "testdata/valid_topo.pb.txt", unsatisfied},
+		want:    "FAIL device dut: no node matches: r1 is not of vendor NOKIA, otg is a Keysight node\n",
+		wantErr: "1 requirements failed",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cCmd := New()
+			buf := bytes.NewBuffer([]byte{})
+			cCmd.SetOut(buf)
+			cCmd.SetErr(io.Discard)
+			cCmd.SetArgs(tt.args)
+			err := cCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("checkCmd failed: %s", s)
+			}
+			if tt.want == "" {
+				return
+			}
+			if got := buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("checkCmd unexpected output: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	want, err := topo.Load("testdata/valid_topo.pb.txt")
+	if err != nil {
+		t.Fatalf("cannot load topology: %v", err)
+	}
+	dir := t.TempDir()
+	tests := []struct {
+		desc string
+		args []string
+		// output is the file written, stdout if empty.
+		output     string
+		wantFormat string
+		wantErr    string
+	}{{
+		desc:    "no args",
+		args:    []string{"convert"},
+		wantErr: "missing topology",
+	}, {
+		desc:       "json to stdout",
+		args:       []string{"convert", "testdata/valid_topo.pb.txt", "--to", "json"},
+		wantFormat: topo.FormatJSON,
+	}, {
+		desc:       "yaml by extension",
+		args:       []string{"convert", "testdata/valid_topo.pb.txt", "-o", filepath.Join(dir, "topo.yaml")},
+		output:     filepath.Join(dir, "topo.yaml"),
+		wantFormat: topo.FormatYAML,
+	}, {
+		desc:       "text by default",
+		args:       []string{"convert", "testdata/valid_topo.pb.txt", "-o", filepath.Join(dir, "topo.out")},
+		output:     filepath.Join(dir, "topo.out"),
+		wantFormat: topo.FormatText,
+	}, {
+		desc:    "topology format",
+		args:    []string{"convert", "testdata/valid_topo.pb.txt", "--topology-format", "json"},
+		wantErr: "could not parse json",
+	}, {
+		desc:    "invalid format",
+		args:    []string{"convert", "testdata/valid_topo.pb.txt", "--to", "xml"},
+		wantErr: `invalid topology format "xml"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cCmd := New()
+			cCmd.PersistentFlags().String("topology-format", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			cCmd.SetOut(buf)
+			cCmd.SetErr(io.Discard)
+			cCmd.SetArgs(tt.args)
+			err := cCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("convertCmd failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			b := buf.Bytes()
+			if tt.output != "" {
+				if b, err = os.ReadFile(tt.output); err != nil {
+					t.Fatalf("cannot read converted topology: %v", err)
+				}
+			}
+			got, err := topo.Parse(b, tt.wantFormat)
+			if err != nil {
+				t.Fatalf("cannot parse converted topology: %v\n%s", err, b)
+			}
+			if s := cmp.Diff(want, got, protocmp.Transform()); s != "" {
+				t.Errorf("convertCmd unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	want, err := topo.Load("testdata/valid_topo.pb.txt")
+	if err != nil {
+		t.Fatalf("cannot load topology: %v", err)
+	}
+	if _, err := topo.Migrate(want); err != nil {
+		t.Fatalf("cannot migrate topology: %v", err)
+	}
+	dir := t.TempDir()
+	newer := filepath.Join(dir, "newer.pb.txt")
+	if err := os.WriteFile(newer, []byte(fmt.Sprintf("name: \"t\"\nversion: %d\n", topo.CurrentVersion+1)), 0644); err != nil {
+		t.Fatalf("cannot write topology: %v", err)
+	}
+	tests := []struct {
+		desc string
+		args []string
+		// output is the file written, stdout if empty.
+		output     string
+		wantFormat string
+		wantErr    string
+	}{{
+		desc:    "no args",
+		args:    []string{"migrate"},
+		wantErr: "missing topology",
+	}, {
+		desc:       "stdout in topology format",
+		args:       []string{"migrate", "testdata/valid_topo.pb.txt"},
+		wantFormat: topo.FormatText,
+	}, {
+		desc:       "json by extension",
+		args:       []string{"migrate", "testdata/valid_topo.pb.txt", "-o", filepath.Join(dir, "topo.json")},
+		output:     filepath.Join(dir, "topo.json"),
+		wantFormat: topo.FormatJSON,
+	}, {
+		desc:    "newer version",
+		args:    []string{"migrate", newer},
+		wantErr: "is newer than the supported version",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cCmd := New()
+			cCmd.PersistentFlags().String("topology-format", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			cCmd.SetOut(buf)
+			cCmd.SetErr(io.Discard)
+			cCmd.SetArgs(tt.args)
+			err := cCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("migrateCmd failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			b := buf.Bytes()
+			if tt.output != "" {
+				if b, err = os.ReadFile(tt.output); err != nil {
+					t.Fatalf("cannot read migrated topology: %v", err)
+				}
+			}
+			got, err := topo.Parse(b, tt.wantFormat)
+			if err != nil {
+				t.Fatalf("cannot parse migrated topology: %v\n%s", err, b)
+			}
+			if s := cmp.Diff(want, got, protocmp.Transform()); s != "" {
+				t.Errorf("migrateCmd unexpected diff (-want +got):\n%s", s)
+			}
+			if got.GetVersion() != topo.CurrentVersion {
+				t.Errorf("migrateCmd got version %d, want %d", got.GetVersion(), topo.CurrentVersion)
+			}
+		})
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	topoFile := filepath.Join(t.TempDir(), "topo.pb.txt")
+	if err := os.WriteFile(topoFile, []byte(`
+name: "t"
+nodes: { name: "r1" vendor: CISCO model: "8201" }
+nodes: { name: "r2" vendor: HOST }
+links: { a_node: "r1" a_int: "eth10" z_node: "r2" z_int: "eth1" }
+links: { a_node: "r1" a_int: "eth2" z_node: "r2" z_int: "eth2" }
+`), 0644); err != nil {
+		t.Fatalf("cannot write topology: %v", err)
+	}
+	tests := []struct {
+		desc    string
+		args    []string
+		want    string
+		wantErr string
+	}{{
+		desc:    "missing device",
+		args:    []string{"interfaces", topoFile},
+		wantErr: "missing args",
+	}, {
+		desc:    "no topology file",
+		args:    []string{"interfaces", "filedne", "r1"},
+		wantErr: "no such file",
+	}, {
+		desc:    "missing node",
+		args:    []string{"interfaces", topoFile, "r3"},
+		wantErr: `node "r3" not found`,
+	}, {
+		desc: "vendor names",
+		args: []string{"interfaces", topoFile, "r1"},
+		want: "INTERFACE  NAME\neth2       FourHundredGigE0/0/0/1\neth10      FourHundredGigE0/0/0/9\n",
+	}, {
+		desc: "topology names",
+		args: []string{"interfaces", topoFile, "r2"},
+		want: "INTERFACE  NAME\neth1       eth1\neth2       eth2\n",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cCmd := New()
+			buf := bytes.NewBuffer([]byte{})
+			cCmd.SetOut(buf)
+			cCmd.SetErr(io.Discard)
+			cCmd.SetArgs(tt.args)
+			err := cCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("interfacesCmd failed: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("interfacesCmd unexpected output: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		desc    string
+		args    []string
+		wantErr string
+	}{{
+		desc:    "no args",
+		args:    []string{"watch"},
+		wantErr: "missing topology",
+	}, {
+		desc:    "invalid format",
+		args:    []string{"watch", "testdata/valid_topo.pb.txt", "-o", "yaml"},
+		wantErr: `invalid output format "yaml"`,
+	}, {
+		desc:    "no topology file",
+		args:    []string{"watch", "filedne"},
+		wantErr: "no such file",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cCmd := New()
+			cCmd.PersistentFlags().String("kubecfg", "", "")
+			cCmd.SetOut(io.Discard)
+			cCmd.SetErr(io.Discard)
+			cCmd.SetArgs(tt.args)
+			err := cCmd.ExecuteContext(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("watchCmd failed: %s", s)
+			}
+		})
+	}
+}
+
+func TestPrepull(t *testing.T) {
+	tInstance := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name:   "r1",
+			Type:   tpb.Node_Type(1013),
+			Config: &tpb.Config{Image: "xrd:7.8", InitWait: &tpb.InitWait{Disabled: true}},
+		}, {
+			Name:   "r2",
+			Type:   tpb.Node_Type(1013),
+			Config: &tpb.Config{Image: "private:1", InitWait: &tpb.InitWait{Disabled: true}},
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1013), NewNC)
+	clusterNode := func(name string, images ...string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				Images:     []corev1.ContainerImage{{Names: images}},
+			},
+		}
+	}
+	tests := []struct {
+		desc    string
+		args    []string
+		objs    []runtime.Object
+		want    string
+		wantErr string
+	}{{
+		desc:    "no topology",
+		args:    []string{"prepull"},
+		wantErr: "missing topology",
+	}, {
+		desc: "pulled",
+		args: []string{"prepull", fTopo.Name(), "--concurrency", "1"},
+		objs: []runtime.Object{clusterNode("w1", "docker.io/library/private:1"), clusterNode("w2")},
+		want: `Image "private:1" already on cluster node "w1"
+Pulled image "xrd:7.8" onto cluster node "w1" in 0s
+Pulled image "xrd:7.8" onto cluster node "w2" in 0s
+`,
+		wantErr: `failed to pull image "private:1" onto cluster node "w2": InvalidImageName: invalid reference format`,
+	}}
+
+	origOpts := opts
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset")
+			}
+			kClient := kfake.NewSimpleClientset(tt.objs...)
+			// The fake clientset has no job controller creating the pods
+			// of the jobs pulling the images.
+			kClient.PrependReactor("create", "jobs", func(action ktest.Action) (bool, runtime.Object, error) {
+				j := action.(ktest.CreateAction).GetObject().(*batchv1.Job)
+				j.UID = types.UID("uid-" + j.Name)
+				cs := corev1.ContainerStatus{Name: "pull", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}}
+				if j.Spec.Template.Spec.Containers[0].Image == "private:1" {
+					cs.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "InvalidImageName", Message: "invalid reference format"}}
+				}
+				p := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: j.Name + "-x", Labels: map[string]string{"job-name": j.Name, "controller-uid": string(j.UID)}},
+					Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{cs}},
+				}
+				if err := kClient.Tracker().Create(corev1.SchemeGroupVersion.WithResource("pods"), p, action.GetNamespace()); err != nil {
+					return true, nil, err
+				}
+				return false, nil, nil
+			})
+			opts = []topo.Option{
+				topo.WithClusterConfig(&rest.Config{}),
+				topo.WithKubeClient(kClient),
+				topo.WithTopoClient(tf),
+			}
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SilenceUsage = true
+			rCmd.SilenceErrors = true
+			rCmd.SetArgs(tt.args)
+			err = rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("prepullFn failed: %s", s)
+			}
+			if s := cmp.Diff(tt.want, buf.String()); s != "" {
+				t.Errorf("prepullFn unexpected output (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestResources(t *testing.T) {
+	tInstance := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name:        "r1",
+			Type:        tpb.Node_Type(1014),
+			Vendor:      tpb.Vendor_CISCO,
+			Config:      &tpb.Config{Image: "xrd:7.8", InitWait: &tpb.InitWait{Disabled: true}},
+			Constraints: map[string]string{"cpu": "4", "memory": "12Gi"},
+		}, {
+			Name:        "r2",
+			Type:        tpb.Node_Type(1014),
+			Vendor:      tpb.Vendor_CISCO,
+			Config:      &tpb.Config{Image: "xrd:7.8", InitWait: &tpb.InitWait{Disabled: true}},
+			Constraints: map[string]string{"cpu": "4", "memory": "12Gi"},
+		}},
+	}
+	fTopo, closer := writeTopology(t, tInstance)
+	defer closer()
+	node.Register(tpb.Node_Type(1014), NewNC)
+	worker := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "w1"},
+		Status: corev1.NodeStatus{
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			Allocatable: corev1.ResourceList{"cpu": resource.MustParse("6"), "memory": resource.MustParse("32Gi")},
+			Images:      []corev1.ContainerImage{{Names: []string{"docker.io/library/xrd:7.8"}, SizeBytes: 3 << 29}},
+		},
+	}
+	tests := []struct {
+		desc    string
+		args    []string
+		want    string
+		wantErr string
+	}{{
+		desc:    "no topology",
+		args:    []string{"resources"},
+		wantErr: "missing topology",
+	}, {
+		desc:    "invalid format",
+		args:    []string{"resources", fTopo.Name(), "-o", "yaml"},
+		wantErr: `invalid output format "yaml"`,
+	}, {
+		desc: "text",
+		args: []string{"resources", fTopo.Name()},
+		want: `Topology "test": 2 nodes, requests cpu 8, memory 24Gi
+
+VENDOR  NODES  REQUESTS
+CISCO   2      cpu 8, memory 24Gi
+
+IMAGE    NODES  SIZE
+xrd:7.8  2      -
+`,
+	}, {
+		desc: "cluster",
+		args: []string{"resources", fTopo.Name(), "--cluster"},
+		want: `Topology "test": 2 nodes, requests cpu 8, memory 24Gi
+
+VENDOR  NODES  REQUESTS
+CISCO   2      cpu 8, memory 24Gi
+
+IMAGE    NODES  SIZE
+xrd:7.8  2      1.5GiB
+
+Cluster: 1 schedulable nodes, free cpu 6, memory 32Gi
+Nodes not fitting into the cluster: r2
+`,
+		wantErr: `1 of 2 nodes of topology "test" do not fit into the cluster: r2`,
+	}, {
+		desc: "json",
+		args: []string{"resources", fTopo.Name(), "-o", "json"},
+		want: `{
+  "name": "test",
+  "nodes": 2,
+  "requests": {
+    "cpu": "8",
+    "memory": "24Gi"
+  },
+  "vendors": [
+    {
+      "vendor": "CISCO",
+      "nodes": 2,
+      "requests": {
+        "cpu": "8",
+        "memory": "24Gi"
+      }
+    }
+  ],
+  "images": [
+    {
+      "image": "xrd:7.8",
+      "nodes": 2
+    }
+  ]
+}
+`,
+	}}
+
+	origOpts := opts
+	defer func() {
+		opts = origOpts
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset")
+			}
+			opts = []topo.Option{
+				topo.WithClusterConfig(&rest.Config{}),
+				topo.WithKubeClient(kfake.NewSimpleClientset(worker)),
+				topo.WithTopoClient(tf),
+			}
+			rCmd := New()
+			rCmd.PersistentFlags().String("kubecfg", "", "")
+			buf := bytes.NewBuffer([]byte{})
+			rCmd.SetOut(buf)
+			rCmd.SilenceUsage = true
+			rCmd.SilenceErrors = true
+			rCmd.SetArgs(tt.args)
+			err = rCmd.ExecuteContext(context.Background())
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("resourcesFn failed: %s", s)
+			}
+			if s := cmp.Diff(tt.want, buf.String()); s != "" {
+				t.Errorf("resourcesFn unexpected output (-want +got):\n%s", s)
+			}
 		})
 	}
 }
@@ -0,0 +1,84 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The operator creates the topologies of KneTopology resources in the cluster
+// it runs in and deletes them along with the resources.
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"time"
+
+	log "github.com/golang/glog"
+	knev1alpha1 "github.com/openconfig/kne/api/kne/types/v1alpha1"
+	"github.com/openconfig/kne/topo/metrics"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var (
+	// Flags.
+	kubecfg     = flag.String("kubecfg", "", "kubeconfig file, the in-cluster config is used if empty")
+	resync      = flag.Duration("resync", 10*time.Minute, "Interval of resyncs of the KneTopology resources")
+	metricsAddr = flag.String("metrics_addr", "", "Address to serve Prometheus metrics on, e.g. :9090 (disabled if empty)")
+)
+
+// serveMetrics serves the Prometheus metrics of topology operations on addr.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	log.Infof("Serving metrics at %s/metrics", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("failed to serve metrics: %v", err)
+	}
+}
+
+func main() {
+	flag.Parse()
+	rCfg, err := rest.InClusterConfig()
+	if *kubecfg != "" {
+		rCfg, err = clientcmd.BuildConfigFromFlags("", *kubecfg)
+	}
+	if err != nil {
+		log.Fatalf("failed to get cluster config: %v", err)
+	}
+	dClient, err := dynamic.NewForConfig(rCfg)
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+	if *metricsAddr != "" {
+		go serveMetrics(*metricsAddr)
+	}
+	ctx := context.Background()
+	q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "knetopologies")
+	defer q.ShutDown()
+	f := dynamicinformer.NewDynamicSharedInformerFactory(dClient, *resync)
+	informer := f.ForResource(knev1alpha1.GVR()).Informer()
+	informer.AddEventHandler(enqueue(q))
+	stop := make(chan struct{})
+	defer close(stop)
+	f.Start(stop)
+	if !cache.WaitForCacheSync(stop, informer.HasSynced) {
+		log.Fatalf("failed to sync KneTopology resources")
+	}
+	r := &reconciler{client: dClient.Resource(knev1alpha1.GVR()), rCfg: rCfg}
+	log.Infof("Operator reconciling KneTopology resources")
+	r.run(ctx, q)
+}
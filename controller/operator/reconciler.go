@@ -0,0 +1,243 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/golang/glog"
+	knev1alpha1 "github.com/openconfig/kne/api/kne/types/v1alpha1"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo"
+	"google.golang.org/protobuf/encoding/prototext"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// topologyManager creates and deletes a topology.
+type topologyManager interface {
+	Create(ctx context.Context, timeout time.Duration) error
+	Delete(ctx context.Context) error
+}
+
+// newManager returns the manager of topology t, stubbed for testing.
+var newManager = func(t *tpb.Topology, rCfg *rest.Config) (topologyManager, error) {
+	return topo.New(t, topo.WithClusterConfig(rCfg))
+}
+
+// reconciler creates the topologies of KneTopology resources and deletes them
+// along with the resources.
+type reconciler struct {
+	client dynamic.ResourceInterface
+	rCfg   *rest.Config
+}
+
+// enqueue returns an event handler adding the names of the changed resources
+// to q.
+func enqueue(q workqueue.Interface) cache.ResourceEventHandler {
+	add := func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			log.Errorf("Failed to get key of %v: %v", obj, err)
+			return
+		}
+		q.Add(key)
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    add,
+		UpdateFunc: func(_, obj interface{}) { add(obj) },
+		DeleteFunc: add,
+	}
+}
+
+// run reconciles the resources added to q until q is shut down. Failed
+// reconciles are retried with the rate limit of q.
+func (r *reconciler) run(ctx context.Context, q workqueue.RateLimitingInterface) {
+	for {
+		key, shutdown := q.Get()
+		if shutdown {
+			return
+		}
+		name := key.(string)
+		if err := r.reconcile(ctx, name); err != nil {
+			log.Errorf("Failed to reconcile topology %q, retrying: %v", name, err)
+			q.AddRateLimited(key)
+		} else {
+			q.Forget(key)
+		}
+		q.Done(key)
+	}
+}
+
+// reconcile creates or deletes the topology of the KneTopology name. The
+// topology is created once per generation of the resource, a change of the
+// spec deletes the topology and creates it again.
+func (r *reconciler) reconcile(ctx context.Context, name string) error {
+	u, err := r.client.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	kt := &knev1alpha1.KneTopology{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), kt); err != nil {
+		return fmt.Errorf("failed to type assert return to KneTopology: %w", err)
+	}
+	if kt.DeletionTimestamp != nil {
+		if !hasFinalizer(kt) {
+			return nil
+		}
+		return r.delete(ctx, kt)
+	}
+	if !hasFinalizer(kt) {
+		kt.Finalizers = append(kt.Finalizers, knev1alpha1.Finalizer)
+		if kt, err = r.update(ctx, kt); err != nil {
+			return err
+		}
+	}
+	if kt.Status.ObservedGeneration == kt.Generation && (kt.Status.Phase == knev1alpha1.PhaseReady || kt.Status.Phase == knev1alpha1.PhaseFailed) {
+		return nil
+	}
+	t, timeout, err := topologySpec(kt)
+	if err != nil {
+		return r.setStatus(ctx, kt, knev1alpha1.PhaseFailed, err)
+	}
+	m, err := newManager(t, r.rCfg)
+	if err != nil {
+		return r.setStatus(ctx, kt, knev1alpha1.PhaseFailed, err)
+	}
+	// The topology is deleted if its spec changed or its creation was
+	// interrupted, e.g. by a restart of the operator.
+	if kt.Status.ObservedGeneration != 0 && (kt.Status.ObservedGeneration != kt.Generation || kt.Status.Phase == knev1alpha1.PhaseCreating) {
+		log.Infof("Deleting topology %q before creating it again", name)
+		if err := m.Delete(ctx); err != nil {
+			log.Warningf("Failed to delete topology %q: %v", name, err)
+		}
+	}
+	if err := r.setStatus(ctx, kt, knev1alpha1.PhaseCreating, nil); err != nil {
+		return err
+	}
+	log.Infof("Creating topology %q", name)
+	if err := m.Create(ctx, timeout); err != nil {
+		log.Errorf("Failed to create topology %q: %v", name, err)
+		return r.setStatus(ctx, kt, knev1alpha1.PhaseFailed, err)
+	}
+	log.Infof("Topology %q created", name)
+	return r.setStatus(ctx, kt, knev1alpha1.PhaseReady, nil)
+}
+
+// delete deletes the topology of kt and removes the finalizer of kt.
+func (r *reconciler) delete(ctx context.Context, kt *knev1alpha1.KneTopology) error {
+	if err := r.setStatus(ctx, kt, knev1alpha1.PhaseDeleting, nil); err != nil {
+		return err
+	}
+	if t, _, err := topologySpec(kt); err != nil {
+		// Nothing to delete if the topology was never created.
+		log.Warningf("Not deleting invalid topology %q: %v", kt.Name, err)
+	} else {
+		m, err := newManager(t, r.rCfg)
+		if err != nil {
+			return err
+		}
+		log.Infof("Deleting topology %q", kt.Name)
+		if err := m.Delete(ctx); err != nil {
+			return err
+		}
+	}
+	var finalizers []string
+	for _, f := range kt.Finalizers {
+		if f != knev1alpha1.Finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	kt.Finalizers = finalizers
+	_, err := r.update(ctx, kt)
+	return err
+}
+
+func hasFinalizer(kt *knev1alpha1.KneTopology) bool {
+	for _, f := range kt.Finalizers {
+		if f == knev1alpha1.Finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// topologySpec returns the topology and node timeout of kt. The topology is
+// named after kt.
+func topologySpec(kt *knev1alpha1.KneTopology) (*tpb.Topology, time.Duration, error) {
+	t := &tpb.Topology{}
+	if err := prototext.Unmarshal([]byte(kt.Spec.Topology), t); err != nil {
+		return nil, 0, fmt.Errorf("invalid topology: %w", err)
+	}
+	t.Name = kt.Name
+	var timeout time.Duration
+	if kt.Spec.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(kt.Spec.Timeout); err != nil {
+			return nil, 0, fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+	return t, timeout, nil
+}
+
+// setStatus sets the phase of kt and the message of err, if not nil, for the
+// current generation of kt.
+func (r *reconciler) setStatus(ctx context.Context, kt *knev1alpha1.KneTopology, phase string, err error) error {
+	kt.Status = knev1alpha1.KneTopologyStatus{
+		Phase:              phase,
+		ObservedGeneration: kt.Generation,
+	}
+	if err != nil {
+		kt.Status.Message = err.Error()
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(kt)
+	if err != nil {
+		return fmt.Errorf("failed to convert KneTopology to unstructured: %w", err)
+	}
+	u, err := r.client.UpdateStatus(ctx, &unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	kt.ResourceVersion = u.GetResourceVersion()
+	return nil
+}
+
+// update updates kt and returns the updated resource.
+func (r *reconciler) update(ctx context.Context, kt *knev1alpha1.KneTopology) (*knev1alpha1.KneTopology, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(kt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert KneTopology to unstructured: %w", err)
+	}
+	u, err := r.client.Update(ctx, &unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := &knev1alpha1.KneTopology{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), result); err != nil {
+		return nil, fmt.Errorf("failed to type assert return to KneTopology: %w", err)
+	}
+	return result, nil
+}
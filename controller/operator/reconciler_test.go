@@ -0,0 +1,253 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	knev1alpha1 "github.com/openconfig/kne/api/kne/types/v1alpha1"
+	tpb "github.com/openconfig/kne/proto/topo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+)
+
+type fakeManager struct {
+	topo      *tpb.Topology
+	createErr error
+	deleteErr error
+	created   time.Duration
+	calls     []string
+}
+
+func (f *fakeManager) Create(_ context.Context, timeout time.Duration) error {
+	f.calls = append(f.calls, "create")
+	f.created = timeout
+	return f.createErr
+}
+
+func (f *fakeManager) Delete(context.Context) error {
+	f.calls = append(f.calls, "delete")
+	return f.deleteErr
+}
+
+const testTopology = `
+name: "ignored"
+nodes: {
+  name: "r1"
+}
+`
+
+func kneTopology(mod func(*knev1alpha1.KneTopology)) *knev1alpha1.KneTopology {
+	kt := &knev1alpha1.KneTopology{
+		TypeMeta:   metav1.TypeMeta{Kind: "KneTopology", APIVersion: "kne.openconfig.net/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 1},
+		Spec:       knev1alpha1.KneTopologySpec{Topology: testTopology},
+	}
+	if mod != nil {
+		mod(kt)
+	}
+	return kt
+}
+
+func TestReconcile(t *testing.T) {
+	origNewManager := newManager
+	defer func() {
+		newManager = origNewManager
+	}()
+	deleted := metav1.Now()
+	tests := []struct {
+		desc           string
+		in             *knev1alpha1.KneTopology
+		createErr      error
+		deleteErr      error
+		wantCalls      []string
+		wantTimeout    time.Duration
+		wantStatus     knev1alpha1.KneTopologyStatus
+		wantMessage    string
+		wantFinalizers []string
+		wantGone       bool
+		wantErr        string
+	}{{
+		desc:           "created",
+		in:             kneTopology(func(kt *knev1alpha1.KneTopology) { kt.Spec.Timeout = "5m" }),
+		wantCalls:      []string{"create"},
+		wantTimeout:    5 * time.Minute,
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseReady, ObservedGeneration: 1},
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+	}, {
+		desc:           "create failed",
+		in:             kneTopology(nil),
+		createErr:      fmt.Errorf("node r1 failed"),
+		wantCalls:      []string{"create"},
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseFailed, ObservedGeneration: 1},
+		wantMessage:    "node r1 failed",
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+	}, {
+		desc: "invalid topology",
+		in: kneTopology(func(kt *knev1alpha1.KneTopology) {
+			kt.Spec.Topology = "nodes: {"
+		}),
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseFailed, ObservedGeneration: 1},
+		wantMessage:    "invalid topology",
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+	}, {
+		desc: "invalid timeout",
+		in: kneTopology(func(kt *knev1alpha1.KneTopology) {
+			kt.Spec.Timeout = "soon"
+		}),
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseFailed, ObservedGeneration: 1},
+		wantMessage:    "invalid timeout",
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+	}, {
+		desc: "up to date",
+		in: kneTopology(func(kt *knev1alpha1.KneTopology) {
+			kt.Finalizers = []string{knev1alpha1.Finalizer}
+			kt.Status = knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseReady, ObservedGeneration: 1}
+		}),
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseReady, ObservedGeneration: 1},
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+	}, {
+		desc: "spec changed",
+		in: kneTopology(func(kt *knev1alpha1.KneTopology) {
+			kt.Generation = 2
+			kt.Finalizers = []string{knev1alpha1.Finalizer}
+			kt.Status = knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseReady, ObservedGeneration: 1}
+		}),
+		wantCalls:      []string{"delete", "create"},
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseReady, ObservedGeneration: 2},
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+	}, {
+		desc: "creation interrupted",
+		in: kneTopology(func(kt *knev1alpha1.KneTopology) {
+			kt.Finalizers = []string{knev1alpha1.Finalizer}
+			kt.Status = knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseCreating, ObservedGeneration: 1}
+		}),
+		wantCalls:      []string{"delete", "create"},
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseReady, ObservedGeneration: 1},
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+	}, {
+		desc: "deleted",
+		in: kneTopology(func(kt *knev1alpha1.KneTopology) {
+			kt.DeletionTimestamp = &deleted
+			kt.Finalizers = []string{"other", knev1alpha1.Finalizer}
+			kt.Status = knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseReady, ObservedGeneration: 1}
+		}),
+		wantCalls:      []string{"delete"},
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseDeleting, ObservedGeneration: 1},
+		wantFinalizers: []string{"other"},
+	}, {
+		desc: "delete failed",
+		in: kneTopology(func(kt *knev1alpha1.KneTopology) {
+			kt.DeletionTimestamp = &deleted
+			kt.Finalizers = []string{knev1alpha1.Finalizer}
+		}),
+		deleteErr:      fmt.Errorf("namespace stuck"),
+		wantCalls:      []string{"delete"},
+		wantStatus:     knev1alpha1.KneTopologyStatus{Phase: knev1alpha1.PhaseDeleting, ObservedGeneration: 1},
+		wantFinalizers: []string{knev1alpha1.Finalizer},
+		wantErr:        "namespace stuck",
+	}, {
+		desc:     "not found",
+		wantGone: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var objs []runtime.Object
+			if tt.in != nil {
+				objs = append(objs, tt.in)
+			}
+			dc := dynamicfake.NewSimpleDynamicClient(knev1alpha1.Scheme, objs...)
+			fm := &fakeManager{createErr: tt.createErr, deleteErr: tt.deleteErr}
+			newManager = func(topo *tpb.Topology, _ *rest.Config) (topologyManager, error) {
+				fm.topo = topo
+				return fm, nil
+			}
+			r := &reconciler{client: dc.Resource(knev1alpha1.GVR())}
+			err := r.reconcile(context.Background(), "test")
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if s := cmp.Diff(tt.wantCalls, fm.calls); s != "" {
+				t.Errorf("reconcile() unexpected manager calls diff (-want +got):\n%s", s)
+			}
+			if fm.topo != nil && fm.topo.GetName() != "test" {
+				t.Errorf("reconcile() got topology name %q, want %q", fm.topo.GetName(), "test")
+			}
+			if fm.created != tt.wantTimeout {
+				t.Errorf("reconcile() got timeout %v, want %v", fm.created, tt.wantTimeout)
+			}
+			u, err := r.client.Get(context.Background(), "test", metav1.GetOptions{})
+			if tt.wantGone {
+				if err == nil {
+					t.Fatalf("reconcile() unexpectedly created a topology")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get topology: %v", err)
+			}
+			got := &knev1alpha1.KneTopology{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), got); err != nil {
+				t.Fatalf("failed to convert topology: %v", err)
+			}
+			if s := cmp.Diff(tt.wantFinalizers, got.Finalizers); s != "" {
+				t.Errorf("reconcile() unexpected finalizers diff (-want +got):\n%s", s)
+			}
+			if !strings.Contains(got.Status.Message, tt.wantMessage) || (tt.wantMessage == "") != (got.Status.Message == "") {
+				t.Errorf("reconcile() got status message %q, want %q", got.Status.Message, tt.wantMessage)
+			}
+			got.Status.Message = ""
+			if s := cmp.Diff(tt.wantStatus, got.Status); s != "" {
+				t.Errorf("reconcile() unexpected status diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	origNewManager := newManager
+	defer func() {
+		newManager = origNewManager
+	}()
+	fm := &fakeManager{}
+	newManager = func(*tpb.Topology, *rest.Config) (topologyManager, error) {
+		return fm, nil
+	}
+	dc := dynamicfake.NewSimpleDynamicClient(knev1alpha1.Scheme, kneTopology(nil))
+	r := &reconciler{client: dc.Resource(knev1alpha1.GVR())}
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	enqueue(q).OnAdd(kneTopology(nil))
+	// Duplicate events are reconciled once.
+	enqueue(q).OnUpdate(nil, kneTopology(nil))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.run(context.Background(), q)
+	}()
+	q.ShutDownWithDrain()
+	<-done
+	if s := cmp.Diff([]string{"create"}, fm.calls); s != "" {
+		t.Errorf("run() unexpected manager calls diff (-want +got):\n%s", s)
+	}
+}
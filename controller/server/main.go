@@ -19,6 +19,7 @@ import (
 	"flag"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
@@ -29,6 +30,7 @@ import (
 	cpb "github.com/openconfig/kne/proto/controller"
 	tpb "github.com/openconfig/kne/proto/topo"
 	"github.com/openconfig/kne/topo"
+	"github.com/openconfig/kne/topo/metrics"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/alts"
@@ -47,7 +49,8 @@ var (
 	defaultSRLinuxManifestDir = ""
 	defaultCEOSLabManifestDir = ""
 	// Flags.
-	port = flag.Int("port", 50051, "Controller server port")
+	port        = flag.Int("port", 50051, "Controller server port")
+	metricsAddr = flag.String("metrics_addr", "", "Address to serve Prometheus metrics on, e.g. :9090 (disabled if empty)")
 )
 
 func init() {
@@ -67,15 +70,41 @@ type server struct {
 
 	muDeploy    sync.Mutex // guards deployements map
 	deployments map[string]*deploy.Deployment
-	muTopo      sync.Mutex        // guards topos map
-	topos       map[string][]byte // stores the topology protobuf from the initial topology creation request
+	muTopo      sync.Mutex                  // guards topos map
+	topos       map[string]*createdTopology // stores the topologies from the initial topology creation requests
+}
+
+// createdTopology is a topology created by a CreateTopology request.
+type createdTopology struct {
+	txtPb   []byte // topology protobuf of the request
+	kubecfg string // kubecfg the topology was created with
+	context string // context of kubecfg the topology was created in
 }
 
 func newServer() *server {
 	return &server{
 		deployments: map[string]*deploy.Deployment{},
-		topos:       map[string][]byte{},
+		topos:       map[string]*createdTopology{},
+	}
+}
+
+// topoManager returns a manager of the topology name created by a
+// CreateTopology request, for the cluster it was created in. s.muTopo must be
+// held.
+func (s *server) topoManager(name string) (*topo.Manager, error) {
+	t, ok := s.topos[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "topology %q not found", name)
+	}
+	topoPb := &tpb.Topology{}
+	if err := prototext.Unmarshal(t.txtPb, topoPb); err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid topology protobuf: %v", err)
+	}
+	tm, err := topo.New(topoPb, topo.WithKubecfg(t.kubecfg), topo.WithContext(t.context))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create topology manager for %s: %v", topoPb.Name, err)
 	}
+	return tm, nil
 }
 
 func newDeployment(req *cpb.CreateClusterRequest) (*deploy.Deployment, error) {
@@ -304,7 +333,7 @@ func (s *server) CreateTopology(ctx context.Context, req *cpb.CreateTopologyRequ
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "kubecfg %q does not exist: %v", path, err)
 	}
-	tm, err := topo.New(topoPb, topo.WithKubecfg(kcfg))
+	tm, err := topo.New(topoPb, topo.WithKubecfg(kcfg), topo.WithContext(req.GetContext()))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create topology manager: %v", err)
 	}
@@ -312,7 +341,7 @@ func (s *server) CreateTopology(ctx context.Context, req *cpb.CreateTopologyRequ
 		return nil, status.Errorf(codes.Internal, "failed to create topology: %v", err)
 	}
 
-	s.topos[topoPb.GetName()] = txtPb
+	s.topos[topoPb.GetName()] = &createdTopology{txtPb: txtPb, kubecfg: kcfg, context: req.GetContext()}
 	return &cpb.CreateTopologyResponse{
 		TopologyName: req.Topology.GetName(),
 		State:        cpb.TopologyState_TOPOLOGY_STATE_RUNNING,
@@ -323,21 +352,9 @@ func (s *server) DeleteTopology(ctx context.Context, req *cpb.DeleteTopologyRequ
 	log.Infof("Received DeleteTopology request: %v", req)
 	s.muTopo.Lock()
 	defer s.muTopo.Unlock()
-	txtPb, ok := s.topos[req.GetTopologyName()]
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "topology %q not found", req.GetTopologyName())
-	}
-	topoPb := &tpb.Topology{}
-	if err := prototext.Unmarshal(txtPb, topoPb); err != nil {
-		return nil, status.Errorf(codes.Internal, "invalid topology protobuf: %v", err)
-	}
-	kcfg, err := validatePath(defaultKubeCfg)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "default kubecfg %q does not exist: %v", defaultKubeCfg, err)
-	}
-	tm, err := topo.New(topoPb, topo.WithKubecfg(kcfg))
+	tm, err := s.topoManager(req.GetTopologyName())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create topology manager: %v", err)
+		return nil, err
 	}
 	if err := tm.Delete(ctx); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete topology: %v", err)
@@ -349,21 +366,9 @@ func (s *server) ShowTopology(ctx context.Context, req *cpb.ShowTopologyRequest)
 	log.Infof("Received ShowTopology request: %v", req)
 	s.muTopo.Lock()
 	defer s.muTopo.Unlock()
-	txtPb, ok := s.topos[req.GetTopologyName()]
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "topology %q not found", req.GetTopologyName())
-	}
-	topoPb := &tpb.Topology{}
-	if err := prototext.Unmarshal(txtPb, topoPb); err != nil {
-		return nil, status.Errorf(codes.Internal, "invalid topology protobuf: %v", err)
-	}
-	kcfg, err := validatePath(defaultKubeCfg)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "default kubecfg %q does not exist: %v", defaultKubeCfg, err)
-	}
-	tm, err := topo.New(topoPb, topo.WithKubecfg(kcfg))
+	tm, err := s.topoManager(req.GetTopologyName())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create topology manager: %v", err)
+		return nil, err
 	}
 	resp, err := tm.Show(ctx)
 	if err != nil {
@@ -376,21 +381,9 @@ func (s *server) PushConfig(ctx context.Context, req *cpb.PushConfigRequest) (*c
 	log.Infof("Received PushConfig request: %v", req)
 	s.muTopo.Lock()
 	defer s.muTopo.Unlock()
-	txtPb, ok := s.topos[req.GetTopologyName()]
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "topology %q not found", req.GetTopologyName())
-	}
-	topoPb := &tpb.Topology{}
-	if err := prototext.Unmarshal(txtPb, topoPb); err != nil {
-		return nil, status.Errorf(codes.Internal, "invalid topology protobuf: %v", err)
-	}
-	kcfg, err := validatePath(defaultKubeCfg)
+	tm, err := s.topoManager(req.GetTopologyName())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "default kubecfg %q does not exist: %v", defaultKubeCfg, err)
-	}
-	tm, err := topo.New(topoPb, topo.WithKubecfg(kcfg))
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create topology manager for %s: %v", topoPb.Name, err)
+		return nil, err
 	}
 	log.Infof("Pushing config of size %v to device %q", len(req.GetConfig()), req.GetDeviceName())
 	if err := tm.ConfigPush(ctx, req.GetDeviceName(), bytes.NewReader(req.GetConfig())); err != nil {
@@ -403,21 +396,9 @@ func (s *server) ResetConfig(ctx context.Context, req *cpb.ResetConfigRequest) (
 	log.Infof("Received ResetConfig request: %v", req)
 	s.muTopo.Lock()
 	defer s.muTopo.Unlock()
-	txtPb, ok := s.topos[req.GetTopologyName()]
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "topology %q not found", req.GetTopologyName())
-	}
-	topoPb := &tpb.Topology{}
-	if err := prototext.Unmarshal(txtPb, topoPb); err != nil {
-		return nil, status.Errorf(codes.Internal, "invalid topology protobuf: %v", err)
-	}
-	kcfg, err := validatePath(defaultKubeCfg)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "default kubecfg %q does not exist: %v", defaultKubeCfg, err)
-	}
-	tm, err := topo.New(topoPb, topo.WithKubecfg(kcfg))
+	tm, err := s.topoManager(req.GetTopologyName())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create topology manager for %s: %v", topoPb.Name, err)
+		return nil, err
 	}
 	log.Infof("Resetting config for device %q", req.GetDeviceName())
 	if err := tm.ResetCfg(ctx, req.GetDeviceName()); err != nil {
@@ -437,6 +418,16 @@ func validatePath(path string) (string, error) {
 	return path, nil
 }
 
+// serveMetrics serves the Prometheus metrics of topology operations on addr.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	log.Infof("Serving metrics at %s/metrics", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("failed to serve metrics: %v", err)
+	}
+}
+
 func main() {
 	flag.Parse()
 	addr := fmt.Sprintf(":%d", *port)
@@ -453,6 +444,9 @@ func main() {
 		}),
 	)
 	cpb.RegisterTopologyManagerServer(s, newServer())
+	if *metricsAddr != "" {
+		go serveMetrics(*metricsAddr)
+	}
 	log.Infof("Controller server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -9,6 +10,8 @@ import (
 	"github.com/h-fam/errdiff"
 	"github.com/openconfig/kne/deploy"
 	cpb "github.com/openconfig/kne/proto/controller"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestNewDeployment(t *testing.T) {
@@ -670,3 +673,52 @@ func TestNewDeployment(t *testing.T) {
 		})
 	}
 }
+
+func TestTopoManager(t *testing.T) {
+	kubecfg := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubecfg, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: a
+  cluster:
+    server: https://a.example.com
+contexts:
+- name: a
+  context:
+    cluster: a
+`), 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	s := newServer()
+	s.topos["valid"] = &createdTopology{txtPb: []byte(`name: "valid"`), kubecfg: kubecfg, context: "a"}
+	s.topos["invalid"] = &createdTopology{txtPb: []byte(`invalid`), kubecfg: kubecfg, context: "a"}
+	s.topos["missing context"] = &createdTopology{txtPb: []byte(`name: "missing"`), kubecfg: kubecfg, context: "b"}
+	tests := []struct {
+		desc     string
+		name     string
+		wantCode codes.Code
+	}{{
+		desc: "valid",
+		name: "valid",
+	}, {
+		desc:     "not found",
+		name:     "dne",
+		wantCode: codes.NotFound,
+	}, {
+		desc:     "invalid protobuf",
+		name:     "invalid",
+		wantCode: codes.Internal,
+	}, {
+		desc:     "missing context",
+		name:     "missing context",
+		wantCode: codes.Internal,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := s.topoManager(tt.name)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("topoManager() got code %v, want %v: %v", got, tt.wantCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/openconfig/kne/topo/node"
+	"github.com/openconfig/kne/topo/topofile"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// NodePool sizes the nodes of a managed cloud cluster. If Topology is set the
+// number of nodes is derived from the cpu and memory constraints of the nodes
+// of the topology and the allocatable capacity of a cluster node, with Nodes
+// as the minimum. Topology nodes without constraints are not accounted for.
+type NodePool struct {
+	MachineType string `yaml:"machineType"`
+	Nodes       int    `yaml:"nodes"`
+	// Topology is the path of a topology the node pool is sized for.
+	Topology string `yaml:"topology"`
+	// NodeCPU and NodeMemory are the allocatable capacity of a cluster node,
+	// required to size the node pool for Topology.
+	NodeCPU    string `yaml:"nodeCPU"`
+	NodeMemory string `yaml:"nodeMemory"`
+}
+
+// size returns the number of nodes of the pool.
+func (p *NodePool) size() (int, error) {
+	n := p.Nodes
+	if n < 1 {
+		n = 1
+	}
+	if p.Topology == "" {
+		return n, nil
+	}
+	if p.NodeCPU == "" && p.NodeMemory == "" {
+		return 0, fmt.Errorf("nodeCPU or nodeMemory must be set to size the node pool for topology %q", p.Topology)
+	}
+	t, err := topofile.Load(p.Topology)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load topology %q: %w", p.Topology, err)
+	}
+	var cpu, memory resource.Quantity
+	for _, tn := range t.GetNodes() {
+		rr, err := node.ParseConstraints(tn.GetConstraints())
+		if err != nil {
+			return 0, fmt.Errorf("node %q: %w", tn.GetName(), err)
+		}
+		if q, ok := rr.Requests[corev1.ResourceCPU]; ok {
+			cpu.Add(q)
+		}
+		if q, ok := rr.Requests[corev1.ResourceMemory]; ok {
+			memory.Add(q)
+		}
+	}
+	for _, c := range []struct {
+		name     string
+		capacity string
+		total    resource.Quantity
+	}{{"nodeCPU", p.NodeCPU, cpu}, {"nodeMemory", p.NodeMemory, memory}} {
+		if c.capacity == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(c.capacity)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", c.name, c.capacity, err)
+		}
+		if q.MilliValue() <= 0 {
+			return 0, fmt.Errorf("invalid %s %q: must be positive", c.name, c.capacity)
+		}
+		if need := int((c.total.MilliValue() + q.MilliValue() - 1) / q.MilliValue()); need > n {
+			n = need
+		}
+	}
+	log.Infof("Sized node pool to %d nodes for topology %q (cpu %s, memory %s)", n, p.Topology, cpu.String(), memory.String())
+	return n, nil
+}
+
+// GKESpec provisions or attaches to a GKE cluster using gcloud.
+type GKESpec struct {
+	Name    string `yaml:"name"`
+	Project string `yaml:"project"`
+	// Zone or Region is the location of the cluster.
+	Zone   string `yaml:"zone"`
+	Region string `yaml:"region"`
+	// Version is the Kubernetes version of the cluster.
+	Version string `yaml:"version"`
+	// Recycle attaches to an existing cluster of the same name.
+	Recycle  bool     `yaml:"recycle"`
+	NodePool NodePool `yaml:",inline"`
+	// Args are additional arguments of gcloud container clusters create.
+	Args []string `yaml:"args"`
+}
+
+func (g *GKESpec) checkDependencies() error {
+	if _, err := execLookPath("gcloud"); err != nil {
+		return fmt.Errorf("install dependency %q to deploy", "gcloud")
+	}
+	if g.Name == "" {
+		return fmt.Errorf("name of the GKE cluster must be set")
+	}
+	if (g.Zone == "") == (g.Region == "") {
+		return fmt.Errorf("exactly one of zone or region of the GKE cluster must be set")
+	}
+	return nil
+}
+
+// locationArgs returns the gcloud arguments selecting the cluster.
+func (g *GKESpec) locationArgs() []string {
+	var args []string
+	if g.Project != "" {
+		args = append(args, "--project", g.Project)
+	}
+	if g.Region != "" {
+		return append(args, "--region", g.Region)
+	}
+	return append(args, "--zone", g.Zone)
+}
+
+func (g *GKESpec) Deploy(ctx context.Context) error {
+	if err := g.checkDependencies(); err != nil {
+		return err
+	}
+	exists := false
+	if g.Recycle {
+		log.Infof("Attempting to recycle existing GKE cluster %q...", g.Name)
+		exists = execer.Exec("gcloud", append([]string{"container", "clusters", "describe", g.Name}, g.locationArgs()...)...) == nil
+	}
+	if exists {
+		log.Infof("Recycling existing GKE cluster %q", g.Name)
+	} else {
+		nodes, err := g.NodePool.size()
+		if err != nil {
+			return err
+		}
+		args := append([]string{"container", "clusters", "create", g.Name}, g.locationArgs()...)
+		args = append(args, "--num-nodes", strconv.Itoa(nodes))
+		if g.NodePool.MachineType != "" {
+			args = append(args, "--machine-type", g.NodePool.MachineType)
+		}
+		if g.Version != "" {
+			args = append(args, "--cluster-version", g.Version)
+		}
+		args = append(args, g.Args...)
+		log.Infof("Creating GKE cluster with: %v", args)
+		if err := execer.Exec("gcloud", args...); err != nil {
+			return fmt.Errorf("failed to create cluster: %w", err)
+		}
+		log.Infof("Deployed GKE cluster: %s", g.Name)
+	}
+	if err := execer.Exec("gcloud", append([]string{"container", "clusters", "get-credentials", g.Name}, g.locationArgs()...)...); err != nil {
+		return fmt.Errorf("failed to get credentials of cluster: %w", err)
+	}
+	return nil
+}
+
+func (g *GKESpec) Delete() error {
+	if err := execer.Exec("gcloud", append(append([]string{"container", "clusters", "delete", g.Name}, g.locationArgs()...), "--quiet")...); err != nil {
+		return fmt.Errorf("failed to delete cluster using cli: %w", err)
+	}
+	return nil
+}
+
+func (g *GKESpec) Healthy() error {
+	if err := execer.Exec("kubectl", "cluster-info"); err != nil {
+		return fmt.Errorf("cluster not healthy: %w", err)
+	}
+	return nil
+}
+
+func (g *GKESpec) GetName() string {
+	return g.Name
+}
+
+// EKSSpec provisions or attaches to an EKS cluster using eksctl.
+type EKSSpec struct {
+	Name   string `yaml:"name"`
+	Region string `yaml:"region"`
+	// Version is the Kubernetes version of the cluster.
+	Version string `yaml:"version"`
+	// Recycle attaches to an existing cluster of the same name.
+	Recycle  bool     `yaml:"recycle"`
+	NodePool NodePool `yaml:",inline"`
+	// Args are additional arguments of eksctl create cluster.
+	Args []string `yaml:"args"`
+}
+
+func (e *EKSSpec) checkDependencies() error {
+	if _, err := execLookPath("eksctl"); err != nil {
+		return fmt.Errorf("install dependency %q to deploy", "eksctl")
+	}
+	if e.Name == "" {
+		return fmt.Errorf("name of the EKS cluster must be set")
+	}
+	return nil
+}
+
+// regionArgs returns the eksctl arguments selecting the region, if set.
+func (e *EKSSpec) regionArgs(args ...string) []string {
+	if e.Region != "" {
+		args = append(args, "--region", e.Region)
+	}
+	return args
+}
+
+func (e *EKSSpec) Deploy(ctx context.Context) error {
+	if err := e.checkDependencies(); err != nil {
+		return err
+	}
+	if e.Recycle {
+		log.Infof("Attempting to recycle existing EKS cluster %q...", e.Name)
+		if err := execer.Exec("eksctl", e.regionArgs("get", "cluster", "--name", e.Name)...); err == nil {
+			log.Infof("Recycling existing EKS cluster %q", e.Name)
+			if err := execer.Exec("eksctl", e.regionArgs("utils", "write-kubeconfig", "--cluster", e.Name)...); err != nil {
+				return fmt.Errorf("failed to get credentials of cluster: %w", err)
+			}
+			return nil
+		}
+	}
+	nodes, err := e.NodePool.size()
+	if err != nil {
+		return err
+	}
+	args := e.regionArgs("create", "cluster", "--name", e.Name, "--nodes", strconv.Itoa(nodes))
+	if e.NodePool.MachineType != "" {
+		args = append(args, "--node-type", e.NodePool.MachineType)
+	}
+	if e.Version != "" {
+		args = append(args, "--version", e.Version)
+	}
+	args = append(args, e.Args...)
+	log.Infof("Creating EKS cluster with: %v", args)
+	// eksctl writes the kubeconfig of the cluster once it is created.
+	if err := execer.Exec("eksctl", args...); err != nil {
+		return fmt.Errorf("failed to create cluster: %w", err)
+	}
+	log.Infof("Deployed EKS cluster: %s", e.Name)
+	return nil
+}
+
+func (e *EKSSpec) Delete() error {
+	if err := execer.Exec("eksctl", e.regionArgs("delete", "cluster", "--name", e.Name)...); err != nil {
+		return fmt.Errorf("failed to delete cluster using cli: %w", err)
+	}
+	return nil
+}
+
+func (e *EKSSpec) Healthy() error {
+	if err := execer.Exec("kubectl", "cluster-info"); err != nil {
+		return fmt.Errorf("cluster not healthy: %w", err)
+	}
+	return nil
+}
+
+func (e *EKSSpec) GetName() string {
+	return e.Name
+}
+
+// CloudLBSpec is the ingress of managed cloud clusters, which provision a load
+// balancer for services of type LoadBalancer themselves. Nothing is deployed.
+type CloudLBSpec struct{}
+
+func (c *CloudLBSpec) SetKClient(kubernetes.Interface) {}
+
+func (c *CloudLBSpec) SetRCfg(*rest.Config) {}
+
+func (c *CloudLBSpec) Deploy(ctx context.Context) error {
+	log.Infof("Using the load balancer of the cloud provider")
+	return nil
+}
+
+func (c *CloudLBSpec) Healthy(ctx context.Context) error {
+	return nil
+}
+
+// Manifests returns no manifests as nothing is deployed.
+func (c *CloudLBSpec) Manifests(ctx context.Context) ([]byte, error) {
+	return nil, nil
+}
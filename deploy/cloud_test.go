@@ -0,0 +1,276 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/h-fam/errdiff"
+	"github.com/openconfig/kne/os/exec"
+)
+
+func writeTopology(t *testing.T, s string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "topo.pb.txt")
+	if err := os.WriteFile(path, []byte(s), 0644); err != nil {
+		t.Fatalf("failed to write topology: %v", err)
+	}
+	return path
+}
+
+func TestNodePoolSize(t *testing.T) {
+	topology := writeTopology(t, `
+name: "test"
+nodes: {
+	name: "r1"
+	constraints: { key: "cpu" value: "4" }
+	constraints: { key: "memory" value: "12Gi" }
+}
+nodes: {
+	name: "r2"
+	constraints: { key: "cpu" value: "500m" }
+	constraints: { key: "memory" value: "2Gi" }
+}
+nodes: {
+	name: "r3"
+}
+`)
+	tests := []struct {
+		desc    string
+		p       *NodePool
+		want    int
+		wantErr string
+	}{{
+		desc: "default",
+		p:    &NodePool{},
+		want: 1,
+	}, {
+		desc: "nodes",
+		p:    &NodePool{Nodes: 3},
+		want: 3,
+	}, {
+		desc: "cpu",
+		p:    &NodePool{Topology: topology, NodeCPU: "2"},
+		want: 3,
+	}, {
+		desc: "memory",
+		p:    &NodePool{Topology: topology, NodeCPU: "8", NodeMemory: "4Gi"},
+		want: 4,
+	}, {
+		desc: "nodes minimum",
+		p:    &NodePool{Topology: topology, NodeCPU: "8", NodeMemory: "16Gi", Nodes: 2},
+		want: 2,
+	}, {
+		desc:    "no capacity",
+		p:       &NodePool{Topology: topology},
+		wantErr: "nodeCPU or nodeMemory must be set",
+	}, {
+		desc:    "invalid capacity",
+		p:       &NodePool{Topology: topology, NodeCPU: "lots"},
+		wantErr: "invalid nodeCPU",
+	}, {
+		desc:    "zero capacity",
+		p:       &NodePool{Topology: topology, NodeCPU: "0"},
+		wantErr: "must be positive",
+	}, {
+		desc:    "invalid constraint",
+		p:       &NodePool{Topology: writeTopology(t, `nodes: { name: "r1" constraints: { key: "cpu" value: "lots" } }`), NodeCPU: "2"},
+		wantErr: `node "r1": invalid cpu constraint "lots"`,
+	}, {
+		desc:    "missing topology",
+		p:       &NodePool{Topology: filepath.Join(t.TempDir(), "missing.pb.txt"), NodeCPU: "2"},
+		wantErr: "failed to load topology",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.p.size()
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if got != tt.want {
+				t.Fatalf("size() got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGKESpec(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc        string
+		g           *GKESpec
+		execer      execerInterface
+		execPathErr bool
+		wantErr     string
+	}{{
+		desc: "create cluster",
+		g: &GKESpec{
+			Name:     "test",
+			Project:  "project",
+			Zone:     "us-west1-b",
+			Version:  "1.24",
+			NodePool: NodePool{MachineType: "n2-standard-8", Nodes: 3},
+		},
+		// create, get-credentials
+		execer: exec.NewFakeExecer(nil, nil),
+	}, {
+		desc: "create cluster with recycle",
+		g: &GKESpec{
+			Name:    "test",
+			Region:  "us-west1",
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(errors.New("not found"), nil, nil),
+	}, {
+		desc: "exists cluster with recycle",
+		g: &GKESpec{
+			Name:    "test",
+			Region:  "us-west1",
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(nil, nil),
+	}, {
+		desc:        "unable to find gcloud cli",
+		g:           &GKESpec{Name: "test", Zone: "us-west1-b"},
+		execPathErr: true,
+		wantErr:     `install dependency "gcloud" to deploy`,
+	}, {
+		desc:    "missing name",
+		g:       &GKESpec{Zone: "us-west1-b"},
+		wantErr: "name of the GKE cluster must be set",
+	}, {
+		desc:    "missing location",
+		g:       &GKESpec{Name: "test"},
+		wantErr: "exactly one of zone or region",
+	}, {
+		desc:    "zone and region",
+		g:       &GKESpec{Name: "test", Zone: "us-west1-b", Region: "us-west1"},
+		wantErr: "exactly one of zone or region",
+	}, {
+		desc: "invalid node pool",
+		g: &GKESpec{
+			Name:     "test",
+			Zone:     "us-west1-b",
+			NodePool: NodePool{Topology: "topo.pb.txt"},
+		},
+		wantErr: "nodeCPU or nodeMemory must be set",
+	}, {
+		desc:    "create cluster fail",
+		g:       &GKESpec{Name: "test", Zone: "us-west1-b"},
+		execer:  exec.NewFakeExecer(errors.New("cmd failed")),
+		wantErr: "failed to create cluster",
+	}, {
+		desc:    "get credentials fail",
+		g:       &GKESpec{Name: "test", Zone: "us-west1-b"},
+		execer:  exec.NewFakeExecer(nil, errors.New("cmd failed")),
+		wantErr: "failed to get credentials of cluster",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if tt.execer != nil {
+				execer = tt.execer
+			}
+			execLookPath = func(_ string) (string, error) {
+				if tt.execPathErr {
+					return "", errors.New("unable to find on path")
+				}
+				return "fakePath", nil
+			}
+			err := tt.g.Deploy(ctx)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+		})
+	}
+}
+
+func TestEKSSpec(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc        string
+		e           *EKSSpec
+		execer      execerInterface
+		execPathErr bool
+		wantErr     string
+	}{{
+		desc: "create cluster",
+		e: &EKSSpec{
+			Name:     "test",
+			Region:   "us-west-2",
+			Version:  "1.24",
+			NodePool: NodePool{MachineType: "m5.2xlarge", Nodes: 3},
+		},
+		execer: exec.NewFakeExecer(nil),
+	}, {
+		desc: "create cluster with recycle",
+		e: &EKSSpec{
+			Name:    "test",
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(errors.New("not found"), nil),
+	}, {
+		desc: "exists cluster with recycle",
+		e: &EKSSpec{
+			Name:    "test",
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(nil, nil),
+	}, {
+		desc: "exists cluster with recycle credentials fail",
+		e: &EKSSpec{
+			Name:    "test",
+			Recycle: true,
+		},
+		execer:  exec.NewFakeExecer(nil, errors.New("cmd failed")),
+		wantErr: "failed to get credentials of cluster",
+	}, {
+		desc:        "unable to find eksctl cli",
+		e:           &EKSSpec{Name: "test"},
+		execPathErr: true,
+		wantErr:     `install dependency "eksctl" to deploy`,
+	}, {
+		desc:    "missing name",
+		e:       &EKSSpec{},
+		wantErr: "name of the EKS cluster must be set",
+	}, {
+		desc:    "create cluster fail",
+		e:       &EKSSpec{Name: "test"},
+		execer:  exec.NewFakeExecer(errors.New("cmd failed")),
+		wantErr: "failed to create cluster",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if tt.execer != nil {
+				execer = tt.execer
+			}
+			execLookPath = func(_ string) (string, error) {
+				if tt.execPathErr {
+					return "", errors.New("unable to find on path")
+				}
+				return "fakePath", nil
+			}
+			err := tt.e.Deploy(ctx)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+		})
+	}
+}
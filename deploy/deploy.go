@@ -99,11 +99,87 @@ type Controller interface {
 	Healthy(context.Context) error
 }
 
+// ImageArchiveLoader is implemented by clusters whose nodes can be loaded with
+// the images of an image archive, to deploy without access to a registry.
+type ImageArchiveLoader interface {
+	// LoadImageArchive loads the images of the archive at path, as written by
+	// docker save, into the nodes of the cluster.
+	LoadImageArchive(path string) error
+}
+
 type Deployment struct {
 	Cluster     Cluster
 	Ingress     Ingress
 	CNI         CNI
 	Controllers []Controller
+	// ImageArchives are loaded into the cluster after it is deployed, before
+	// the ingress, CNI and controllers.
+	ImageArchives []string
+	// Context is the context of the kubeconfig the ingress, CNI and
+	// controllers are deployed with. If empty the context of the cluster is
+	// used if known, otherwise the current context.
+	Context string
+}
+
+// kubeContexter is implemented by clusters with a known kubeconfig context.
+type kubeContexter interface {
+	kubeContext() string
+}
+
+// kubeContext returns the context of the kubeconfig the deployment uses, empty
+// for the current context.
+func (d *Deployment) kubeContext() string {
+	if d.Context != "" {
+		return d.Context
+	}
+	if c, ok := d.Cluster.(kubeContexter); ok {
+		return c.kubeContext()
+	}
+	return ""
+}
+
+// kubectlExecer runs kubectl with a kubeconfig and context.
+type kubectlExecer struct {
+	execerInterface
+	kubecfg string
+	context string
+}
+
+func (e *kubectlExecer) Exec(cmd string, args ...string) error {
+	if cmd != "kubectl" {
+		return e.execerInterface.Exec(cmd, args...)
+	}
+	// Flags of the command itself come last so they take precedence.
+	var kArgs []string
+	if e.kubecfg != "" {
+		kArgs = append(kArgs, "--kubeconfig", e.kubecfg)
+	}
+	if e.context != "" {
+		kArgs = append(kArgs, "--context", e.context)
+	}
+	return e.execerInterface.Exec(cmd, append(kArgs, args...)...)
+}
+
+// kubectlSetter is implemented by ingresses, CNIs and controllers applying
+// manifests with kubectl, to apply them to the cluster of the deployment.
+type kubectlSetter interface {
+	setKubectl(execerInterface)
+}
+
+// setKubectl sets the execer running kubectl of v if v applies manifests.
+func setKubectl(v interface{}, e execerInterface) {
+	if s, ok := v.(kubectlSetter); ok {
+		s.setKubectl(e)
+	}
+}
+
+// kubectlOrExecer returns e, the execer running kubectl set by the deployment,
+// or execer if not set.
+func kubectlOrExecer(e execerInterface) execerInterface {
+	if e != nil {
+		return e
+	}
+	return execer
 }
 
 func (d *Deployment) String() string {
@@ -136,18 +212,33 @@ func (d *Deployment) Deploy(ctx context.Context, kubecfg string) error {
 		return err
 	}
 	log.Infof("Cluster deployed")
+	if err := d.loadImageArchives(); err != nil {
+		return err
+	}
 	// Once cluster is up, set kClient
-	rCfg, err := clientcmd.BuildConfigFromFlags("", kubecfg)
+	kubeContext := d.kubeContext()
+	rCfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubecfg},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
 	if err != nil {
 		return err
 	}
+	// The ingress, CNI and controllers are applied to the same cluster.
+	kubectl := &kubectlExecer{execerInterface: execer, kubecfg: kubecfg, context: kubeContext}
+	setKubectl(d.Ingress, kubectl)
+	setKubectl(d.CNI, kubectl)
+	for _, c := range d.Controllers {
+		setKubectl(c, kubectl)
+	}
 	kClient, err := kubernetes.NewForConfig(rCfg)
 	if err != nil {
 		return err
 	}
 
 	log.Infof("Checking kubectl versions.")
-	if err := vExec.Exec("kubectl", "version", "--output=yaml"); err != nil {
+	vKubectl := &kubectlExecer{execerInterface: vExec, kubecfg: kubecfg, context: kubeContext}
+	if err := vKubectl.Exec("kubectl", "version", "--output=yaml"); err != nil {
 		return fmt.Errorf("failed get kubectl version: %w", err)
 	}
 	kubeYAML := kubeVersion{}
@@ -174,6 +265,7 @@ func (d *Deployment) Deploy(ctx context.Context, kubecfg string) error {
 	log.Info("Found k8s versions:\n", vOut)
 	vOut.Reset()
 
+	d.setIngressNetwork()
 	d.Ingress.SetKClient(kClient)
 	d.Ingress.SetRCfg(rCfg)
 
@@ -214,6 +306,44 @@ func (d *Deployment) Deploy(ctx context.Context, kubecfg string) error {
 	return nil
 }
 
+// loadImageArchives loads the image archives of the deployment into the
+// cluster.
+func (d *Deployment) loadImageArchives() error {
+	if len(d.ImageArchives) == 0 {
+		return nil
+	}
+	l, ok := d.Cluster.(ImageArchiveLoader)
+	if !ok {
+		return fmt.Errorf("image archives cannot be loaded into cluster %T", d.Cluster)
+	}
+	for _, path := range d.ImageArchives {
+		log.Infof("Loading image archive %q", path)
+		if err := l.LoadImageArchive(path); err != nil {
+			return fmt.Errorf("failed to load image archive %q: %w", path, err)
+		}
+	}
+	log.Infof("Loaded all image archives")
+	return nil
+}
+
+// dockerNetworker is implemented by clusters run in a docker network other
+// than the kind network.
+type dockerNetworker interface {
+	dockerNetwork() string
+}
+
+// setIngressNetwork defaults the docker network of a MetalLB ingress to the
+// network of the cluster.
+func (d *Deployment) setIngressNetwork() {
+	m, ok := d.Ingress.(*MetalLBSpec)
+	if !ok || m.Network != "" || len(m.Addresses) != 0 {
+		return
+	}
+	if n, ok := d.Cluster.(dockerNetworker); ok {
+		m.Network = n.dockerNetwork()
+	}
+}
+
 func (d *Deployment) Delete() error {
 	log.Infof("Deleting cluster...")
 	if err := d.Cluster.Delete(); err != nil {
@@ -446,6 +576,10 @@ func (k *KindSpec) Healthy() error {
 	return nil
 }
 
+func (k *KindSpec) kubeContext() string {
+	return "kind-" + k.GetName()
+}
+
 func (k *KindSpec) GetName() string {
 	if k.Name != "" {
 		return k.Name
@@ -511,7 +645,19 @@ func (k *KindSpec) setupGoogleArtifactRegistryAccess() error {
 }
 
 func (k *KindSpec) loadContainerImages() error {
-	for s, d := range k.ContainerImages {
+	return loadContainerImages(k.ContainerImages, func(image string) error {
+		args := []string{"load", "docker-image", image}
+		if k.Name != "" {
+			args = append(args, "--name", k.Name)
+		}
+		return execer.Exec("kind", args...)
+	})
+}
+
+// loadContainerImages pulls the source images of images, tags them with their
+// destination image if set, and loads them into the cluster using load.
+func loadContainerImages(images map[string]string, load func(string) error) error {
+	for s, d := range images {
 		if s == "" {
 			return fmt.Errorf("source container must not be empty")
 		}
@@ -529,11 +675,7 @@ func (k *KindSpec) loadContainerImages() error {
 				return fmt.Errorf("failed to tag %q with %q: %w", s, d, err)
 			}
 		}
-		args := []string{"load", "docker-image", d}
-		if k.Name != "" {
-			args = append(args, "--name", k.Name)
-		}
-		if err := execer.Exec("kind", args...); err != nil {
+		if err := load(d); err != nil {
 			return fmt.Errorf("failed to load %q: %w", d, err)
 		}
 	}
@@ -541,6 +683,16 @@ func (k *KindSpec) loadContainerImages() error {
 	return nil
 }
 
+// LoadImageArchive loads the images of the archive at path into the nodes of
+// the cluster.
+func (k *KindSpec) LoadImageArchive(path string) error {
+	args := []string{"load", "image-archive", path}
+	if k.Name != "" {
+		args = append(args, "--name", k.Name)
+	}
+	return execer.Exec("kind", args...)
+}
+
 func writeDockerConfig(path string, registries []string) error {
 	f, err := os.Create(path)
 	if err != nil {
@@ -553,16 +705,27 @@ func writeDockerConfig(path string, registries []string) error {
 type MetalLBSpec struct {
 	IPCount     int    `yaml:"ip_count"`
 	ManifestDir string `yaml:"manifests"`
-	kClient     kubernetes.Interface
-	mClient     metallbclientv1.Interface
-	rCfg        *rest.Config
-	dClient     dclient.NetworkAPIClient
+	// Network is the docker network the address pool is allocated from, by
+	// default the network of the cluster or kind.
+	Network string `yaml:"network"`
+	// Addresses are the address ranges of the pool, e.g. 10.0.0.50-10.0.0.150,
+	// used instead of allocating them from a docker network.
+	Addresses []string `yaml:"addresses"`
+	kClient   kubernetes.Interface
+	mClient   metallbclientv1.Interface
+	rCfg      *rest.Config
+	dClient   dclient.NetworkAPIClient
+	kubectl   execerInterface
 }
 
 func (m *MetalLBSpec) SetKClient(c kubernetes.Interface) {
 	m.kClient = c
 }
 
+func (m *MetalLBSpec) setKubectl(e execerInterface) {
+	m.kubectl = e
+}
+
 func (m *MetalLBSpec) SetRCfg(cfg *rest.Config) {
 	m.rCfg = cfg
 }
@@ -586,25 +749,22 @@ func makePool(n *net.IPNet, count int) *metallbv1.IPAddressPool {
 	end := make(net.IP, len(start))
 	copy(end, start)
 	inc(end, count)
+	return newPool([]string{fmt.Sprintf("%s - %s", start, end)})
+}
+
+func newPool(addresses []string) *metallbv1.IPAddressPool {
 	return &metallbv1.IPAddressPool{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "metallb-system",
 			Name:      "kne-service-pool",
 		},
 		Spec: metallbv1.IPAddressPoolSpec{
-			Addresses: []string{fmt.Sprintf("%s - %s", start, end)},
+			Addresses: addresses,
 		},
 	}
 }
 
 func (m *MetalLBSpec) Deploy(ctx context.Context) error {
-	if m.dClient == nil {
-		var err error
-		m.dClient, err = dclient.NewClientWithOpts(dclient.FromEnv)
-		if err != nil {
-			return err
-		}
-	}
 	if m.mClient == nil {
 		var err error
 		m.mClient, err = metallbclientv1.NewForConfig(m.rCfg)
@@ -614,7 +774,7 @@ func (m *MetalLBSpec) Deploy(ctx context.Context) error {
 	}
 
 	log.Infof("Creating metallb namespace")
-	if err := execer.Exec("kubectl", "apply", "-f", filepath.Join(m.ManifestDir, "metallb-native.yaml")); err != nil {
+	if err := kubectlOrExecer(m.kubectl).Exec("kubectl", "apply", "-f", filepath.Join(m.ManifestDir, "metallb-native.yaml")); err != nil {
 		return err
 	}
 	_, err := m.kClient.CoreV1().Secrets("metallb-system").Get(ctx, "memberlist", metav1.GetOptions{})
@@ -646,33 +806,10 @@ func (m *MetalLBSpec) Deploy(ctx context.Context) error {
 	_, err = m.mClient.IPAddressPool("metallb-system").Get(ctx, "kne-service-pool", metav1.GetOptions{})
 	if err != nil {
 		log.Infof("Applying metallb ingress config")
-		// Get Network information from docker.
-		nr, err := m.dClient.NetworkList(ctx, dtypes.NetworkListOptions{})
+		pool, err := m.addressPool(ctx)
 		if err != nil {
 			return err
 		}
-		var network dtypes.NetworkResource
-		for _, v := range nr {
-			if v.Name == "kind" {
-				network = v
-				break
-			}
-		}
-		var n *net.IPNet
-		for _, ipRange := range network.IPAM.Config {
-			_, ipNet, err := net.ParseCIDR(ipRange.Subnet)
-			if err != nil {
-				return err
-			}
-			if ipNet.IP.To4() != nil {
-				n = ipNet
-				break
-			}
-		}
-		if n == nil {
-			return fmt.Errorf("failed to find kind ipv4 docker net")
-		}
-		pool := makePool(n, m.IPCount)
 		retries := 5
 		for ; ; retries-- {
 			_, err = m.mClient.IPAddressPool("metallb-system").Create(ctx, pool, metav1.CreateOptions{})
@@ -685,22 +822,71 @@ func (m *MetalLBSpec) Deploy(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		l2Advert := &metallbv1.L2Advertisement{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "kne-l2-service-pool",
-				Namespace: "metallb-system",
-			},
-			Spec: metallbv1.L2AdvertisementSpec{
-				IPAddressPools: []string{"kne-service-pool"},
-			},
-		}
-		if _, err = m.mClient.L2Advertisement("metallb-system").Create(ctx, l2Advert, metav1.CreateOptions{}); err != nil {
+		if _, err = m.mClient.L2Advertisement("metallb-system").Create(ctx, l2Advertisement(), metav1.CreateOptions{}); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// addressPool returns the pool of service addresses, either the configured
+// addresses or a range of the docker network.
+func (m *MetalLBSpec) addressPool(ctx context.Context) (*metallbv1.IPAddressPool, error) {
+	if len(m.Addresses) != 0 {
+		return newPool(m.Addresses), nil
+	}
+	name := m.Network
+	if name == "" {
+		name = "kind"
+	}
+	if m.dClient == nil {
+		var err error
+		m.dClient, err = dclient.NewClientWithOpts(dclient.FromEnv)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Get Network information from docker.
+	nr, err := m.dClient.NetworkList(ctx, dtypes.NetworkListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var network dtypes.NetworkResource
+	for _, v := range nr {
+		if v.Name == name {
+			network = v
+			break
+		}
+	}
+	var n *net.IPNet
+	for _, ipRange := range network.IPAM.Config {
+		_, ipNet, err := net.ParseCIDR(ipRange.Subnet)
+		if err != nil {
+			return nil, err
+		}
+		if ipNet.IP.To4() != nil {
+			n = ipNet
+			break
+		}
+	}
+	if n == nil {
+		return nil, fmt.Errorf("failed to find %s ipv4 docker net", name)
+	}
+	return makePool(n, m.IPCount), nil
+}
+
+func l2Advertisement() *metallbv1.L2Advertisement {
+	return &metallbv1.L2Advertisement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kne-l2-service-pool",
+			Namespace: "metallb-system",
+		},
+		Spec: metallbv1.L2AdvertisementSpec{
+			IPAddressPools: []string{"kne-service-pool"},
+		},
+	}
+}
+
 func (m *MetalLBSpec) Healthy(ctx context.Context) error {
 	return deploymentHealthy(ctx, m.kClient, "metallb-system")
 }
@@ -708,15 +894,20 @@ func (m *MetalLBSpec) Healthy(ctx context.Context) error {
 type MeshnetSpec struct {
 	ManifestDir string `yaml:"manifests"`
 	kClient     kubernetes.Interface
+	kubectl     execerInterface
 }
 
 func (m *MeshnetSpec) SetKClient(c kubernetes.Interface) {
 	m.kClient = c
 }
 
+func (m *MeshnetSpec) setKubectl(e execerInterface) {
+	m.kubectl = e
+}
+
 func (m *MeshnetSpec) Deploy(ctx context.Context) error {
 	log.Infof("Deploying Meshnet from: %s", m.ManifestDir)
-	if err := execer.Exec("kubectl", "apply", "-f", filepath.Join(m.ManifestDir, "manifest.yaml")); err != nil {
+	if err := kubectlOrExecer(m.kubectl).Exec("kubectl", "apply", "-f", filepath.Join(m.ManifestDir, "manifest.yaml")); err != nil {
 		return err
 	}
 	log.Infof("Meshnet Deployed")
@@ -755,15 +946,20 @@ func (m *MeshnetSpec) Healthy(ctx context.Context) error {
 type CEOSLabSpec struct {
 	ManifestDir string `yaml:"manifests"`
 	kClient     kubernetes.Interface
+	kubectl     execerInterface
 }
 
 func (c *CEOSLabSpec) SetKClient(k kubernetes.Interface) {
 	c.kClient = k
 }
 
+func (c *CEOSLabSpec) setKubectl(e execerInterface) {
+	c.kubectl = e
+}
+
 func (c *CEOSLabSpec) Deploy(ctx context.Context) error {
 	log.Infof("Deploying CEOSLab controller from: %s", c.ManifestDir)
-	if err := execer.Exec("kubectl", "apply", "-f", filepath.Join(c.ManifestDir, "manifest.yaml")); err != nil {
+	if err := kubectlOrExecer(c.kubectl).Exec("kubectl", "apply", "-f", filepath.Join(c.ManifestDir, "manifest.yaml")); err != nil {
 		return err
 	}
 	log.Infof("CEOSLab controller deployed")
@@ -774,18 +970,52 @@ func (c *CEOSLabSpec) Healthy(ctx context.Context) error {
 	return deploymentHealthy(ctx, c.kClient, "arista-ceoslab-operator-system")
 }
 
+// KNESpec deploys the kne operator creating the topologies of KneTopology
+// resources.
+type KNESpec struct {
+	ManifestDir string `yaml:"manifests"`
+	kClient     kubernetes.Interface
+	kubectl     execerInterface
+}
+
+func (k *KNESpec) SetKClient(c kubernetes.Interface) {
+	k.kClient = c
+}
+
+func (k *KNESpec) setKubectl(e execerInterface) {
+	k.kubectl = e
+}
+
+func (k *KNESpec) Deploy(ctx context.Context) error {
+	log.Infof("Deploying KNE operator from: %s", k.ManifestDir)
+	if err := kubectlOrExecer(k.kubectl).Exec("kubectl", "apply", "-f", filepath.Join(k.ManifestDir, "manifest.yaml")); err != nil {
+		return err
+	}
+	log.Infof("KNE operator deployed")
+	return nil
+}
+
+func (k *KNESpec) Healthy(ctx context.Context) error {
+	return deploymentHealthy(ctx, k.kClient, "kne-operator")
+}
+
 type SRLinuxSpec struct {
 	ManifestDir string `yaml:"manifests"`
 	kClient     kubernetes.Interface
+	kubectl     execerInterface
 }
 
 func (s *SRLinuxSpec) SetKClient(c kubernetes.Interface) {
 	s.kClient = c
 }
 
+func (s *SRLinuxSpec) setKubectl(e execerInterface) {
+	s.kubectl = e
+}
+
 func (s *SRLinuxSpec) Deploy(ctx context.Context) error {
 	log.Infof("Deploying SRLinux controller from: %s", s.ManifestDir)
-	if err := execer.Exec("kubectl", "apply", "-f", filepath.Join(s.ManifestDir, "manifest.yaml")); err != nil {
+	if err := kubectlOrExecer(s.kubectl).Exec("kubectl", "apply", "-f", filepath.Join(s.ManifestDir, "manifest.yaml")); err != nil {
 		return err
 	}
 	log.Infof("SRLinux controller deployed")
@@ -800,6 +1030,7 @@ type IxiaTGSpec struct {
 	ManifestDir string           `yaml:"manifests"`
 	ConfigMap   *IxiaTGConfigMap `yaml:"configMap"`
 	kClient     kubernetes.Interface
+	kubectl     execerInterface
 }
 
 type IxiaTGConfigMap struct {
@@ -817,9 +1048,13 @@ func (i *IxiaTGSpec) SetKClient(c kubernetes.Interface) {
 	i.kClient = c
 }
 
+func (i *IxiaTGSpec) setKubectl(e execerInterface) {
+	i.kubectl = e
+}
+
 func (i *IxiaTGSpec) Deploy(ctx context.Context) error {
 	log.Infof("Deploying IxiaTG controller from: %s", i.ManifestDir)
-	if err := execer.Exec("kubectl", "apply", "-f", filepath.Join(i.ManifestDir, "ixiatg-operator.yaml")); err != nil {
+	if err := kubectlOrExecer(i.kubectl).Exec("kubectl", "apply", "-f", filepath.Join(i.ManifestDir, "ixiatg-operator.yaml")); err != nil {
 		return err
 	}
 	if i.ConfigMap == nil {
@@ -829,17 +1064,16 @@ func (i *IxiaTGSpec) Deploy(ctx context.Context) error {
 			return nil
 		}
 		log.Infof("Deploying IxiaTG configmap from: %s", path)
-		if err := execer.Exec("kubectl", "apply", "-f", path); err != nil {
+		if err := kubectlOrExecer(i.kubectl).Exec("kubectl", "apply", "-f", path); err != nil {
 			return err
 		}
 		log.Infof("IxiaTG controller Deployed")
 		return nil
 	}
-	b, err := json.MarshalIndent(i.ConfigMap, "    ", "  ")
+	b, err := i.configMapYAML()
 	if err != nil {
 		return err
 	}
-	b = append([]byte(ixiaTGConfigMapHeader), b...)
 	f, err := os.CreateTemp("", "ixiatg-configmap-*.yaml")
 	if err != nil {
 		return err
@@ -849,13 +1083,23 @@ func (i *IxiaTGSpec) Deploy(ctx context.Context) error {
 		return err
 	}
 	log.Infof("Deploying IxiaTG configmap from: %s", f.Name())
-	if err := execer.Exec("kubectl", "apply", "-f", f.Name()); err != nil {
+	if err := kubectlOrExecer(i.kubectl).Exec("kubectl", "apply", "-f", f.Name()); err != nil {
 		return err
 	}
 	log.Infof("IxiaTG controller deployed")
 	return nil
 }
 
+// configMapYAML returns the manifest of the config map of the images used by
+// the controller.
+func (i *IxiaTGSpec) configMapYAML() ([]byte, error) {
+	b, err := json.MarshalIndent(i.ConfigMap, "    ", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(ixiaTGConfigMapHeader), b...), nil
+}
+
 func (i *IxiaTGSpec) Healthy(ctx context.Context) error {
 	return deploymentHealthy(ctx, i.kClient, "ixiatg-op-system")
 }
@@ -994,6 +994,101 @@ func TestIxiaTGSpec(t *testing.T) {
 	}
 }
 
+func TestKNESpec(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	deploymentName := "kne-operator"
+	deploymentNS := "kne-operator"
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: deploymentNS,
+		},
+	}
+	tests := []struct {
+		desc        string
+		kne         *KNESpec
+		execer      execerInterface
+		dErr        string
+		hErr        string
+		ctx         context.Context
+		mockKClient func(*fake.Clientset)
+	}{{
+		desc:   "1 replica",
+		kne:    &KNESpec{},
+		execer: exec.NewFakeExecer(nil),
+		mockKClient: func(k *fake.Clientset) {
+			reaction := func(action ktest.Action) (handled bool, ret watch.Interface, err error) {
+				f := newFakeWatch([]watch.Event{{
+					Type: watch.Added,
+					Object: &appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      deploymentName,
+							Namespace: deploymentNS,
+						},
+						Status: appsv1.DeploymentStatus{
+							UnavailableReplicas: 1,
+						},
+					},
+				}, {
+					Type: watch.Modified,
+					Object: &appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      deploymentName,
+							Namespace: deploymentNS,
+						},
+						Status: appsv1.DeploymentStatus{
+							AvailableReplicas: 1,
+							ReadyReplicas:     1,
+							Replicas:          1,
+							UpdatedReplicas:   1,
+						},
+					},
+				}})
+				return true, f, nil
+			}
+			k.PrependWatchReactor("deployments", reaction)
+		},
+	}, {
+		desc:   "operator deploy error",
+		kne:    &KNESpec{},
+		execer: exec.NewFakeExecer(errors.New("failed to apply operator")),
+		dErr:   "failed to apply operator",
+	}, {
+		desc:   "context canceled",
+		kne:    &KNESpec{},
+		execer: exec.NewFakeExecer(nil),
+		ctx:    canceledCtx,
+		hErr:   "context canceled",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ki := fake.NewSimpleClientset(d)
+			if tt.mockKClient != nil {
+				tt.mockKClient(ki)
+			}
+			tt.kne.SetKClient(ki)
+			if tt.execer != nil {
+				execer = tt.execer
+			}
+			err := tt.kne.Deploy(context.Background())
+			if s := errdiff.Substring(err, tt.dErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if tt.ctx == nil {
+				tt.ctx = context.Background()
+			}
+			err = tt.kne.Healthy(tt.ctx)
+			if s := errdiff.Substring(err, tt.hErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+		})
+	}
+}
+
 func TestSRLinuxSpec(t *testing.T) {
 	canceledCtx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -1289,3 +1384,175 @@ func TestCEOSLabSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadImageArchives(t *testing.T) {
+	tests := []struct {
+		desc    string
+		d       *Deployment
+		execer  execerInterface
+		wantErr string
+	}{{
+		desc: "no archives",
+		d:    &Deployment{Cluster: &KindSpec{}},
+	}, {
+		desc:   "kind",
+		d:      &Deployment{Cluster: &KindSpec{Name: "kne"}, ImageArchives: []string{"a.tar", "b.tar"}},
+		execer: exec.NewFakeExecer(nil, nil),
+	}, {
+		desc:   "minikube",
+		d:      &Deployment{Cluster: &MinikubeSpec{}, ImageArchives: []string{"a.tar"}},
+		execer: exec.NewFakeExecer(nil),
+	}, {
+		desc:   "k3s",
+		d:      &Deployment{Cluster: &K3sSpec{}, ImageArchives: []string{"a.tar"}},
+		execer: exec.NewFakeExecer(nil),
+	}, {
+		desc:    "load fail",
+		d:       &Deployment{Cluster: &KindSpec{}, ImageArchives: []string{"a.tar", "b.tar"}},
+		execer:  exec.NewFakeExecer(nil, errors.New("cmd failed")),
+		wantErr: `failed to load image archive "b.tar"`,
+	}, {
+		desc:    "not supported",
+		d:       &Deployment{Cluster: &GKESpec{}, ImageArchives: []string{"a.tar"}},
+		wantErr: "image archives cannot be loaded",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if tt.execer != nil {
+				execer = tt.execer
+			}
+			err := tt.d.loadImageArchives()
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+		})
+	}
+}
+
+// recordExecer records the commands it runs.
+type recordExecer struct {
+	*exec.FakeExecer
+	cmds [][]string
+}
+
+func (r *recordExecer) Exec(cmd string, args ...string) error {
+	r.cmds = append(r.cmds, append([]string{cmd}, args...))
+	return nil
+}
+
+func TestKubectlExecer(t *testing.T) {
+	tests := []struct {
+		desc    string
+		kubecfg string
+		context string
+		cmd     []string
+		want    []string
+	}{{
+		desc:    "kubectl",
+		kubecfg: "/kube/config",
+		context: "kind-kne",
+		cmd:     []string{"kubectl", "apply", "-f", "manifest.yaml"},
+		want:    []string{"kubectl", "--kubeconfig", "/kube/config", "--context", "kind-kne", "apply", "-f", "manifest.yaml"},
+	}, {
+		desc:    "context only",
+		context: "kne",
+		cmd:     []string{"kubectl", "cluster-info"},
+		want:    []string{"kubectl", "--context", "kne", "cluster-info"},
+	}, {
+		desc:    "not kubectl",
+		kubecfg: "/kube/config",
+		context: "kind-kne",
+		cmd:     []string{"docker", "ps"},
+		want:    []string{"docker", "ps"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := &recordExecer{FakeExecer: exec.NewFakeExecer()}
+			e := &kubectlExecer{execerInterface: r, kubecfg: tt.kubecfg, context: tt.context}
+			if err := e.Exec(tt.cmd[0], tt.cmd[1:]...); err != nil {
+				t.Fatalf("Exec() failed: %v", err)
+			}
+			if s := cmp.Diff([][]string{tt.want}, r.cmds); s != "" {
+				t.Fatalf("Exec() unexpected commands (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestSetKubectl(t *testing.T) {
+	tests := []struct {
+		desc string
+		spec interface {
+			Deploy(context.Context) error
+		}
+		want [][]string
+	}{{
+		desc: "meshnet",
+		spec: &MeshnetSpec{ManifestDir: "/meshnet"},
+		want: [][]string{{"kubectl", "--kubeconfig", "/kube/config", "--context", "kind-kne", "apply", "-f", "/meshnet/manifest.yaml"}},
+	}, {
+		desc: "ceoslab",
+		spec: &CEOSLabSpec{ManifestDir: "/ceoslab"},
+		want: [][]string{{"kubectl", "--kubeconfig", "/kube/config", "--context", "kind-kne", "apply", "-f", "/ceoslab/manifest.yaml"}},
+	}, {
+		desc: "kne",
+		spec: &KNESpec{ManifestDir: "/kne"},
+		want: [][]string{{"kubectl", "--kubeconfig", "/kube/config", "--context", "kind-kne", "apply", "-f", "/kne/manifest.yaml"}},
+	}, {
+		desc: "srlinux",
+		spec: &SRLinuxSpec{ManifestDir: "/srlinux"},
+		want: [][]string{{"kubectl", "--kubeconfig", "/kube/config", "--context", "kind-kne", "apply", "-f", "/srlinux/manifest.yaml"}},
+	}}
+	origExecer := execer
+	defer func() {
+		execer = origExecer
+	}()
+	// The package execer must not be used once the kubectl execer is set.
+	execer = exec.NewFakeExecer(errors.New("package execer used"))
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := &recordExecer{FakeExecer: exec.NewFakeExecer()}
+			setKubectl(tt.spec, &kubectlExecer{execerInterface: r, kubecfg: "/kube/config", context: "kind-kne"})
+			if err := tt.spec.Deploy(context.Background()); err != nil {
+				t.Fatalf("Deploy() failed: %v", err)
+			}
+			if s := cmp.Diff(tt.want, r.cmds); s != "" {
+				t.Fatalf("Deploy() unexpected commands (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestDeploymentKubeContext(t *testing.T) {
+	tests := []struct {
+		desc string
+		d    *Deployment
+		want string
+	}{{
+		desc: "kind",
+		d:    &Deployment{Cluster: &KindSpec{Name: "kne"}},
+		want: "kind-kne",
+	}, {
+		desc: "kind default",
+		d:    &Deployment{Cluster: &KindSpec{}},
+		want: "kind-kind",
+	}, {
+		desc: "minikube",
+		d:    &Deployment{Cluster: &MinikubeSpec{Name: "kne"}},
+		want: "kne",
+	}, {
+		desc: "current context",
+		d:    &Deployment{Cluster: &GKESpec{Name: "kne"}},
+	}, {
+		desc: "explicit",
+		d:    &Deployment{Cluster: &KindSpec{Name: "kne"}, Context: "other"},
+		want: "other",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.d.kubeContext(); got != tt.want {
+				t.Errorf("kubeContext() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
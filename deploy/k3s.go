@@ -0,0 +1,171 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const k3sInstallURL = "https://get.k3s.io"
+
+var (
+	// clusterPollInterval is the interval between health checks of a cluster
+	// being started.
+	clusterPollInterval = time.Second
+	// k3sUninstallScript is installed by k3s to remove it from the host.
+	k3sUninstallScript = "/usr/local/bin/k3s-uninstall.sh"
+)
+
+// K3sSpec deploys a single node k3s cluster on the host. As the cluster is
+// not run in docker, the address pool of the ingress must be set explicitly.
+// The bundled traefik ingress and service load balancer of k3s are disabled so
+// they do not conflict with the ingress of the deployment.
+type K3sSpec struct {
+	// Version is the k3s release to install, e.g. v1.24.3+k3s1. The latest
+	// stable release is installed if empty.
+	Version string `yaml:"version"`
+	// Recycle reuses an existing healthy cluster.
+	Recycle bool `yaml:"recycle"`
+	// Kubecfg is the path the kubeconfig of the cluster is written to. If empty
+	// it is written to /etc/rancher/k3s/k3s.yaml.
+	Kubecfg string `yaml:"kubecfg"`
+	// Wait is the time to wait for the cluster to be healthy, by default 1m.
+	Wait time.Duration `yaml:"wait"`
+	// Args are additional arguments of the k3s server.
+	Args []string `yaml:"args"`
+	// AdditionalManifests are applied after the cluster is healthy.
+	AdditionalManifests []string `yaml:"additionalManifests"`
+}
+
+func (k *K3sSpec) checkDependencies() error {
+	for _, bin := range []string{"curl", "sh"} {
+		if _, err := execLookPath(bin); err != nil {
+			return fmt.Errorf("install dependency %q to deploy", bin)
+		}
+	}
+	return nil
+}
+
+func (k *K3sSpec) create(ctx context.Context) error {
+	if k.Recycle {
+		log.Infof("Attempting to recycle existing k3s cluster...")
+		if err := k.Healthy(); err == nil {
+			log.Infof("Recycling existing k3s cluster")
+			return nil
+		}
+	}
+	dir, err := os.MkdirTemp("", "kne_k3s")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "install.sh")
+	if err := execer.Exec("curl", "-sfL", "-o", script, k3sInstallURL); err != nil {
+		return fmt.Errorf("failed to download k3s install script: %w", err)
+	}
+	var args []string
+	if k.Version != "" {
+		args = append(args, "INSTALL_K3S_VERSION="+k.Version)
+	}
+	args = append(args, "sh", script, "server", "--disable", "traefik", "--disable", "servicelb")
+	if k.Kubecfg != "" {
+		args = append(args, "--write-kubeconfig", k.Kubecfg, "--write-kubeconfig-mode", "644")
+	}
+	args = append(args, k.Args...)
+	log.Infof("Creating k3s cluster with: %v", args)
+	if err := execer.Exec("env", args...); err != nil {
+		return fmt.Errorf("failed to create cluster: %w", err)
+	}
+	wait := k.Wait
+	if wait == 0 {
+		wait = healthTimeout
+	}
+	if err := waitHealthy(ctx, wait, k.Healthy); err != nil {
+		return err
+	}
+	log.Infof("Deployed k3s cluster")
+	return nil
+}
+
+func (k *K3sSpec) Deploy(ctx context.Context) error {
+	if err := k.checkDependencies(); err != nil {
+		return err
+	}
+	if err := k.create(ctx); err != nil {
+		return err
+	}
+	for _, s := range k.AdditionalManifests {
+		log.Infof("Found manifest %q", s)
+		if err := execer.Exec("kubectl", k.kubectlArgs("apply", "-f", s)...); err != nil {
+			return fmt.Errorf("failed to deploy manifest: %w", err)
+		}
+	}
+	return nil
+}
+
+func (k *K3sSpec) Delete() error {
+	if err := execer.Exec(k3sUninstallScript); err != nil {
+		return fmt.Errorf("failed to delete cluster using cli: %w", err)
+	}
+	return nil
+}
+
+func (k *K3sSpec) Healthy() error {
+	if err := execer.Exec("kubectl", k.kubectlArgs("cluster-info")...); err != nil {
+		return fmt.Errorf("cluster not healthy: %w", err)
+	}
+	return nil
+}
+
+func (k *K3sSpec) GetName() string {
+	return "k3s"
+}
+
+// LoadImageArchive imports the images of the archive at path into the
+// containerd of k3s. This requires root privileges.
+func (k *K3sSpec) LoadImageArchive(path string) error {
+	return execer.Exec("k3s", "ctr", "images", "import", path)
+}
+
+// kubectlArgs returns args with the kubeconfig of the cluster, if set.
+func (k *K3sSpec) kubectlArgs(args ...string) []string {
+	if k.Kubecfg != "" {
+		args = append(args, "--kubeconfig", k.Kubecfg)
+	}
+	return args
+}
+
+// waitHealthy waits up to timeout for healthy to succeed.
+func waitHealthy(ctx context.Context, timeout time.Duration, healthy func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		err := healthy()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(clusterPollInterval):
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/h-fam/errdiff"
+	"github.com/openconfig/kne/os/exec"
+)
+
+func TestK3sSpec(t *testing.T) {
+	ctx := context.Background()
+	origInterval := clusterPollInterval
+	defer func() {
+		clusterPollInterval = origInterval
+	}()
+	clusterPollInterval = time.Millisecond
+
+	tests := []struct {
+		desc        string
+		k           *K3sSpec
+		execer      execerInterface
+		execPathErr bool
+		wantErr     string
+	}{{
+		desc: "create cluster",
+		k: &K3sSpec{
+			Version: "v1.24.3+k3s1",
+			Kubecfg: "/tmp/k3s.yaml",
+		},
+		// download, install, cluster-info
+		execer: exec.NewFakeExecer(nil, nil, nil),
+	}, {
+		desc: "create cluster with recycle",
+		k: &K3sSpec{
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(errors.New("no cluster"), nil, nil, nil),
+	}, {
+		desc: "exists cluster with recycle",
+		k: &K3sSpec{
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(nil),
+	}, {
+		desc: "create cluster with manifests",
+		k: &K3sSpec{
+			AdditionalManifests: []string{"manifest.yaml"},
+		},
+		execer: exec.NewFakeExecer(nil, nil, nil, nil),
+	}, {
+		desc:   "cluster healthy after retry",
+		k:      &K3sSpec{},
+		execer: exec.NewFakeExecer(nil, nil, errors.New("not ready"), nil),
+	}, {
+		desc: "cluster not healthy",
+		k: &K3sSpec{
+			Wait: 10 * time.Millisecond,
+		},
+		// Once the responses run out all health checks fail.
+		execer:  exec.NewFakeExecer(nil, nil, errors.New("not ready")),
+		wantErr: "cluster not healthy",
+	}, {
+		desc:        "unable to find curl",
+		k:           &K3sSpec{},
+		execPathErr: true,
+		wantErr:     `install dependency "curl" to deploy`,
+	}, {
+		desc:    "download fail",
+		k:       &K3sSpec{},
+		execer:  exec.NewFakeExecer(errors.New("cmd failed")),
+		wantErr: "failed to download k3s install script",
+	}, {
+		desc:    "install fail",
+		k:       &K3sSpec{},
+		execer:  exec.NewFakeExecer(nil, errors.New("cmd failed")),
+		wantErr: "failed to create cluster",
+	}, {
+		desc: "manifest fail",
+		k: &K3sSpec{
+			AdditionalManifests: []string{"manifest.yaml"},
+		},
+		execer:  exec.NewFakeExecer(nil, nil, nil, errors.New("cmd failed")),
+		wantErr: "failed to deploy manifest",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if tt.execer != nil {
+				execer = tt.execer
+			}
+			execLookPath = func(_ string) (string, error) {
+				if tt.execPathErr {
+					return "", errors.New("unable to find on path")
+				}
+				return "fakePath", nil
+			}
+			err := tt.k.Deploy(ctx)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	metallbv1 "go.universe.tf/metallb/api/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// Manifester is implemented by the components of a deployment whose manifests
+// can be rendered without deploying them.
+type Manifester interface {
+	Manifests(context.Context) ([]byte, error)
+}
+
+// Manifests writes the manifests the deployment would apply to the cluster to
+// w as a multi document YAML stream, in the order they are applied. Nothing is
+// deployed. The cluster itself is not rendered.
+func (d *Deployment) Manifests(ctx context.Context, w io.Writer) error {
+	d.setIngressNetwork()
+	var components []interface{}
+	if d.Ingress != nil {
+		components = append(components, d.Ingress)
+	}
+	if d.CNI != nil {
+		components = append(components, d.CNI)
+	}
+	for _, c := range d.Controllers {
+		components = append(components, c)
+	}
+	var docs [][]byte
+	for _, c := range components {
+		m, ok := c.(Manifester)
+		if !ok {
+			return fmt.Errorf("manifests of %T cannot be rendered", c)
+		}
+		b, err := m.Manifests(ctx)
+		if err != nil {
+			return err
+		}
+		if len(b) != 0 {
+			docs = append(docs, b)
+		}
+	}
+	_, err := w.Write(joinManifests(docs...))
+	return err
+}
+
+// joinManifests joins the YAML documents of docs into a single stream.
+func joinManifests(docs ...[]byte) []byte {
+	var buf bytes.Buffer
+	for i, d := range docs {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(d)
+		if len(d) > 0 && d[len(d)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+func readManifest(dir, name string) ([]byte, error) {
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return joinManifests(b), nil
+}
+
+// Manifests returns the manifests of metallb and of the service address pool
+// of the kind docker network. The memberlist secret is not included as its
+// key is generated at deployment.
+func (m *MetalLBSpec) Manifests(ctx context.Context) ([]byte, error) {
+	b, err := readManifest(m.ManifestDir, "metallb-native.yaml")
+	if err != nil {
+		return nil, err
+	}
+	pool, err := m.addressPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pool.TypeMeta.APIVersion = metallbv1.GroupVersion.String()
+	pool.TypeMeta.Kind = "IPAddressPool"
+	pb, err := yaml.Marshal(pool)
+	if err != nil {
+		return nil, err
+	}
+	l2 := l2Advertisement()
+	l2.TypeMeta.APIVersion = metallbv1.GroupVersion.String()
+	l2.TypeMeta.Kind = "L2Advertisement"
+	lb, err := yaml.Marshal(l2)
+	if err != nil {
+		return nil, err
+	}
+	return joinManifests(b, pb, lb), nil
+}
+
+// Manifests returns the manifests of meshnet.
+func (m *MeshnetSpec) Manifests(ctx context.Context) ([]byte, error) {
+	return readManifest(m.ManifestDir, "manifest.yaml")
+}
+
+// Manifests returns the manifests of the CEOSLab controller.
+func (c *CEOSLabSpec) Manifests(ctx context.Context) ([]byte, error) {
+	return readManifest(c.ManifestDir, "manifest.yaml")
+}
+
+// Manifests returns the manifests of the KNE operator.
+func (k *KNESpec) Manifests(ctx context.Context) ([]byte, error) {
+	return readManifest(k.ManifestDir, "manifest.yaml")
+}
+
+// Manifests returns the manifests of the SRLinux controller.
+func (s *SRLinuxSpec) Manifests(ctx context.Context) ([]byte, error) {
+	return readManifest(s.ManifestDir, "manifest.yaml")
+}
+
+// Manifests returns the manifests of the IxiaTG controller and its config map,
+// if one is configured or found in the manifest directory.
+func (i *IxiaTGSpec) Manifests(ctx context.Context) ([]byte, error) {
+	b, err := readManifest(i.ManifestDir, "ixiatg-operator.yaml")
+	if err != nil {
+		return nil, err
+	}
+	if i.ConfigMap == nil {
+		path := filepath.Join(i.ManifestDir, "ixia-configmap.yaml")
+		if _, err := osStat(path); err != nil {
+			return b, nil
+		}
+		cb, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return joinManifests(b, cb), nil
+	}
+	cb, err := i.configMapYAML()
+	if err != nil {
+		return nil, err
+	}
+	return joinManifests(b, cb), nil
+}
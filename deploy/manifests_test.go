@@ -0,0 +1,215 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	"github.com/openconfig/kne/deploy/mocks"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// noManifestsIngress is an ingress whose manifests cannot be rendered.
+type noManifestsIngress struct{}
+
+func (noManifestsIngress) Deploy(context.Context) error    { return nil }
+func (noManifestsIngress) SetKClient(kubernetes.Interface) {}
+func (noManifestsIngress) SetRCfg(*rest.Config)            {}
+func (noManifestsIngress) Healthy(context.Context) error   { return nil }
+
+func writeManifests(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, s := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(s), 0644); err != nil {
+			t.Fatalf("failed to write manifest %q: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestManifests(t *testing.T) {
+	nl := []dtypes.NetworkResource{{
+		Name: "kind",
+		IPAM: network.IPAM{
+			Config: []network.IPAMConfig{{
+				Subnet: "172.18.0.0/16",
+			}},
+		},
+	}}
+	metallbDir := writeManifests(t, map[string]string{"metallb-native.yaml": "kind: Namespace\nmetadata:\n  name: metallb-system"})
+	meshnetDir := writeManifests(t, map[string]string{"manifest.yaml": "kind: DaemonSet\nmetadata:\n  name: meshnet\n"})
+	kneDir := writeManifests(t, map[string]string{"manifest.yaml": "kind: Deployment\nmetadata:\n  name: kne-operator\n"})
+	ixiaDir := writeManifests(t, map[string]string{
+		"ixiatg-operator.yaml": "kind: Deployment\nmetadata:\n  name: ixiatg-op\n",
+		"ixia-configmap.yaml":  "kind: ConfigMap\nmetadata:\n  name: ixiatg-release-config\n",
+	})
+	wantPool := `---
+apiVersion: metallb.io/v1beta1
+kind: IPAddressPool
+metadata:
+  creationTimestamp: null
+  name: kne-service-pool
+  namespace: metallb-system
+spec:
+  addresses:
+  - 172.18.0.50 - 172.18.0.60
+status: {}
+---
+apiVersion: metallb.io/v1beta1
+kind: L2Advertisement
+metadata:
+  creationTimestamp: null
+  name: kne-l2-service-pool
+  namespace: metallb-system
+spec:
+  ipAddressPools:
+  - kne-service-pool
+status: {}
+`
+	tests := []struct {
+		desc        string
+		d           *Deployment
+		mockExpects func(*mocks.MockNetworkAPIClient)
+		want        string
+		wantErr     string
+	}{{
+		desc: "deployment",
+		d: &Deployment{
+			Ingress: &MetalLBSpec{ManifestDir: metallbDir, IPCount: 10},
+			CNI:     &MeshnetSpec{ManifestDir: meshnetDir},
+			Controllers: []Controller{
+				&KNESpec{ManifestDir: kneDir},
+				&IxiaTGSpec{ManifestDir: ixiaDir},
+			},
+		},
+		mockExpects: func(m *mocks.MockNetworkAPIClient) {
+			m.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return(nl, nil)
+		},
+		want: "kind: Namespace\nmetadata:\n  name: metallb-system\n" + wantPool +
+			"---\nkind: DaemonSet\nmetadata:\n  name: meshnet\n" +
+			"---\nkind: Deployment\nmetadata:\n  name: kne-operator\n" +
+			"---\nkind: Deployment\nmetadata:\n  name: ixiatg-op\n" +
+			"---\nkind: ConfigMap\nmetadata:\n  name: ixiatg-release-config\n",
+	}, {
+		desc: "ixiatg config map",
+		d: &Deployment{
+			Controllers: []Controller{
+				&IxiaTGSpec{
+					ManifestDir: writeManifests(t, map[string]string{"ixiatg-operator.yaml": "kind: Deployment\n"}),
+					ConfigMap:   &IxiaTGConfigMap{Release: "0.0.1-9999"},
+				},
+			},
+		},
+		want: "kind: Deployment\n---\n" + ixiaTGConfigMapHeader + `{
+      "release": "0.0.1-9999",
+      "images": null
+    }
+`,
+	}, {
+		desc: "addresses",
+		d: &Deployment{
+			Ingress: &MetalLBSpec{ManifestDir: metallbDir, Addresses: []string{"10.0.0.50-10.0.0.60"}},
+		},
+		want: "kind: Namespace\nmetadata:\n  name: metallb-system\n" + strings.Replace(wantPool, "172.18.0.50 - 172.18.0.60", "10.0.0.50-10.0.0.60", 1),
+	}, {
+		desc: "minikube network",
+		d: &Deployment{
+			Cluster: &MinikubeSpec{Name: "kne"},
+			Ingress: &MetalLBSpec{ManifestDir: metallbDir, IPCount: 10},
+		},
+		mockExpects: func(m *mocks.MockNetworkAPIClient) {
+			m.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return([]dtypes.NetworkResource{{
+				Name: "kne",
+				IPAM: network.IPAM{
+					Config: []network.IPAMConfig{{
+						Subnet: "192.168.49.0/24",
+					}},
+				},
+			}}, nil)
+		},
+		want: "kind: Namespace\nmetadata:\n  name: metallb-system\n" + strings.Replace(wantPool, "172.18.0.50 - 172.18.0.60", "192.168.49.50 - 192.168.49.60", 1),
+	}, {
+		desc: "cloud load balancer",
+		d: &Deployment{
+			Ingress: &CloudLBSpec{},
+			CNI:     &MeshnetSpec{ManifestDir: meshnetDir},
+		},
+		want: "kind: DaemonSet\nmetadata:\n  name: meshnet\n",
+	}, {
+		desc: "no kind network",
+		d: &Deployment{
+			Ingress: &MetalLBSpec{ManifestDir: metallbDir, IPCount: 10},
+		},
+		mockExpects: func(m *mocks.MockNetworkAPIClient) {
+			m.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return(nil, nil)
+		},
+		wantErr: "failed to find kind ipv4 docker net",
+	}, {
+		desc: "docker error",
+		d: &Deployment{
+			Ingress: &MetalLBSpec{ManifestDir: metallbDir, IPCount: 10},
+		},
+		mockExpects: func(m *mocks.MockNetworkAPIClient) {
+			m.EXPECT().NetworkList(gomock.Any(), gomock.Any()).Return(nil, errors.New("docker error"))
+		},
+		wantErr: "docker error",
+	}, {
+		desc: "missing manifest",
+		d: &Deployment{
+			CNI: &MeshnetSpec{ManifestDir: t.TempDir()},
+		},
+		wantErr: "no such file",
+	}, {
+		desc: "not renderable",
+		d: &Deployment{
+			Ingress: noManifestsIngress{},
+		},
+		wantErr: "cannot be rendered",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if tt.mockExpects != nil {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+				m := mocks.NewMockNetworkAPIClient(mockCtrl)
+				tt.mockExpects(m)
+				tt.d.Ingress.(*MetalLBSpec).dClient = m
+			}
+			var sb strings.Builder
+			err := tt.d.Manifests(context.Background(), &sb)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if s := cmp.Diff(tt.want, sb.String()); s != "" {
+				t.Fatalf("Manifests() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmi/errlist"
+	log "github.com/sirupsen/logrus"
+)
+
+// MinikubeSpec deploys a minikube cluster. With the docker driver the address
+// pool of the ingress defaults to the docker network of the cluster.
+type MinikubeSpec struct {
+	// Name is the minikube profile of the cluster, by default minikube.
+	Name string `yaml:"name"`
+	// Recycle reuses an existing healthy cluster.
+	Recycle bool `yaml:"recycle"`
+	// Version is the minimum version of minikube.
+	Version string `yaml:"version"`
+	// KubernetesVersion is the version of kubernetes run by the cluster.
+	KubernetesVersion string `yaml:"kubernetesVersion"`
+	// Driver is the minikube driver, by default docker.
+	Driver string `yaml:"driver"`
+	// CNI is the base CNI of the cluster meshnet is chained with, by default
+	// bridge.
+	CNI    string `yaml:"cni"`
+	Nodes  int    `yaml:"nodes"`
+	CPUs   int    `yaml:"cpus"`
+	Memory string `yaml:"memory"`
+	// Wait is the time to wait for the cluster components to be healthy.
+	Wait time.Duration `yaml:"wait"`
+	// Args are additional arguments of minikube start.
+	Args                []string          `yaml:"args"`
+	ContainerImages     map[string]string `yaml:"containerImages"`
+	AdditionalManifests []string          `yaml:"additionalManifests"`
+}
+
+func (m *MinikubeSpec) checkDependencies() error {
+	var errs errlist.List
+	bins := []string{"minikube"}
+	if len(m.ContainerImages) != 0 {
+		bins = append(bins, "docker")
+	}
+	for _, bin := range bins {
+		if _, err := execLookPath(bin); err != nil {
+			errs.Add(fmt.Errorf("install dependency %q to deploy", bin))
+		}
+	}
+	if errs.Err() != nil {
+		return errs.Err()
+	}
+	if m.Version != "" {
+		wantV, err := getVersion(m.Version)
+		if err != nil {
+			return err
+		}
+		if err := vExec.Exec("minikube", "version", "--short"); err != nil {
+			return fmt.Errorf("failed to get minikube version: %w", err)
+		}
+		// Reset buffer for next read
+		defer vOut.Reset()
+		gotV, err := getVersion(strings.TrimSpace(vOut.String()))
+		if err != nil {
+			return fmt.Errorf("minikube version check failed: %w", err)
+		}
+		if gotV.Less(wantV) {
+			return fmt.Errorf("minikube version check failed: got %s, want %s", gotV, wantV)
+		}
+		log.Infof("minikube version valid: got %s want %s", gotV, wantV)
+	}
+	return nil
+}
+
+func (m *MinikubeSpec) create() error {
+	if m.Recycle {
+		log.Infof("Attempting to recycle existing cluster %q...", m.GetName())
+		if err := m.Healthy(); err == nil {
+			log.Infof("Recycling existing cluster %q", m.GetName())
+			return nil
+		}
+	}
+	cni := m.CNI
+	if cni == "" {
+		cni = "bridge"
+	}
+	args := []string{"start", "--profile", m.GetName(), "--driver", m.driver(), "--cni", cni}
+	if m.KubernetesVersion != "" {
+		args = append(args, "--kubernetes-version", m.KubernetesVersion)
+	}
+	if m.Nodes != 0 {
+		args = append(args, "--nodes", strconv.Itoa(m.Nodes))
+	}
+	if m.CPUs != 0 {
+		args = append(args, "--cpus", strconv.Itoa(m.CPUs))
+	}
+	if m.Memory != "" {
+		args = append(args, "--memory", m.Memory)
+	}
+	if m.Wait != 0 {
+		args = append(args, "--wait", "all", "--wait-timeout", m.Wait.String())
+	}
+	args = append(args, m.Args...)
+	log.Infof("Creating minikube cluster with: %v", args)
+	if err := execer.Exec("minikube", args...); err != nil {
+		return fmt.Errorf("failed to create cluster: %w", err)
+	}
+	log.Infof("Deployed minikube cluster: %s", m.GetName())
+	return nil
+}
+
+func (m *MinikubeSpec) Deploy(ctx context.Context) error {
+	if err := m.checkDependencies(); err != nil {
+		return err
+	}
+	if err := m.create(); err != nil {
+		return err
+	}
+	for _, s := range m.AdditionalManifests {
+		log.Infof("Found manifest %q", s)
+		if err := execer.Exec("kubectl", "apply", "--context", m.GetName(), "-f", s); err != nil {
+			return fmt.Errorf("failed to deploy manifest: %w", err)
+		}
+	}
+	if len(m.ContainerImages) != 0 {
+		log.Infof("Loading container images")
+		if err := loadContainerImages(m.ContainerImages, func(image string) error {
+			return execer.Exec("minikube", "image", "load", "--profile", m.GetName(), image)
+		}); err != nil {
+			return fmt.Errorf("failed to load container images: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *MinikubeSpec) Delete() error {
+	if err := execer.Exec("minikube", "delete", "--profile", m.GetName()); err != nil {
+		return fmt.Errorf("failed to delete cluster using cli: %w", err)
+	}
+	return nil
+}
+
+func (m *MinikubeSpec) Healthy() error {
+	if err := execer.Exec("kubectl", "cluster-info", "--context", m.GetName()); err != nil {
+		return fmt.Errorf("cluster not healthy: %w", err)
+	}
+	return nil
+}
+
+func (m *MinikubeSpec) GetName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return "minikube"
+}
+
+// LoadImageArchive loads the images of the archive at path into the nodes of
+// the cluster.
+func (m *MinikubeSpec) LoadImageArchive(path string) error {
+	return execer.Exec("minikube", "image", "load", "--profile", m.GetName(), path)
+}
+
+func (m *MinikubeSpec) kubeContext() string {
+	return m.GetName()
+}
+
+func (m *MinikubeSpec) driver() string {
+	if m.Driver != "" {
+		return m.Driver
+	}
+	return "docker"
+}
+
+// dockerNetwork returns the docker network of the cluster, empty if it is not
+// run in docker.
+func (m *MinikubeSpec) dockerNetwork() string {
+	if m.driver() != "docker" {
+		return ""
+	}
+	return m.GetName()
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/h-fam/errdiff"
+	"github.com/openconfig/kne/os/exec"
+)
+
+func TestMinikubeSpec(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		desc        string
+		m           *MinikubeSpec
+		execer      execerInterface
+		vExecer     execerInterface
+		execPathErr bool
+		wantErr     string
+	}{{
+		desc: "create cluster",
+		m: &MinikubeSpec{
+			Name:              "test",
+			KubernetesVersion: "v1.24.3",
+			Nodes:             2,
+			CPUs:              4,
+			Memory:            "8g",
+		},
+		execer: exec.NewFakeExecer(nil),
+	}, {
+		desc: "create cluster with recycle",
+		m: &MinikubeSpec{
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(errors.New("no cluster"), nil),
+	}, {
+		desc: "exists cluster with recycle",
+		m: &MinikubeSpec{
+			Recycle: true,
+		},
+		execer: exec.NewFakeExecer(nil),
+	}, {
+		desc: "create cluster with manifests and images",
+		m: &MinikubeSpec{
+			AdditionalManifests: []string{"manifest.yaml"},
+			ContainerImages: map[string]string{
+				"source": "dest",
+			},
+		},
+		// start, apply, pull, tag, load
+		execer: exec.NewFakeExecer(nil, nil, nil, nil, nil),
+	}, {
+		desc:        "unable to find minikube cli",
+		m:           &MinikubeSpec{},
+		execPathErr: true,
+		wantErr:     `install dependency "minikube" to deploy`,
+	}, {
+		desc:    "create cluster fail",
+		m:       &MinikubeSpec{},
+		execer:  exec.NewFakeExecer(errors.New("cmd failed")),
+		wantErr: "failed to create cluster",
+	}, {
+		desc: "manifest fail",
+		m: &MinikubeSpec{
+			AdditionalManifests: []string{"manifest.yaml"},
+		},
+		execer:  exec.NewFakeExecer(nil, errors.New("cmd failed")),
+		wantErr: "failed to deploy manifest",
+	}, {
+		desc: "image load fail",
+		m: &MinikubeSpec{
+			ContainerImages: map[string]string{
+				"source": "",
+			},
+		},
+		execer:  exec.NewFakeExecer(nil, nil, errors.New("cmd failed")),
+		wantErr: "failed to load container images",
+	}, {
+		desc: "minikube version pass",
+		m: &MinikubeSpec{
+			Version: "v1.26.0",
+		},
+		execer:  exec.NewFakeExecer(nil),
+		vExecer: exec.NewFakeExecerWithIO(vOut, vOut, exec.Response{Stdout: "v1.26.1\n"}),
+	}, {
+		desc: "minikube version fail",
+		m: &MinikubeSpec{
+			Version: "v1.26.0",
+		},
+		vExecer: exec.NewFakeExecerWithIO(vOut, vOut, exec.Response{Stdout: "v1.25.2\n"}),
+		wantErr: "minikube version check failed: got v1.25.2, want v1.26.0",
+	}, {
+		desc: "minikube version exec fail",
+		m: &MinikubeSpec{
+			Version: "v1.26.0",
+		},
+		vExecer: exec.NewFakeExecerWithIO(vOut, vOut, exec.Response{Err: errors.New("cmd failed")}),
+		wantErr: "failed to get minikube version",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if tt.execer != nil {
+				execer = tt.execer
+			}
+			if tt.vExecer != nil {
+				vExec = tt.vExecer
+			}
+			execLookPath = func(_ string) (string, error) {
+				if tt.execPathErr {
+					return "", errors.New("unable to find on path")
+				}
+				return "fakePath", nil
+			}
+			err := tt.m.Deploy(ctx)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+		})
+	}
+}
+
+func TestMinikubeDockerNetwork(t *testing.T) {
+	tests := []struct {
+		desc string
+		m    *MinikubeSpec
+		want string
+	}{{
+		desc: "default",
+		m:    &MinikubeSpec{},
+		want: "minikube",
+	}, {
+		desc: "profile",
+		m:    &MinikubeSpec{Name: "kne"},
+		want: "kne",
+	}, {
+		desc: "not docker",
+		m:    &MinikubeSpec{Name: "kne", Driver: "kvm2"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.m.dockerNetwork(); got != tt.want {
+				t.Fatalf("dockerNetwork() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,930 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.18.1
+// source: bind.proto
+
+package bind
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Device_Vendor int32
+
+const (
+	Device_VENDOR_UNSPECIFIED Device_Vendor = 0
+	Device_ADVA               Device_Vendor = 1
+	Device_ARISTA             Device_Vendor = 2
+	Device_CIENA              Device_Vendor = 3
+	Device_CISCO              Device_Vendor = 4
+	Device_DELL               Device_Vendor = 5
+	Device_JUNIPER            Device_Vendor = 6
+	Device_IXIA               Device_Vendor = 7
+	Device_NOKIA              Device_Vendor = 8
+	Device_OPENCONFIG         Device_Vendor = 9
+)
+
+// Enum value maps for Device_Vendor.
+var (
+	Device_Vendor_name = map[int32]string{
+		0: "VENDOR_UNSPECIFIED",
+		1: "ADVA",
+		2: "ARISTA",
+		3: "CIENA",
+		4: "CISCO",
+		5: "DELL",
+		6: "JUNIPER",
+		7: "IXIA",
+		8: "NOKIA",
+		9: "OPENCONFIG",
+	}
+	Device_Vendor_value = map[string]int32{
+		"VENDOR_UNSPECIFIED": 0,
+		"ADVA":               1,
+		"ARISTA":             2,
+		"CIENA":              3,
+		"CISCO":              4,
+		"DELL":               5,
+		"JUNIPER":            6,
+		"IXIA":               7,
+		"NOKIA":              8,
+		"OPENCONFIG":         9,
+	}
+)
+
+func (x Device_Vendor) Enum() *Device_Vendor {
+	p := new(Device_Vendor)
+	*p = x
+	return p
+}
+
+func (x Device_Vendor) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Device_Vendor) Descriptor() protoreflect.EnumDescriptor {
+	return file_bind_proto_enumTypes[0].Descriptor()
+}
+
+func (Device_Vendor) Type() protoreflect.EnumType {
+	return &file_bind_proto_enumTypes[0]
+}
+
+func (x Device_Vendor) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Device_Vendor.Descriptor instead.
+func (Device_Vendor) EnumDescriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{1, 0}
+}
+
+// Testbed is an Ondatra testbed: the devices and links a test requires.
+type Testbed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duts  []*Device `protobuf:"bytes,1,rep,name=duts,proto3" json:"duts,omitempty"`
+	Ates  []*Device `protobuf:"bytes,2,rep,name=ates,proto3" json:"ates,omitempty"`
+	Links []*Link   `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+}
+
+func (x *Testbed) Reset() {
+	*x = Testbed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Testbed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Testbed) ProtoMessage() {}
+
+func (x *Testbed) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Testbed.ProtoReflect.Descriptor instead.
+func (*Testbed) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Testbed) GetDuts() []*Device {
+	if x != nil {
+		return x.Duts
+	}
+	return nil
+}
+
+func (x *Testbed) GetAtes() []*Device {
+	if x != nil {
+		return x.Ates
+	}
+	return nil
+}
+
+func (x *Testbed) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+// Device is a device of a testbed.
+type Device struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vendor          Device_Vendor `protobuf:"varint,2,opt,name=vendor,proto3,enum=bind.Device_Vendor" json:"vendor,omitempty"`
+	HardwareModel   string        `protobuf:"bytes,3,opt,name=hardware_model,json=hardwareModel,proto3" json:"hardware_model,omitempty"`
+	SoftwareVersion string        `protobuf:"bytes,4,opt,name=software_version,json=softwareVersion,proto3" json:"software_version,omitempty"`
+	Ports           []*Port       `protobuf:"bytes,5,rep,name=ports,proto3" json:"ports,omitempty"`
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Device) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Device) GetVendor() Device_Vendor {
+	if x != nil {
+		return x.Vendor
+	}
+	return Device_VENDOR_UNSPECIFIED
+}
+
+func (x *Device) GetHardwareModel() string {
+	if x != nil {
+		return x.HardwareModel
+	}
+	return ""
+}
+
+func (x *Device) GetSoftwareVersion() string {
+	if x != nil {
+		return x.SoftwareVersion
+	}
+	return ""
+}
+
+func (x *Device) GetPorts() []*Port {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+// Port is a port of a testbed device.
+type Port struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *Port) Reset() {
+	*x = Port{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Port) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Port) ProtoMessage() {}
+
+func (x *Port) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Port.ProtoReflect.Descriptor instead.
+func (*Port) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Port) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Link is a link between two ports of the testbed, each as "<device>:<port>".
+type Link struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	A string `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B string `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Link) GetA() string {
+	if x != nil {
+		return x.A
+	}
+	return ""
+}
+
+func (x *Link) GetB() string {
+	if x != nil {
+		return x.B
+	}
+	return ""
+}
+
+// Binding binds the devices of a testbed to the nodes of a topology.
+type Binding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duts []*BoundDevice `protobuf:"bytes,1,rep,name=duts,proto3" json:"duts,omitempty"`
+	Ates []*BoundDevice `protobuf:"bytes,2,rep,name=ates,proto3" json:"ates,omitempty"`
+	// Default options of all devices.
+	Options *Options `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *Binding) Reset() {
+	*x = Binding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Binding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Binding) ProtoMessage() {}
+
+func (x *Binding) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Binding.ProtoReflect.Descriptor instead.
+func (*Binding) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Binding) GetDuts() []*BoundDevice {
+	if x != nil {
+		return x.Duts
+	}
+	return nil
+}
+
+func (x *Binding) GetAtes() []*BoundDevice {
+	if x != nil {
+		return x.Ates
+	}
+	return nil
+}
+
+func (x *Binding) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// BoundDevice is a device of the testbed bound to a node.
+type BoundDevice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`     // ID of the device in the testbed.
+	Name    string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // Name of the node.
+	Options *Options     `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	Ssh     *Options     `protobuf:"bytes,4,opt,name=ssh,proto3" json:"ssh,omitempty"`
+	Gnmi    *Options     `protobuf:"bytes,5,opt,name=gnmi,proto3" json:"gnmi,omitempty"`
+	Gnoi    *Options     `protobuf:"bytes,6,opt,name=gnoi,proto3" json:"gnoi,omitempty"`
+	Gribi   *Options     `protobuf:"bytes,7,opt,name=gribi,proto3" json:"gribi,omitempty"`
+	P4Rt    *Options     `protobuf:"bytes,8,opt,name=p4rt,proto3" json:"p4rt,omitempty"`
+	Otg     *Options     `protobuf:"bytes,9,opt,name=otg,proto3" json:"otg,omitempty"`
+	Ports   []*BoundPort `protobuf:"bytes,10,rep,name=ports,proto3" json:"ports,omitempty"`
+}
+
+func (x *BoundDevice) Reset() {
+	*x = BoundDevice{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BoundDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoundDevice) ProtoMessage() {}
+
+func (x *BoundDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoundDevice.ProtoReflect.Descriptor instead.
+func (*BoundDevice) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BoundDevice) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BoundDevice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BoundDevice) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *BoundDevice) GetSsh() *Options {
+	if x != nil {
+		return x.Ssh
+	}
+	return nil
+}
+
+func (x *BoundDevice) GetGnmi() *Options {
+	if x != nil {
+		return x.Gnmi
+	}
+	return nil
+}
+
+func (x *BoundDevice) GetGnoi() *Options {
+	if x != nil {
+		return x.Gnoi
+	}
+	return nil
+}
+
+func (x *BoundDevice) GetGribi() *Options {
+	if x != nil {
+		return x.Gribi
+	}
+	return nil
+}
+
+func (x *BoundDevice) GetP4Rt() *Options {
+	if x != nil {
+		return x.P4Rt
+	}
+	return nil
+}
+
+func (x *BoundDevice) GetOtg() *Options {
+	if x != nil {
+		return x.Otg
+	}
+	return nil
+}
+
+func (x *BoundDevice) GetPorts() []*BoundPort {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+// BoundPort is a port of the testbed bound to an interface of the node.
+type BoundPort struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`     // ID of the port in the testbed.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // Name of the interface on the node.
+}
+
+func (x *BoundPort) Reset() {
+	*x = BoundPort{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BoundPort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoundPort) ProtoMessage() {}
+
+func (x *BoundPort) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoundPort.ProtoReflect.Descriptor instead.
+func (*BoundPort) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BoundPort) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BoundPort) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Options are the dial options of a device or service.
+type Options struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target     string `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"` // Address of the service as host:port.
+	Username   string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password   string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Insecure   bool   `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	SkipVerify bool   `protobuf:"varint,5,opt,name=skip_verify,json=skipVerify,proto3" json:"skip_verify,omitempty"`
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bind_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_bind_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_bind_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Options) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Options) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Options) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *Options) GetInsecure() bool {
+	if x != nil {
+		return x.Insecure
+	}
+	return false
+}
+
+func (x *Options) GetSkipVerify() bool {
+	if x != nil {
+		return x.SkipVerify
+	}
+	return false
+}
+
+var File_bind_proto protoreflect.FileDescriptor
+
+var file_bind_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x62, 0x69,
+	0x6e, 0x64, 0x22, 0x6f, 0x0a, 0x07, 0x54, 0x65, 0x73, 0x74, 0x62, 0x65, 0x64, 0x12, 0x20, 0x0a,
+	0x04, 0x64, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x62, 0x69,
+	0x6e, 0x64, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x04, 0x64, 0x75, 0x74, 0x73, 0x12,
+	0x20, 0x0a, 0x04, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x62, 0x69, 0x6e, 0x64, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x04, 0x61, 0x74, 0x65,
+	0x73, 0x12, 0x20, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x6c, 0x69,
+	0x6e, 0x6b, 0x73, 0x22, 0xc4, 0x02, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2b,
+	0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
+	0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x56, 0x65, 0x6e,
+	0x64, 0x6f, 0x72, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x68,
+	0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x4d, 0x6f, 0x64,
+	0x65, 0x6c, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x6f,
+	0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a,
+	0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62,
+	0x69, 0x6e, 0x64, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x22,
+	0x88, 0x01, 0x0a, 0x06, 0x56, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x12, 0x56, 0x45,
+	0x4e, 0x44, 0x4f, 0x52, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x41, 0x44, 0x56, 0x41, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06,
+	0x41, 0x52, 0x49, 0x53, 0x54, 0x41, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x49, 0x45, 0x4e,
+	0x41, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x49, 0x53, 0x43, 0x4f, 0x10, 0x04, 0x12, 0x08,
+	0x0a, 0x04, 0x44, 0x45, 0x4c, 0x4c, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x4a, 0x55, 0x4e, 0x49,
+	0x50, 0x45, 0x52, 0x10, 0x06, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x58, 0x49, 0x41, 0x10, 0x07, 0x12,
+	0x09, 0x0a, 0x05, 0x4e, 0x4f, 0x4b, 0x49, 0x41, 0x10, 0x08, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x50,
+	0x45, 0x4e, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x09, 0x22, 0x16, 0x0a, 0x04, 0x50, 0x6f,
+	0x72, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x22, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x0c, 0x0a, 0x01, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x62, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x01, 0x62, 0x22, 0x80, 0x01, 0x0a, 0x07, 0x42, 0x69, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x25, 0x0a, 0x04, 0x64, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x52, 0x04, 0x64, 0x75, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x04, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x42,
+	0x6f, 0x75, 0x6e, 0x64, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x04, 0x61, 0x74, 0x65, 0x73,
+	0x12, 0x27, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xd1, 0x02, 0x0a, 0x0b, 0x42, 0x6f,
+	0x75, 0x6e, 0x64, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a,
+	0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x03, 0x73, 0x73, 0x68, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x03, 0x73, 0x73, 0x68, 0x12, 0x21, 0x0a, 0x04, 0x67, 0x6e, 0x6d, 0x69, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x04, 0x67, 0x6e, 0x6d, 0x69, 0x12, 0x21, 0x0a, 0x04, 0x67, 0x6e,
+	0x6f, 0x69, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x04, 0x67, 0x6e, 0x6f, 0x69, 0x12, 0x23, 0x0a,
+	0x05, 0x67, 0x72, 0x69, 0x62, 0x69, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62,
+	0x69, 0x6e, 0x64, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x05, 0x67, 0x72, 0x69,
+	0x62, 0x69, 0x12, 0x21, 0x0a, 0x04, 0x70, 0x34, 0x72, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x04, 0x70, 0x34, 0x72, 0x74, 0x12, 0x1f, 0x0a, 0x03, 0x6f, 0x74, 0x67, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x03, 0x6f, 0x74, 0x67, 0x12, 0x25, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x69, 0x6e, 0x64, 0x2e, 0x42, 0x6f, 0x75,
+	0x6e, 0x64, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x2f, 0x0a,
+	0x09, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x96,
+	0x01, 0x0a, 0x07, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e,
+	0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x6e,
+	0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x76,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x6b, 0x69,
+	0x70, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2f, 0x6b, 0x6e, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x69, 0x6e, 0x64, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_bind_proto_rawDescOnce sync.Once
+	file_bind_proto_rawDescData = file_bind_proto_rawDesc
+)
+
+func file_bind_proto_rawDescGZIP() []byte {
+	file_bind_proto_rawDescOnce.Do(func() {
+		file_bind_proto_rawDescData = protoimpl.X.CompressGZIP(file_bind_proto_rawDescData)
+	})
+	return file_bind_proto_rawDescData
+}
+
+var file_bind_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_bind_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_bind_proto_goTypes = []interface{}{
+	(Device_Vendor)(0),  // 0: bind.Device.Vendor
+	(*Testbed)(nil),     // 1: bind.Testbed
+	(*Device)(nil),      // 2: bind.Device
+	(*Port)(nil),        // 3: bind.Port
+	(*Link)(nil),        // 4: bind.Link
+	(*Binding)(nil),     // 5: bind.Binding
+	(*BoundDevice)(nil), // 6: bind.BoundDevice
+	(*BoundPort)(nil),   // 7: bind.BoundPort
+	(*Options)(nil),     // 8: bind.Options
+}
+var file_bind_proto_depIdxs = []int32{
+	2,  // 0: bind.Testbed.duts:type_name -> bind.Device
+	2,  // 1: bind.Testbed.ates:type_name -> bind.Device
+	4,  // 2: bind.Testbed.links:type_name -> bind.Link
+	0,  // 3: bind.Device.vendor:type_name -> bind.Device.Vendor
+	3,  // 4: bind.Device.ports:type_name -> bind.Port
+	6,  // 5: bind.Binding.duts:type_name -> bind.BoundDevice
+	6,  // 6: bind.Binding.ates:type_name -> bind.BoundDevice
+	8,  // 7: bind.Binding.options:type_name -> bind.Options
+	8,  // 8: bind.BoundDevice.options:type_name -> bind.Options
+	8,  // 9: bind.BoundDevice.ssh:type_name -> bind.Options
+	8,  // 10: bind.BoundDevice.gnmi:type_name -> bind.Options
+	8,  // 11: bind.BoundDevice.gnoi:type_name -> bind.Options
+	8,  // 12: bind.BoundDevice.gribi:type_name -> bind.Options
+	8,  // 13: bind.BoundDevice.p4rt:type_name -> bind.Options
+	8,  // 14: bind.BoundDevice.otg:type_name -> bind.Options
+	7,  // 15: bind.BoundDevice.ports:type_name -> bind.BoundPort
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_bind_proto_init() }
+func file_bind_proto_init() {
+	if File_bind_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_bind_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Testbed); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bind_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Device); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bind_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Port); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bind_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Link); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bind_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Binding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bind_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BoundDevice); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bind_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BoundPort); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bind_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Options); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_bind_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bind_proto_goTypes,
+		DependencyIndexes: file_bind_proto_depIdxs,
+		EnumInfos:         file_bind_proto_enumTypes,
+		MessageInfos:      file_bind_proto_msgTypes,
+	}.Build()
+	File_bind_proto = out.File
+	file_bind_proto_rawDesc = nil
+	file_bind_proto_goTypes = nil
+	file_bind_proto_depIdxs = nil
+}
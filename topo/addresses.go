@@ -0,0 +1,116 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"fmt"
+	"net/netip"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+// assignLinkAddresses adds the addresses of the links of t to the ip_addresses
+// of their interfaces in nodes. Links without addresses whose interfaces have
+// none are assigned the addresses of their subnet of the link_subnet of t, if
+// set.
+func assignLinkAddresses(t *tpb.Topology, nodes map[string]*tpb.Node) error {
+	var pool netip.Prefix
+	if s := t.GetLinkSubnet(); s != "" {
+		var err error
+		if pool, err = netip.ParsePrefix(s); err != nil {
+			return fmt.Errorf("invalid link subnet %q: %v", s, err)
+		}
+		pool = pool.Masked()
+	}
+	for i, l := range t.GetLinks() {
+		aInt := nodes[l.GetANode()].GetInterfaces()[l.GetAInt()]
+		zInt := nodes[l.GetZNode()].GetInterfaces()[l.GetZInt()]
+		aIP, zIP := l.GetAIp(), l.GetZIp()
+		if aIP == "" && zIP == "" && len(aInt.GetIpAddresses()) == 0 && len(zInt.GetIpAddresses()) == 0 && pool.IsValid() {
+			a, z, err := linkSubnet(pool, i)
+			if err != nil {
+				return fmt.Errorf("link %s:%s %s:%s: %w", l.GetANode(), l.GetAInt(), l.GetZNode(), l.GetZInt(), err)
+			}
+			aIP, zIP = a.String(), z.String()
+		}
+		if err := addAddress(aInt, aIP); err != nil {
+			return fmt.Errorf("invalid address of interface %s:%s: %w", l.GetANode(), l.GetAInt(), err)
+		}
+		if err := addAddress(zInt, zIP); err != nil {
+			return fmt.Errorf("invalid address of interface %s:%s: %w", l.GetZNode(), l.GetZInt(), err)
+		}
+	}
+	for _, n := range nodes {
+		for k, intf := range n.GetInterfaces() {
+			for _, a := range intf.GetIpAddresses() {
+				if _, err := netip.ParsePrefix(a); err != nil {
+					return fmt.Errorf("invalid address of interface %s:%s: %v", n.GetName(), k, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addAddress adds the address a to the addresses of intf, unless a is empty
+// or intf already has it.
+func addAddress(intf *tpb.Interface, a string) error {
+	if a == "" {
+		return nil
+	}
+	if _, err := netip.ParsePrefix(a); err != nil {
+		return err
+	}
+	for _, ia := range intf.GetIpAddresses() {
+		if ia == a {
+			return nil
+		}
+	}
+	intf.IpAddresses = append(intf.IpAddresses, a)
+	return nil
+}
+
+// linkSubnet returns the addresses of the i-th link subnet of pool, a /31 of
+// an IPv4 pool or a /127 of an IPv6 pool.
+func linkSubnet(pool netip.Prefix, i int) (netip.Prefix, netip.Prefix, error) {
+	bits := 127
+	if pool.Addr().Is4() {
+		bits = 31
+	}
+	if pool.Bits() > bits {
+		return netip.Prefix{}, netip.Prefix{}, fmt.Errorf("link subnet %s is smaller than a /%d", pool, bits)
+	}
+	a := addOffset(pool.Addr(), uint64(2*i))
+	z := a.Next()
+	if !pool.Contains(a) || !pool.Contains(z) {
+		return netip.Prefix{}, netip.Prefix{}, fmt.Errorf("link subnet %s exhausted", pool)
+	}
+	return netip.PrefixFrom(a, bits), netip.PrefixFrom(z, bits), nil
+}
+
+// addOffset returns the address off addresses after a, wrapping around.
+func addOffset(a netip.Addr, off uint64) netip.Addr {
+	b := a.As16()
+	for i := len(b) - 1; i >= 0 && off > 0; i-- {
+		sum := uint64(b[i]) + off&0xff
+		b[i] = byte(sum)
+		off = off>>8 + sum>>8
+	}
+	r := netip.AddrFrom16(b)
+	if a.Is4() {
+		return r.Unmap()
+	}
+	return r
+}
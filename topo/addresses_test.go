@@ -0,0 +1,144 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+func TestAssignLinkAddresses(t *testing.T) {
+	links := func(ls ...*tpb.Link) []*tpb.Link { return ls }
+	tests := []struct {
+		desc    string
+		subnet  string
+		links   []*tpb.Link
+		intfs   map[string][]string
+		want    map[string][]string
+		wantErr string
+	}{{
+		desc:  "no addresses",
+		links: links(&tpb.Link{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"}),
+		want:  map[string][]string{},
+	}, {
+		desc:  "link addresses",
+		links: links(&tpb.Link{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1", AIp: "192.168.0.1/30", ZIp: "192.168.0.2/30"}),
+		intfs: map[string][]string{"r1:eth1": {"192.168.0.1/30", "2001:db8::1/64"}},
+		want: map[string][]string{
+			"r1:eth1": {"192.168.0.1/30", "2001:db8::1/64"},
+			"r2:eth1": {"192.168.0.2/30"},
+		},
+	}, {
+		desc:   "subnet",
+		subnet: "10.100.0.0/16",
+		links: links(
+			&tpb.Link{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"},
+			&tpb.Link{ANode: "r1", AInt: "eth2", ZNode: "r3", ZInt: "eth1", AIp: "192.168.0.1/30"},
+			&tpb.Link{ANode: "r2", AInt: "eth2", ZNode: "r3", ZInt: "eth2"},
+			&tpb.Link{ANode: "r1", AInt: "eth3", ZNode: "r3", ZInt: "eth3"},
+		),
+		intfs: map[string][]string{"r3:eth3": {"172.16.0.1/24"}},
+		want: map[string][]string{
+			"r1:eth1": {"10.100.0.0/31"},
+			"r2:eth1": {"10.100.0.1/31"},
+			"r1:eth2": {"192.168.0.1/30"},
+			"r2:eth2": {"10.100.0.4/31"},
+			"r3:eth2": {"10.100.0.5/31"},
+			"r3:eth3": {"172.16.0.1/24"},
+		},
+	}, {
+		desc:   "ipv6 subnet",
+		subnet: "2001:db8::ff:0/112",
+		links: links(
+			&tpb.Link{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"},
+			&tpb.Link{ANode: "r1", AInt: "eth2", ZNode: "r2", ZInt: "eth2"},
+		),
+		want: map[string][]string{
+			"r1:eth1": {"2001:db8::ff:0/127"},
+			"r2:eth1": {"2001:db8::ff:1/127"},
+			"r1:eth2": {"2001:db8::ff:2/127"},
+			"r2:eth2": {"2001:db8::ff:3/127"},
+		},
+	}, {
+		desc:   "subnet exhausted",
+		subnet: "10.100.0.0/31",
+		links: links(
+			&tpb.Link{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"},
+			&tpb.Link{ANode: "r1", AInt: "eth2", ZNode: "r2", ZInt: "eth2"},
+		),
+		wantErr: "link r1:eth2 r2:eth2: link subnet 10.100.0.0/31 exhausted",
+	}, {
+		desc:    "subnet too small",
+		subnet:  "10.100.0.0/32",
+		links:   links(&tpb.Link{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"}),
+		wantErr: "smaller than a /31",
+	}, {
+		desc:    "invalid subnet",
+		subnet:  "10.100.0.0",
+		wantErr: "invalid link subnet",
+	}, {
+		desc:    "invalid link address",
+		links:   links(&tpb.Link{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1", ZIp: "192.168.0.300/24"}),
+		wantErr: "invalid address of interface r2:eth1",
+	}, {
+		desc:    "invalid interface address",
+		intfs:   map[string][]string{"r3:eth1": {"192.168.0.1"}},
+		wantErr: "invalid address of interface r3:eth1",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			nodes := map[string]*tpb.Node{}
+			intf := func(n, i string) *tpb.Interface {
+				if nodes[n] == nil {
+					nodes[n] = &tpb.Node{Name: n, Interfaces: map[string]*tpb.Interface{}}
+				}
+				if nodes[n].Interfaces[i] == nil {
+					nodes[n].Interfaces[i] = &tpb.Interface{IntName: i}
+				}
+				return nodes[n].Interfaces[i]
+			}
+			for _, l := range tt.links {
+				intf(l.ANode, l.AInt)
+				intf(l.ZNode, l.ZInt)
+			}
+			for k, as := range tt.intfs {
+				n, i, _ := strings.Cut(k, ":")
+				intf(n, i).IpAddresses = as
+			}
+			err := assignLinkAddresses(&tpb.Topology{LinkSubnet: tt.subnet, Links: tt.links}, nodes)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("assignLinkAddresses() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			got := map[string][]string{}
+			for _, n := range nodes {
+				for k, i := range n.Interfaces {
+					if len(i.IpAddresses) > 0 {
+						got[n.Name+":"+k] = i.IpAddresses
+					}
+				}
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("assignLinkAddresses() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,568 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	bpb "github.com/openconfig/kne/proto/bind"
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+// BindOptions are the options of the binding returned by Bind.
+type BindOptions struct {
+	// Username and Password are the default credentials of the devices.
+	Username string
+	Password string
+	// SkipVerify skips the verification of the certificates of the devices.
+	SkipVerify bool
+}
+
+// bindVendors maps the vendors of testbed devices to the vendors of nodes.
+var bindVendors = map[bpb.Device_Vendor]tpb.Vendor{
+	bpb.Device_ARISTA:     tpb.Vendor_ARISTA,
+	bpb.Device_CISCO:      tpb.Vendor_CISCO,
+	bpb.Device_JUNIPER:    tpb.Vendor_JUNIPER,
+	bpb.Device_IXIA:       tpb.Vendor_KEYSIGHT,
+	bpb.Device_NOKIA:      tpb.Vendor_NOKIA,
+	bpb.Device_OPENCONFIG: tpb.Vendor_OPENCONFIG,
+}
+
+// typeVendors are the vendors of the nodes which only set the deprecated type.
+var typeVendors = map[tpb.Node_Type]tpb.Vendor{
+	tpb.Node_HOST:         tpb.Vendor_HOST,
+	tpb.Node_ARISTA_CEOS:  tpb.Vendor_ARISTA,
+	tpb.Node_JUNIPER_CEVO: tpb.Vendor_JUNIPER,
+	tpb.Node_JUNIPER_VMX:  tpb.Vendor_JUNIPER,
+	tpb.Node_CISCO_CXR:    tpb.Vendor_CISCO,
+	tpb.Node_CISCO_CSR:    tpb.Vendor_CISCO,
+	tpb.Node_CISCO_XRD:    tpb.Vendor_CISCO,
+	tpb.Node_CISCO_E8000:  tpb.Vendor_CISCO,
+	tpb.Node_QUAGGA:       tpb.Vendor_QUAGGA,
+	tpb.Node_FRR:          tpb.Vendor_FRR,
+	tpb.Node_GOBGP:        tpb.Vendor_GOBGP,
+	tpb.Node_NOKIA_SRL:    tpb.Vendor_NOKIA,
+	tpb.Node_IXIA_TG:      tpb.Vendor_KEYSIGHT,
+	tpb.Node_LEMMING:      tpb.Vendor_OPENCONFIG,
+}
+
+// endpoint is a port of a device or an interface of a node.
+type endpoint struct {
+	dev, port string
+}
+
+func (e endpoint) String() string {
+	return e.dev + ":" + e.port
+}
+
+// binder searches an assignment of the devices and ports of a testbed to the
+// nodes and interfaces of a topology.
+type binder struct {
+	devs    []*bpb.Device
+	ates    map[string]bool
+	links   [][2]endpoint
+	ports   map[string][]string
+	nodes   []*tpb.Node
+	tLinks  [][2]endpoint
+	ifaces  map[string][]string
+	nodeOf  map[string]*tpb.Node
+	used    map[string]bool
+	intfOf  map[endpoint]string
+	usedInt map[endpoint]bool
+	usedLnk map[int]bool
+}
+
+// Bind binds the devices of the testbed tb to the nodes of the topology pb and
+// their ports to the interfaces of the nodes, so that every link of the
+// testbed is a link of the topology. A device is bound to a node of the same
+// name if possible. DUTs are bound to nodes of the same vendor and model, if
+// set, and ATEs to Keysight nodes. The services of the nodes must have been
+// assigned external IPs, as in the topology returned by Show, to resolve the
+// service endpoints of the devices.
+func Bind(pb *tpb.Topology, tb *bpb.Testbed, opts BindOptions) (*bpb.Binding, error) {
+	b, err := newBinder(pb, tb)
+	if err != nil {
+		return nil, err
+	}
+	if !b.assign(0) {
+		return nil, unsatisfiedError(pb, b.check())
+	}
+	bind := &bpb.Binding{}
+	if opts.Username != "" || opts.Password != "" || opts.SkipVerify {
+		bind.Options = &bpb.Options{Username: opts.Username, Password: opts.Password, SkipVerify: opts.SkipVerify}
+	}
+	for _, d := range b.devs {
+		n := b.nodeOf[d.GetId()]
+		bd := &bpb.BoundDevice{Id: d.GetId(), Name: n.GetName()}
+		for _, p := range d.GetPorts() {
+			intf := b.intfOf[endpoint{d.GetId(), p.GetId()}]
+			name := intf
+			if i := n.GetInterfaces()[intf]; i.GetName() != "" {
+				name = i.GetName()
+			}
+			bd.Ports = append(bd.Ports, &bpb.BoundPort{Id: p.GetId(), Name: name})
+		}
+		if err := bindServices(n, bd); err != nil {
+			return nil, err
+		}
+		if b.ates[d.GetId()] {
+			bind.Ates = append(bind.Ates, bd)
+		} else {
+			bind.Duts = append(bind.Duts, bd)
+		}
+	}
+	return bind, nil
+}
+
+// TestbedFailure is a requirement of a testbed a topology does not satisfy.
+type TestbedFailure struct {
+	// Device is the testbed device with the requirement, if any.
+	Device string
+	// Link is the testbed link with the requirement, if any.
+	Link   string
+	Reason string
+}
+
+func (f *TestbedFailure) String() string {
+	switch {
+	case f.Device != "":
+		return fmt.Sprintf("device %s: %s", f.Device, f.Reason)
+	case f.Link != "":
+		return fmt.Sprintf("link %s: %s", f.Link, f.Reason)
+	}
+	return f.Reason
+}
+
+// CheckTestbed checks whether the devices and links of the testbed tb can be
+// bound to the topology pb, as by Bind, without any cluster resources. The
+// requirements of the testbed the topology does not satisfy are returned, none
+// if it can be bound. An error is returned if the testbed is invalid.
+func CheckTestbed(pb *tpb.Topology, tb *bpb.Testbed) ([]*TestbedFailure, error) {
+	b, err := newBinder(pb, tb)
+	if err != nil {
+		return nil, err
+	}
+	if b.assign(0) {
+		return nil, nil
+	}
+	return b.check(), nil
+}
+
+// unsatisfiedError returns the error of the topology pb not satisfying a
+// testbed with failures.
+func unsatisfiedError(pb *tpb.Topology, failures []*TestbedFailure) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "topology %q cannot satisfy the testbed:", pb.GetName())
+	for _, f := range failures {
+		fmt.Fprintf(&sb, "\n  %v", f)
+	}
+	return fmt.Errorf("%s", sb.String())
+}
+
+// check returns the failed requirements of the testbed. The devices without
+// any matching node are returned first, then the pairs of devices whose
+// matching nodes have too few links between them. If each requirement is
+// satisfied on its own, a failure of the testbed as a whole is returned.
+func (b *binder) check() []*TestbedFailure {
+	var failures []*TestbedFailure
+	cands := map[string][]*tpb.Node{}
+	for _, d := range b.devs {
+		var reasons []string
+		for _, n := range b.nodes {
+			if r := b.mismatch(d, n); r != "" {
+				reasons = append(reasons, r)
+			} else {
+				cands[d.GetId()] = append(cands[d.GetId()], n)
+			}
+		}
+		if len(cands[d.GetId()]) == 0 {
+			reason := "the topology has no nodes"
+			if len(reasons) != 0 {
+				reason = "no node matches: " + strings.Join(reasons, ", ")
+			}
+			failures = append(failures, &TestbedFailure{Device: d.GetId(), Reason: reason})
+		}
+	}
+	type pair struct{ a, z string }
+	need := map[pair]int{}
+	var pairs []pair
+	first := map[pair][2]endpoint{}
+	for _, l := range b.links {
+		p := pair{l[0].dev, l[1].dev}
+		if _, ok := need[pair{p.z, p.a}]; ok {
+			p = pair{p.z, p.a}
+		}
+		if need[p] == 0 {
+			pairs = append(pairs, p)
+			first[p] = l
+		}
+		need[p]++
+	}
+	for _, p := range pairs {
+		if len(cands[p.a]) == 0 || len(cands[p.z]) == 0 {
+			continue
+		}
+		have := 0
+		for _, na := range cands[p.a] {
+			for _, nz := range cands[p.z] {
+				if na == nz {
+					continue
+				}
+				if c := b.linkCount(na.GetName(), nz.GetName()); c > have {
+					have = c
+				}
+			}
+		}
+		if have >= need[p] {
+			continue
+		}
+		l := first[p]
+		reason := fmt.Sprintf("no link between the nodes matching %s (%s) and %s (%s)", p.a, nodeNames(cands[p.a]), p.z, nodeNames(cands[p.z]))
+		if have > 0 {
+			reason = fmt.Sprintf("%d links required between %s and %s, the nodes matching them have at most %d", need[p], p.a, p.z, have)
+		}
+		failures = append(failures, &TestbedFailure{Link: fmt.Sprintf("%v <-> %v", l[0], l[1]), Reason: reason})
+	}
+	if len(failures) == 0 {
+		failures = append(failures, &TestbedFailure{Reason: fmt.Sprintf("no assignment of the %d devices to distinct nodes satisfies all %d links", len(b.devs), len(b.links))})
+	}
+	return failures
+}
+
+// linkCount returns the number of links of the topology between the nodes a
+// and z.
+func (b *binder) linkCount(a, z string) int {
+	count := 0
+	for _, l := range b.tLinks {
+		if (l[0].dev == a && l[1].dev == z) || (l[0].dev == z && l[1].dev == a) {
+			count++
+		}
+	}
+	return count
+}
+
+func nodeNames(nodes []*tpb.Node) string {
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.GetName())
+	}
+	return strings.Join(names, ", ")
+}
+
+func newBinder(pb *tpb.Topology, tb *bpb.Testbed) (*binder, error) {
+	b := &binder{
+		ates:    map[string]bool{},
+		ports:   map[string][]string{},
+		nodes:   pb.GetNodes(),
+		ifaces:  map[string][]string{},
+		nodeOf:  map[string]*tpb.Node{},
+		used:    map[string]bool{},
+		intfOf:  map[endpoint]string{},
+		usedInt: map[endpoint]bool{},
+		usedLnk: map[int]bool{},
+	}
+	for _, d := range tb.GetAtes() {
+		b.ates[d.GetId()] = true
+	}
+	for _, d := range append(append([]*bpb.Device{}, tb.GetDuts()...), tb.GetAtes()...) {
+		if _, ok := b.ports[d.GetId()]; ok {
+			return nil, fmt.Errorf("duplicate testbed device %q", d.GetId())
+		}
+		b.ports[d.GetId()] = nil
+		for _, p := range d.GetPorts() {
+			b.ports[d.GetId()] = append(b.ports[d.GetId()], p.GetId())
+		}
+		b.devs = append(b.devs, d)
+	}
+	for _, l := range tb.GetLinks() {
+		a, err := b.parseEndpoint(l.GetA())
+		if err != nil {
+			return nil, err
+		}
+		z, err := b.parseEndpoint(l.GetB())
+		if err != nil {
+			return nil, err
+		}
+		b.links = append(b.links, [2]endpoint{a, z})
+	}
+	seen := map[endpoint]bool{}
+	addIface := func(e endpoint) {
+		if !seen[e] {
+			seen[e] = true
+			b.ifaces[e.dev] = append(b.ifaces[e.dev], e.port)
+		}
+	}
+	for _, l := range pb.GetLinks() {
+		a, z := endpoint{l.GetANode(), l.GetAInt()}, endpoint{l.GetZNode(), l.GetZInt()}
+		b.tLinks = append(b.tLinks, [2]endpoint{a, z})
+		addIface(a)
+		addIface(z)
+	}
+	for _, n := range pb.GetNodes() {
+		var names []string
+		for name := range n.GetInterfaces() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			addIface(endpoint{n.GetName(), name})
+		}
+	}
+	return b, nil
+}
+
+// parseEndpoint parses a testbed link endpoint "<device>:<port>".
+func (b *binder) parseEndpoint(s string) (endpoint, error) {
+	dev, port, ok := strings.Cut(s, ":")
+	if !ok {
+		return endpoint{}, fmt.Errorf("invalid testbed link endpoint %q, must be <device>:<port>", s)
+	}
+	ports, ok := b.ports[dev]
+	if !ok {
+		return endpoint{}, fmt.Errorf("testbed link endpoint %q: unknown device %q", s, dev)
+	}
+	for _, p := range ports {
+		if p == port {
+			return endpoint{dev, port}, nil
+		}
+	}
+	return endpoint{}, fmt.Errorf("testbed link endpoint %q: unknown port %q", s, port)
+}
+
+// candidates returns the unused nodes the device d can be bound to, the node
+// named like d first.
+func (b *binder) candidates(d *bpb.Device) []*tpb.Node {
+	var nodes []*tpb.Node
+	for _, n := range b.nodes {
+		if b.used[n.GetName()] || !b.matches(d, n) {
+			continue
+		}
+		if n.GetName() == d.GetId() {
+			nodes = append([]*tpb.Node{n}, nodes...)
+		} else {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// matches returns whether the device d can be bound to the node n.
+func (b *binder) matches(d *bpb.Device, n *tpb.Node) bool {
+	return b.mismatch(d, n) == ""
+}
+
+// mismatch returns why the device d cannot be bound to the node n, empty if it
+// can be.
+func (b *binder) mismatch(d *bpb.Device, n *tpb.Node) string {
+	v := n.GetVendor()
+	if v == tpb.Vendor_UNKNOWN {
+		v = typeVendors[n.GetType()]
+	}
+	switch ate := b.ates[d.GetId()]; {
+	case ate && v != tpb.Vendor_KEYSIGHT:
+		return fmt.Sprintf("%s is not a Keysight node", n.GetName())
+	case !ate && v == tpb.Vendor_KEYSIGHT:
+		return fmt.Sprintf("%s is a Keysight node", n.GetName())
+	}
+	if dv := d.GetVendor(); dv != bpb.Device_VENDOR_UNSPECIFIED {
+		if nv, ok := bindVendors[dv]; !ok || nv != v {
+			return fmt.Sprintf("%s is not of vendor %s", n.GetName(), dv)
+		}
+	}
+	if m := d.GetHardwareModel(); m != "" && m != n.GetModel() {
+		return fmt.Sprintf("%s is not of model %q", n.GetName(), m)
+	}
+	if have, want := len(b.ifaces[n.GetName()]), len(d.GetPorts()); have < want {
+		return fmt.Sprintf("%s has %d of %d interfaces", n.GetName(), have, want)
+	}
+	return ""
+}
+
+// assign binds the devices from the i-th on and their ports, backtracking
+// until all are bound.
+func (b *binder) assign(i int) bool {
+	if i == len(b.devs) {
+		return b.assignLinks(0) && b.assignPorts()
+	}
+	d := b.devs[i]
+	for _, n := range b.candidates(d) {
+		b.nodeOf[d.GetId()] = n
+		b.used[n.GetName()] = true
+		if b.feasible(d.GetId()) && b.assign(i+1) {
+			return true
+		}
+		delete(b.nodeOf, d.GetId())
+		delete(b.used, n.GetName())
+	}
+	return false
+}
+
+// feasible returns whether, for every device bound so far, there are as many
+// links between its node and the node of dev as there are testbed links
+// between the devices.
+func (b *binder) feasible(dev string) bool {
+	need := map[string]int{}
+	for _, l := range b.links {
+		switch {
+		case l[0].dev == dev && b.nodeOf[l[1].dev] != nil:
+			need[l[1].dev]++
+		case l[1].dev == dev && b.nodeOf[l[0].dev] != nil:
+			need[l[0].dev]++
+		}
+	}
+	n := b.nodeOf[dev].GetName()
+	for other, count := range need {
+		if b.linkCount(n, b.nodeOf[other].GetName()) < count {
+			return false
+		}
+	}
+	return true
+}
+
+// assignLinks binds the testbed links from the i-th on to unused links of the
+// topology between the nodes of their devices, backtracking until all are
+// bound.
+func (b *binder) assignLinks(i int) bool {
+	if i == len(b.links) {
+		return true
+	}
+	l := b.links[i]
+	na, nz := b.nodeOf[l[0].dev].GetName(), b.nodeOf[l[1].dev].GetName()
+	for j, tl := range b.tLinks {
+		if b.usedLnk[j] {
+			continue
+		}
+		intfs := [2]string{}
+		switch {
+		case tl[0].dev == na && tl[1].dev == nz:
+			intfs = [2]string{tl[0].port, tl[1].port}
+		case tl[1].dev == na && tl[0].dev == nz:
+			intfs = [2]string{tl[1].port, tl[0].port}
+		default:
+			continue
+		}
+		var bound []endpoint
+		ok := true
+		for k, p := range l {
+			if intf, set := b.intfOf[p]; set {
+				ok = ok && intf == intfs[k]
+				continue
+			}
+			ni := endpoint{b.nodeOf[p.dev].GetName(), intfs[k]}
+			if b.usedInt[ni] {
+				ok = false
+				continue
+			}
+			b.intfOf[p] = intfs[k]
+			b.usedInt[ni] = true
+			bound = append(bound, p)
+		}
+		b.usedLnk[j] = true
+		if ok && b.assignLinks(i+1) {
+			return true
+		}
+		delete(b.usedLnk, j)
+		for _, p := range bound {
+			delete(b.usedInt, endpoint{b.nodeOf[p.dev].GetName(), b.intfOf[p]})
+			delete(b.intfOf, p)
+		}
+	}
+	return false
+}
+
+// assignPorts binds the ports without links to the unused interfaces of the
+// nodes of their devices.
+func (b *binder) assignPorts() bool {
+	var bound []endpoint
+	for _, d := range b.devs {
+		n := b.nodeOf[d.GetId()].GetName()
+		for _, p := range d.GetPorts() {
+			e := endpoint{d.GetId(), p.GetId()}
+			if _, ok := b.intfOf[e]; ok {
+				continue
+			}
+			found := false
+			for _, intf := range b.ifaces[n] {
+				if ni := (endpoint{n, intf}); !b.usedInt[ni] {
+					b.intfOf[e] = intf
+					b.usedInt[ni] = true
+					bound = append(bound, e)
+					found = true
+					break
+				}
+			}
+			if !found {
+				for _, e := range bound {
+					delete(b.usedInt, endpoint{b.nodeOf[e.dev].GetName(), b.intfOf[e]})
+					delete(b.intfOf, e)
+				}
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// bindServices sets the service endpoints of the device bd from the services
+// of the node n, by the name of the services.
+func bindServices(n *tpb.Node, bd *bpb.BoundDevice) error {
+	opts := map[string]**bpb.Options{
+		"ssh":   &bd.Ssh,
+		"gnmi":  &bd.Gnmi,
+		"gnoi":  &bd.Gnoi,
+		"gribi": &bd.Gribi,
+		"p4rt":  &bd.P4Rt,
+		"otg":   &bd.Otg,
+	}
+	var ports []uint32
+	for p := range n.GetServices() {
+		ports = append(ports, p)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	// The services are keyed by their outside port once resolved, so a
+	// service may also be found unresolved under its inside port.
+	unresolved := map[string]bool{}
+	for _, p := range ports {
+		s := n.GetServices()[p]
+		o, ok := opts[s.GetName()]
+		if !ok || *o != nil {
+			continue
+		}
+		if s.GetOutsideIp() == "" {
+			unresolved[s.GetName()] = true
+			continue
+		}
+		port := s.GetOutside()
+		if port == 0 {
+			port = s.GetInside()
+		}
+		*o = &bpb.Options{Target: net.JoinHostPort(s.GetOutsideIp(), strconv.Itoa(int(port)))}
+	}
+	var names []string
+	for name := range unresolved {
+		if *opts[name] == nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) != 0 {
+		sort.Strings(names)
+		return fmt.Errorf("services %s of node %q have no external IP, the topology must be created", strings.Join(names, ", "), n.GetName())
+	}
+	return nil
+}
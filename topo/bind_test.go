@@ -0,0 +1,273 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	bpb "github.com/openconfig/kne/proto/bind"
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+const bindTopology = `
+name: "bind"
+nodes: {
+	name: "r1"
+	vendor: ARISTA
+	interfaces: { key: "eth1" value: { name: "Ethernet1" } }
+	interfaces: { key: "eth2" value: { name: "Ethernet2" } }
+	interfaces: { key: "eth3" value: { name: "Ethernet3" } }
+	interfaces: { key: "eth4" value: { name: "Ethernet4" } }
+	services: { key: 9339 value: { name: "gnmi" inside: 6030 outside: 9339 outside_ip: "192.168.18.1" } }
+	services: { key: 22 value: { name: "ssh" inside: 22 outside: 22 outside_ip: "192.168.18.1" } }
+}
+nodes: {
+	name: "r2"
+	type: CISCO_XRD
+}
+nodes: {
+	name: "r3"
+	vendor: ARISTA
+	model: "ceos"
+	services: { key: 9339 value: { name: "gnmi" inside: 6030 outside: 9339 outside_ip: "192.168.18.3" } }
+}
+nodes: {
+	name: "otg"
+	vendor: KEYSIGHT
+	services: { key: 40051 value: { name: "otg" inside: 40051 outside: 40051 outside_ip: "192.168.18.4" } }
+}
+links: { a_node: "r1" a_int: "eth1" z_node: "r2" z_int: "eth1" }
+links: { a_node: "r1" a_int: "eth2" z_node: "r3" z_int: "eth1" }
+links: { a_node: "r2" a_int: "eth2" z_node: "r3" z_int: "eth2" }
+links: { a_node: "otg" a_int: "eth1" z_node: "r1" z_int: "eth3" }
+links: { a_node: "otg" a_int: "eth2" z_node: "r2" z_int: "eth3" }
+`
+
+func TestBind(t *testing.T) {
+	pb := &tpb.Topology{}
+	if err := prototext.Unmarshal([]byte(bindTopology), pb); err != nil {
+		t.Fatalf("cannot parse topology: %v", err)
+	}
+	tests := []struct {
+		desc string
+		// topo is the topology bound, the default topology if nil.
+		topo    *tpb.Topology
+		testbed string
+		opts    BindOptions
+		want    string
+		wantErr string
+	}{{
+		desc: "device bound to node of same name",
+		testbed: `
+			duts: { id: "r2" vendor: CISCO ports: { id: "port1" } }
+			ates: { id: "ate" ports: { id: "port1" } }
+			links: { a: "r2:port1" b: "ate:port1" }
+		`,
+		want: `
+			duts: { id: "r2" name: "r2" ports: { id: "port1" name: "eth3" } }
+			ates: { id: "ate" name: "otg" otg: { target: "192.168.18.4:40051" } ports: { id: "port1" name: "eth2" } }
+		`,
+	}, {
+		desc: "backtracking",
+		testbed: `
+			duts: { id: "dut1" vendor: ARISTA ports: { id: "port1" } }
+			duts: { id: "dut2" vendor: ARISTA ports: { id: "port1" } ports: { id: "port2" } }
+			ates: { id: "ate" ports: { id: "port1" } }
+			links: { a: "dut1:port1" b: "dut2:port1" }
+			links: { a: "dut2:port2" b: "ate:port1" }
+		`,
+		want: `
+			duts: { id: "dut1" name: "r3" gnmi: { target: "192.168.18.3:9339" } ports: { id: "port1" name: "eth1" } }
+			duts: {
+				id: "dut2"
+				name: "r1"
+				ssh: { target: "192.168.18.1:22" }
+				gnmi: { target: "192.168.18.1:9339" }
+				ports: { id: "port1" name: "Ethernet2" }
+				ports: { id: "port2" name: "Ethernet3" }
+			}
+			ates: { id: "ate" name: "otg" otg: { target: "192.168.18.4:40051" } ports: { id: "port1" name: "eth1" } }
+		`,
+	}, {
+		desc: "port without link and credentials",
+		testbed: `
+			duts: { id: "dut" hardware_model: "ceos" ports: { id: "port1" } ports: { id: "port2" } }
+		`,
+		opts: BindOptions{Username: "admin", Password: "admin", SkipVerify: true},
+		want: `
+			duts: { id: "dut" name: "r3" gnmi: { target: "192.168.18.3:9339" } ports: { id: "port1" name: "eth1" } ports: { id: "port2" name: "eth2" } }
+			options: { username: "admin" password: "admin" skip_verify: true }
+		`,
+	}, {
+		desc: "unsatisfiable",
+		testbed: `
+			duts: { id: "dut1" vendor: NOKIA }
+		`,
+		wantErr: "cannot satisfy the testbed",
+	}, {
+		desc: "too many links",
+		testbed: `
+			duts: { id: "dut1" vendor: CISCO ports: { id: "port1" } ports: { id: "port2" } }
+			ates: { id: "ate" ports: { id: "port1" } ports: { id: "port2" } }
+			links: { a: "dut1:port1" b: "ate:port1" }
+			links: { a: "dut1:port2" b: "ate:port2" }
+		`,
+		wantErr: "cannot satisfy the testbed",
+	}, {
+		desc: "unknown port",
+		testbed: `
+			duts: { id: "dut1" ports: { id: "port1" } }
+			duts: { id: "dut2" ports: { id: "port1" } }
+			links: { a: "dut1:port1" b: "dut2:port2" }
+		`,
+		wantErr: `unknown port "port2"`,
+	}, {
+		desc: "invalid endpoint",
+		testbed: `
+			duts: { id: "dut1" ports: { id: "port1" } }
+			links: { a: "dut1" b: "dut1:port1" }
+		`,
+		wantErr: "must be <device>:<port>",
+	}, {
+		desc: "unresolved services",
+		topo: &tpb.Topology{Nodes: []*tpb.Node{{
+			Name:     "r1",
+			Vendor:   tpb.Vendor_ARISTA,
+			Services: map[uint32]*tpb.Service{6030: {Name: "gnmi", Inside: 6030}},
+		}}},
+		testbed: `
+			duts: { id: "r1" }
+		`,
+		wantErr: `services gnmi of node "r1" have no external IP`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tb := &bpb.Testbed{}
+			if err := prototext.Unmarshal([]byte(tt.testbed), tb); err != nil {
+				t.Fatalf("cannot parse testbed: %v", err)
+			}
+			topo := tt.topo
+			if topo == nil {
+				topo = pb
+			}
+			got, err := Bind(topo, tb, tt.opts)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("Bind() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			want := &bpb.Binding{}
+			if err := prototext.Unmarshal([]byte(tt.want), want); err != nil {
+				t.Fatalf("cannot parse binding: %v", err)
+			}
+			if s := cmp.Diff(want, got, protocmp.Transform()); s != "" {
+				t.Errorf("Bind() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestCheckTestbed(t *testing.T) {
+	pb := &tpb.Topology{}
+	if err := prototext.Unmarshal([]byte(bindTopology), pb); err != nil {
+		t.Fatalf("cannot parse topology: %v", err)
+	}
+	tests := []struct {
+		desc    string
+		testbed string
+		want    []string
+		wantErr string
+	}{{
+		desc: "satisfied",
+		testbed: `
+			duts: { id: "dut1" vendor: ARISTA ports: { id: "port1" } }
+			ates: { id: "ate" ports: { id: "port1" } }
+			links: { a: "dut1:port1" b: "ate:port1" }
+		`,
+	}, {
+		desc: "vendor",
+		testbed: `
+			duts: { id: "dut1" vendor: NOKIA }
+		`,
+		want: []string{`device dut1: no node matches: r1 is not of vendor NOKIA, r2 is not of vendor NOKIA, r3 is not of vendor NOKIA, otg is a Keysight node`},
+	}, {
+		desc: "port count",
+		testbed: `
+			duts: { id: "dut1" vendor: ARISTA ports: { id: "p1" } ports: { id: "p2" } ports: { id: "p3" } ports: { id: "p4" } ports: { id: "p5" } }
+			ates: { id: "ate" hardware_model: "ixia-c" }
+		`,
+		want: []string{
+			`device dut1: no node matches: r1 has 4 of 5 interfaces, r2 is not of vendor ARISTA, r3 has 2 of 5 interfaces, otg is a Keysight node`,
+			`device ate: no node matches: r1 is not a Keysight node, r2 is not a Keysight node, r3 is not a Keysight node, otg is not of model "ixia-c"`,
+		},
+	}, {
+		desc: "no link",
+		testbed: `
+			duts: { id: "dut1" hardware_model: "ceos" ports: { id: "port1" } }
+			ates: { id: "ate" ports: { id: "port1" } }
+			links: { a: "ate:port1" b: "dut1:port1" }
+		`,
+		want: []string{`link ate:port1 <-> dut1:port1: no link between the nodes matching ate (otg) and dut1 (r3)`},
+	}, {
+		desc: "too few links",
+		testbed: `
+			duts: { id: "dut1" vendor: CISCO ports: { id: "port1" } ports: { id: "port2" } }
+			ates: { id: "ate" ports: { id: "port1" } ports: { id: "port2" } }
+			links: { a: "dut1:port1" b: "ate:port1" }
+			links: { a: "ate:port2" b: "dut1:port2" }
+		`,
+		want: []string{`link dut1:port1 <-> ate:port1: 2 links required between dut1 and ate, the nodes matching them have at most 1`},
+	}, {
+		desc: "too many devices",
+		testbed: `
+			duts: { id: "dut1" vendor: ARISTA }
+			duts: { id: "dut2" vendor: ARISTA }
+			duts: { id: "dut3" vendor: ARISTA }
+		`,
+		want: []string{`no assignment of the 3 devices to distinct nodes satisfies all 0 links`},
+	}, {
+		desc: "invalid testbed",
+		testbed: `
+			duts: { id: "dut1" }
+			duts: { id: "dut1" }
+		`,
+		wantErr: `duplicate testbed device "dut1"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tb := &bpb.Testbed{}
+			if err := prototext.Unmarshal([]byte(tt.testbed), tb); err != nil {
+				t.Fatalf("cannot parse testbed: %v", err)
+			}
+			failures, err := CheckTestbed(pb, tb)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("CheckTestbed() unexpected error: %s", s)
+			}
+			var got []string
+			for _, f := range failures {
+				got = append(got, f.String())
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("CheckTestbed() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// primaryResources are listed first in the breakdown of the requests.
+var primaryResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// nodeRequest is the resources requested by a node of the topology.
+type nodeRequest struct {
+	name     string
+	requests corev1.ResourceList
+	// spec holds the node selector, tolerations and sidecars of the pod of
+	// the node.
+	spec corev1.PodSpec
+}
+
+// clusterCapacity is the free capacity of a cluster node.
+type clusterCapacity struct {
+	node *corev1.Node
+	free corev1.ResourceList
+}
+
+// WithSkipCapacityCheck skips the check of the capacity of the cluster for the
+// constraints of the nodes before the topology is created.
+func WithSkipCapacityCheck(skip bool) Option {
+	return func(m *Manager) {
+		m.skipCapacityCheck = skip
+	}
+}
+
+// checkCapacity checks the constraints of the nodes, e.g. cpu, memory and
+// hugepages, fit into the schedulable capacity of the cluster, the allocatable
+// resources of the ready and uncordoned cluster nodes less the requests of the
+// pods running on them. The nodes are placed largest first onto the cluster
+// node with the most free capacity matching their node selector and with
+// taints they tolerate, an error with a breakdown of the requests of each node
+// is returned if any node does not fit. The check is skipped if no node has
+// constraints or the capacity of the cluster cannot be determined.
+func (m *Manager) checkCapacity(ctx context.Context) error {
+	requests, err := m.nodeRequests()
+	if err != nil {
+		return err
+	}
+	return m.checkRequests(ctx, fmt.Sprintf("topology %q", m.topo.GetName()), requests)
+}
+
+// checkRequests checks the requests, of the nodes of what, fit into the
+// schedulable capacity of the cluster as described for checkCapacity.
+func (m *Manager) checkRequests(ctx context.Context, what string, requests []*nodeRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	cluster, err := m.schedulableCapacity(ctx)
+	if err != nil {
+		m.logger().Warnf("Skipping capacity check: %v", err)
+		return nil
+	}
+	if len(cluster) == 0 {
+		m.logger().Warnf("Skipping capacity check: no schedulable cluster nodes found")
+		return nil
+	}
+	total, available := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, r := range requests {
+		addResources(total, r.requests)
+	}
+	// Only the resources requested by the nodes are reported as available.
+	for _, c := range cluster {
+		for res := range total {
+			addResources(available, corev1.ResourceList{res: c.free[res]})
+		}
+	}
+	unschedulable := place(requests, cluster)
+	if len(unschedulable) == 0 {
+		m.logger().Infof("Cluster capacity check passed: requested %s of schedulable %s", FormatResources(total), FormatResources(available))
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "insufficient cluster capacity for %s: %d of %d nodes cannot be scheduled (requested %s, schedulable %s):",
+		what, len(unschedulable), len(requests), FormatResources(total), FormatResources(available))
+	for _, r := range requests {
+		fmt.Fprintf(&b, "\n  %s: %s", r.name, FormatResources(r.requests))
+		if unschedulable[r.name] {
+			b.WriteString(" (does not fit)")
+		}
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+// place places the requests largest first onto the cluster node with the
+// most free capacity they can be scheduled on, reducing the free capacity of
+// the cluster nodes, and returns the names of the requests which do not fit.
+func place(requests []*nodeRequest, cluster []*clusterCapacity) map[string]bool {
+	sorted := append([]*nodeRequest{}, requests...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, name := range primaryResources {
+			a, b := sorted[i].requests[name], sorted[j].requests[name]
+			if c := a.Cmp(b); c != 0 {
+				return c > 0
+			}
+		}
+		return false
+	})
+	unschedulable := map[string]bool{}
+	for _, r := range sorted {
+		var best *clusterCapacity
+		for _, c := range cluster {
+			if !canSchedule(&r.spec, c.node) || !fits(r.requests, c.free) {
+				continue
+			}
+			if best == nil {
+				best = c
+				continue
+			}
+			bq, cq := best.free[corev1.ResourceCPU], c.free[corev1.ResourceCPU]
+			if cq.Cmp(bq) > 0 {
+				best = c
+			}
+		}
+		if best == nil {
+			unschedulable[r.name] = true
+			continue
+		}
+		for name, q := range r.requests {
+			left := best.free[name]
+			left.Sub(q)
+			best.free[name] = left
+		}
+	}
+	return unschedulable
+}
+
+// nodeRequests returns the constraints of the nodes of the topology with any,
+// including the constraints of their sidecars, sorted by node name.
+func (m *Manager) nodeRequests() ([]*nodeRequest, error) {
+	var requests []*nodeRequest
+	for _, name := range m.nodeNames() {
+		pb := m.nodes[name].GetProto()
+		rr, err := node.ParseConstraints(pb.GetConstraints())
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %w", name, err)
+		}
+		r := &nodeRequest{name: name, requests: rr.Requests}
+		node.ApplyScheduling(&r.spec, pb)
+		if err := node.ApplySidecars(&r.spec, pb); err != nil {
+			return nil, err
+		}
+		for _, c := range r.spec.Containers {
+			addResources(r.requests, c.Resources.Requests)
+		}
+		if len(r.requests) != 0 {
+			requests = append(requests, r)
+		}
+	}
+	return requests, nil
+}
+
+// schedulableCapacity returns the free resources of each ready and uncordoned
+// cluster node.
+func (m *Manager) schedulableCapacity(ctx context.Context) ([]*clusterCapacity, error) {
+	nodes, err := m.kClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster nodes: %w", err)
+	}
+	pods, err := m.kClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	used := map[string]corev1.ResourceList{}
+	for _, p := range pods.Items {
+		if p.Spec.NodeName == "" || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if used[p.Spec.NodeName] == nil {
+			used[p.Spec.NodeName] = corev1.ResourceList{}
+		}
+		for _, c := range p.Spec.Containers {
+			addResources(used[p.Spec.NodeName], c.Resources.Requests)
+		}
+	}
+	var cluster []*clusterCapacity
+	for i := range nodes.Items {
+		n := &nodes.Items[i]
+		if !schedulable(n) {
+			continue
+		}
+		f := corev1.ResourceList{}
+		for res, q := range n.Status.Allocatable {
+			q = q.DeepCopy()
+			q.Sub(used[n.Name][res])
+			f[res] = q
+		}
+		cluster = append(cluster, &clusterCapacity{node: n, free: f})
+	}
+	return cluster, nil
+}
+
+// schedulable returns true if n is ready and not cordoned.
+func schedulable(n *corev1.Node) bool {
+	if n.Spec.Unschedulable {
+		return false
+	}
+	for _, c := range n.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// canSchedule returns true if n matches the node selector of spec and spec
+// tolerates the taints of n preventing scheduling.
+func canSchedule(spec *corev1.PodSpec, n *corev1.Node) bool {
+	for k, v := range spec.NodeSelector {
+		if n.Labels[k] != v {
+			return false
+		}
+	}
+	for i := range n.Spec.Taints {
+		t := &n.Spec.Taints[i]
+		if t.Effect != corev1.TaintEffectNoSchedule && t.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for _, tol := range spec.Tolerations {
+			if tol.ToleratesTaint(t) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// addResources adds the resources of r to total.
+func addResources(total, r corev1.ResourceList) {
+	for res, q := range r {
+		t := total[res]
+		t.Add(q)
+		total[res] = t
+	}
+}
+
+// fits returns true if the requests fit into free.
+func fits(requests, free corev1.ResourceList) bool {
+	for name, q := range requests {
+		if f := free[name]; q.Cmp(f) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatResources returns the resources of r, cpu and memory first, e.g.
+// "cpu 4, memory 12Gi, hugepages-1Gi 4Gi".
+func FormatResources(r corev1.ResourceList) string {
+	var s, other []string
+	for _, res := range primaryResources {
+		if q, ok := r[res]; ok {
+			s = append(s, fmt.Sprintf("%s %s", res, q.String()))
+		}
+	}
+	for res, q := range r {
+		if res != corev1.ResourceCPU && res != corev1.ResourceMemory {
+			other = append(other, fmt.Sprintf("%s %s", res, q.String()))
+		}
+	}
+	sort.Strings(other)
+	return strings.Join(append(s, other...), ", ")
+}
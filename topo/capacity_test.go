@@ -0,0 +1,285 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/h-fam/errdiff"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func clusterNode(name, cpu, memory string, mods ...func(*corev1.Node)) *corev1.Node {
+	n := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	for _, m := range mods {
+		m(n)
+	}
+	return n
+}
+
+func constrainedNode(name, cpu, memory string) node.Node {
+	c := map[string]string{}
+	if cpu != "" {
+		c["cpu"] = cpu
+	}
+	if memory != "" {
+		c["memory"] = memory
+	}
+	return &node.Impl{Proto: &tpb.Node{Name: name, Constraints: c}}
+}
+
+func scheduledNode(name, cpu, memory string, s *tpb.Scheduling) node.Node {
+	n := constrainedNode(name, cpu, memory)
+	n.GetProto().Scheduling = s
+	return n
+}
+
+func TestCheckCapacity(t *testing.T) {
+	cordoned := func(n *corev1.Node) { n.Spec.Unschedulable = true }
+	tainted := func(n *corev1.Node) {
+		n.Spec.Taints = []corev1.Taint{{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule}}
+	}
+	notReady := func(n *corev1.Node) { n.Status.Conditions[0].Status = corev1.ConditionFalse }
+	tests := []struct {
+		desc    string
+		nodes   map[string]node.Node
+		objs    []runtime.Object
+		wantErr string
+	}{{
+		desc: "fits",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+			"r2": constrainedNode("r2", "4", "12Gi"),
+		},
+		objs: []runtime.Object{clusterNode("w1", "8", "32Gi")},
+	}, {
+		desc: "fits across cluster nodes",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+			"r2": constrainedNode("r2", "4", "12Gi"),
+			"r3": constrainedNode("r3", "1", ""),
+		},
+		objs: []runtime.Object{clusterNode("w1", "5", "16Gi"), clusterNode("w2", "4", "16Gi")},
+	}, {
+		desc: "no constraints",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "", ""),
+		},
+	}, {
+		desc: "no cluster nodes",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+		},
+	}, {
+		desc: "insufficient",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+			"r2": constrainedNode("r2", "4", "12Gi"),
+			"h1": constrainedNode("h1", "1", ""),
+		},
+		objs: []runtime.Object{clusterNode("w1", "6", "16Gi")},
+		wantErr: `insufficient cluster capacity for topology "test": 1 of 3 nodes cannot be scheduled (requested cpu 9, memory 24Gi, schedulable cpu 6, memory 16Gi):
+  h1: cpu 1
+  r1: cpu 4, memory 12Gi
+  r2: cpu 4, memory 12Gi (does not fit)`,
+	}, {
+		desc: "fragmented",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+		},
+		objs:    []runtime.Object{clusterNode("w1", "2", "8Gi"), clusterNode("w2", "2", "8Gi")},
+		wantErr: "r1: cpu 4, memory 12Gi (does not fit)",
+	}, {
+		desc: "running pods",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+		},
+		objs: []runtime.Object{
+			clusterNode("w1", "8", "32Gi"),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"},
+				Spec: corev1.PodSpec{
+					NodeName: "w1",
+					Containers: []corev1.Container{{
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")}},
+					}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "other"},
+				Spec: corev1.PodSpec{
+					NodeName: "w1",
+					Containers: []corev1.Container{{
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+					}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+		},
+		wantErr: "schedulable cpu 2, memory 32Gi",
+	}, {
+		desc: "unschedulable cluster nodes",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+		},
+		objs: []runtime.Object{
+			clusterNode("w1", "8", "32Gi"),
+			clusterNode("w2", "8", "32Gi", cordoned),
+			clusterNode("w3", "8", "32Gi", tainted),
+			clusterNode("w4", "8", "32Gi", notReady),
+		},
+	}, {
+		desc: "only cordoned cluster nodes",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+		},
+		objs: []runtime.Object{clusterNode("w1", "8", "32Gi", cordoned)},
+	}, {
+		desc: "taint not tolerated",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "4", "12Gi"),
+		},
+		objs:    []runtime.Object{clusterNode("w1", "8", "32Gi", tainted)},
+		wantErr: "r1: cpu 4, memory 12Gi (does not fit)",
+	}, {
+		desc: "taint tolerated",
+		nodes: map[string]node.Node{
+			"r1": scheduledNode("r1", "4", "12Gi", &tpb.Scheduling{
+				Tolerations: []*tpb.Toleration{{Key: "node-role.kubernetes.io/control-plane", Operator: "Exists"}},
+			}),
+		},
+		objs: []runtime.Object{clusterNode("w1", "8", "32Gi", tainted)},
+	}, {
+		desc: "node selector",
+		nodes: map[string]node.Node{
+			"r1": scheduledNode("r1", "4", "12Gi", &tpb.Scheduling{NodeSelector: map[string]string{"pool": "big"}}),
+			"r2": scheduledNode("r2", "4", "12Gi", &tpb.Scheduling{NodeSelector: map[string]string{"pool": "big"}}),
+		},
+		objs: []runtime.Object{
+			clusterNode("w1", "16", "64Gi"),
+			clusterNode("w2", "6", "16Gi", func(n *corev1.Node) { n.Labels = map[string]string{"pool": "big"} }),
+		},
+		wantErr: "r2: cpu 4, memory 12Gi (does not fit)",
+	}, {
+		desc: "sidecars",
+		nodes: map[string]node.Node{
+			"r1": &node.Impl{Proto: &tpb.Node{
+				Name:        "r1",
+				Constraints: map[string]string{"cpu": "4"},
+				Config: &tpb.Config{Sidecars: []*tpb.Sidecar{
+					{Name: "exporter", Image: "exporter", Constraints: map[string]string{"cpu": "500m", "memory": "1Gi"}},
+				}},
+			}},
+		},
+		objs:    []runtime.Object{clusterNode("w1", "4", "8Gi")},
+		wantErr: "r1: cpu 4500m, memory 1Gi (does not fit)",
+	}, {
+		desc: "hugepages",
+		nodes: map[string]node.Node{
+			"r1": &node.Impl{Proto: &tpb.Node{Name: "r1", Constraints: map[string]string{"cpu": "2", "hugepages-1Gi": "4Gi"}}},
+			"r2": &node.Impl{Proto: &tpb.Node{Name: "r2", Constraints: map[string]string{"cpu": "2", "hugepages-1Gi": "4Gi"}}},
+		},
+		objs: []runtime.Object{
+			clusterNode("w1", "8", "32Gi", func(n *corev1.Node) { n.Status.Allocatable["hugepages-1Gi"] = resource.MustParse("4Gi") }),
+			clusterNode("w2", "16", "32Gi"),
+		},
+		wantErr: `1 of 2 nodes cannot be scheduled (requested cpu 4, hugepages-1Gi 8Gi, schedulable cpu 24, hugepages-1Gi 4Gi):
+  r1: cpu 2, hugepages-1Gi 4Gi
+  r2: cpu 2, hugepages-1Gi 4Gi (does not fit)`,
+	}, {
+		desc: "invalid constraint",
+		nodes: map[string]node.Node{
+			"r1": constrainedNode("r1", "four", ""),
+		},
+		wantErr: `node "r1": invalid cpu constraint "four"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				nodes:   tt.nodes,
+				kClient: kfake.NewSimpleClientset(tt.objs...),
+			}
+			err := m.checkCapacity(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Errorf("checkCapacity() unexpected error: %s", s)
+			}
+		})
+	}
+}
+
+func TestCreateCapacity(t *testing.T) {
+	node.Register(tpb.Node_Type(1011), NewConfigurable)
+	tp := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name:        "r1",
+			Type:        tpb.Node_Type(1011),
+			Constraints: map[string]string{"cpu": "16"},
+			Config:      &tpb.Config{},
+		}},
+	}
+	tests := []struct {
+		desc    string
+		skip    bool
+		wantErr string
+	}{{
+		desc:    "checked",
+		wantErr: "insufficient cluster capacity",
+	}, {
+		desc: "skipped",
+		skip: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			kf := kfake.NewSimpleClientset(clusterNode("w1", "8", "32Gi"))
+			m, err := New(tp, WithClusterConfig(&rest.Config{}), WithKubeClient(kf), WithTopoClient(tf), WithSkipCapacityCheck(tt.skip))
+			if err != nil {
+				t.Fatalf("New() failed to create new topology manager: %v", err)
+			}
+			err = m.Create(context.Background(), 100*time.Millisecond)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Errorf("Create() unexpected error: %s", s)
+			}
+			if _, err := kf.CoreV1().Namespaces().Get(context.Background(), "test", metav1.GetOptions{}); (err == nil) != tt.skip {
+				t.Errorf("Create() namespace created: %v, want %v", err == nil, tt.skip)
+			}
+		})
+	}
+}
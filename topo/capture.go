@@ -0,0 +1,329 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// DefaultCaptureImage is the image of the ephemeral container used to run
+// tcpdump for nodes whose image does not provide it.
+const DefaultCaptureImage = "nicolaka/netshoot:latest"
+
+// captureScript starts tcpdump writing pcap data to stdout once a line is read
+// from stdin and stops it once stdin is closed. This ensures no capture data
+// is written before the caller is attached and that tcpdump does not outlive
+// the capture session.
+const captureScript = `read _; tcpdump -U -n -w - -i %s & pid=$!; cat >/dev/null; kill $pid`
+
+var (
+	intfNameRe = regexp.MustCompile(`^[\w.@:-]+$`)
+
+	// captureReadyInterval is the interval used to poll for the capture
+	// container to be running.
+	captureReadyInterval = time.Second
+)
+
+// remoteCommand runs an exec or attach session on the provided pod. It is a
+// variable for testing.
+var remoteCommand = func(kClient kubernetes.Interface, rCfg *rest.Config, pod *corev1.Pod, subresource string, opts runtime.Object, stdin io.Reader, stdout, stderr io.Writer) error {
+	req := kClient.CoreV1().RESTClient().Post().Resource("pods").Name(pod.Name).Namespace(pod.Namespace).SubResource(subresource)
+	req.VersionedParams(opts, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(rCfg, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return exec.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
+
+// CaptureOptions configures a packet capture.
+type CaptureOptions struct {
+	// Image is the image of the ephemeral container used when the node image
+	// does not provide tcpdump. If empty DefaultCaptureImage is used.
+	Image string
+}
+
+// Capture runs tcpdump on the provided interface of the node and writes the
+// captured packets in pcap format to w until ctx is canceled. The interface
+// may be given either as the pod interface name (e.g. eth1) or the vendor
+// interface name (e.g. Ethernet1). tcpdump is run in the node container if
+// available, otherwise in an ephemeral container sharing the network namespace
+// of the node pod.
+func (m *Manager) Capture(ctx context.Context, nodeName, intf string, w io.Writer, opts CaptureOptions) error {
+	n, ok := m.nodes[nodeName]
+	if !ok {
+		return fmt.Errorf("node %q not found", nodeName)
+	}
+	podIntf := captureInterface(n.GetProto(), intf)
+	if !intfNameRe.MatchString(podIntf) {
+		return fmt.Errorf("invalid interface name %q", podIntf)
+	}
+	pods, err := n.Pods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pods for node %q: %w", nodeName, err)
+	}
+	var pod *corev1.Pod
+	for _, p := range pods {
+		if p != nil {
+			pod = p
+			break
+		}
+	}
+	if pod == nil || len(pod.Spec.Containers) == 0 {
+		return fmt.Errorf("no pod found for node %q", nodeName)
+	}
+	container := pod.Spec.Containers[0].Name
+	for _, c := range pod.Spec.Containers {
+		if c.Name == nodeName {
+			container = c.Name
+		}
+	}
+	stderr := log.StandardLogger().WriterLevel(log.InfoLevel)
+	defer stderr.Close()
+	cmd := []string{"sh", "-c", fmt.Sprintf(captureScript, podIntf)}
+
+	// Prefer running tcpdump directly in the node container.
+	probe := &corev1.PodExecOptions{
+		Container: container,
+		Command:   []string{"tcpdump", "--version"},
+		Stdout:    true,
+		Stderr:    true,
+	}
+	if err := remoteCommand(m.kClient, m.rCfg, pod, "exec", probe, nil, io.Discard, io.Discard); err == nil {
+		m.logger().Infof("Capturing on %s:%s in container %q", nodeName, podIntf, container)
+		return remoteCommand(m.kClient, m.rCfg, pod, "exec", &corev1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+		}, captureStdin(ctx), w, stderr)
+	}
+
+	image := opts.Image
+	if image == "" {
+		image = DefaultCaptureImage
+	}
+	name := fmt.Sprintf("kne-capture-%s", rand.String(5))
+	m.logger().Infof("Capturing on %s:%s in ephemeral container %q using image %q", nodeName, podIntf, name, image)
+	pod = pod.DeepCopy()
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:      name,
+			Image:     image,
+			Command:   cmd,
+			Stdin:     true,
+			StdinOnce: true,
+		},
+		TargetContainerName: container,
+	})
+	if _, err := m.kClient.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(ctx, pod.Name, pod, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to create capture container for node %q: %w", nodeName, err)
+	}
+	if err := m.waitContainerRunning(ctx, pod.Namespace, pod.Name, name); err != nil {
+		return err
+	}
+	return remoteCommand(m.kClient, m.rCfg, pod, "attach", &corev1.PodAttachOptions{
+		Container: name,
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    true,
+	}, captureStdin(ctx), w, stderr)
+}
+
+// captureInterface returns the pod interface name for intf on the node. If
+// intf is not a known interface of the node it is returned unchanged, which
+// allows capturing on interfaces not part of the topology such as eth0.
+func captureInterface(pb *tpb.Node, intf string) string {
+	if _, ok := pb.GetInterfaces()[intf]; ok {
+		return intf
+	}
+	for k, v := range pb.GetInterfaces() {
+		if v.GetName() == intf {
+			return k
+		}
+	}
+	return intf
+}
+
+// captureStdin returns the stdin of a capture session which starts the
+// capture immediately and stops it once ctx is canceled.
+func captureStdin(ctx context.Context) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		if _, err := pw.Write([]byte("\n")); err != nil {
+			return
+		}
+		<-ctx.Done()
+		pw.Close()
+	}()
+	return pr
+}
+
+// waitContainerRunning waits for the named ephemeral container of the pod to
+// be running.
+func (m *Manager) waitContainerRunning(ctx context.Context, namespace, podName, name string) error {
+	for {
+		p, err := m.kClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, s := range p.Status.EphemeralContainerStatuses {
+			if s.Name != name {
+				continue
+			}
+			switch {
+			case s.State.Running != nil:
+				return nil
+			case s.State.Terminated != nil:
+				return fmt.Errorf("capture container %q terminated: %s", name, s.State.Terminated.Reason)
+			case s.State.Waiting != nil && s.State.Waiting.Reason == "ErrImagePull":
+				return fmt.Errorf("capture container %q failed to pull image: %s", name, s.State.Waiting.Message)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context canceled before capture container %q running", name)
+		case <-time.After(captureReadyInterval):
+		}
+	}
+}
+
+// StreamCapture runs Capture serving the pcap stream on endpoint until ctx is
+// canceled, for consumption by tools such as wireshark. The endpoint is either
+// "tcp:<address>", in which case a capture is started for each client
+// connecting to address (e.g. `wireshark -k -i TCP@<address>`), or
+// "fifo:<path>", in which case a named pipe is created at path and the
+// capture is written to it once a reader opens the pipe (e.g.
+// `wireshark -k -i <path>`).
+func (m *Manager) StreamCapture(ctx context.Context, nodeName, intf, endpoint string, opts CaptureOptions) error {
+	kind, addr, ok := strings.Cut(endpoint, ":")
+	if !ok || addr == "" {
+		return fmt.Errorf("invalid stream endpoint %q, must be tcp:<address> or fifo:<path>", endpoint)
+	}
+	switch kind {
+	case "tcp":
+		return m.streamCaptureTCP(ctx, nodeName, intf, addr, opts)
+	case "fifo":
+		return m.streamCaptureFIFO(ctx, nodeName, intf, addr, opts)
+	default:
+		return fmt.Errorf("invalid stream endpoint %q, must be tcp:<address> or fifo:<path>", endpoint)
+	}
+}
+
+// cancelWriter cancels the capture it is writing for once a write fails, for
+// example when the consumer of the stream goes away.
+type cancelWriter struct {
+	w      io.Writer
+	cancel func()
+}
+
+func (c *cancelWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	if err != nil {
+		c.cancel()
+	}
+	return n, err
+}
+
+// captureTo runs a capture writing to w until ctx is canceled or a write to w
+// fails.
+func (m *Manager) captureTo(ctx context.Context, nodeName, intf string, w io.Writer, opts CaptureOptions) error {
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	err := m.Capture(cctx, nodeName, intf, &cancelWriter{w: w, cancel: cancel}, opts)
+	if cctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (m *Manager) streamCaptureTCP(ctx context.Context, nodeName, intf, addr string, opts CaptureOptions) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		lis.Close()
+	}()
+	m.logger().Infof("Serving capture of %s:%s on tcp %s", nodeName, intf, lis.Addr())
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		m.logger().Infof("Starting capture for client %s", conn.RemoteAddr())
+		err = m.captureTo(ctx, nodeName, intf, conn, opts)
+		conn.Close()
+		if err != nil {
+			return err
+		}
+		m.logger().Infof("Finished capture for client %s", conn.RemoteAddr())
+	}
+}
+
+func (m *Manager) streamCaptureFIFO(ctx context.Context, nodeName, intf, path string, opts CaptureOptions) error {
+	if err := mkfifo(path); err != nil {
+		return fmt.Errorf("failed to create fifo %q: %w", path, err)
+	}
+	defer os.Remove(path)
+	m.logger().Infof("Serving capture of %s:%s on fifo %s, waiting for reader", nodeName, intf, path)
+	// Opening a fifo for writing blocks until there is a reader.
+	ch := make(chan *os.File, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		ch <- f
+	}()
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("failed to open fifo %q: %w", path, err)
+	case f := <-ch:
+		defer f.Close()
+		return m.captureTo(ctx, nodeName, intf, f, opts)
+	}
+}
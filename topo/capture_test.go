@@ -0,0 +1,325 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h-fam/errdiff"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	ktest "k8s.io/client-go/testing"
+)
+
+func TestCaptureInterface(t *testing.T) {
+	pb := &tpb.Node{
+		Interfaces: map[string]*tpb.Interface{
+			"eth1": {Name: "Ethernet1"},
+			"eth2": {Name: "Ethernet2"},
+		},
+	}
+	tests := []struct {
+		intf string
+		want string
+	}{
+		{intf: "eth1", want: "eth1"},
+		{intf: "Ethernet2", want: "eth2"},
+		{intf: "eth0", want: "eth0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.intf, func(t *testing.T) {
+			if got := captureInterface(pb, tt.intf); got != tt.want {
+				t.Errorf("captureInterface(%q) got %q, want %q", tt.intf, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	origRemoteCommand := remoteCommand
+	origInterval := captureReadyInterval
+	defer func() {
+		remoteCommand = origRemoteCommand
+		captureReadyInterval = origInterval
+	}()
+	captureReadyInterval = time.Millisecond
+
+	type call struct {
+		subresource string
+		container   string
+		cmd         string
+	}
+	tests := []struct {
+		desc       string
+		node       string
+		intf       string
+		noTcpdump  bool
+		ephemState corev1.ContainerState
+		wantCalls  []call
+		wantErr    string
+	}{{
+		desc: "tcpdump in node container",
+		node: "r1",
+		intf: "eth1",
+		wantCalls: []call{
+			{subresource: "exec", container: "r1", cmd: "tcpdump --version"},
+			{subresource: "exec", container: "r1", cmd: "-i eth1 "},
+		},
+	}, {
+		desc: "vendor interface name",
+		node: "r1",
+		intf: "Ethernet1",
+		wantCalls: []call{
+			{subresource: "exec", container: "r1", cmd: "tcpdump --version"},
+			{subresource: "exec", container: "r1", cmd: "-i eth1 "},
+		},
+	}, {
+		desc:       "ephemeral container",
+		node:       "r1",
+		intf:       "eth1",
+		noTcpdump:  true,
+		ephemState: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		wantCalls: []call{
+			{subresource: "exec", container: "r1", cmd: "tcpdump --version"},
+			{subresource: "attach", container: "kne-capture-"},
+		},
+	}, {
+		desc:       "ephemeral container terminated",
+		node:       "r1",
+		intf:       "eth1",
+		noTcpdump:  true,
+		ephemState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error"}},
+		wantErr:    "terminated: Error",
+	}, {
+		desc:    "invalid interface",
+		node:    "r1",
+		intf:    "eth1;reboot",
+		wantErr: "invalid interface name",
+	}, {
+		desc:    "node not found",
+		node:    "dne",
+		intf:    "eth1",
+		wantErr: `node "dne" not found`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kClient := kfake.NewSimpleClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "r1"}},
+				},
+			})
+			kClient.PrependReactor("update", "pods", func(action ktest.Action) (bool, runtime.Object, error) {
+				p := action.(ktest.UpdateAction).GetObject().(*corev1.Pod)
+				for _, e := range p.Spec.EphemeralContainers {
+					p.Status.EphemeralContainerStatuses = append(p.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+						Name:  e.Name,
+						State: tt.ephemState,
+					})
+				}
+				return false, nil, nil
+			})
+			var calls []call
+			remoteCommand = func(_ kubernetes.Interface, _ *rest.Config, _ *corev1.Pod, subresource string, opts runtime.Object, _ io.Reader, stdout, _ io.Writer) error {
+				c := call{subresource: subresource}
+				switch o := opts.(type) {
+				case *corev1.PodExecOptions:
+					c.container = o.Container
+					c.cmd = strings.Join(o.Command, " ")
+				case *corev1.PodAttachOptions:
+					c.container = o.Container
+				}
+				calls = append(calls, c)
+				if c.cmd == "tcpdump --version" {
+					if tt.noTcpdump {
+						return fmt.Errorf("tcpdump: not found")
+					}
+					return nil
+				}
+				_, err := fmt.Fprint(stdout, "pcap")
+				return err
+			}
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				kClient: kClient,
+				nodes: map[string]node.Node{
+					"r1": &configurable{Impl: &node.Impl{
+						Namespace:  "test",
+						KubeClient: kClient,
+						Proto: &tpb.Node{
+							Name: "r1",
+							Interfaces: map[string]*tpb.Interface{
+								"eth1": {Name: "Ethernet1"},
+							},
+						},
+					}},
+				},
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var buf bytes.Buffer
+			err := m.Capture(ctx, tt.node, tt.intf, &buf, CaptureOptions{})
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("Capture() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("Capture() unexpected calls: got %+v, want %+v", calls, tt.wantCalls)
+			}
+			for i, want := range tt.wantCalls {
+				got := calls[i]
+				if got.subresource != want.subresource || !strings.HasPrefix(got.container, want.container) || !strings.Contains(got.cmd, want.cmd) {
+					t.Errorf("Capture() unexpected call %d: got %+v, want %+v", i, got, want)
+				}
+			}
+			if got := buf.String(); got != "pcap" {
+				t.Errorf("Capture() unexpected output: got %q, want %q", got, "pcap")
+			}
+		})
+	}
+}
+
+func TestStreamCapture(t *testing.T) {
+	origRemoteCommand := remoteCommand
+	defer func() {
+		remoteCommand = origRemoteCommand
+	}()
+	remoteCommand = func(_ kubernetes.Interface, _ *rest.Config, _ *corev1.Pod, _ string, opts runtime.Object, _ io.Reader, stdout, _ io.Writer) error {
+		if o, ok := opts.(*corev1.PodExecOptions); ok && o.Command[0] == "tcpdump" {
+			return nil
+		}
+		_, err := fmt.Fprint(stdout, "pcap")
+		return err
+	}
+	kClient := kfake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "r1"}},
+		},
+	})
+	m := &Manager{
+		topo:    &tpb.Topology{Name: "test"},
+		kClient: kClient,
+		nodes: map[string]node.Node{
+			"r1": &configurable{Impl: &node.Impl{
+				Namespace:  "test",
+				KubeClient: kClient,
+				Proto:      &tpb.Node{Name: "r1"},
+			}},
+		},
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find free port: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	fifo := filepath.Join(t.TempDir(), "r1.pcap")
+
+	tests := []struct {
+		desc     string
+		endpoint string
+		read     func() (string, error)
+		wantErr  string
+	}{{
+		desc:     "tcp",
+		endpoint: "tcp:" + addr,
+		read: func() (string, error) {
+			var conn net.Conn
+			var err error
+			for i := 0; i < 100; i++ {
+				if conn, err = net.Dial("tcp", addr); err == nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err != nil {
+				return "", err
+			}
+			defer conn.Close()
+			b := make([]byte, 4)
+			_, err = io.ReadFull(conn, b)
+			return string(b), err
+		},
+	}, {
+		desc:     "fifo",
+		endpoint: "fifo:" + fifo,
+		read: func() (string, error) {
+			var f *os.File
+			var err error
+			for i := 0; i < 100; i++ {
+				if _, err = os.Stat(fifo); err == nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if f, err = os.Open(fifo); err != nil {
+				return "", err
+			}
+			defer f.Close()
+			b := make([]byte, 4)
+			_, err = io.ReadFull(f, b)
+			return string(b), err
+		},
+	}, {
+		desc:     "invalid endpoint",
+		endpoint: "udp:" + addr,
+		wantErr:  "invalid stream endpoint",
+	}, {
+		desc:     "missing address",
+		endpoint: "tcp",
+		wantErr:  "invalid stream endpoint",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- m.StreamCapture(ctx, "r1", "eth1", tt.endpoint, CaptureOptions{})
+			}()
+			if tt.read != nil {
+				got, err := tt.read()
+				if err != nil {
+					t.Fatalf("failed to read stream: %v", err)
+				}
+				if got != "pcap" {
+					t.Errorf("StreamCapture() unexpected output: got %q, want %q", got, "pcap")
+				}
+				cancel()
+			}
+			if s := errdiff.Check(<-errCh, tt.wantErr); s != "" {
+				t.Fatalf("StreamCapture() unexpected error: %s", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+// podDeletePollInterval is the interval between checks whether the deleted
+// pods of a node are gone.
+var podDeletePollInterval = time.Second
+
+// killedPodsAnnotation is the annotation of the meshnet topology of a killed
+// node holding the pods without a controller deleted by KillNode, to be
+// recreated by RestartNode.
+const killedPodsAnnotation = "kne.openconfig.net/killed-pods"
+
+// KillNode abruptly deletes the pods of the node, without a grace period, to
+// simulate the failure of the device. The meshnet topology and the services of
+// the node are kept so it can be restarted with RestartNode. Pods managed by a
+// controller, like those created by vendor operators, are recreated by their
+// controller. The other pods are saved in an annotation of the meshnet
+// topology of the node, as nothing else can recreate them.
+func (m *Manager) KillNode(ctx context.Context, nodeName string) error {
+	pods, err := m.nodePods(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	var bare []*corev1.Pod
+	for _, p := range pods {
+		if metav1.GetControllerOf(p) == nil {
+			bare = append(bare, recreatedPod(p))
+		}
+	}
+	if len(bare) != 0 {
+		err := m.setKilledPods(ctx, nodeName, bare)
+		switch {
+		case apierrors.IsNotFound(err):
+			m.logger().WithField("node", nodeName).Warnf("Node %q has no meshnet topology to save its pods, it cannot be restarted", nodeName)
+		case err != nil:
+			return fmt.Errorf("failed to save pods of node %q: %w", nodeName, err)
+		}
+	}
+	for _, p := range pods {
+		m.logger().WithField("node", nodeName).Infof("Killing pod %q of node %q", p.Name, nodeName)
+		if err := m.kClient.CoreV1().Pods(p.Namespace).Delete(ctx, p.Name, metav1.DeleteOptions{GracePeriodSeconds: pointer.Int64(0)}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to kill pod %q of node %q: %w", p.Name, nodeName, err)
+		}
+	}
+	m.recordNodeState(ctx, nodeName, node.StatusFailed, fmt.Errorf("node killed"))
+	m.recordNodeEvent(ctx, nodeName, corev1.EventTypeWarning, EventNodeKilled, "Node killed")
+	return nil
+}
+
+// RestartNode restarts the node by deleting its pods, waiting until they are
+// gone and recreating them from their spec. The meshnet topology and the
+// services of the node are kept, so the links of the node are set up again
+// once the pods are recreated. Pods managed by a controller are recreated by
+// their controller instead. The pods of a node killed by KillNode are
+// recreated from the pods saved by KillNode.
+func (m *Manager) RestartNode(ctx context.Context, nodeName string) error {
+	if _, ok := m.nodes[nodeName]; !ok {
+		return fmt.Errorf("node %q not found", nodeName)
+	}
+	logger := m.logger().WithField("node", nodeName)
+	killed, err := m.killedPods(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	if len(killed) != 0 {
+		for _, p := range killed {
+			logger.Infof("Recreating killed pod %q of node %q", p.Name, nodeName)
+			if _, err := m.kClient.CoreV1().Pods(p.Namespace).Create(ctx, p, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("failed to recreate pod %q of node %q: %w", p.Name, nodeName, err)
+			}
+		}
+		if err := m.setKilledPods(ctx, nodeName, nil); err != nil {
+			logger.Warnf("Failed to clear killed pods of node %q: %v", nodeName, err)
+		}
+		m.recordNodeState(ctx, nodeName, node.StatusCreating, nil)
+		m.recordNodeEvent(ctx, nodeName, corev1.EventTypeNormal, EventNodeRestarted, "Node restarted")
+		return nil
+	}
+	pods, err := m.nodePods(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, p := range pods {
+		logger.Infof("Restarting pod %q of node %q", p.Name, nodeName)
+		if err := m.kClient.CoreV1().Pods(p.Namespace).Delete(ctx, p.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %q of node %q: %w", p.Name, nodeName, err)
+		}
+	}
+	for _, p := range pods {
+		if err := m.waitPodDeleted(ctx, p); err != nil {
+			return fmt.Errorf("node %q: %w", nodeName, err)
+		}
+		if metav1.GetControllerOf(p) != nil {
+			logger.Infof("Pod %q of node %q is recreated by its controller", p.Name, nodeName)
+			continue
+		}
+		if _, err := m.kClient.CoreV1().Pods(p.Namespace).Create(ctx, recreatedPod(p), metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to recreate pod %q of node %q: %w", p.Name, nodeName, err)
+		}
+	}
+	m.recordNodeState(ctx, nodeName, node.StatusCreating, nil)
+	m.recordNodeEvent(ctx, nodeName, corev1.EventTypeNormal, EventNodeRestarted, "Node restarted")
+	return nil
+}
+
+// setKilledPods saves pods in the annotation of the meshnet topology of the
+// node, removing the annotation if pods is empty.
+func (m *Manager) setKilledPods(ctx context.Context, nodeName string, pods []*corev1.Pod) error {
+	var v interface{}
+	if len(pods) != 0 {
+		b, err := json.Marshal(pods)
+		if err != nil {
+			return err
+		}
+		v = string(b)
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{killedPodsAnnotation: v},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = m.tClient.Topology(m.topo.GetName()).Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// killedPods returns the pods of the node saved by KillNode, none if the node
+// was not killed or has no meshnet topology.
+func (m *Manager) killedPods(ctx context.Context, nodeName string) ([]*corev1.Pod, error) {
+	t, err := m.tClient.Topology(m.topo.GetName()).Get(ctx, nodeName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meshnet topology of node %q: %w", nodeName, err)
+	}
+	v, ok := t.GetAnnotations()[killedPodsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var pods []*corev1.Pod
+	if err := json.Unmarshal([]byte(v), &pods); err != nil {
+		return nil, fmt.Errorf("invalid killed pods of node %q: %w", nodeName, err)
+	}
+	return pods, nil
+}
+
+// nodePods returns the pods of the node.
+func (m *Manager) nodePods(ctx context.Context, nodeName string) ([]*corev1.Pod, error) {
+	n, ok := m.nodes[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("node %q not found", nodeName)
+	}
+	pods, err := n.Pods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods of node %q: %w", nodeName, err)
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("node %q has no pods", nodeName)
+	}
+	return pods, nil
+}
+
+// waitPodDeleted waits until the pod p is gone, or has been replaced by a pod
+// of the same name.
+func (m *Manager) waitPodDeleted(ctx context.Context, p *corev1.Pod) error {
+	for {
+		cur, err := m.kClient.CoreV1().Pods(p.Namespace).Get(ctx, p.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to get pod %q: %w", p.Name, err)
+		case cur.UID != p.UID:
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("pod %q not deleted: %w", p.Name, ctx.Err())
+		case <-time.After(podDeletePollInterval):
+		}
+	}
+}
+
+// recreatedPod returns a copy of the pod p to create again, without the fields
+// set by the cluster. The pod is scheduled again.
+func recreatedPod(p *corev1.Pod) *corev1.Pod {
+	spec := p.Spec.DeepCopy()
+	spec.NodeName = ""
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        p.Name,
+			Namespace:   p.Namespace,
+			Labels:      p.Labels,
+			Annotations: p.Annotations,
+		},
+		Spec: *spec,
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+func TestChaos(t *testing.T) {
+	tests := []struct {
+		desc    string
+		restart bool
+		// killed kills the node before it is restarted.
+		killed bool
+		node   string
+		// wantPod are the labels of the pod of the node after the
+		// operation, nil if the pod is gone.
+		wantPod map[string]string
+		// wantSaved is true if the pods of the node are saved to be
+		// recreated when the node is restarted.
+		wantSaved bool
+		wantState node.Status
+		wantErr   string
+	}{{
+		desc:      "kill",
+		node:      "r1",
+		wantSaved: true,
+		wantState: node.StatusFailed,
+	}, {
+		desc:      "restart",
+		restart:   true,
+		node:      "r1",
+		wantPod:   map[string]string{"app": "r1", "topo": "test"},
+		wantState: node.StatusCreating,
+	}, {
+		desc:      "restart killed",
+		restart:   true,
+		killed:    true,
+		node:      "r1",
+		wantPod:   map[string]string{"app": "r1", "topo": "test"},
+		wantState: node.StatusCreating,
+	}, {
+		desc:      "restart pod managed by controller",
+		restart:   true,
+		node:      "r2",
+		wantState: node.StatusCreating,
+	}, {
+		desc:    "node without pod",
+		node:    "r3",
+		wantErr: `failed to get pods of node "r3"`,
+	}, {
+		desc:    "unknown node",
+		restart: true,
+		node:    "r4",
+		wantErr: `node "r4" not found`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kClient := kfake.NewSimpleClientset(
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test", UID: "uid-r1", Labels: map[string]string{"app": "r1", "topo": "test"}},
+					Spec:       corev1.PodSpec{NodeName: "worker", Containers: []corev1.Container{{Name: "r1", Image: "r1:latest"}}},
+					Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "r2", Namespace: "test", UID: "uid-r2", OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "ceoslab.arista.com/v1alpha1",
+						Kind:       "CEosLabDevice",
+						Name:       "r2",
+						Controller: pointer.Bool(true),
+					}}},
+				},
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "service-r1", Namespace: "test"}},
+			)
+			tClient, err := tfake.NewSimpleClientset(
+				&topologyv1.Topology{
+					TypeMeta:   metav1.TypeMeta{Kind: "Topology", APIVersion: "networkop.co.uk/v1beta1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+				},
+				&topologyv1.Topology{
+					TypeMeta:   metav1.TypeMeta{Kind: "Topology", APIVersion: "networkop.co.uk/v1beta1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "r2", Namespace: "test"},
+				},
+			)
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			nodes := map[string]node.Node{}
+			for _, name := range []string{"r1", "r2", "r3"} {
+				nodes[name] = &node.Impl{Namespace: "test", KubeClient: kClient, Proto: &tpb.Node{Name: name}}
+			}
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				kClient: kClient,
+				tClient: tClient,
+				nodes:   nodes,
+			}
+			ctx := context.Background()
+			if tt.killed {
+				if err := m.KillNode(ctx, tt.node); err != nil {
+					t.Fatalf("KillNode() unexpected error: %v", err)
+				}
+				if _, err := kClient.CoreV1().Pods("test").Get(ctx, tt.node, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+					t.Fatalf("pod %q not killed: %v", tt.node, err)
+				}
+			}
+			if tt.restart {
+				err = m.RestartNode(ctx, tt.node)
+			} else {
+				err = m.KillNode(ctx, tt.node)
+			}
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			p, err := kClient.CoreV1().Pods("test").Get(ctx, tt.node, metav1.GetOptions{})
+			switch {
+			case tt.wantPod == nil:
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("pod %q not deleted: %v", tt.node, err)
+				}
+			case err != nil:
+				t.Errorf("pod %q not recreated: %v", tt.node, err)
+			default:
+				if s := cmp.Diff(tt.wantPod, p.Labels); s != "" {
+					t.Errorf("recreated pod labels unexpected diff (-want +got):\n%s", s)
+				}
+				if p.Spec.NodeName != "" || p.UID == "uid-r1" || len(p.Spec.Containers) != 1 {
+					t.Errorf("recreated pod %+v not a new pod with the same spec", p)
+				}
+			}
+			// The meshnet topology and the services of the node are kept.
+			topo, err := tClient.Topology("test").Get(ctx, tt.node, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("meshnet topology of node %q not kept: %v", tt.node, err)
+			}
+			if got := node.Status(topo.Status.State); got != tt.wantState {
+				t.Errorf("node state got %s, want %s", got, tt.wantState)
+			}
+			if _, saved := topo.GetAnnotations()[killedPodsAnnotation]; saved != tt.wantSaved {
+				t.Errorf("killed pods of node %q saved %v, want %v", tt.node, saved, tt.wantSaved)
+			}
+			if _, err := kClient.CoreV1().Services("test").Get(ctx, "service-r1", metav1.GetOptions{}); err != nil {
+				t.Errorf("service of node r1 not kept: %v", err)
+			}
+		})
+	}
+}
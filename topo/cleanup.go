@@ -0,0 +1,243 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmi/errlist"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// Kinds of the resources found by Cleanup.
+const (
+	KindNamespace = "Namespace"
+	KindService   = "Service"
+	KindConfigMap = "ConfigMap"
+	KindTopology  = "Topology"
+)
+
+// Leftover is a resource created by KNE that is left over in the cluster by an
+// interrupted create or delete of a topology.
+type Leftover struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Reason is why the resource is considered left over.
+	Reason string
+}
+
+func (l *Leftover) String() string {
+	if l.Kind == KindNamespace {
+		return fmt.Sprintf("%s %s: %s", l.Kind, l.Name, l.Reason)
+	}
+	return fmt.Sprintf("%s %s/%s: %s", l.Kind, l.Namespace, l.Name, l.Reason)
+}
+
+// Cleanup finds the resources left over by interrupted creates and deletes of
+// topologies in namespaces, or in all namespaces if none are given, and
+// deletes them unless dryRun is set. The left over resources are returned,
+// sorted by namespace. Only the cluster options, like WithKubecfg and
+// WithContext, apply.
+//
+// A resource is left over if the node it belongs to has no pod: the meshnet
+// topologies, services and config maps of the node. A namespace created by KNE
+// is left over if no node has a pod in it. Cleanup must not run while a
+// topology is being created, as the nodes not created yet would be considered
+// left over.
+func Cleanup(ctx context.Context, namespaces []string, dryRun bool, opts ...Option) ([]*Leftover, error) {
+	m := &Manager{}
+	for _, o := range opts {
+		o(m)
+	}
+	if err := m.initClients(); err != nil {
+		return nil, err
+	}
+	if len(namespaces) == 0 {
+		var err error
+		if namespaces, err = m.kneNamespaces(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var leftovers []*Leftover
+	var errs errlist.List
+	for _, ns := range namespaces {
+		l, err := m.leftovers(ctx, ns)
+		if err != nil {
+			errs.Add(fmt.Errorf("namespace %q: %w", ns, err))
+			continue
+		}
+		leftovers = append(leftovers, l...)
+	}
+	if dryRun {
+		return leftovers, errs.Err()
+	}
+	for _, l := range leftovers {
+		log.Infof("Deleting %v", l)
+		if err := m.deleteLeftover(ctx, l); err != nil && !apierrors.IsNotFound(err) {
+			errs.Add(fmt.Errorf("failed to delete %s %q in namespace %q: %w", l.Kind, l.Name, l.Namespace, err))
+		}
+	}
+	return leftovers, errs.Err()
+}
+
+// kneNamespaces returns the namespaces with resources created by KNE, all of
+// them have the topo label.
+func (m *Manager) kneNamespaces(ctx context.Context) ([]string, error) {
+	opts := metav1.ListOptions{LabelSelector: "topo"}
+	found := map[string]bool{}
+	nss, err := m.kClient.CoreV1().Namespaces().List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	for _, ns := range nss.Items {
+		found[ns.Name] = true
+	}
+	pods, err := m.kClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, p := range pods.Items {
+		found[p.Namespace] = true
+	}
+	svcs, err := m.kClient.CoreV1().Services(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	for _, s := range svcs.Items {
+		found[s.Namespace] = true
+	}
+	cms, err := m.kClient.CoreV1().ConfigMaps(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config maps: %w", err)
+	}
+	for _, cm := range cms.Items {
+		found[cm.Namespace] = true
+	}
+	topos, err := m.tClient.Topology(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meshnet topologies: %w", err)
+	}
+	for _, t := range topos.Items {
+		found[t.Namespace] = true
+	}
+	var names []string
+	for ns := range found {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// leftovers returns the left over resources in namespace ns.
+func (m *Manager) leftovers(ctx context.Context, ns string) ([]*Leftover, error) {
+	nsObj, err := m.kClient.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if nsObj.DeletionTimestamp != nil {
+		// The namespace and all its resources are already being deleted.
+		return nil, nil
+	}
+	pods, err := m.kClient.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: "topo"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	hasPod := map[string]bool{}
+	for _, p := range pods.Items {
+		hasPod[p.Name] = true
+	}
+	var leftovers []*Leftover
+	add := func(kind, name, node string) {
+		leftovers = append(leftovers, &Leftover{Kind: kind, Namespace: ns, Name: name, Reason: fmt.Sprintf("node %q has no pod", node)})
+	}
+
+	// The nodes are known by their meshnet topologies, and by the labels of
+	// their services and config maps. Config maps created by older versions
+	// have no labels, these are found by the name of a known node.
+	nodes := map[string]bool{}
+	topos, err := m.tClient.Topology(ns).List(ctx, metav1.ListOptions{LabelSelector: "topo"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meshnet topologies: %w", err)
+	}
+	for _, t := range topos.Items {
+		nodes[t.Name] = true
+		if !hasPod[t.Name] {
+			add(KindTopology, t.Name, t.Name)
+		}
+	}
+	svcs, err := m.kClient.CoreV1().Services(ns).List(ctx, metav1.ListOptions{LabelSelector: "pod"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	for _, s := range svcs.Items {
+		n := s.Labels["pod"]
+		if s.Name != fmt.Sprintf("service-%s", n) {
+			continue
+		}
+		nodes[n] = true
+		if !hasPod[n] {
+			add(KindService, s.Name, n)
+		}
+	}
+	cms, err := m.kClient.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config maps: %w", err)
+	}
+	for _, cm := range cms.Items {
+		n, ok := configMapNode(&cm, nodes, hasPod)
+		if ok && !hasPod[n] {
+			add(KindConfigMap, cm.Name, n)
+		}
+	}
+	if nsObj.Labels["topo"] != "" && len(pods.Items) == 0 {
+		leftovers = append(leftovers, &Leftover{Kind: KindNamespace, Namespace: ns, Name: ns, Reason: "no node has a pod"})
+	}
+	return leftovers, nil
+}
+
+// configMapNode returns the node of the config map cm if it is the config map
+// of a node.
+func configMapNode(cm *corev1.ConfigMap, nodes, pods map[string]bool) (string, bool) {
+	if _, ok := cm.Labels["topo"]; ok {
+		n, ok := cm.Labels["app"]
+		return n, ok && cm.Name == fmt.Sprintf("%s-config", n)
+	}
+	n := strings.TrimSuffix(cm.Name, "-config")
+	return n, n != cm.Name && (nodes[n] || pods[n])
+}
+
+// deleteLeftover deletes the left over resource l.
+func (m *Manager) deleteLeftover(ctx context.Context, l *Leftover) error {
+	switch l.Kind {
+	case KindNamespace:
+		prop := metav1.DeletePropagationForeground
+		return m.kClient.CoreV1().Namespaces().Delete(ctx, l.Name, metav1.DeleteOptions{PropagationPolicy: &prop})
+	case KindService:
+		return m.kClient.CoreV1().Services(l.Namespace).Delete(ctx, l.Name, metav1.DeleteOptions{GracePeriodSeconds: pointer.Int64(0)})
+	case KindConfigMap:
+		return m.kClient.CoreV1().ConfigMaps(l.Namespace).Delete(ctx, l.Name, metav1.DeleteOptions{})
+	case KindTopology:
+		return m.tClient.Topology(l.Namespace).Delete(ctx, l.Name, metav1.DeleteOptions{})
+	}
+	return fmt.Errorf("unknown kind %q", l.Kind)
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func kneObjects() ([]runtime.Object, []runtime.Object) {
+	labels := func(kv ...string) map[string]string {
+		l := map[string]string{}
+		for i := 0; i < len(kv); i += 2 {
+			l[kv[i]] = kv[i+1]
+		}
+		return l
+	}
+	meta := func(ns, name string, l map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: ns, Name: name, Labels: l}
+	}
+	topology := func(ns, name string) runtime.Object {
+		return &topologyv1.Topology{
+			TypeMeta:   metav1.TypeMeta{Kind: "Topology", APIVersion: "networkop.co.uk/v1beta1"},
+			ObjectMeta: meta(ns, name, labels("topo", ns)),
+		}
+	}
+	kObjs := []runtime.Object{
+		// t1 is a topology whose create was interrupted after r1 had been
+		// created. r2-config was created by an older version without labels.
+		&corev1.Namespace{ObjectMeta: meta("", "t1", labels("topo", "t1"))},
+		&corev1.Pod{ObjectMeta: meta("t1", "r1", labels("app", "r1", "topo", "t1"))},
+		&corev1.Service{ObjectMeta: meta("t1", "service-r1", labels("pod", "r1", "topo", "t1"))},
+		&corev1.Service{ObjectMeta: meta("t1", "service-r2", labels("pod", "r2", "topo", "t1"))},
+		&corev1.ConfigMap{ObjectMeta: meta("t1", "r1-config", labels("app", "r1", "topo", "t1"))},
+		&corev1.ConfigMap{ObjectMeta: meta("t1", "r2-config", nil)},
+		&corev1.ConfigMap{ObjectMeta: meta("t1", "other-config", nil)},
+		&corev1.ConfigMap{ObjectMeta: meta("t1", "kube-root-ca.crt", nil)},
+		// t2 is a topology whose delete was interrupted after the pods were
+		// deleted.
+		&corev1.Namespace{ObjectMeta: meta("", "t2", labels("topo", "t2"))},
+		&corev1.ConfigMap{ObjectMeta: meta("t2", "r1-config", labels("app", "r1", "topo", "t2"))},
+		// user is a namespace not created by KNE.
+		&corev1.Namespace{ObjectMeta: meta("", "user", nil)},
+		&corev1.Pod{ObjectMeta: meta("user", "app", labels("app", "app"))},
+		&corev1.Service{ObjectMeta: meta("user", "service-r3", labels("pod", "r3", "topo", "user"))},
+		// t3 is a healthy topology.
+		&corev1.Namespace{ObjectMeta: meta("", "t3", labels("topo", "t3"))},
+		&corev1.Pod{ObjectMeta: meta("t3", "r1", labels("app", "r1", "topo", "t3"))},
+		&corev1.Service{ObjectMeta: meta("t3", "service-r1", labels("pod", "r1", "topo", "t3"))},
+		&corev1.ConfigMap{ObjectMeta: meta("t3", "r1-config", labels("app", "r1", "topo", "t3"))},
+		// other is a namespace without KNE resources.
+		&corev1.Namespace{ObjectMeta: meta("", "other", nil)},
+	}
+	tObjs := []runtime.Object{
+		topology("t1", "r1"),
+		topology("t1", "r2"),
+		topology("t2", "r1"),
+		topology("t3", "r1"),
+	}
+	return kObjs, tObjs
+}
+
+func TestCleanup(t *testing.T) {
+	tests := []struct {
+		desc       string
+		namespaces []string
+		dryRun     bool
+		want       []string
+		wantErr    string
+	}{{
+		desc: "all namespaces",
+		want: []string{
+			`Topology t1/r2: node "r2" has no pod`,
+			`Service t1/service-r2: node "r2" has no pod`,
+			`ConfigMap t1/r2-config: node "r2" has no pod`,
+			`Topology t2/r1: node "r1" has no pod`,
+			`ConfigMap t2/r1-config: node "r1" has no pod`,
+			`Namespace t2: no node has a pod`,
+			`Service user/service-r3: node "r3" has no pod`,
+		},
+	}, {
+		desc:   "dry run",
+		dryRun: true,
+		want: []string{
+			`Topology t1/r2: node "r2" has no pod`,
+			`Service t1/service-r2: node "r2" has no pod`,
+			`ConfigMap t1/r2-config: node "r2" has no pod`,
+			`Topology t2/r1: node "r1" has no pod`,
+			`ConfigMap t2/r1-config: node "r1" has no pod`,
+			`Namespace t2: no node has a pod`,
+			`Service user/service-r3: node "r3" has no pod`,
+		},
+	}, {
+		desc:       "namespaces",
+		namespaces: []string{"t2", "t3", "other"},
+		want: []string{
+			`Topology t2/r1: node "r1" has no pod`,
+			`ConfigMap t2/r1-config: node "r1" has no pod`,
+			`Namespace t2: no node has a pod`,
+		},
+	}, {
+		desc:       "missing namespace",
+		namespaces: []string{"t1", "missing"},
+		want: []string{
+			`Topology t1/r2: node "r2" has no pod`,
+			`Service t1/service-r2: node "r2" has no pod`,
+			`ConfigMap t1/r2-config: node "r2" has no pod`,
+		},
+		wantErr: `namespace "missing"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kObjs, tObjs := kneObjects()
+			kf := kfake.NewSimpleClientset(kObjs...)
+			tf, err := tfake.NewSimpleClientset(tObjs...)
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			ctx := context.Background()
+			got, err := Cleanup(ctx, tt.namespaces, tt.dryRun, WithClusterConfig(&rest.Config{}), WithKubeClient(kf), WithTopoClient(tf))
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("Cleanup() unexpected error: %s", s)
+			}
+			var gotS []string
+			for _, l := range got {
+				gotS = append(gotS, l.String())
+			}
+			if s := cmp.Diff(tt.want, gotS); s != "" {
+				t.Fatalf("Cleanup() unexpected diff (-want +got):\n%s", s)
+			}
+			for _, l := range got {
+				var err error
+				switch l.Kind {
+				case KindNamespace:
+					_, err = kf.CoreV1().Namespaces().Get(ctx, l.Name, metav1.GetOptions{})
+				case KindService:
+					_, err = kf.CoreV1().Services(l.Namespace).Get(ctx, l.Name, metav1.GetOptions{})
+				case KindConfigMap:
+					_, err = kf.CoreV1().ConfigMaps(l.Namespace).Get(ctx, l.Name, metav1.GetOptions{})
+				case KindTopology:
+					_, err = tf.Topology(l.Namespace).Get(ctx, l.Name, metav1.GetOptions{})
+				}
+				if deleted := apierrors.IsNotFound(err); deleted == tt.dryRun {
+					t.Errorf("Cleanup() %v deleted: %v, want %v", l, deleted, !tt.dryRun)
+				}
+			}
+			// The resources of nodes with pods are kept.
+			if _, err := kf.CoreV1().ConfigMaps("t3").Get(ctx, "r1-config", metav1.GetOptions{}); err != nil {
+				t.Errorf("Cleanup() deleted config map of healthy topology: %v", err)
+			}
+			if _, err := tf.Topology("t1").Get(ctx, "r1", metav1.GetOptions{}); err != nil {
+				t.Errorf("Cleanup() deleted meshnet topology of node with pod: %v", err)
+			}
+		})
+	}
+}
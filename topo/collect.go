@@ -0,0 +1,229 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/kne/topo/node"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/prototext"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// debugNamespaces are the namespaces of the cluster wide KNE components whose
+// pods and logs are included in a debug bundle.
+var debugNamespaces = []string{
+	"meshnet",
+	"metallb-system",
+	"arista-ceoslab-operator-system",
+	"srlinux-controller",
+	"ixiatg-op-system",
+}
+
+// bundle writes files to a gzipped tar archive.
+type bundle struct {
+	tw   *tar.Writer
+	now  time.Time
+	errs []string
+}
+
+func (b *bundle) add(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: b.now,
+	}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := b.tw.Write(data)
+	return err
+}
+
+// addYAML adds obj marshalled as YAML to the bundle.
+func (b *bundle) addYAML(name string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %q: %w", name, err)
+	}
+	return b.add(name, data)
+}
+
+// fail records a non fatal collection error which is included in the bundle.
+func (b *bundle) fail(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Warn(msg)
+	b.errs = append(b.errs, msg)
+}
+
+// Collect writes a gzipped tar archive to w containing debug information for
+// the topology: the topology proto, the pods, services, events and logs of
+// every node, the meshnet Topology resources, the pods and logs of the cluster
+// wide KNE components and the output of vendor diagnostics for nodes which
+// implement node.Diagnoser. Collection is best effort, failures to collect
+// individual items are recorded in errors.txt in the archive.
+func (m *Manager) Collect(ctx context.Context, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	b := &bundle{
+		tw:  tar.NewWriter(gw),
+		now: time.Now(),
+	}
+	if err := m.collect(ctx, b); err != nil {
+		return err
+	}
+	if len(b.errs) != 0 {
+		if err := b.add("errors.txt", []byte(strings.Join(b.errs, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	if err := b.tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func (m *Manager) collect(ctx context.Context, b *bundle) error {
+	if err := b.add("topology.pb.txt", []byte(prototext.Format(m.topo))); err != nil {
+		return err
+	}
+	ns := m.topo.GetName()
+	events, err := m.kClient.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.fail("failed to list events in namespace %q: %v", ns, err)
+	} else if err := b.addYAML("events.yaml", events); err != nil {
+		return err
+	}
+	topologies, err := m.topologyResources(ctx)
+	if err != nil {
+		b.fail("%v", err)
+	} else if err := b.addYAML("topologies.yaml", topologies); err != nil {
+		return err
+	}
+	var names []string
+	for name := range m.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := m.collectNode(ctx, b, name, m.nodes[name]); err != nil {
+			return err
+		}
+	}
+	for _, dns := range debugNamespaces {
+		pods, err := m.kClient.CoreV1().Pods(dns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			b.fail("failed to list pods in namespace %q: %v", dns, err)
+			continue
+		}
+		if len(pods.Items) == 0 {
+			continue
+		}
+		if err := b.addYAML(path.Join("cluster", dns, "pods.yaml"), pods); err != nil {
+			return err
+		}
+		for i := range pods.Items {
+			if err := m.collectPodLogs(ctx, b, path.Join("cluster", dns, "logs"), &pods.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Manager) collectNode(ctx context.Context, b *bundle, name string, n node.Node) error {
+	dir := path.Join("nodes", name)
+	if err := b.add(path.Join(dir, "node.pb.txt"), []byte(prototext.Format(n.GetProto()))); err != nil {
+		return err
+	}
+	pods, err := n.Pods(ctx)
+	if err != nil {
+		b.fail("failed to get pods for node %q: %v", name, err)
+	} else {
+		var ps []*corev1.Pod
+		for _, p := range pods {
+			if p != nil {
+				ps = append(ps, p)
+			}
+		}
+		if err := b.addYAML(path.Join(dir, "pods.yaml"), ps); err != nil {
+			return err
+		}
+		for _, p := range ps {
+			if err := m.collectPodLogs(ctx, b, path.Join(dir, "logs"), p); err != nil {
+				return err
+			}
+		}
+	}
+	services, err := n.Services(ctx)
+	if err != nil {
+		b.fail("failed to get services for node %q: %v", name, err)
+	} else if err := b.addYAML(path.Join(dir, "services.yaml"), services); err != nil {
+		return err
+	}
+	d, ok := n.(node.Diagnoser)
+	if !ok {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := d.Diagnostics(ctx, &buf); err != nil {
+		b.fail("failed to collect diagnostics for node %q: %v", name, err)
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	return b.add(path.Join(dir, "diagnostics.txt"), buf.Bytes())
+}
+
+// collectPodLogs adds the logs of all containers, including init containers,
+// of the pod to dir in the bundle.
+func (m *Manager) collectPodLogs(ctx context.Context, b *bundle, dir string, p *corev1.Pod) error {
+	var containers []string
+	for _, c := range p.Spec.InitContainers {
+		containers = append(containers, c.Name)
+	}
+	for _, c := range p.Spec.Containers {
+		containers = append(containers, c.Name)
+	}
+	for _, c := range containers {
+		rc, err := m.kClient.CoreV1().Pods(p.Namespace).GetLogs(p.Name, &corev1.PodLogOptions{Container: c}).Stream(ctx)
+		if err != nil {
+			b.fail("failed to get logs for %s/%s: %v", p.Name, c, err)
+			continue
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			b.fail("failed to read logs for %s/%s: %v", p.Name, c, err)
+			continue
+		}
+		if err := b.add(path.Join(dir, fmt.Sprintf("%s-%s.log", p.Name, c)), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+type diagnosable struct {
+	*node.Impl
+	dErr string
+}
+
+func (d *diagnosable) Diagnostics(_ context.Context, w io.Writer) error {
+	fmt.Fprint(w, "show tech")
+	if d.dErr != "" {
+		return fmt.Errorf(d.dErr)
+	}
+	return nil
+}
+
+func readBundle(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("failed to read gzip: %v", err)
+	}
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("failed to read %q: %v", hdr.Name, err)
+		}
+		files[hdr.Name] = string(b)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	kClient := kfake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init-r1"}},
+				Containers:     []corev1.Container{{Name: "r1"}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "r2", Namespace: "test"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "r2"}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-r1", Namespace: "test"},
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "e1", Namespace: "test"},
+			Reason:     "Started",
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "meshnet-abcde", Namespace: "meshnet"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "meshnet"}},
+			},
+		},
+	)
+	tClient, err := tfake.NewSimpleClientset(&topologyv1.Topology{
+		ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create fake topology clientset: %v", err)
+	}
+	newImpl := func(name string) *node.Impl {
+		return &node.Impl{
+			Namespace:  "test",
+			KubeClient: kClient,
+			Proto:      &tpb.Node{Name: name},
+		}
+	}
+	m := &Manager{
+		topo:    &tpb.Topology{Name: "test"},
+		kClient: kClient,
+		tClient: tClient,
+		nodes: map[string]node.Node{
+			"r1": &diagnosable{Impl: newImpl("r1")},
+			"r2": &diagnosable{Impl: newImpl("r2"), dErr: "cli not ready"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := m.Collect(context.Background(), &buf); err != nil {
+		t.Fatalf("Collect() failed: %v", err)
+	}
+	files := readBundle(t, &buf)
+	var got []string
+	for name := range files {
+		got = append(got, name)
+	}
+	want := []string{
+		"cluster/meshnet/logs/meshnet-abcde-meshnet.log",
+		"cluster/meshnet/pods.yaml",
+		"errors.txt",
+		"events.yaml",
+		"nodes/r1/diagnostics.txt",
+		"nodes/r1/logs/r1-init-r1.log",
+		"nodes/r1/logs/r1-r1.log",
+		"nodes/r1/node.pb.txt",
+		"nodes/r1/pods.yaml",
+		"nodes/r1/services.yaml",
+		"nodes/r2/diagnostics.txt",
+		"nodes/r2/logs/r2-r2.log",
+		"nodes/r2/node.pb.txt",
+		"nodes/r2/pods.yaml",
+		"topologies.yaml",
+		"topology.pb.txt",
+	}
+	if s := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); s != "" {
+		t.Errorf("Collect() unexpected files (-want +got):\n%s", s)
+	}
+	wantErrs := `failed to get services for node "r2": services "service-r2" not found
+failed to collect diagnostics for node "r2": cli not ready
+`
+	if s := cmp.Diff(wantErrs, files["errors.txt"]); s != "" {
+		t.Errorf("Collect() unexpected errors.txt (-want +got):\n%s", s)
+	}
+	if got, want := files["nodes/r1/logs/r1-r1.log"], "fake logs"; got != want {
+		t.Errorf("Collect() unexpected logs: got %q, want %q", got, want)
+	}
+	if got, want := files["nodes/r1/diagnostics.txt"], "show tech"; got != want {
+		t.Errorf("Collect() unexpected diagnostics: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/openconfig/kne/topo/node"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxProgressLine bounds the bytes kept of the line being received in the
+// output of an async config push.
+const maxProgressLine = 256
+
+// ConfigPushProgress is the progress of an async config push.
+type ConfigPushProgress struct {
+	// Bytes and Lines are the bytes and lines of device output received.
+	Bytes int64
+	Lines int
+	// LastLine is the last non empty line of device output received.
+	LastLine string
+	// Elapsed is the time since the push started, or its duration once done.
+	Elapsed time.Duration
+}
+
+// ConfigPushHandle is the handle of an async config push returned by
+// ConfigPushAsync.
+type ConfigPushHandle struct {
+	Node string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+	start  time.Time
+	w      io.Writer
+
+	mu       sync.Mutex
+	bytes    int64
+	lines    int
+	lastLine string
+	line     []byte
+	elapsed  time.Duration
+}
+
+// ConfigPushAsync starts pushing config to the provided node as
+// ConfigPushStream does and returns without waiting for the push to finish.
+// The output of the device is written to w if it is not nil and counted in
+// the progress of the push. The push is canceled with ctx or the Cancel method
+// of the returned handle. Only nodes implementing ConfigPushStreamer support
+// async pushes, as the progress of other pushes is unknown and they may not be
+// aborted cleanly, status.Unimplemented is returned for other nodes.
+func (m *Manager) ConfigPushAsync(ctx context.Context, nodeName string, r io.Reader, w io.Writer) (*ConfigPushHandle, error) {
+	n, _, err := m.configPusher(nodeName, w)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := n.(node.ConfigPushStreamer); !ok {
+		return nil, status.Errorf(codes.Unimplemented, "node %q does not implement ConfigPushStreamer interface, %s nodes do not support async config pushes", nodeName, vendorName(n.GetProto()))
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	h := &ConfigPushHandle{
+		Node:   nodeName,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		start:  time.Now(),
+		w:      w,
+	}
+	go func() {
+		defer cancel()
+		err := m.ConfigPushStream(ctx, nodeName, r, h)
+		h.mu.Lock()
+		h.err = err
+		h.elapsed = time.Since(h.start)
+		h.mu.Unlock()
+		close(h.done)
+	}()
+	return h, nil
+}
+
+// Write counts the device output in p and writes it to the writer of the
+// push, if any.
+func (h *ConfigPushHandle) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.bytes += int64(len(p))
+	for _, b := range p {
+		if b != '\n' {
+			if len(h.line) < maxProgressLine {
+				h.line = append(h.line, b)
+			}
+			continue
+		}
+		h.lines++
+		if l := bytes.TrimSpace(h.line); len(l) > 0 {
+			h.lastLine = string(l)
+		}
+		h.line = h.line[:0]
+	}
+	if h.w == nil {
+		return len(p), nil
+	}
+	return h.w.Write(p)
+}
+
+// Progress returns the current progress of the push.
+func (h *ConfigPushHandle) Progress() *ConfigPushProgress {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p := &ConfigPushProgress{
+		Bytes:    h.bytes,
+		Lines:    h.lines,
+		LastLine: h.lastLine,
+		Elapsed:  h.elapsed,
+	}
+	if l := bytes.TrimSpace(h.line); len(l) > 0 {
+		p.LastLine = string(l)
+	}
+	select {
+	case <-h.done:
+	default:
+		p.Elapsed = time.Since(h.start)
+	}
+	return p
+}
+
+// Cancel cancels the push. Wait returns once the push is aborted.
+func (h *ConfigPushHandle) Cancel() {
+	h.cancel()
+}
+
+// Done returns a channel closed when the push is finished.
+func (h *ConfigPushHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait waits for the push to finish and returns its error.
+func (h *ConfigPushHandle) Wait() error {
+	<-h.done
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/h-fam/errdiff"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// streamer is a node echoing the config pushed to it as device output. The
+// push of the config "block" blocks until it is canceled.
+type streamer struct {
+	*node.Impl
+}
+
+func (s *streamer) ConfigPush(ctx context.Context, r io.Reader) error {
+	return s.ConfigPushStream(ctx, r, io.Discard)
+}
+
+func (s *streamer) ConfigPushStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if string(b) == "block" {
+		<-ctx.Done()
+		return fmt.Errorf("push aborted: %w", ctx.Err())
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		fmt.Fprintf(w, "r1# %s\n", l)
+	}
+	return nil
+}
+
+func newPushManager(t *testing.T) *Manager {
+	t.Helper()
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	return &Manager{
+		topo: &tpb.Topology{Name: "test"},
+		nodes: map[string]node.Node{
+			"r1": &streamer{Impl: &node.Impl{Proto: &tpb.Node{Name: "r1"}}},
+			"r2": &configurable{Impl: &node.Impl{Proto: &tpb.Node{Name: "r2"}}},
+			"r3": &node.Impl{Proto: &tpb.Node{Name: "r3"}},
+		},
+		kClient: kfake.NewSimpleClientset(),
+		tClient: tf,
+	}
+}
+
+func TestConfigPushStream(t *testing.T) {
+	tests := []struct {
+		desc    string
+		node    string
+		config  string
+		want    string
+		wantErr string
+	}{{
+		desc:   "streamed",
+		node:   "r1",
+		config: "hostname r1\ncommit",
+		want:   "r1# hostname r1\nr1# commit\n",
+	}, {
+		desc:   "not streamed",
+		node:   "r2",
+		config: "hostname r2",
+	}, {
+		desc:    "push failed",
+		node:    "r2",
+		config:  "error",
+		wantErr: "error",
+	}, {
+		desc:    "not a config pusher",
+		node:    "r3",
+		wantErr: "does not implement ConfigPusher",
+	}, {
+		desc:    "unknown node",
+		node:    "r4",
+		wantErr: `node "r4" not found`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := newPushManager(t)
+			var w bytes.Buffer
+			err := m.ConfigPushStream(context.Background(), tt.node, strings.NewReader(tt.config), &w)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("ConfigPushStream() unexpected error: %s", s)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("ConfigPushStream() streamed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigPushAsync(t *testing.T) {
+	tests := []struct {
+		desc         string
+		node         string
+		config       string
+		cancel       bool
+		want         *ConfigPushProgress
+		wantErr      string
+		wantStartErr string
+	}{{
+		desc:   "success",
+		node:   "r1",
+		config: "hostname r1\n\ncommit",
+		want:   &ConfigPushProgress{Bytes: 32, Lines: 3, LastLine: "r1# commit"},
+	}, {
+		desc:    "canceled",
+		node:    "r1",
+		config:  "block",
+		cancel:  true,
+		want:    &ConfigPushProgress{},
+		wantErr: "context canceled",
+	}, {
+		desc:         "not streamed",
+		node:         "r2",
+		config:       "hostname r2",
+		wantStartErr: "does not implement ConfigPushStreamer interface",
+	}, {
+		desc:         "not a config pusher",
+		node:         "r3",
+		wantStartErr: "does not implement ConfigPusher",
+	}, {
+		desc:         "unknown node",
+		node:         "r4",
+		wantStartErr: `node "r4" not found`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := newPushManager(t)
+			var w bytes.Buffer
+			h, err := m.ConfigPushAsync(context.Background(), tt.node, strings.NewReader(tt.config), &w)
+			if s := errdiff.Check(err, tt.wantStartErr); s != "" {
+				t.Fatalf("ConfigPushAsync() unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if h.Node != tt.node {
+				t.Errorf("ConfigPushAsync() got handle of node %q, want %q", h.Node, tt.node)
+			}
+			if tt.cancel {
+				h.Cancel()
+			}
+			if s := errdiff.Check(h.Wait(), tt.wantErr); s != "" {
+				t.Errorf("Wait() unexpected error: %s", s)
+			}
+			select {
+			case <-h.Done():
+			default:
+				t.Errorf("Done() not closed after Wait()")
+			}
+			got := h.Progress()
+			if s := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(ConfigPushProgress{}, "Elapsed")); s != "" {
+				t.Errorf("Progress() unexpected diff (-want +got):\n%s", s)
+			}
+			if got.Elapsed <= 0 {
+				t.Errorf("Progress() got elapsed %v, want > 0", got.Elapsed)
+			}
+			if int64(w.Len()) != got.Bytes {
+				t.Errorf("ConfigPushAsync() wrote %d bytes, want %d", w.Len(), got.Bytes)
+			}
+		})
+	}
+}
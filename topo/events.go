@@ -0,0 +1,88 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the Kubernetes events recorded for topology operations.
+const (
+	EventTopologyCreated  = "TopologyCreated"
+	EventConfigPushed     = "ConfigPushed"
+	EventConfigPushFailed = "ConfigPushFailed"
+	EventConfigReset      = "ConfigReset"
+	EventResetFailed      = "ResetFailed"
+	EventCertInstalled    = "CertInstalled"
+	EventCertFailed       = "CertFailed"
+	EventCertRotated      = "CertRotated"
+	EventNodeKilled       = "NodeKilled"
+	EventNodeRestarted    = "NodeRestarted"
+)
+
+// eventComponent is the source component of the recorded events.
+const eventComponent = "kne"
+
+// recordEvent records a Kubernetes event for obj. Recording is best effort,
+// failures are only logged.
+func (m *Manager) recordEvent(ctx context.Context, obj corev1.ObjectReference, eventType, reason, format string, args ...interface{}) {
+	now := metav1.NewTime(time.Now())
+	ns := obj.Namespace
+	if ns == "" {
+		ns = m.topo.GetName()
+	}
+	e := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", obj.Name, now.UnixNano()),
+			Namespace: ns,
+		},
+		InvolvedObject:      obj,
+		Reason:              reason,
+		Message:             fmt.Sprintf(format, args...),
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: eventComponent},
+		ReportingController: eventComponent,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+	if _, err := m.kClient.CoreV1().Events(ns).Create(ctx, e, metav1.CreateOptions{}); err != nil {
+		m.logger().Warnf("Failed to record %s event for %s %q: %v", reason, obj.Kind, obj.Name, err)
+	}
+}
+
+// recordTopologyEvent records a Kubernetes event for the topology namespace.
+func (m *Manager) recordTopologyEvent(ctx context.Context, eventType, reason, format string, args ...interface{}) {
+	m.recordEvent(ctx, corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Namespace",
+		Name:       m.topo.GetName(),
+	}, eventType, reason, format, args...)
+}
+
+// recordNodeEvent records a Kubernetes event for the pod of the node.
+func (m *Manager) recordNodeEvent(ctx context.Context, nodeName, eventType, reason, format string, args ...interface{}) {
+	m.recordEvent(ctx, corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  m.topo.GetName(),
+		Name:       nodeName,
+	}, eventType, reason, format, args...)
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEvents(t *testing.T) {
+	certCfg := &tpb.Config{
+		Cert: &tpb.CertificateCfg{
+			Config: &tpb.CertificateCfg_SelfSigned{
+				SelfSigned: &tpb.SelfSignedCertCfg{CertName: "gnmiCert"},
+			},
+		},
+	}
+	type event struct {
+		Kind    string
+		Name    string
+		Type    string
+		Reason  string
+		Message string
+	}
+	tests := []struct {
+		desc string
+		op   func(ctx context.Context, m *Manager) error
+		want []event
+	}{{
+		desc: "config pushed",
+		op: func(ctx context.Context, m *Manager) error {
+			return m.ConfigPush(ctx, "r1", bytes.NewReader([]byte("good config")))
+		},
+		want: []event{{"Pod", "r1", corev1.EventTypeNormal, EventConfigPushed, "Config pushed"}},
+	}, {
+		desc: "config push failed",
+		op: func(ctx context.Context, m *Manager) error {
+			return m.ConfigPush(ctx, "r1", bytes.NewReader([]byte("error")))
+		},
+		want: []event{{"Pod", "r1", corev1.EventTypeWarning, EventConfigPushFailed, "Config push failed: error"}},
+	}, {
+		desc: "config reset",
+		op: func(ctx context.Context, m *Manager) error {
+			return m.ResetCfg(ctx, "r2")
+		},
+		want: []event{{"Pod", "r2", corev1.EventTypeNormal, EventConfigReset, "Config reset to vendor default"}},
+	}, {
+		desc: "config reset failed",
+		op: func(ctx context.Context, m *Manager) error {
+			return m.ResetCfg(ctx, "r3")
+		},
+		want: []event{{"Pod", "r3", corev1.EventTypeWarning, EventResetFailed, "Config reset failed: failed to reset"}},
+	}, {
+		desc: "cert installed",
+		op: func(ctx context.Context, m *Manager) error {
+			return m.GenerateSelfSigned(ctx, "r4")
+		},
+		want: []event{{"Pod", "r4", corev1.EventTypeNormal, EventCertInstalled, `Self signed cert "gnmiCert" installed`}},
+	}, {
+		desc: "cert rotated",
+		op: func(ctx context.Context, m *Manager) error {
+			return m.RotateCert(ctx, "r4")
+		},
+		want: []event{{"Pod", "r4", corev1.EventTypeNormal, EventCertRotated, `Cert "gnmiCert" rotated`}},
+	}, {
+		desc: "topology created",
+		op: func(ctx context.Context, m *Manager) error {
+			m.recordTopologyEvent(ctx, corev1.EventTypeNormal, EventTopologyCreated, "Topology %q created", "test")
+			return nil
+		},
+		want: []event{{"Namespace", "test", corev1.EventTypeNormal, EventTopologyCreated, `Topology "test" created`}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ctx := context.Background()
+			kClient := kfake.NewSimpleClientset()
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				kClient: kClient,
+				tClient: tf,
+				nodes: map[string]node.Node{
+					"r1": &configurable{Impl: &node.Impl{Proto: &tpb.Node{Name: "r1"}}},
+					"r2": &resettable{},
+					"r3": &resettable{rErr: "failed to reset"},
+					"r4": &certable{proto: &tpb.Node{Config: certCfg}},
+				},
+			}
+			// Errors are checked by the tests of the operations.
+			_ = tt.op(ctx, m)
+			events, err := kClient.CoreV1().Events("test").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list events: %v", err)
+			}
+			var got []event
+			for _, e := range events.Items {
+				if e.Source.Component != eventComponent {
+					t.Errorf("event %q has source %q, want %q", e.Name, e.Source.Component, eventComponent)
+				}
+				got = append(got, event{e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Type, e.Reason, e.Message})
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("unexpected events diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
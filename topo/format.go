@@ -0,0 +1,50 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/topofile"
+)
+
+// Formats of topology files, see package topofile.
+const (
+	FormatText = topofile.FormatText
+	FormatJSON = topofile.FormatJSON
+	FormatYAML = topofile.FormatYAML
+)
+
+// FormatOf returns the format of the topology file at path by its extension,
+// the text format for unknown extensions.
+func FormatOf(path string) string {
+	return topofile.FormatOf(path)
+}
+
+// LoadFormat loads a Topology from path in format, or in the format of its
+// extension if empty. Topologies of a version newer than CurrentVersion are
+// rejected.
+func LoadFormat(path, format string) (*tpb.Topology, error) {
+	return topofile.LoadFormat(path, format)
+}
+
+// Parse parses a Topology from b in format.
+func Parse(b []byte, format string) (*tpb.Topology, error) {
+	return topofile.Parse(b, format)
+}
+
+// Marshal returns the topology t in format.
+func Marshal(t *tpb.Topology, format string) ([]byte, error) {
+	return topofile.Marshal(t, format)
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Config push states reported in NodeHealth.
+const (
+	ConfigPushPending = "Pushing"
+	ConfigPushDone    = "Pushed"
+	ConfigPushFailed  = "Failed"
+)
+
+// TopologyHealth is the health of a topology returned by Health.
+type TopologyHealth struct {
+	Name string `json:"name"`
+	// Healthy is true if all nodes are healthy.
+	Healthy bool          `json:"healthy"`
+	Nodes   []*NodeHealth `json:"nodes"`
+}
+
+// NodeHealth is the health of a node.
+type NodeHealth struct {
+	Node  string      `json:"node"`
+	Phase node.Status `json:"phase"`
+	// Reason is set if the node failed.
+	Reason string `json:"reason,omitempty"`
+	// Restarts is the number of restarts of the containers of the node pods.
+	Restarts int32            `json:"restarts"`
+	Services []*ServiceHealth `json:"services,omitempty"`
+	// ConfigPush is the state of the last config push to the node, empty if
+	// no config was pushed since the creation of the node.
+	ConfigPush       string `json:"config_push,omitempty"`
+	ConfigPushReason string `json:"config_push_reason,omitempty"`
+	// Healthy is true if the node is ready, its services have external IPs and
+	// its last config push did not fail.
+	Healthy bool `json:"healthy"`
+}
+
+// ServiceHealth is the endpoint of a service of a node.
+type ServiceHealth struct {
+	Name      string `json:"name"`
+	Inside    uint32 `json:"inside"`
+	Outside   uint32 `json:"outside"`
+	InsideIP  string `json:"inside_ip,omitempty"`
+	OutsideIP string `json:"outside_ip,omitempty"`
+	NodePort  uint32 `json:"node_port,omitempty"`
+}
+
+// Health returns the health of the nodes of the topology in the order of the
+// nodes of the topology. Unlike Show it does not fail if services are not
+// assigned external IPs, the nodes are reported unhealthy instead.
+func (m *Manager) Health(ctx context.Context) (*TopologyHealth, error) {
+	topos, err := m.topologyResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := map[string]*topologyv1.TopologyStatus{}
+	for _, t := range topos {
+		statuses[t.Name] = &t.Status
+	}
+	h := &TopologyHealth{Name: m.topo.GetName(), Healthy: true}
+	for _, pb := range m.topo.GetNodes() {
+		n, ok := m.nodes[pb.GetName()]
+		if !ok {
+			continue
+		}
+		nh, err := m.nodeHealth(ctx, n, statuses[n.Name()])
+		if err != nil {
+			return nil, err
+		}
+		h.Healthy = h.Healthy && nh.Healthy
+		h.Nodes = append(h.Nodes, nh)
+	}
+	return h, nil
+}
+
+// nodeHealth returns the health of n. The result of the last config push is
+// read from ts, the status of the meshnet Topology resource of n, which is nil
+// if the resource does not exist.
+func (m *Manager) nodeHealth(ctx context.Context, n node.Node, ts *topologyv1.TopologyStatus) (*NodeHealth, error) {
+	phase, err := m.nodeStatus(ctx, n)
+	nh := &NodeHealth{Node: n.Name(), Phase: phase}
+	if err != nil {
+		nh.Reason = err.Error()
+	}
+	// The pods are not known yet for nodes being created.
+	if pods, err := n.Pods(ctx); err == nil {
+		for _, p := range pods {
+			for _, cs := range append(append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...), p.Status.ContainerStatuses...) {
+				nh.Restarts += cs.RestartCount
+			}
+		}
+	}
+	// Nodes without services do not have a service resource.
+	services, err := n.Services(ctx)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("could not get services for node %s: %v", n.Name(), err)
+	}
+	healthy := true
+	for _, s := range services {
+		var outsideIP string
+		if len(s.Status.LoadBalancer.Ingress) > 0 {
+			outsideIP = s.Status.LoadBalancer.Ingress[0].IP
+		} else {
+			healthy = false
+		}
+		for _, p := range s.Spec.Ports {
+			nh.Services = append(nh.Services, &ServiceHealth{
+				Name:      p.Name,
+				Inside:    uint32(p.TargetPort.IntVal),
+				Outside:   uint32(p.Port),
+				InsideIP:  s.Spec.ClusterIP,
+				OutsideIP: outsideIP,
+				NodePort:  uint32(p.NodePort),
+			})
+		}
+	}
+	sort.Slice(nh.Services, func(i, j int) bool {
+		return nh.Services[i].Outside < nh.Services[j].Outside
+	})
+	switch {
+	case phase == node.StatusConfigPushing:
+		nh.ConfigPush = ConfigPushPending
+	case ts == nil:
+	case ts.ConfigPush == ConfigPushDone:
+		nh.ConfigPush = ConfigPushDone
+	case ts.ConfigPush == ConfigPushFailed:
+		nh.ConfigPush = ConfigPushFailed
+		nh.ConfigPushReason = ts.ConfigPushReason
+	}
+	nh.Healthy = healthy && phase == node.StatusReady && nh.ConfigPush != ConfigPushFailed
+	return nh, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHealth(t *testing.T) {
+	pod := func(name string, ready bool, restarts int32) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: name, RestartCount: restarts}},
+			},
+		}
+		if ready {
+			p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		return p
+	}
+	service := func(name string, ips ...string) *corev1.Service {
+		s := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-" + name, Namespace: "test"},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: "10.0.0.1",
+				Ports: []corev1.ServicePort{{
+					Name:       "ssh",
+					Port:       22,
+					TargetPort: intstr.FromInt(22),
+					NodePort:   30022,
+				}, {
+					Name:       "gnmi",
+					Port:       9339,
+					TargetPort: intstr.FromInt(6030),
+				}},
+			},
+		}
+		for _, ip := range ips {
+			s.Status.LoadBalancer.Ingress = append(s.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: ip})
+		}
+		return s
+	}
+	topology := func(name string, status topologyv1.TopologyStatus) runtime.Object {
+		return &topologyv1.Topology{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Status:     status,
+		}
+	}
+	services := func(ip string) []*ServiceHealth {
+		return []*ServiceHealth{
+			{Name: "ssh", Inside: 22, Outside: 22, InsideIP: "10.0.0.1", OutsideIP: ip, NodePort: 30022},
+			{Name: "gnmi", Inside: 6030, Outside: 9339, InsideIP: "10.0.0.1", OutsideIP: ip},
+		}
+	}
+	tests := []struct {
+		desc  string
+		objs  []runtime.Object
+		topos []runtime.Object
+		want  *TopologyHealth
+	}{{
+		desc: "healthy",
+		objs: []runtime.Object{
+			pod("r1", true, 0), service("r1", "192.168.18.100"),
+			pod("r2", true, 2),
+		},
+		topos: []runtime.Object{
+			topology("r1", topologyv1.TopologyStatus{State: string(node.StatusReady), ConfigPush: ConfigPushDone}),
+			topology("r2", topologyv1.TopologyStatus{}),
+		},
+		want: &TopologyHealth{Name: "test", Healthy: true, Nodes: []*NodeHealth{
+			{Node: "r1", Phase: node.StatusReady, Services: services("192.168.18.100"), ConfigPush: ConfigPushDone, Healthy: true},
+			{Node: "r2", Phase: node.StatusReady, Restarts: 2, Healthy: true},
+		}},
+	}, {
+		desc: "booting",
+		objs: []runtime.Object{
+			pod("r1", true, 0), service("r1", "192.168.18.100"),
+			pod("r2", false, 0),
+		},
+		want: &TopologyHealth{Name: "test", Nodes: []*NodeHealth{
+			{Node: "r1", Phase: node.StatusReady, Services: services("192.168.18.100"), Healthy: true},
+			{Node: "r2", Phase: node.StatusBooting},
+		}},
+	}, {
+		desc: "missing external IP",
+		objs: []runtime.Object{
+			pod("r1", true, 0), service("r1"),
+			pod("r2", true, 0),
+		},
+		want: &TopologyHealth{Name: "test", Nodes: []*NodeHealth{
+			{Node: "r1", Phase: node.StatusReady, Services: services("")},
+			{Node: "r2", Phase: node.StatusReady, Healthy: true},
+		}},
+	}, {
+		desc: "config push failed",
+		objs: []runtime.Object{
+			pod("r1", true, 0), service("r1", "192.168.18.100"),
+			pod("r2", true, 0),
+		},
+		topos: []runtime.Object{
+			topology("r2", topologyv1.TopologyStatus{State: string(node.StatusFailed), Reason: "config push failed: bad config", ConfigPush: ConfigPushFailed, ConfigPushReason: "bad config"}),
+		},
+		want: &TopologyHealth{Name: "test", Nodes: []*NodeHealth{
+			{Node: "r1", Phase: node.StatusReady, Services: services("192.168.18.100"), Healthy: true},
+			{Node: "r2", Phase: node.StatusReady, ConfigPush: ConfigPushFailed, ConfigPushReason: "bad config"},
+		}},
+	}, {
+		desc: "config pushing",
+		objs: []runtime.Object{
+			pod("r1", true, 0), service("r1", "192.168.18.100"),
+			pod("r2", true, 0),
+		},
+		topos: []runtime.Object{
+			topology("r2", topologyv1.TopologyStatus{State: string(node.StatusConfigPushing), ConfigPush: ConfigPushFailed, ConfigPushReason: "bad config"}),
+		},
+		want: &TopologyHealth{Name: "test", Nodes: []*NodeHealth{
+			{Node: "r1", Phase: node.StatusReady, Services: services("192.168.18.100"), Healthy: true},
+			{Node: "r2", Phase: node.StatusConfigPushing, ConfigPush: ConfigPushPending},
+		}},
+	}, {
+		desc: "no pod",
+		objs: []runtime.Object{service("r1", "192.168.18.100"), pod("r2", true, 0)},
+		want: &TopologyHealth{Name: "test", Nodes: []*NodeHealth{
+			{Node: "r1", Phase: node.StatusUnknown, Reason: `pods "r1" not found`, Services: services("192.168.18.100")},
+			{Node: "r2", Phase: node.StatusReady, Healthy: true},
+		}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kClient := kfake.NewSimpleClientset(tt.objs...)
+			tf, err := tfake.NewSimpleClientset(tt.topos...)
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			m := &Manager{
+				topo: &tpb.Topology{Name: "test", Nodes: []*tpb.Node{{Name: "r1"}, {Name: "r2"}}},
+				nodes: map[string]node.Node{
+					"r1": &configurable{Impl: &node.Impl{Namespace: "test", KubeClient: kClient, Proto: &tpb.Node{Name: "r1"}}},
+					"r2": &configurable{Impl: &node.Impl{Namespace: "test", KubeClient: kClient, Proto: &tpb.Node{Name: "r2"}}},
+				},
+				kClient: kClient,
+				tClient: tf,
+			}
+			got, err := m.Health(context.Background())
+			if err != nil {
+				t.Fatalf("Health() unexpected error: %v", err)
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("Health() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
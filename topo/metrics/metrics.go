@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides the Prometheus metrics of topology operations.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/status"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const namespace = "kne"
+
+// Operations reported in the operation label of OperationFailures.
+const (
+	OpCreateNode   = "create_node"
+	OpNodeStatus   = "node_status"
+	OpConfigPush   = "config_push"
+	OpResetConfig  = "reset_config"
+	OpGenerateCert = "generate_cert"
+	OpRotateCert   = "rotate_cert"
+	OpDeleteNode   = "delete_node"
+)
+
+var (
+	// NodeCreateDuration is the time taken to create the resources of a node
+	// by vendor.
+	NodeCreateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "node_create_duration_seconds",
+		Help:      "Time taken to create the resources of a node.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"vendor"})
+	// ConfigPushDuration is the time taken to push config to a node by vendor.
+	ConfigPushDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "config_push_duration_seconds",
+		Help:      "Time taken to push config to a node.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"vendor"})
+	// OperationFailures counts failed operations by operation and reason.
+	OperationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "operation_failures_total",
+		Help:      "Number of failed topology operations.",
+	}, []string{"operation", "reason"})
+	// ActiveTopologies is set for each namespace with a topology created.
+	ActiveTopologies = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_topologies",
+		Help:      "Number of topologies created per namespace.",
+	}, []string{"namespace"})
+)
+
+func init() {
+	prometheus.MustRegister(NodeCreateDuration, ConfigPushDuration, OperationFailures, ActiveTopologies)
+}
+
+// Since observes the duration since start on o.
+func Since(o prometheus.Observer, start time.Time) {
+	o.Observe(time.Since(start).Seconds())
+}
+
+// Failed records a failure of op with the reason derived from err.
+func Failed(op string, err error) {
+	OperationFailures.WithLabelValues(op, Reason(err)).Inc()
+}
+
+// failure is implemented by errors providing the reason of a failure from a
+// bounded set, such as the node.FailureError returned for failed nodes.
+type failure interface {
+	FailureReason() string
+}
+
+// Reason returns a bounded reason label for err. This is the reason of node
+// failures, the reason of Kubernetes API errors or the name of the gRPC status
+// code of err, Unknown for any other error. The first error of an error list
+// is used.
+func Reason(err error) string {
+	if l, ok := err.(interface{ Errors() []error }); ok && len(l.Errors()) > 0 {
+		err = l.Errors()[0]
+	}
+	var f failure
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "DeadlineExceeded"
+	case errors.Is(err, context.Canceled):
+		return "Canceled"
+	case errors.As(err, &f) && f.FailureReason() != "":
+		return f.FailureReason()
+	case kerrors.ReasonForError(err) != metav1.StatusReasonUnknown:
+		return string(kerrors.ReasonForError(err))
+	}
+	return status.Code(err).String()
+}
+
+// Handler returns the HTTP handler serving the metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/errlist"
+	"github.com/openconfig/kne/topo/node"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestReason(t *testing.T) {
+	tests := []struct {
+		desc string
+		err  error
+		want string
+	}{{
+		desc: "status error",
+		err:  status.Errorf(codes.Unimplemented, "not implemented"),
+		want: "Unimplemented",
+	}, {
+		desc: "deadline exceeded",
+		err:  fmt.Errorf("failed to push config: %w", context.DeadlineExceeded),
+		want: "DeadlineExceeded",
+	}, {
+		desc: "canceled",
+		err:  context.Canceled,
+		want: "Canceled",
+	}, {
+		desc: "node failure",
+		err: fmt.Errorf("Node %q: Status FAILED Reason %w", "r1", &node.FailureError{
+			Reason: "CrashLoopBackOff",
+			Err:    fmt.Errorf("container r1: CrashLoopBackOff: back-off restarting"),
+		}),
+		want: "CrashLoopBackOff",
+	}, {
+		desc: "node failure in error list",
+		err: func() error {
+			var errs errlist.List
+			errs.Add(&node.FailureError{Reason: "PodFailed", Err: fmt.Errorf("pod Failed")})
+			errs.Add(fmt.Errorf("failed"))
+			return errs.Err()
+		}(),
+		want: "PodFailed",
+	}, {
+		desc: "api error",
+		err:  fmt.Errorf("failed to create pod: %w", kerrors.NewAlreadyExists(schema.GroupResource{Resource: "pods"}, "r1")),
+		want: "AlreadyExists",
+	}, {
+		desc: "wrapped api error",
+		err:  fmt.Errorf("failed to get service: %w", kerrors.NewNotFound(schema.GroupResource{Resource: "services"}, "r1")),
+		want: "NotFound",
+	}, {
+		desc: "plain error",
+		err:  fmt.Errorf("failed to push config to %q: connection reset", "r1"),
+		want: "Unknown",
+	}, {
+		desc: "other error",
+		err:  fmt.Errorf("failed"),
+		want: "Unknown",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := Reason(tt.err); got != tt.want {
+				t.Errorf("Reason() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	Failed(OpConfigPush, status.Errorf(codes.Unavailable, "connection refused"))
+	if got := testutil.ToFloat64(OperationFailures.WithLabelValues(OpConfigPush, "Unavailable")); got != 1 {
+		t.Errorf("Failed() recorded %v failures, want 1", got)
+	}
+	Since(NodeCreateDuration.WithLabelValues("ARISTA"), time.Now())
+	ActiveTopologies.WithLabelValues("test").Set(1)
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+	for _, want := range []string{
+		`kne_operation_failures_total{operation="config_push",reason="Unavailable"} 1`,
+		`kne_node_create_duration_seconds_count{vendor="ARISTA"} 1`,
+		`kne_active_topologies{namespace="test"} 1`,
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("Handler() output does not contain %q:\n%s", want, b)
+		}
+	}
+}
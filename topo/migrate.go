@@ -0,0 +1,116 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"fmt"
+	"sort"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node/cisco"
+	"github.com/openconfig/kne/topo/topofile"
+)
+
+// CurrentVersion is the version of the topology schema of this release.
+const CurrentVersion = topofile.CurrentVersion
+
+// migration upgrades a topology of the previous version to version and
+// returns a description of each change made.
+type migration struct {
+	version uint32
+	fn      func(*tpb.Topology) []string
+}
+
+// migrations are the upgrades of the topology schema in order of version.
+var migrations = []migration{
+	{version: 1, fn: migrateV1},
+}
+
+// typeMigrations are the vendors and models replacing the deprecated node
+// types. Types without a vendor implementation are not listed.
+var typeMigrations = map[tpb.Node_Type]struct {
+	vendor tpb.Vendor
+	model  string
+}{
+	tpb.Node_HOST:         {vendor: tpb.Vendor_HOST},
+	tpb.Node_ARISTA_CEOS:  {vendor: tpb.Vendor_ARISTA},
+	tpb.Node_JUNIPER_CEVO: {vendor: tpb.Vendor_JUNIPER},
+	tpb.Node_CISCO_XRD:    {vendor: tpb.Vendor_CISCO, model: cisco.ModelXRD},
+	tpb.Node_CISCO_E8000:  {vendor: tpb.Vendor_CISCO, model: "8201"},
+	tpb.Node_GOBGP:        {vendor: tpb.Vendor_GOBGP},
+	tpb.Node_NOKIA_SRL:    {vendor: tpb.Vendor_NOKIA},
+	tpb.Node_IXIA_TG:      {vendor: tpb.Vendor_KEYSIGHT},
+	tpb.Node_LEMMING:      {vendor: tpb.Vendor_OPENCONFIG},
+}
+
+// Migrate upgrades the topology t in place to CurrentVersion and returns a
+// description of each change made. An error is returned if t is of a newer
+// version.
+func Migrate(t *tpb.Topology) ([]string, error) {
+	if err := topofile.CheckVersion(t); err != nil {
+		return nil, err
+	}
+	var changes []string
+	for _, m := range migrations {
+		if m.version <= t.GetVersion() {
+			continue
+		}
+		changes = append(changes, m.fn(t)...)
+		t.Version = m.version
+	}
+	return changes, nil
+}
+
+// migrateV1 replaces the deprecated node types by vendors and sets the
+// inside ports of services not setting them to their keys.
+func migrateV1(t *tpb.Topology) []string {
+	var changes []string
+	for _, n := range t.GetNodes() {
+		if n.GetType() != tpb.Node_UNKNOWN {
+			if n.GetVendor() != tpb.Vendor_UNKNOWN {
+				// The vendor takes precedence, the type is ignored.
+				changes = append(changes, fmt.Sprintf("node %q: removed type %s, using vendor %s", n.GetName(), n.GetType(), n.GetVendor()))
+				n.Type = tpb.Node_UNKNOWN
+			} else if tm, ok := typeMigrations[n.GetType()]; ok {
+				change := fmt.Sprintf("node %q: replaced type %s by vendor %s", n.GetName(), n.GetType(), tm.vendor)
+				n.Vendor = tm.vendor
+				if n.GetModel() == "" && tm.model != "" {
+					n.Model = tm.model
+					change += fmt.Sprintf(" model %s", tm.model)
+				}
+				changes = append(changes, change)
+				n.Type = tpb.Node_UNKNOWN
+			} else {
+				changes = append(changes, fmt.Sprintf("node %q: kept type %s, it has no vendor implementation", n.GetName(), n.GetType()))
+			}
+		}
+		var ports []uint32
+		for k := range n.GetServices() {
+			ports = append(ports, k)
+		}
+		sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+		for _, k := range ports {
+			if s := n.Services[k]; s.GetInside() == 0 {
+				if s == nil {
+					s = &tpb.Service{}
+					n.Services[k] = s
+				}
+				s.Inside = k
+				changes = append(changes, fmt.Sprintf("node %q: set inside port of service %d", n.GetName(), k))
+			}
+		}
+	}
+	return changes
+}
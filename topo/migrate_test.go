@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		desc        string
+		topo        *tpb.Topology
+		want        *tpb.Topology
+		wantChanges []string
+		wantErr     string
+	}{{
+		desc: "unversioned",
+		topo: &tpb.Topology{
+			Name: "t",
+			Nodes: []*tpb.Node{{
+				Name: "r1",
+				Type: tpb.Node_ARISTA_CEOS,
+				Services: map[uint32]*tpb.Service{
+					22:  {Name: "ssh"},
+					443: {Inside: 8443},
+				},
+			}, {
+				Name: "r2",
+				Type: tpb.Node_CISCO_XRD,
+			}, {
+				Name:   "r3",
+				Type:   tpb.Node_NOKIA_SRL,
+				Vendor: tpb.Vendor_NOKIA,
+			}, {
+				Name: "r4",
+				Type: tpb.Node_QUAGGA,
+			}},
+		},
+		want: &tpb.Topology{
+			Name:    "t",
+			Version: CurrentVersion,
+			Nodes: []*tpb.Node{{
+				Name:   "r1",
+				Vendor: tpb.Vendor_ARISTA,
+				Services: map[uint32]*tpb.Service{
+					22:  {Name: "ssh", Inside: 22},
+					443: {Inside: 8443},
+				},
+			}, {
+				Name:   "r2",
+				Vendor: tpb.Vendor_CISCO,
+				Model:  "xrd",
+			}, {
+				Name:   "r3",
+				Vendor: tpb.Vendor_NOKIA,
+			}, {
+				Name: "r4",
+				Type: tpb.Node_QUAGGA,
+			}},
+		},
+		wantChanges: []string{
+			`node "r1": replaced type ARISTA_CEOS by vendor ARISTA`,
+			`node "r1": set inside port of service 22`,
+			`node "r2": replaced type CISCO_XRD by vendor CISCO model xrd`,
+			`node "r3": removed type NOKIA_SRL, using vendor NOKIA`,
+			`node "r4": kept type QUAGGA, it has no vendor implementation`,
+		},
+	}, {
+		desc: "current",
+		topo: &tpb.Topology{
+			Name:    "t",
+			Version: CurrentVersion,
+			Nodes:   []*tpb.Node{{Name: "r1", Type: tpb.Node_ARISTA_CEOS}},
+		},
+		want: &tpb.Topology{
+			Name:    "t",
+			Version: CurrentVersion,
+			Nodes:   []*tpb.Node{{Name: "r1", Type: tpb.Node_ARISTA_CEOS}},
+		},
+	}, {
+		desc:    "newer",
+		topo:    &tpb.Topology{Name: "t", Version: CurrentVersion + 1},
+		wantErr: "newer than the supported version",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			changes, err := Migrate(tt.topo)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("Migrate() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if s := cmp.Diff(tt.wantChanges, changes); s != "" {
+				t.Errorf("Migrate() unexpected changes (-want +got):\n%s", s)
+			}
+			if s := cmp.Diff(tt.want, tt.topo, protocmp.Transform()); s != "" {
+				t.Errorf("Migrate() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
@@ -27,12 +27,10 @@ import (
 	scraplinetwork "github.com/scrapli/scrapligo/driver/network"
 	scrapliopts "github.com/scrapli/scrapligo/driver/options"
 	scrapliutil "github.com/scrapli/scrapligo/util"
-	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
 
 	ceos "github.com/aristanetworks/arista-ceoslab-operator/api/v1alpha1"
 	ceosclient "github.com/aristanetworks/arista-ceoslab-operator/api/v1alpha1/clientset"
@@ -55,9 +53,11 @@ type Node struct {
 
 // Add validations for interfaces the node provides
 var (
-	_ node.Certer       = (*Node)(nil)
-	_ node.ConfigPusher = (*Node)(nil)
-	_ node.Resetter     = (*Node)(nil)
+	_ node.Certer             = (*Node)(nil)
+	_ node.ConfigPusher       = (*Node)(nil)
+	_ node.ConfigPushStreamer = (*Node)(nil)
+	_ node.Resetter           = (*Node)(nil)
+	_ node.Diagnoser          = (*Node)(nil)
 
 	ethIntfRe  = regexp.MustCompile(`^Ethernet\d+(?:/\d+)?(?:/\d+)?$`)
 	mgmtIntfRe = regexp.MustCompile(`^Management\d+(?:/\d+)?$`)
@@ -93,7 +93,7 @@ func (n *Node) Create(ctx context.Context) error {
 }
 
 func (n *Node) CreateCRD(ctx context.Context) error {
-	log.Infof("Creating new CEosLabDevice CRD for node: %v", n.Name())
+	n.Logger().Infof("Creating new CEosLabDevice CRD for node: %v", n.Name())
 	proto := n.GetProto()
 	config := proto.GetConfig()
 	device := &ceos.CEosLabDevice{
@@ -161,21 +161,21 @@ func (n *Node) CreateCRD(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	// Wait for pods
-	w, err := n.KubeClient.CoreV1().Pods(n.Namespace).Watch(ctx, metav1.ListOptions{
-		FieldSelector: fields.SelectorFromSet(fields.Set{metav1.ObjectNameField: n.Name()}).String(),
-	})
-	if err != nil {
+	// Wait for the pod to be created by the controller
+	if _, err := n.WaitForPod(ctx, func(*corev1.Pod) bool { return true }); err != nil {
 		return err
 	}
-	for e := range w.ResultChan() {
-		p := e.Object.(*corev1.Pod)
-		if p.Status.Phase == corev1.PodPending {
-			break
-		}
-	}
-	log.Infof("Created CEosLabDevice CRD for node: %v", n.Name())
-	return err
+	n.Logger().Infof("Created CEosLabDevice CRD for node: %v", n.Name())
+	return nil
+}
+
+// readyCmd succeeds once the cEOS agents are up and the CLI accepts commands.
+var readyCmd = []string{"Cli", "-p", "15", "-c", "show version"}
+
+// Status returns the current node state. The node is only reported running
+// once the cEOS agents are up, which happens some time after the pod is ready.
+func (n *Node) Status(ctx context.Context) (node.Status, error) {
+	return n.ReadyStatus(ctx, readyCmd, "")
 }
 
 func (n *Node) Delete(ctx context.Context) error {
@@ -190,7 +190,7 @@ func (n *Node) Delete(ctx context.Context) error {
 	if err := n.DeleteConfig(ctx); err != nil {
 		return err
 	}
-	log.Infof("Deleted CEosLabDevice resources of node: %v", n.Name())
+	n.Logger().Infof("Deleted CEosLabDevice resources of node: %v", n.Name())
 	return nil
 }
 
@@ -198,10 +198,11 @@ func (n *Node) Delete(ctx context.Context) error {
 // to accept inputs.
 // scrapligo options can be provided to this function for a caller to modify scrapligo platform.
 // For example, mock transport can be set via options
-func (n *Node) SpawnCLIConn() error {
+func (n *Node) SpawnCLIConn(extraOpts ...scrapliutil.Option) error {
 	opts := []scrapliutil.Option{
 		scrapliopts.WithAuthBypass(),
 	}
+	opts = append(opts, extraOpts...)
 
 	// add options defined in test package
 	opts = append(opts, n.testOpts...)
@@ -222,38 +223,52 @@ func (n *Node) GenerateSelfSigned(ctx context.Context) error {
 }
 
 func (n *Node) ConfigPush(ctx context.Context, r io.Reader) error {
-	log.Infof("%s - pushing config", n.Name())
+	return n.ConfigPushStream(ctx, r, nil)
+}
+
+// ConfigPushStream pushes the config read from r, writing the output of the
+// device to w if not nil.
+func (n *Node) ConfigPushStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	n.Logger().Infof("%s - pushing config", n.Name())
 
 	cfg, err := io.ReadAll(r)
 	cfgs := string(cfg)
 
-	log.Debug(cfgs)
+	n.Logger().Debug(cfgs)
 
 	if err != nil {
 		return err
 	}
 
-	err = n.SpawnCLIConn()
+	var opts []scrapliutil.Option
+	if w != nil {
+		opts = append(opts, scrapliopts.WithChannelLog(w))
+	}
+	err = n.SpawnCLIConn(opts...)
 	if err != nil {
 		return err
 	}
 
 	defer n.cliConn.Close()
 
-	resp, err := n.cliConn.SendConfig(cfgs)
+	err = n.RunCLI(ctx, node.AbortCLI(n.cliConn), func() error {
+		resp, err := n.cliConn.SendConfig(cfgs)
+		if err != nil {
+			return err
+		}
+		return resp.Failed
+	})
 	if err != nil {
 		return err
 	}
 
-	if resp.Failed == nil {
-		log.Infof("%s - finshed config push", n.Impl.Proto.Name)
-	}
+	n.Logger().Infof("%s - finshed config push", n.Impl.Proto.Name)
 
-	return resp.Failed
+	return nil
 }
 
 func (n *Node) ResetCfg(ctx context.Context) error {
-	log.Infof("%s resetting config", n.Name())
+	n.Logger().Infof("%s resetting config", n.Name())
 
 	err := n.SpawnCLIConn()
 	if err != nil {
@@ -272,12 +287,29 @@ func (n *Node) ResetCfg(ctx context.Context) error {
 	}
 
 	if resp.Failed == nil {
-		log.Infof("%s - finshed resetting config", n.Name())
+		n.Logger().Infof("%s - finshed resetting config", n.Name())
 	}
 
 	return resp.Failed
 }
 
+// diagnosticCmds are the commands run to collect diagnostics from the node.
+var diagnosticCmds = []string{
+	"show version",
+	"show tech-support",
+}
+
+// Diagnostics writes the output of the vendor diagnostic commands to w.
+func (n *Node) Diagnostics(ctx context.Context, w io.Writer) error {
+	n.Logger().Infof("%s - collecting diagnostics", n.Name())
+
+	if err := n.SpawnCLIConn(); err != nil {
+		return err
+	}
+
+	return n.WriteDiagnostics(ctx, n.cliConn, diagnosticCmds, w)
+}
+
 func defaults(pb *tpb.Node) *tpb.Node {
 	if pb == nil {
 		pb = &tpb.Node{
@@ -22,7 +22,6 @@ import (
 	"strconv"
 
 	"github.com/openconfig/kne/topo/node"
-	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -57,16 +56,16 @@ type Node struct {
 }
 
 func (n *Node) Create(ctx context.Context) error {
-	log.Infof("Creating Cisco %s node resource %s", n.Proto.Model, n.Name())
+	n.Logger().Infof("Creating Cisco %s node resource %s", n.Proto.Model, n.Name())
 
 	if err := n.CreateConfig(ctx); err != nil {
 		return fmt.Errorf("node %s failed to create config-map %w", n.Name(), err)
 	}
-	log.Infof("Created Cisco %s node %s configmap", n.Proto.Model, n.Name())
+	n.Logger().Infof("Created Cisco %s node %s configmap", n.Proto.Model, n.Name())
 	pb := n.Proto
-	initContainerImage := pb.Config.InitImage
-	if initContainerImage == "" {
-		initContainerImage = node.DefaultInitContainerImage
+	initContainers, err := node.InitContainers(pb)
+	if err != nil {
+		return err
 	}
 	secContext := &corev1.SecurityContext{
 		Privileged: pointer.Bool(true),
@@ -80,6 +79,9 @@ func (n *Node) Create(ctx context.Context) error {
 			},
 		}
 	}
+	if sc := node.ToSecurityContext(pb.Config.GetSecurityContext()); sc != nil {
+		secContext = sc
+	}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: n.Name(),
@@ -89,15 +91,7 @@ func (n *Node) Create(ctx context.Context) error {
 			},
 		},
 		Spec: corev1.PodSpec{
-			InitContainers: []corev1.Container{{
-				Name:  fmt.Sprintf("init-%s", n.Name()),
-				Image: initContainerImage,
-				Args: []string{
-					fmt.Sprintf("%d", len(pb.GetInterfaces())+1),
-					fmt.Sprintf("%d", pb.GetConfig().Sleep),
-				},
-				ImagePullPolicy: "IfNotPresent",
-			}},
+			InitContainers: initContainers,
 			Containers: []corev1.Container{{
 				Name:            n.Name(),
 				Image:           pb.Config.Image,
@@ -121,6 +115,7 @@ func (n *Node) Create(ctx context.Context) error {
 					},
 				},
 			}},
+			ImagePullSecrets:              node.ToImagePullSecrets(pb),
 			TerminationGracePeriodSeconds: pointer.Int64(0),
 			NodeSelector:                  map[string]string{},
 			Affinity: &corev1.Affinity{
@@ -162,19 +157,41 @@ func (n *Node) Create(ctx context.Context) error {
 			})
 		}
 	}
+	node.ApplyScheduling(&pod.Spec, pb)
+	if err := node.ApplyVolumes(&pod.Spec, pb); err != nil {
+		return err
+	}
+	if err := node.ApplySidecars(&pod.Spec, pb); err != nil {
+		return err
+	}
 	sPod, err := n.KubeClient.CoreV1().Pods(n.Namespace).Create(ctx, pod, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create pod for %q: %w", pb.Name, err)
 	}
-	log.Debugf("Pod created:\n%+v\n", sPod)
-	log.Infof("Created Cisco %s node resource %s pod", n.Proto.Model, n.Name())
+	n.Logger().Debugf("Pod created:\n%+v\n", sPod)
+	n.Logger().Infof("Created Cisco %s node resource %s pod", n.Proto.Model, n.Name())
 	if err := n.CreateService(ctx); err != nil {
 		return err
 	}
-	log.Infof("Created Cisco %s node resource %s services", n.Proto.Model, n.Name())
+	n.Logger().Infof("Created Cisco %s node resource %s services", n.Proto.Model, n.Name())
 	return nil
 }
 
+// xrdReadyCmd succeeds once the XR process manager has started the XR
+// processes and the XR CLI accepts commands.
+var xrdReadyCmd = []string{"/pkg/bin/xr_cli.sh", "show version"}
+
+// Status returns the current node state. XRd nodes are only reported running
+// once XR is ready to be configured, which happens some time after the pod is
+// ready. The other models run XR in a VM inside the pod, with no XR CLI in the
+// container to check, so the readiness of the pod is reported for them.
+func (n *Node) Status(ctx context.Context) (node.Status, error) {
+	if n.Proto.Model != ModelXRD {
+		return n.Impl.Status(ctx)
+	}
+	return n.ReadyStatus(ctx, xrdReadyCmd, "IOS XR")
+}
+
 func constraints(pb *tpb.Node) *tpb.Node {
 	if pb.Constraints == nil {
 		pb.Constraints = map[string]string{}
@@ -270,6 +287,20 @@ func setXRDEnv(pb *tpb.Node) error {
 	return nil
 }
 
+// InterfaceNames returns the Cisco names of the interfaces of the node by the
+// model of the node.
+func (n *Node) InterfaceNames() (map[string]string, error) {
+	names := map[string]string{}
+	for eth := range n.Proto.GetInterfaces() {
+		name, err := getCiscoInterfaceID(n.Proto, eth)
+		if err != nil {
+			return nil, err
+		}
+		names[eth] = name
+	}
+	return names, nil
+}
+
 func getCiscoInterfaceID(pb *tpb.Node, eth string) (string, error) {
 	ethWithIDRegx := regexp.MustCompile(`e(t(h(e(r(n(e(t)*)*)*)*)*)*)\d+`) // check for e|et|eth|....
 	ethRegx := regexp.MustCompile(`e(t(h(e(r(n(e(t)*)*)*)*)*)*)`)
@@ -656,3 +656,61 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestInterfaceNames(t *testing.T) {
+	tests := []struct {
+		desc    string
+		pb      *tpb.Node
+		want    map[string]string
+		wantErr string
+	}{{
+		desc: "xrd",
+		pb: &tpb.Node{
+			Name: "pod1",
+			Interfaces: map[string]*tpb.Interface{
+				"eth1": {},
+				"eth2": {Name: "GIG1"},
+			},
+		},
+		want: map[string]string{
+			"eth1": "GigabitEthernet0/0/0/0",
+			"eth2": "GIG1",
+		},
+	}, {
+		desc: "8201",
+		pb: &tpb.Node{
+			Name:  "pod1",
+			Model: "8201",
+			Interfaces: map[string]*tpb.Interface{
+				"eth1":  {},
+				"eth25": {},
+			},
+		},
+		want: map[string]string{
+			"eth1":  "FourHundredGigE0/0/0/0",
+			"eth25": "HundredGigE0/0/0/24",
+		},
+	}, {
+		desc: "unsupported interface",
+		pb: &tpb.Node{
+			Name:  "pod1",
+			Model: "8101-32H",
+			Interfaces: map[string]*tpb.Interface{
+				"eth33": {},
+			},
+		},
+		wantErr: "eth1..eth32 is supported",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			n := &Node{Impl: &node.Impl{Proto: tt.pb}}
+			got, err := n.InterfaceNames()
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("InterfaceNames() unexpected error: %s", s)
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("InterfaceNames() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
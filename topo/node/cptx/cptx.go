@@ -17,7 +17,6 @@ import (
 	scrapliopts "github.com/scrapli/scrapligo/driver/options"
 	scrapliutil "github.com/scrapli/scrapligo/util"
 	scraplicfg "github.com/scrapli/scrapligocfg"
-	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -60,19 +59,22 @@ type Node struct {
 
 // Add validations for interfaces the node provides
 var (
-	_ node.ConfigPusher = (*Node)(nil)
-	_ node.Resetter     = (*Node)(nil)
+	_ node.ConfigPusher       = (*Node)(nil)
+	_ node.ConfigPushStreamer = (*Node)(nil)
+	_ node.Resetter           = (*Node)(nil)
+	_ node.Diagnoser          = (*Node)(nil)
 )
 
 // SpawnCLIConn spawns a CLI connection towards a Network OS using `kubectl exec` terminal and ensures CLI is ready
 // to accept inputs.
 // scrapligo options can be provided to this function for a caller to modify scrapligo platform.
 // For example, mock transport can be set via options
-func (n *Node) SpawnCLIConn() error {
+func (n *Node) SpawnCLIConn(extraOpts ...scrapliutil.Option) error {
 	opts := []scrapliutil.Option{
 		scrapliopts.WithAuthBypass(),
 		scrapliopts.WithTimeoutOps(scrapliOperationTimeout),
 	}
+	opts = append(opts, extraOpts...)
 
 	// add options defined in test package
 	opts = append(opts, n.testOpts...)
@@ -86,24 +88,46 @@ func (n *Node) SpawnCLIConn() error {
 }
 
 func (n *Node) ConfigPush(ctx context.Context, r io.Reader) error {
-	log.Infof("%s - pushing config", n.Name())
+	return n.ConfigPushStream(ctx, r, nil)
+}
+
+// ConfigPushStream pushes the config read from r with a load merge and
+// commit, writing the output of the device to w if not nil.
+func (n *Node) ConfigPushStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	n.Logger().Infof("%s - pushing config", n.Name())
 
 	cfg, err := io.ReadAll(r)
 	cfgs := string(cfg)
 
-	log.Debug(cfgs)
+	n.Logger().Debug(cfgs)
 
 	if err != nil {
 		return err
 	}
 
-	err = n.SpawnCLIConn()
+	var opts []scrapliutil.Option
+	if w != nil {
+		opts = append(opts, scrapliopts.WithChannelLog(w))
+	}
+	err = n.SpawnCLIConn(opts...)
 	if err != nil {
 		return err
 	}
 
 	defer n.cliConn.Close()
 
+	if err := n.RunCLI(ctx, node.AbortCLI(n.cliConn), func() error { return n.loadConfig(cfgs) }); err != nil {
+		return err
+	}
+
+	n.Logger().Infof("%s - finished config push", n.Name())
+
+	return nil
+}
+
+// loadConfig merges cfgs into the candidate config over the CLI connection
+// and commits it.
+func (n *Node) loadConfig(cfgs string) error {
 	// use a static candidate file name for test transport
 	var candidateConfigFile string
 	if len(n.testOpts) != 0 {
@@ -139,17 +163,11 @@ func (n *Node) ConfigPush(ctx context.Context, r io.Reader) error {
 	if err != nil {
 		return err
 	}
-	if resp.Failed != nil {
-		return resp.Failed
-	}
-
-	log.Infof("%s - finished config push", n.Name())
-
-	return nil
+	return resp.Failed
 }
 
 func (n *Node) ResetCfg(ctx context.Context) error {
-	log.Infof("%s - resetting config", n.Name())
+	n.Logger().Infof("%s - resetting config", n.Name())
 
 	err := n.SpawnCLIConn()
 	if err != nil {
@@ -169,24 +187,41 @@ func (n *Node) ResetCfg(ctx context.Context) error {
 	}
 
 	if resp.Failed == nil {
-		log.Infof("%s - finshed resetting config", n.Name())
+		n.Logger().Infof("%s - finshed resetting config", n.Name())
 	}
 
 	return resp.Failed
 }
 
+// diagnosticCmds are the commands run to collect diagnostics from the node.
+var diagnosticCmds = []string{
+	"show version",
+	"request support information",
+}
+
+// Diagnostics writes the output of the vendor diagnostic commands to w.
+func (n *Node) Diagnostics(ctx context.Context, w io.Writer) error {
+	n.Logger().Infof("%s - collecting diagnostics", n.Name())
+
+	if err := n.SpawnCLIConn(); err != nil {
+		return err
+	}
+
+	return n.WriteDiagnostics(ctx, n.cliConn, diagnosticCmds, w)
+}
+
 func (n *Node) Create(ctx context.Context) error {
-	log.Infof("Creating cPTX node resource %s", n.Name())
+	n.Logger().Infof("Creating cPTX node resource %s", n.Name())
 
 	if err := n.CreateConfig(ctx); err != nil {
 		return fmt.Errorf("node %s failed to create config-map %w", n.Name(), err)
 	}
-	log.Infof("Created cPTX node %s configmap", n.Name())
+	n.Logger().Infof("Created cPTX node %s configmap", n.Name())
 
 	pb := n.Proto
-	initContainerImage := pb.Config.InitImage
-	if initContainerImage == "" {
-		initContainerImage = node.DefaultInitContainerImage
+	initContainers, err := node.InitContainers(pb)
+	if err != nil {
+		return err
 	}
 
 	// downward api - pass some useful values to container
@@ -195,6 +230,16 @@ func (n *Node) Create(ctx context.Context) error {
 	}
 	pb.Config.Env["CPTX_CPU_LIMIT"] = pb.Constraints["cpu"]
 	pb.Config.Env["CPTX_MEMORY_LIMIT"] = pb.Constraints["memory"]
+	secContext := &corev1.SecurityContext{
+		Privileged: pointer.Bool(true),
+		RunAsUser:  pointer.Int64(0),
+		Capabilities: &corev1.Capabilities{
+			Add: []corev1.Capability{"SYS_ADMIN"},
+		},
+	}
+	if sc := node.ToSecurityContext(pb.Config.GetSecurityContext()); sc != nil {
+		secContext = sc
+	}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: n.Name(),
@@ -204,15 +249,7 @@ func (n *Node) Create(ctx context.Context) error {
 			},
 		},
 		Spec: corev1.PodSpec{
-			InitContainers: []corev1.Container{{
-				Name:  fmt.Sprintf("init-%s", n.Name()),
-				Image: initContainerImage,
-				Args: []string{
-					fmt.Sprintf("%d", len(pb.GetInterfaces())+1),
-					fmt.Sprintf("%d", pb.GetConfig().Sleep),
-				},
-				ImagePullPolicy: "IfNotPresent",
-			}},
+			InitContainers: initContainers,
 			Containers: []corev1.Container{{
 				Name:            n.Name(),
 				Image:           pb.Config.Image,
@@ -221,13 +258,7 @@ func (n *Node) Create(ctx context.Context) error {
 				Env:             node.ToEnvVar(pb.Config.Env),
 				Resources:       node.ToResourceRequirements(pb.Constraints),
 				ImagePullPolicy: "IfNotPresent",
-				SecurityContext: &corev1.SecurityContext{
-					Privileged: pointer.Bool(true),
-					RunAsUser:  pointer.Int64(0),
-					Capabilities: &corev1.Capabilities{
-						Add: []corev1.Capability{"SYS_ADMIN"},
-					},
-				},
+				SecurityContext: secContext,
 				VolumeMounts: []corev1.VolumeMount{{
 					Name:      fmt.Sprintf("%s-run-mount", pb.Name),
 					ReadOnly:  false,
@@ -242,6 +273,7 @@ func (n *Node) Create(ctx context.Context) error {
 					},
 				},
 			}},
+			ImagePullSecrets:              node.ToImagePullSecrets(pb),
 			TerminationGracePeriodSeconds: pointer.Int64(0),
 			NodeSelector:                  map[string]string{},
 			Affinity: &corev1.Affinity{
@@ -283,16 +315,23 @@ func (n *Node) Create(ctx context.Context) error {
 			})
 		}
 	}
+	node.ApplyScheduling(&pod.Spec, pb)
+	if err := node.ApplyVolumes(&pod.Spec, pb); err != nil {
+		return err
+	}
+	if err := node.ApplySidecars(&pod.Spec, pb); err != nil {
+		return err
+	}
 	sPod, err := n.KubeClient.CoreV1().Pods(n.Namespace).Create(ctx, pod, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create pod for %q: %w", pb.Name, err)
 	}
-	log.Debugf("Pod created:\n%+v\n", sPod)
-	log.Infof("Created cPTX node resource %s pod", n.Name())
+	n.Logger().Debugf("Pod created:\n%+v\n", sPod)
+	n.Logger().Infof("Created cPTX node resource %s pod", n.Name())
 	if err := n.CreateService(ctx); err != nil {
 		return err
 	}
-	log.Infof("Created cPTX node resource %s services", n.Name())
+	n.Logger().Infof("Created cPTX node resource %s services", n.Name())
 	return nil
 }
 
@@ -0,0 +1,190 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package external implements nodes for devices outside of the cluster, e.g.
+// physical DUTs in the lab. The interfaces of an external node are bridged to
+// VLANs or VXLAN tunnels on the network interfaces of the cluster node running
+// its pod, so the emulated nodes linked to it are wired to the device.
+package external
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/netip"
+	"sort"
+	"strings"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+)
+
+const (
+	// bridgeContainer is the name of the init container bridging the
+	// interfaces to the external device.
+	bridgeContainer = "init-external"
+	// hostProcVolume is the name of the volume of the /proc of the cluster
+	// node, used to enter its network namespace.
+	hostProcVolume = "host-proc"
+	hostProcPath   = "/host/proc"
+	// defaultVxlanPort is the IANA assigned VXLAN port.
+	defaultVxlanPort = 4789
+)
+
+func New(nodeImpl *node.Impl) (node.Node, error) {
+	if nodeImpl == nil {
+		return nil, fmt.Errorf("nodeImpl cannot be nil")
+	}
+	if nodeImpl.Proto == nil {
+		return nil, fmt.Errorf("nodeImpl.Proto cannot be nil")
+	}
+	cfg := defaults(nodeImpl.Proto)
+	if err := bridgeInit(cfg, nodeImpl.Namespace); err != nil {
+		return nil, err
+	}
+	nodeImpl.Proto = cfg
+	n := &Node{
+		Impl: nodeImpl,
+	}
+	return n, nil
+}
+
+type Node struct {
+	*node.Impl
+}
+
+func defaults(pb *tpb.Node) *tpb.Node {
+	if pb.Config == nil {
+		pb.Config = &tpb.Config{}
+	}
+	if len(pb.GetConfig().GetCommand()) == 0 {
+		pb.Config.Command = []string{"/bin/sh", "-c", "sleep 2000000000000"}
+	}
+	if pb.Config.EntryCommand == "" {
+		pb.Config.EntryCommand = fmt.Sprintf("kubectl exec -it %s -- sh", pb.Name)
+	}
+	if pb.Config.Image == "" {
+		pb.Config.Image = "nicolaka/netshoot:latest"
+	}
+	if pb.Config.SecurityContext == nil {
+		// The interfaces are bridged by an init container, the node container
+		// only sleeps.
+		pb.Config.SecurityContext = &tpb.SecurityContext{}
+	}
+	return pb
+}
+
+// bridgeInit adds an init container bridging the interfaces of the node to
+// their VLAN or VXLAN tunnel, run once the interfaces are added to the pod.
+// The VLAN and VXLAN devices are created in the network namespace of the
+// cluster node and moved into the pod, where the traffic of each interface is
+// redirected to its device and back. The devices are removed with the network
+// namespace of the pod. The container runs the image of the node, which must
+// provide the ip, tc and nsenter commands.
+func bridgeInit(pb *tpb.Node, namespace string) error {
+	for _, ic := range pb.GetConfig().GetInitContainers() {
+		if ic.GetName() == bridgeContainer {
+			return nil
+		}
+	}
+	var names []string
+	for k := range pb.GetInterfaces() {
+		names = append(names, k)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	hostNet := fmt.Sprintf("nsenter --net=%s/1/ns/net", hostProcPath)
+	cmds := []string{"set -e"}
+	for _, k := range names {
+		ext := pb.GetInterfaces()[k].GetExternal()
+		if ext == nil {
+			return fmt.Errorf("node %q: interface %q is not bridged to the external device", pb.GetName(), k)
+		}
+		// The device is renamed to x<interface> in the pod.
+		if !node.ValidInterfaceName("x" + k) {
+			return fmt.Errorf("node %q: invalid interface name %q", pb.GetName(), k)
+		}
+		if d := ext.GetDevice(); d != "" && !node.ValidInterfaceName(d) {
+			return fmt.Errorf("node %q: invalid device %q of interface %q", pb.GetName(), d, k)
+		}
+		// The device is created with a name unique to the interface, as the
+		// network namespace of the cluster node is shared by all its pods.
+		h := fnv.New32a()
+		h.Write([]byte(namespace + "/" + pb.GetName() + "/" + k))
+		tmp := fmt.Sprintf("kne%08x", h.Sum32())
+		var add string
+		switch encap := ext.GetEncap().(type) {
+		case *tpb.ExternalInterface_Vlan:
+			if encap.Vlan < 1 || encap.Vlan > 4094 {
+				return fmt.Errorf("node %q: invalid VLAN %d of interface %q", pb.GetName(), encap.Vlan, k)
+			}
+			if ext.GetDevice() == "" {
+				return fmt.Errorf("node %q: interface %q has a VLAN but no device", pb.GetName(), k)
+			}
+			add = fmt.Sprintf("ip link add link %s name %s type vlan id %d", ext.GetDevice(), tmp, encap.Vlan)
+		case *tpb.ExternalInterface_Vxlan:
+			vx := encap.Vxlan
+			if vx.GetVni() < 1 || vx.GetVni() > 1<<24-1 {
+				return fmt.Errorf("node %q: invalid VNI %d of interface %q", pb.GetName(), vx.GetVni(), k)
+			}
+			remote, err := netip.ParseAddr(vx.GetRemote())
+			if err != nil {
+				return fmt.Errorf("node %q: invalid VXLAN remote of interface %q: %v", pb.GetName(), k, err)
+			}
+			port := vx.GetPort()
+			if port == 0 {
+				port = defaultVxlanPort
+			}
+			if port > 65535 {
+				return fmt.Errorf("node %q: invalid VXLAN port %d of interface %q", pb.GetName(), port, k)
+			}
+			add = fmt.Sprintf("ip link add %s type vxlan id %d remote %s dstport %d", tmp, vx.GetVni(), remote, port)
+			if d := ext.GetDevice(); d != "" {
+				add += " dev " + d
+			}
+		default:
+			return fmt.Errorf("node %q: interface %q has no VLAN or VXLAN", pb.GetName(), k)
+		}
+		x := "x" + k
+		// The device is already in the pod if the init container is run again
+		// with the interfaces of a previous run.
+		cmds = append(cmds,
+			fmt.Sprintf("if ! ip link show dev %s >/dev/null 2>&1; then", x),
+			fmt.Sprintf("%s ip link del dev %s 2>/dev/null || true", hostNet, tmp),
+			fmt.Sprintf("%s %s", hostNet, add),
+			fmt.Sprintf("%s ip link set dev %s netns $$", hostNet, tmp),
+			fmt.Sprintf("ip link set dev %s name %s", tmp, x),
+			"fi",
+		)
+		cmds = append(cmds, node.RedirectCommands(k, x)...)
+		cmds = append(cmds, node.RedirectCommands(x, k)...)
+	}
+	pb.Config.Volumes = append(pb.Config.Volumes, &tpb.Volume{
+		Name:   hostProcVolume,
+		Source: &tpb.Volume_HostPath{HostPath: "/proc"},
+	})
+	pb.Config.InitContainers = append([]*tpb.Sidecar{{
+		Name:       bridgeContainer,
+		Image:      pb.GetConfig().GetImage(),
+		Command:    []string{"/bin/sh", "-c", strings.Join(cmds, "\n")},
+		Mounts:     []*tpb.VolumeMount{{Name: hostProcVolume, MountPath: hostProcPath, ReadOnly: true}},
+		Privileged: true,
+	}}, pb.Config.InitContainers...)
+	return nil
+}
+
+func init() {
+	node.Vendor(tpb.Vendor_EXTERNAL, New)
+}
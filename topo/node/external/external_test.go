@@ -0,0 +1,224 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestNew(t *testing.T) {
+	bridged := &tpb.Sidecar{
+		Name:  bridgeContainer,
+		Image: "nicolaka/netshoot:latest",
+		Command: []string{"/bin/sh", "-c", strings.Join([]string{
+			"set -e",
+			"if ! ip link show dev xeth1 >/dev/null 2>&1; then",
+			"nsenter --net=/host/proc/1/ns/net ip link del dev kne6c64ee13 2>/dev/null || true",
+			"nsenter --net=/host/proc/1/ns/net ip link add link eno2 name kne6c64ee13 type vlan id 100",
+			"nsenter --net=/host/proc/1/ns/net ip link set dev kne6c64ee13 netns $$",
+			"ip link set dev kne6c64ee13 name xeth1",
+			"fi",
+			"ip link set dev eth1 up",
+			"tc qdisc del dev eth1 ingress 2>/dev/null || true",
+			"tc qdisc add dev eth1 ingress",
+			"tc filter add dev eth1 parent ffff: matchall action mirred egress redirect dev xeth1",
+			"ip link set dev xeth1 up",
+			"tc qdisc del dev xeth1 ingress 2>/dev/null || true",
+			"tc qdisc add dev xeth1 ingress",
+			"tc filter add dev xeth1 parent ffff: matchall action mirred egress redirect dev eth1",
+			"if ! ip link show dev xeth2 >/dev/null 2>&1; then",
+			"nsenter --net=/host/proc/1/ns/net ip link del dev kne6d64efa6 2>/dev/null || true",
+			"nsenter --net=/host/proc/1/ns/net ip link add kne6d64efa6 type vxlan id 5000 remote 192.0.2.1 dstport 4789",
+			"nsenter --net=/host/proc/1/ns/net ip link set dev kne6d64efa6 netns $$",
+			"ip link set dev kne6d64efa6 name xeth2",
+			"fi",
+			"ip link set dev eth2 up",
+			"tc qdisc del dev eth2 ingress 2>/dev/null || true",
+			"tc qdisc add dev eth2 ingress",
+			"tc filter add dev eth2 parent ffff: matchall action mirred egress redirect dev xeth2",
+			"ip link set dev xeth2 up",
+			"tc qdisc del dev xeth2 ingress 2>/dev/null || true",
+			"tc qdisc add dev xeth2 ingress",
+			"tc filter add dev xeth2 parent ffff: matchall action mirred egress redirect dev eth2",
+		}, "\n")},
+		Mounts:     []*tpb.VolumeMount{{Name: hostProcVolume, MountPath: hostProcPath, ReadOnly: true}},
+		Privileged: true,
+	}
+	intfs := func(m map[string]*tpb.ExternalInterface) map[string]*tpb.Interface {
+		r := map[string]*tpb.Interface{}
+		for k, ext := range m {
+			r[k] = &tpb.Interface{External: ext}
+		}
+		return r
+	}
+	vlan := &tpb.ExternalInterface{Device: "eno2", Encap: &tpb.ExternalInterface_Vlan{Vlan: 100}}
+	vxlan := &tpb.ExternalInterface{Encap: &tpb.ExternalInterface_Vxlan{Vxlan: &tpb.Vxlan{Vni: 5000, Remote: "192.0.2.1"}}}
+	tests := []struct {
+		desc    string
+		nImpl   *node.Impl
+		want    *tpb.Node
+		wantErr string
+	}{{
+		desc:    "nil impl",
+		wantErr: "nodeImpl cannot be nil",
+	}, {
+		desc:    "nil pb",
+		wantErr: "nodeImpl.Proto cannot be nil",
+		nImpl:   &node.Impl{},
+	}, {
+		desc: "empty pb",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut"},
+		},
+		want: &tpb.Node{
+			Name: "dut",
+			Config: &tpb.Config{
+				Command:         []string{"/bin/sh", "-c", "sleep 2000000000000"},
+				EntryCommand:    "kubectl exec -it dut -- sh",
+				Image:           "nicolaka/netshoot:latest",
+				SecurityContext: &tpb.SecurityContext{},
+			},
+		},
+	}, {
+		desc: "vlan and vxlan",
+		nImpl: &node.Impl{
+			Namespace: "ns",
+			Proto: &tpb.Node{
+				Name:       "dut",
+				Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": vlan, "eth2": vxlan}),
+				Config: &tpb.Config{
+					InitContainers: []*tpb.Sidecar{{Name: "other", Image: "busybox"}},
+				},
+			},
+		},
+		want: &tpb.Node{
+			Name:       "dut",
+			Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": vlan, "eth2": vxlan}),
+			Config: &tpb.Config{
+				Command:         []string{"/bin/sh", "-c", "sleep 2000000000000"},
+				EntryCommand:    "kubectl exec -it dut -- sh",
+				Image:           "nicolaka/netshoot:latest",
+				SecurityContext: &tpb.SecurityContext{},
+				Volumes:         []*tpb.Volume{{Name: hostProcVolume, Source: &tpb.Volume_HostPath{HostPath: "/proc"}}},
+				InitContainers:  []*tpb.Sidecar{bridged, {Name: "other", Image: "busybox"}},
+			},
+		},
+	}, {
+		desc: "not bridged",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: map[string]*tpb.Interface{"eth1": {}}},
+		},
+		wantErr: `interface "eth1" is not bridged`,
+	}, {
+		desc: "no encap",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": {Device: "eno2"}})},
+		},
+		wantErr: "has no VLAN or VXLAN",
+	}, {
+		desc: "vlan without device",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": {Encap: &tpb.ExternalInterface_Vlan{Vlan: 100}}})},
+		},
+		wantErr: "has a VLAN but no device",
+	}, {
+		desc: "invalid vlan",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": {Device: "eno2", Encap: &tpb.ExternalInterface_Vlan{Vlan: 4095}}})},
+		},
+		wantErr: "invalid VLAN 4095",
+	}, {
+		desc: "invalid vni",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": {Encap: &tpb.ExternalInterface_Vxlan{Vxlan: &tpb.Vxlan{Vni: 1 << 24, Remote: "192.0.2.1"}}}})},
+		},
+		wantErr: "invalid VNI",
+	}, {
+		desc: "invalid remote",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": {Encap: &tpb.ExternalInterface_Vxlan{Vxlan: &tpb.Vxlan{Vni: 1, Remote: "lab"}}}})},
+		},
+		wantErr: "invalid VXLAN remote",
+	}, {
+		desc: "invalid port",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": {Encap: &tpb.ExternalInterface_Vxlan{Vxlan: &tpb.Vxlan{Vni: 1, Remote: "192.0.2.1", Port: 70000}}}})},
+		},
+		wantErr: "invalid VXLAN port",
+	}, {
+		desc: "invalid device",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"eth1": {Device: "eno2; reboot", Encap: &tpb.ExternalInterface_Vlan{Vlan: 100}}})},
+		},
+		wantErr: "invalid device",
+	}, {
+		desc: "interface name too long",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "dut", Interfaces: intfs(map[string]*tpb.ExternalInterface{"ethernet1234567": vlan})},
+		},
+		wantErr: "invalid interface name",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			n, err := New(tt.nImpl)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("New() unexpected error: %s", s)
+			}
+			if err != nil || tt.want == nil {
+				return
+			}
+			if s := cmp.Diff(tt.want, n.GetProto(), protocmp.Transform()); s != "" {
+				t.Fatalf("New() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestNewVxlanDevice(t *testing.T) {
+	n, err := New(&node.Impl{
+		Proto: &tpb.Node{
+			Name: "dut",
+			Interfaces: map[string]*tpb.Interface{"eth1": {External: &tpb.ExternalInterface{
+				Device: "eno3",
+				Encap:  &tpb.ExternalInterface_Vxlan{Vxlan: &tpb.Vxlan{Vni: 10, Remote: "2001:db8::1", Port: 8472}},
+			}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	ics := n.GetProto().GetConfig().GetInitContainers()
+	if len(ics) != 1 {
+		t.Fatalf("New() got %d init containers, want 1", len(ics))
+	}
+	if want := "type vxlan id 10 remote 2001:db8::1 dstport 8472 dev eno3\n"; !strings.Contains(ics[0].GetCommand()[2], want) {
+		t.Errorf("New() init container command:\n%s\nmissing %q", ics[0].GetCommand()[2], want)
+	}
+	// Creating the node again does not add a second init container.
+	n, err = New(&node.Impl{Proto: n.GetProto()})
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if got := len(n.GetProto().GetConfig().GetInitContainers()); got != 1 {
+		t.Errorf("New() again got %d init containers, want 1", got)
+	}
+}
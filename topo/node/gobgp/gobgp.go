@@ -57,6 +57,10 @@ func defaults(pb *tpb.Node) *tpb.Node {
 	if pb.Config.ConfigFile == "" {
 		pb.Config.ConfigFile = "gobgp.conf"
 	}
+	if pb.Config.SecurityContext == nil {
+		// The default capabilities allow gobgpd to bind the BGP port.
+		pb.Config.SecurityContext = &tpb.SecurityContext{}
+	}
 	return pb
 }
 
@@ -38,11 +38,12 @@ func TestNew(t *testing.T) {
 		wantPB: &topopb.Node{
 			Name: "test_node",
 			Config: &topopb.Config{
-				Image:        "foobar",
-				Command:      []string{"run", "some", "command"},
-				EntryCommand: "kubectl exec -it test_node -- /bin/bash",
-				ConfigPath:   "/",
-				ConfigFile:   "gobgp.conf",
+				Image:           "foobar",
+				Command:         []string{"run", "some", "command"},
+				EntryCommand:    "kubectl exec -it test_node -- /bin/bash",
+				ConfigPath:      "/",
+				ConfigFile:      "gobgp.conf",
+				SecurityContext: &topopb.SecurityContext{},
 			},
 		},
 	}, {
@@ -55,11 +56,12 @@ func TestNew(t *testing.T) {
 		wantPB: &topopb.Node{
 			Name: "test_node",
 			Config: &topopb.Config{
-				Image:        "hfam/gobgp:latest",
-				Command:      []string{"/usr/local/bin/gobgpd", "-f", "/gobgp.conf", "-t", "yaml"},
-				EntryCommand: "kubectl exec -it test_node -- /bin/bash",
-				ConfigPath:   "/",
-				ConfigFile:   "gobgp.conf",
+				Image:           "hfam/gobgp:latest",
+				Command:         []string{"/usr/local/bin/gobgpd", "-f", "/gobgp.conf", "-t", "yaml"},
+				EntryCommand:    "kubectl exec -it test_node -- /bin/bash",
+				ConfigPath:      "/",
+				ConfigFile:      "gobgp.conf",
+				SecurityContext: &topopb.SecurityContext{},
 			},
 		},
 	}}
@@ -15,11 +15,18 @@ package host
 
 import (
 	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
 
 	tpb "github.com/openconfig/kne/proto/topo"
 	"github.com/openconfig/kne/topo/node"
 )
 
+// addressContainer is the name of the init container configuring the
+// addresses of the interfaces.
+const addressContainer = "init-addresses"
+
 func New(nodeImpl *node.Impl) (node.Node, error) {
 	if nodeImpl == nil {
 		return nil, fmt.Errorf("nodeImpl cannot be nil")
@@ -28,6 +35,9 @@ func New(nodeImpl *node.Impl) (node.Node, error) {
 		return nil, fmt.Errorf("nodeImpl.Proto cannot be nil")
 	}
 	cfg := defaults(nodeImpl.Proto)
+	if err := addressInit(cfg); err != nil {
+		return nil, err
+	}
 	nodeImpl.Proto = cfg
 	n := &Node{
 		Impl: nodeImpl,
@@ -58,9 +68,66 @@ func defaults(pb *tpb.Node) *tpb.Node {
 	if pb.Config.ConfigFile == "" {
 		pb.Config.ConfigFile = "config"
 	}
+	if pb.Config.SecurityContext == nil {
+		// The addresses of the interfaces are configured by an init container
+		// with NET_ADMIN, the node container needs no privileges.
+		pb.Config.SecurityContext = &tpb.SecurityContext{}
+	}
 	return pb
 }
 
+// addressInit adds an init container configuring the ip_addresses of the
+// interfaces of the node, run once the interfaces are added to the pod. The
+// container runs the image of the node, which must provide the ip command.
+func addressInit(pb *tpb.Node) error {
+	var names []string
+	for k, intf := range pb.GetInterfaces() {
+		if len(intf.GetIpAddresses()) > 0 {
+			names = append(names, k)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	for _, ic := range pb.GetConfig().GetInitContainers() {
+		if ic.GetName() == addressContainer {
+			return nil
+		}
+	}
+	sort.Strings(names)
+	var cmds []string
+	for _, k := range names {
+		intf := pb.GetInterfaces()[k]
+		name := intf.GetIntName()
+		if name == "" {
+			name = k
+		}
+		if !node.ValidInterfaceName(name) {
+			return fmt.Errorf("node %q: invalid interface name %q", pb.GetName(), name)
+		}
+		cmds = append(cmds, fmt.Sprintf("ip link set dev %s up", name))
+		for _, ia := range intf.GetIpAddresses() {
+			p, err := netip.ParsePrefix(ia)
+			if err != nil {
+				return fmt.Errorf("node %q: invalid address of interface %q: %v", pb.GetName(), k, err)
+			}
+			a := p.String()
+			// The address is already set if the init container is run again
+			// with the interfaces of a previous run.
+			cmds = append(cmds, fmt.Sprintf("{ ip addr add %s dev %s || ip addr show dev %s | grep -qF ' %s '; }", a, name, name, a))
+		}
+	}
+	pb.Config.InitContainers = append([]*tpb.Sidecar{{
+		Name:    addressContainer,
+		Image:   pb.GetConfig().GetImage(),
+		Command: []string{"/bin/sh", "-c", strings.Join(append([]string{"set -e"}, cmds...), "\n")},
+		SecurityContext: &tpb.SecurityContext{
+			AddCapabilities: []string{"NET_ADMIN"},
+		},
+	}}, pb.Config.InitContainers...)
+	return nil
+}
+
 func init() {
 	node.Register(tpb.Node_HOST, New)
 	node.Vendor(tpb.Vendor_HOST, New)
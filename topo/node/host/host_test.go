@@ -31,11 +31,12 @@ func TestNew(t *testing.T) {
 		},
 		want: &topopb.Node{
 			Config: &topopb.Config{
-				Command:      []string{"/bin/sh", "-c", "sleep 2000000000000"},
-				EntryCommand: fmt.Sprintf("kubectl exec -it %s -- sh", ""),
-				Image:        "alpine:latest",
-				ConfigPath:   "/etc",
-				ConfigFile:   "config",
+				Command:         []string{"/bin/sh", "-c", "sleep 2000000000000"},
+				EntryCommand:    fmt.Sprintf("kubectl exec -it %s -- sh", ""),
+				Image:           "alpine:latest",
+				ConfigPath:      "/etc",
+				ConfigFile:      "config",
+				SecurityContext: &topopb.SecurityContext{},
 			},
 		},
 	}, {
@@ -58,11 +59,12 @@ func TestNew(t *testing.T) {
 		},
 		want: &topopb.Node{
 			Config: &topopb.Config{
-				Command:      []string{"do", "run"},
-				EntryCommand: fmt.Sprintf("kubectl exec -it %s -- sh", ""),
-				Image:        "alpine:latest",
-				ConfigPath:   "/etc",
-				ConfigFile:   "config",
+				Command:         []string{"do", "run"},
+				EntryCommand:    fmt.Sprintf("kubectl exec -it %s -- sh", ""),
+				Image:           "alpine:latest",
+				ConfigPath:      "/etc",
+				ConfigFile:      "config",
+				SecurityContext: &topopb.SecurityContext{},
 			},
 			Services: map[uint32]*topopb.Service{
 				2000: {
@@ -85,13 +87,81 @@ func TestNew(t *testing.T) {
 		},
 		want: &topopb.Node{
 			Config: &topopb.Config{
-				Command:      []string{"do", "run"},
-				EntryCommand: fmt.Sprintf("kubectl exec -it %s -- sh", ""),
-				Image:        "alpine:latest",
-				ConfigPath:   "/etc",
-				ConfigFile:   "config",
+				Command:         []string{"do", "run"},
+				EntryCommand:    fmt.Sprintf("kubectl exec -it %s -- sh", ""),
+				Image:           "alpine:latest",
+				ConfigPath:      "/etc",
+				ConfigFile:      "config",
+				SecurityContext: &topopb.SecurityContext{},
 			},
 		},
+	}, {
+		desc: "interface addresses",
+		nImpl: &node.Impl{
+			Proto: &topopb.Node{
+				Name: "h1",
+				Config: &topopb.Config{
+					InitContainers: []*topopb.Sidecar{{Name: "setup", Image: "setup:latest"}},
+				},
+				Interfaces: map[string]*topopb.Interface{
+					"eth2": {IntName: "eth2", IpAddresses: []string{"2001:db8::1/64"}},
+					"eth1": {IntName: "eth1", IpAddresses: []string{"10.0.0.0/31", "192.168.0.1/24"}},
+					"eth3": {IntName: "eth3"},
+				},
+			},
+		},
+		want: &topopb.Node{
+			Name: "h1",
+			Config: &topopb.Config{
+				Command:         []string{"/bin/sh", "-c", "sleep 2000000000000"},
+				EntryCommand:    "kubectl exec -it h1 -- sh",
+				Image:           "alpine:latest",
+				ConfigPath:      "/etc",
+				ConfigFile:      "config",
+				SecurityContext: &topopb.SecurityContext{},
+				InitContainers: []*topopb.Sidecar{{
+					Name:  "init-addresses",
+					Image: "alpine:latest",
+					Command: []string{"/bin/sh", "-c", `set -e
+ip link set dev eth1 up
+{ ip addr add 10.0.0.0/31 dev eth1 || ip addr show dev eth1 | grep -qF ' 10.0.0.0/31 '; }
+{ ip addr add 192.168.0.1/24 dev eth1 || ip addr show dev eth1 | grep -qF ' 192.168.0.1/24 '; }
+ip link set dev eth2 up
+{ ip addr add 2001:db8::1/64 dev eth2 || ip addr show dev eth2 | grep -qF ' 2001:db8::1/64 '; }`},
+					SecurityContext: &topopb.SecurityContext{AddCapabilities: []string{"NET_ADMIN"}},
+				}, {
+					Name:  "setup",
+					Image: "setup:latest",
+				}},
+			},
+			Interfaces: map[string]*topopb.Interface{
+				"eth2": {IntName: "eth2", IpAddresses: []string{"2001:db8::1/64"}},
+				"eth1": {IntName: "eth1", IpAddresses: []string{"10.0.0.0/31", "192.168.0.1/24"}},
+				"eth3": {IntName: "eth3"},
+			},
+		},
+	}, {
+		desc: "invalid interface address",
+		nImpl: &node.Impl{
+			Proto: &topopb.Node{
+				Name: "h1",
+				Interfaces: map[string]*topopb.Interface{
+					"eth1": {IntName: "eth1", IpAddresses: []string{"10.0.0.1"}},
+				},
+			},
+		},
+		wantErr: `invalid address of interface "eth1"`,
+	}, {
+		desc: "invalid interface name",
+		nImpl: &node.Impl{
+			Proto: &topopb.Node{
+				Name: "h1",
+				Interfaces: map[string]*topopb.Interface{
+					"eth1": {IntName: "eth1; reboot", IpAddresses: []string{"10.0.0.1/24"}},
+				},
+			},
+		},
+		wantErr: `invalid interface name "eth1; reboot"`,
 	}}
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
@@ -0,0 +1,43 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// intfNameRe matches the Linux interface names which can be configured.
+var intfNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidInterfaceName returns whether name is a Linux interface name which can
+// be configured by the commands of a node, i.e. at most 15 characters
+// without characters special to the shell.
+func ValidInterfaceName(name string) bool {
+	return len(name) <= 15 && intfNameRe.MatchString(name)
+}
+
+// RedirectCommands returns the shell commands redirecting all traffic received
+// on the interface from to the interface to with tc. Unlike a bridge tc passes
+// link local protocols such as LLDP and LACP, so the devices see each other as
+// directly connected.
+func RedirectCommands(from, to string) []string {
+	return []string{
+		fmt.Sprintf("ip link set dev %s up", from),
+		fmt.Sprintf("tc qdisc del dev %s ingress 2>/dev/null || true", from),
+		fmt.Sprintf("tc qdisc add dev %s ingress", from),
+		fmt.Sprintf("tc filter add dev %s parent ffff: matchall action mirred egress redirect dev %s", from, to),
+	}
+}
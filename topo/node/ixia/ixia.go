@@ -7,7 +7,6 @@ import (
 	"time"
 
 	ixiatg "github.com/open-traffic-generator/ixia-c-operator/api/v1beta1"
-	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -41,7 +40,7 @@ type Node struct {
 }
 
 func (n *Node) newCRD() *ixiatg.IxiaTG {
-	log.Infof("Creating new ixia CRD for node: %v", n.Name())
+	n.Logger().Infof("Creating new ixia CRD for node: %v", n.Name())
 	ixiaCRD := &ixiatg.IxiaTG{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "network.keysight.com/v1beta1",
@@ -71,7 +70,7 @@ func (n *Node) newCRD() *ixiatg.IxiaTG {
 			Group: ifc.Group,
 		})
 	}
-	log.Tracef("Created new ixia CRD for node %s: %+v", n.Name(), ixiaCRD)
+	n.Logger().Tracef("Created new ixia CRD for node %s: %+v", n.Name(), ixiaCRD)
 	return ixiaCRD
 }
 
@@ -112,7 +111,7 @@ func (n *Node) getStatus(ctx context.Context) (*ixiatg.IxiaTGStatus, error) {
 func (n *Node) waitForState(ctx context.Context, state string, dur time.Duration) (*ixiatg.IxiaTGStatus, error) {
 	start := time.Now()
 
-	log.Infof("Waiting for ixia CRD state to be %s ... (timeout: %v)", state, dur)
+	n.Logger().Infof("Waiting for ixia CRD state to be %s ... (timeout: %v)", state, dur)
 	for time.Since(start) < dur {
 		status, err := n.getStatus(ctx)
 
@@ -125,7 +124,7 @@ func (n *Node) waitForState(ctx context.Context, state string, dur time.Duration
 		}
 
 		if status.State == state {
-			log.Infof("Attained ixia CRD state %s", state)
+			n.Logger().Infof("Attained ixia CRD state %s", state)
 			return status, nil
 		}
 
@@ -136,7 +135,7 @@ func (n *Node) waitForState(ctx context.Context, state string, dur time.Duration
 }
 
 func (n *Node) TopologySpecs(ctx context.Context) ([]*topologyv1.Topology, error) {
-	log.Infof("Getting interfaces for ixia node resource %s ...", n.Name())
+	n.Logger().Infof("Getting interfaces for ixia node resource %s ...", n.Name())
 	desiredState := "INITIATED"
 
 	crd, err := json.Marshal(n.newCRD())
@@ -144,7 +143,7 @@ func (n *Node) TopologySpecs(ctx context.Context) ([]*topologyv1.Topology, error
 		return nil, fmt.Errorf("could not marshal ixia CRD to JSON: %v", err)
 	}
 
-	log.Infof("Creating custom resource for ixia (desiredState=%s) ...", desiredState)
+	n.Logger().Infof("Creating custom resource for ixia (desiredState=%s) ...", desiredState)
 	err = n.KubeClient.CoreV1().RESTClient().
 		Post().
 		AbsPath("/apis/network.keysight.com/v1beta1").
@@ -205,7 +204,7 @@ func (n *Node) TopologySpecs(ctx context.Context) ([]*topologyv1.Topology, error
 }
 
 func (n *Node) Create(ctx context.Context) error {
-	log.Infof("Creating deployment for node resource %s", n.Name())
+	n.Logger().Infof("Creating deployment for node resource %s", n.Name())
 	desiredState := "DEPLOYED"
 
 	crd, err := n.getCRD(ctx)
@@ -220,7 +219,7 @@ func (n *Node) Create(ctx context.Context) error {
 		return fmt.Errorf("could not marshal ixia CRD to JSON: %v", err)
 	}
 
-	log.Infof("Updating ixia CRD (desiredState=%s) ...", desiredState)
+	n.Logger().Infof("Updating ixia CRD (desiredState=%s) ...", desiredState)
 
 	err = n.KubeClient.CoreV1().RESTClient().
 		Patch(types.MergePatchType).
@@ -313,9 +312,9 @@ func (n *Node) Status(ctx context.Context) (node.Status, error) {
 
 	switch status.State {
 	case "DEPLOYED":
-		state = node.StatusRunning
+		state = node.StatusReady
 	case "INITIATED":
-		state = node.StatusPending
+		state = node.StatusCreating
 	case "FAILED":
 		err = fmt.Errorf("got failure in ixia CRD status: %s", status.Reason)
 	}
@@ -324,7 +323,7 @@ func (n *Node) Status(ctx context.Context) (node.Status, error) {
 }
 
 func (n *Node) Delete(ctx context.Context) error {
-	log.Infof("Deleting IxiaTG node resource %s", n.Name())
+	n.Logger().Infof("Deleting IxiaTG node resource %s", n.Name())
 	err := n.KubeClient.CoreV1().RESTClient().
 		Delete().
 		AbsPath("/apis/network.keysight.com/v1beta1").
@@ -335,7 +334,7 @@ func (n *Node) Delete(ctx context.Context) error {
 		Error()
 
 	if err != nil {
-		log.Error(err)
+		n.Logger().Error(err)
 		return err
 	}
 	return nil
@@ -25,7 +25,6 @@ import (
 	"google.golang.org/grpc/status"
 
 	tpb "github.com/openconfig/kne/proto/topo"
-	log "github.com/sirupsen/logrus"
 )
 
 func New(nodeImpl *node.Impl) (node.Node, error) {
@@ -55,7 +54,7 @@ var (
 )
 
 func (n *Node) ResetCfg(ctx context.Context) error {
-	log.Info("ResetCfg is a noop.")
+	n.Logger().Info("ResetCfg is a noop.")
 	return nil
 }
 
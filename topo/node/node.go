@@ -1,18 +1,23 @@
 package node
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	scraplinetwork "github.com/scrapli/scrapligo/driver/network"
+	scrapliopopts "github.com/scrapli/scrapligo/driver/opoptions"
 	scrapliopts "github.com/scrapli/scrapligo/driver/options"
 	scraplilogging "github.com/scrapli/scrapligo/logging"
 	scrapliplatform "github.com/scrapli/scrapligo/platform"
+	scrapliresponse "github.com/scrapli/scrapligo/response"
 	scrapliutil "github.com/scrapli/scrapligo/util"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -23,6 +28,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 	"k8s.io/utils/pointer"
 
 	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
@@ -33,6 +39,12 @@ type Interface interface {
 	Name() string
 	GetNamespace() string
 	GetProto() *tpb.Node
+	// Exec runs cmd in the node pod. If stdin is provided a TTY is allocated
+	// for the session, otherwise stdout and stderr are streamed separately.
+	Exec(ctx context.Context, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+	// InterfaceNames returns the vendor names of the interfaces of the node
+	// keyed by their names in the topology, e.g. eth1.
+	InterfaceNames() (map[string]string, error)
 }
 
 type Implementation interface {
@@ -61,31 +73,104 @@ type Certer interface {
 	GenerateSelfSigned(context.Context) error
 }
 
+// CertRotator provides an interface for rotating the certs of running nodes.
+type CertRotator interface {
+	// RotateCert re-generates the cert of the node and installs it in place
+	// of the current one, without restarting the node or its services.
+	RotateCert(context.Context) error
+}
+
 // ConfigPusher provides an interface for performing config pushes to the node.
 type ConfigPusher interface {
 	ConfigPush(context.Context, io.Reader) error
 }
 
+// ConfigPushStreamer provides an interface for config pushes streaming the
+// output of the device.
+type ConfigPushStreamer interface {
+	// ConfigPushStream pushes the config read from r as ConfigPush does,
+	// writing the output of the device to w as it is received.
+	ConfigPushStream(ctx context.Context, r io.Reader, w io.Writer) error
+}
+
 // Resetter provides Reset interface to nodes.
 type Resetter interface {
 	ResetCfg(ctx context.Context) error
 }
 
+// ContainerExecer provides an interface for running commands in a specific
+// container of the node pod.
+type ContainerExecer interface {
+	ExecContainer(ctx context.Context, container string, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+}
+
+// Diagnoser provides vendor specific diagnostics, such as tech support
+// output, for nodes.
+type Diagnoser interface {
+	Diagnostics(ctx context.Context, w io.Writer) error
+}
+
 // Node is the base interface for all node implementations in KNE.
 type Node interface {
 	Interface
 	Implementation
 }
 
+// Status is the lifecycle state of a node.
 type Status string
 
 const (
-	StatusPending Status = "PENDING"
-	StatusRunning Status = "RUNNING"
+	// StatusCreating is the state of a node whose resources are being created,
+	// e.g. the pod is being scheduled, pulling images or waiting for its links.
+	StatusCreating Status = "CREATING"
+	// StatusBooting is the state of a node whose pod is running but whose
+	// software is not yet ready.
+	StatusBooting Status = "BOOTING"
+	// StatusConfigPushing is the state of a node config is being pushed to.
+	StatusConfigPushing Status = "CONFIG_PUSHING"
+	// StatusReady is the state of a node ready to be used.
+	StatusReady Status = "READY"
+	// StatusFailed is the state of a node which failed, the error returned
+	// along with the status provides the reason.
 	StatusFailed  Status = "FAILED"
 	StatusUnknown Status = "UNKNOWN"
 )
 
+// FailureError is the error returned along with StatusFailed describing why
+// the node failed.
+type FailureError struct {
+	// Reason is a short CamelCase reason of the failure from a bounded set,
+	// e.g. the reason of a waiting container such as CrashLoopBackOff.
+	Reason string
+	Err    error
+}
+
+func (e *FailureError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FailureError) Unwrap() error {
+	return e.Err
+}
+
+// FailureReason returns the reason of the failure.
+func (e *FailureError) FailureReason() string {
+	return e.Reason
+}
+
+// failedReasons are the reasons of waiting containers which indicate the node
+// failed rather than still being created, such as crash looping. Transient
+// failures retried right away, such as ErrImagePull, are not included, only
+// the back off states of repeated failures.
+var failedReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
 type NewNodeFn func(n *Impl) (Node, error)
 
 var (
@@ -122,18 +207,31 @@ type Impl struct {
 	Proto      *tpb.Node
 	BasePath   string
 	Kubecfg    string
+	// KubeContext is the context of Kubecfg used by kubectl, the current
+	// context if empty.
+	KubeContext string
+
+	ready   readyCheck
+	booting bootTimer
 }
 
 // New creates a new node for use in the k8s cluster.  Configure will push the node to
 // the cluster.
-func New(namespace string, pb *tpb.Node, kClient kubernetes.Interface, rCfg *rest.Config, bp, kubecfg string) (Node, error) {
+func New(namespace string, pb *tpb.Node, kClient kubernetes.Interface, rCfg *rest.Config, bp, kubecfg, kubeContext string) (Node, error) {
+	if _, err := ParseConstraints(pb.GetConstraints()); err != nil {
+		return nil, fmt.Errorf("node %q: %w", pb.GetName(), err)
+	}
+	if err := validateSecurityContexts(pb); err != nil {
+		return nil, fmt.Errorf("node %q: %w", pb.GetName(), err)
+	}
 	return getImpl(&Impl{
-		Namespace:  namespace,
-		Proto:      pb,
-		KubeClient: kClient,
-		RestConfig: rCfg,
-		BasePath:   bp,
-		Kubecfg:    kubecfg,
+		Namespace:   namespace,
+		Proto:       pb,
+		KubeClient:  kClient,
+		RestConfig:  rCfg,
+		BasePath:    bp,
+		Kubecfg:     kubecfg,
+		KubeContext: kubeContext,
 	})
 }
 
@@ -141,6 +239,19 @@ func (n *Impl) GetProto() *tpb.Node {
 	return n.Proto
 }
 
+// InterfaceNames returns the names of the interfaces of the node, the name in
+// the topology for interfaces without a vendor name.
+func (n *Impl) InterfaceNames() (map[string]string, error) {
+	names := map[string]string{}
+	for k, v := range n.Proto.GetInterfaces() {
+		names[k] = v.GetName()
+		if names[k] == "" {
+			names[k] = k
+		}
+	}
+	return names, nil
+}
+
 func (n *Impl) GetNamespace() string {
 	return n.Namespace
 }
@@ -195,19 +306,340 @@ func ToEnvVar(kv map[string]string) []corev1.EnvVar {
 	return envVar
 }
 
-func ToResourceRequirements(kv map[string]string) corev1.ResourceRequirements {
+// ParseConstraints returns the resource requirements of the constraints of a
+// node. cpu, memory and ephemeral-storage are requested, any other resource,
+// e.g. hugepages-1Gi or a device plugin resource such as
+// intel.com/sriov_netdevice, is requested and limited to the same quantity as
+// these resources cannot be overcommitted.
+func ParseConstraints(kv map[string]string) (corev1.ResourceRequirements, error) {
 	r := corev1.ResourceRequirements{
 		Requests: map[corev1.ResourceName]resource.Quantity{},
 	}
-	if v, ok := kv["cpu"]; ok {
-		r.Requests["cpu"] = resource.MustParse(v)
+	for k, v := range kv {
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("invalid %s constraint %q: %w", k, v, err)
+		}
+		name := corev1.ResourceName(k)
+		r.Requests[name] = q
+		switch name {
+		case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+		default:
+			if r.Limits == nil {
+				r.Limits = map[corev1.ResourceName]resource.Quantity{}
+			}
+			r.Limits[name] = q
+		}
 	}
-	if v, ok := kv["memory"]; ok {
-		r.Requests["memory"] = resource.MustParse(v)
+	return r, nil
+}
+
+// ToResourceRequirements returns the resource requirements of the
+// constraints of a node, see ParseConstraints. It panics if a constraint is
+// invalid, the constraints of nodes are validated by New.
+func ToResourceRequirements(kv map[string]string) corev1.ResourceRequirements {
+	r, err := ParseConstraints(kv)
+	if err != nil {
+		panic(err)
 	}
 	return r
 }
 
+// ToImagePullSecrets returns the image pull secrets of the pod of the node, nil
+// if it has none.
+func ToImagePullSecrets(pb *tpb.Node) []corev1.LocalObjectReference {
+	if secret := pb.GetConfig().GetImagePullSecret(); secret != "" {
+		return []corev1.LocalObjectReference{{Name: secret}}
+	}
+	return nil
+}
+
+// ApplyScheduling sets the node selector, tolerations and affinity of the pod
+// spec from the scheduling of the node. The affinity is added to the existing
+// affinity of the spec.
+func ApplyScheduling(spec *corev1.PodSpec, pb *tpb.Node) {
+	s := pb.GetScheduling()
+	if s == nil {
+		return
+	}
+	if len(s.GetNodeSelector()) != 0 {
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = map[string]string{}
+		}
+		for k, v := range s.GetNodeSelector() {
+			spec.NodeSelector[k] = v
+		}
+	}
+	for _, t := range s.GetTolerations() {
+		spec.Tolerations = append(spec.Tolerations, corev1.Toleration{
+			Key:      t.GetKey(),
+			Operator: corev1.TolerationOperator(t.GetOperator()),
+			Value:    t.GetValue(),
+			Effect:   corev1.TaintEffect(t.GetEffect()),
+		})
+	}
+	a := s.GetAffinity()
+	if a == nil {
+		return
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if len(a.GetRequired()) != 0 || len(a.GetPreferred()) != 0 {
+		if spec.Affinity.NodeAffinity == nil {
+			spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		na := spec.Affinity.NodeAffinity
+		if len(a.GetRequired()) != 0 {
+			var exprs []corev1.NodeSelectorRequirement
+			for _, r := range a.GetRequired() {
+				exprs = append(exprs, toNodeSelectorRequirement(r))
+			}
+			// The terms of a node selector are ORed, so the requirements
+			// are added to each existing term to all be met.
+			if na.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+				na.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+			}
+			terms := na.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			if len(terms) == 0 {
+				terms = []corev1.NodeSelectorTerm{{}}
+			}
+			for i := range terms {
+				terms[i].MatchExpressions = append(terms[i].MatchExpressions, exprs...)
+			}
+			na.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = terms
+		}
+		for _, r := range a.GetPreferred() {
+			weight := r.GetWeight()
+			if weight == 0 {
+				weight = 1
+			}
+			na.PreferredDuringSchedulingIgnoredDuringExecution = append(na.PreferredDuringSchedulingIgnoredDuringExecution, corev1.PreferredSchedulingTerm{
+				Weight:     weight,
+				Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{toNodeSelectorRequirement(r)}},
+			})
+		}
+	}
+	if len(a.GetSpreadFrom()) != 0 {
+		if spec.Affinity.PodAntiAffinity == nil {
+			spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		paa := spec.Affinity.PodAntiAffinity
+		paa.PreferredDuringSchedulingIgnoredDuringExecution = append(paa.PreferredDuringSchedulingIgnoredDuringExecution, corev1.WeightedPodAffinityTerm{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      "app",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   a.GetSpreadFrom(),
+					}},
+				},
+				TopologyKey: "kubernetes.io/hostname",
+			},
+		})
+	}
+}
+
+// ApplyVolumes adds the volumes of the config of the node to the pod spec and
+// mounts them into the first container of the spec, the node container.
+func ApplyVolumes(spec *corev1.PodSpec, pb *tpb.Node) error {
+	for _, v := range pb.GetConfig().GetVolumes() {
+		if v.GetName() == "" {
+			return fmt.Errorf("volume of node %q has no name", pb.GetName())
+		}
+		vol := corev1.Volume{Name: v.GetName()}
+		switch src := v.GetSource().(type) {
+		case *tpb.Volume_ConfigMap:
+			vol.ConfigMap = &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: src.ConfigMap}}
+		case *tpb.Volume_Secret:
+			vol.Secret = &corev1.SecretVolumeSource{SecretName: src.Secret}
+		case *tpb.Volume_HostPath:
+			vol.HostPath = &corev1.HostPathVolumeSource{Path: src.HostPath}
+		case *tpb.Volume_EmptyDir:
+			vol.EmptyDir = &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMedium(src.EmptyDir.GetMedium())}
+			if l := src.EmptyDir.GetSizeLimit(); l != "" {
+				q, err := resource.ParseQuantity(l)
+				if err != nil {
+					return fmt.Errorf("invalid size limit %q of volume %q of node %q: %w", l, v.GetName(), pb.GetName(), err)
+				}
+				vol.EmptyDir.SizeLimit = &q
+			}
+		default:
+			return fmt.Errorf("volume %q of node %q has no source", v.GetName(), pb.GetName())
+		}
+		spec.Volumes = append(spec.Volumes, vol)
+		if v.GetMountPath() == "" || len(spec.Containers) == 0 {
+			continue
+		}
+		spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      v.GetName(),
+			MountPath: v.GetMountPath(),
+			SubPath:   v.GetSubPath(),
+			ReadOnly:  v.GetReadOnly(),
+		})
+	}
+	return nil
+}
+
+// ApplySidecars adds the sidecar containers of the config of the node to the
+// pod spec.
+func ApplySidecars(spec *corev1.PodSpec, pb *tpb.Node) error {
+	for _, sc := range pb.GetConfig().GetSidecars() {
+		c, err := toContainer(pb, "sidecar", sc)
+		if err != nil {
+			return err
+		}
+		spec.Containers = append(spec.Containers, c)
+	}
+	return nil
+}
+
+// InitContainers returns the init containers of the pod of the node, the
+// container waiting for the interfaces of the node unless disabled followed by
+// the init containers of the config of the node.
+func InitContainers(pb *tpb.Node) ([]corev1.Container, error) {
+	var containers []corev1.Container
+	if w := pb.GetConfig().GetInitWait(); !w.GetDisabled() {
+		image := pb.GetConfig().GetInitImage()
+		if image == "" {
+			image = DefaultInitContainerImage
+		}
+		interfaces := w.GetInterfaces()
+		if interfaces == 0 {
+			interfaces = uint32(len(pb.GetInterfaces()) + 1)
+		}
+		c := corev1.Container{
+			Name:  fmt.Sprintf("init-%s", pb.GetName()),
+			Image: image,
+			Args: []string{
+				fmt.Sprintf("%d", interfaces),
+				fmt.Sprintf("%d", pb.GetConfig().GetSleep()),
+			},
+			ImagePullPolicy: "IfNotPresent",
+		}
+		if pb.GetConfig().GetSecurityContext().GetRestricted() {
+			c.SecurityContext = ToSecurityContext(&tpb.SecurityContext{Restricted: true})
+		}
+		containers = append(containers, c)
+	}
+	for _, ic := range pb.GetConfig().GetInitContainers() {
+		c, err := toContainer(pb, "init container", ic)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// toContainer returns the container of sc, a sidecar or init container of
+// the node as named by kind.
+func toContainer(pb *tpb.Node, kind string, sc *tpb.Sidecar) (corev1.Container, error) {
+	if sc.GetName() == "" || sc.GetImage() == "" {
+		return corev1.Container{}, fmt.Errorf("%s %q of node %q must have a name and an image", kind, sc.GetName(), pb.GetName())
+	}
+	c := corev1.Container{
+		Name:            sc.GetName(),
+		Image:           sc.GetImage(),
+		Command:         sc.GetCommand(),
+		Args:            sc.GetArgs(),
+		Env:             ToEnvVar(sc.GetEnv()),
+		ImagePullPolicy: "IfNotPresent",
+	}
+	if len(sc.GetConstraints()) != 0 {
+		r, err := ParseConstraints(sc.GetConstraints())
+		if err != nil {
+			return corev1.Container{}, fmt.Errorf("%s %q of node %q: %w", kind, sc.GetName(), pb.GetName(), err)
+		}
+		c.Resources = r
+	}
+	if sc.GetSecurityContext() != nil {
+		c.SecurityContext = ToSecurityContext(sc.GetSecurityContext())
+	} else if sc.GetPrivileged() {
+		c.SecurityContext = &corev1.SecurityContext{Privileged: pointer.Bool(true)}
+	}
+	for _, m := range sc.GetMounts() {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      m.GetName(),
+			MountPath: m.GetMountPath(),
+			SubPath:   m.GetSubPath(),
+			ReadOnly:  m.GetReadOnly(),
+		})
+	}
+	return c, nil
+}
+
+// restrictedUser is the user restricted containers run as unless set.
+const restrictedUser = 65534
+
+// ToSecurityContext returns the security context of a container for sc, nil
+// if sc is nil.
+func ToSecurityContext(sc *tpb.SecurityContext) *corev1.SecurityContext {
+	if sc == nil {
+		return nil
+	}
+	c := &corev1.SecurityContext{}
+	if sc.GetPrivileged() {
+		c.Privileged = pointer.Bool(true)
+	}
+	if sc.GetRunAsUser() != 0 {
+		c.RunAsUser = pointer.Int64(sc.GetRunAsUser())
+	}
+	if sc.GetRunAsGroup() != 0 {
+		c.RunAsGroup = pointer.Int64(sc.GetRunAsGroup())
+	}
+	if sc.GetRunAsNonRoot() {
+		c.RunAsNonRoot = pointer.Bool(true)
+	}
+	caps := &corev1.Capabilities{}
+	for _, a := range sc.GetAddCapabilities() {
+		caps.Add = append(caps.Add, corev1.Capability(a))
+	}
+	for _, d := range sc.GetDropCapabilities() {
+		caps.Drop = append(caps.Drop, corev1.Capability(d))
+	}
+	if sc.GetRestricted() {
+		caps.Drop = []corev1.Capability{"ALL"}
+		c.AllowPrivilegeEscalation = pointer.Bool(false)
+		c.RunAsNonRoot = pointer.Bool(true)
+		if c.RunAsUser == nil {
+			c.RunAsUser = pointer.Int64(restrictedUser)
+		}
+		c.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	if len(caps.Add) != 0 || len(caps.Drop) != 0 {
+		c.Capabilities = caps
+	}
+	return c
+}
+
+// validateSecurityContexts returns an error if a security context of the
+// containers of the node is inconsistent.
+func validateSecurityContexts(pb *tpb.Node) error {
+	scs := []*tpb.SecurityContext{pb.GetConfig().GetSecurityContext()}
+	for _, sc := range append(append([]*tpb.Sidecar{}, pb.GetConfig().GetSidecars()...), pb.GetConfig().GetInitContainers()...) {
+		scs = append(scs, sc.GetSecurityContext())
+	}
+	for _, sc := range scs {
+		if sc.GetRestricted() && sc.GetPrivileged() {
+			return fmt.Errorf("security context cannot be both privileged and restricted")
+		}
+		if sc.GetRunAsUser() < 0 || sc.GetRunAsGroup() < 0 {
+			return fmt.Errorf("invalid user %d or group %d of security context", sc.GetRunAsUser(), sc.GetRunAsGroup())
+		}
+	}
+	return nil
+}
+
+func toNodeSelectorRequirement(r *tpb.LabelRequirement) corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{
+		Key:      r.GetKey(),
+		Operator: corev1.NodeSelectorOperator(r.GetOperator()),
+		Values:   r.GetValues(),
+	}
+}
+
 // Create will create the node in the k8s cluster with all services and config
 // maps.
 func (n *Impl) Create(ctx context.Context) error {
@@ -241,6 +673,10 @@ func (n *Impl) CreateConfig(ctx context.Context) error {
 		cm := &corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: fmt.Sprintf("%s-config", pb.Name),
+				Labels: map[string]string{
+					"app":  pb.Name,
+					"topo": n.Namespace,
+				},
 			},
 			Data: map[string]string{
 				pb.Config.ConfigFile: string(data),
@@ -250,7 +686,7 @@ func (n *Impl) CreateConfig(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		log.Infof("Server Config Map:\n%v\n", sCM)
+		n.Logger().Infof("Server Config Map:\n%v\n", sCM)
 	}
 	return nil
 }
@@ -258,10 +694,10 @@ func (n *Impl) CreateConfig(ctx context.Context) error {
 // CreatePod creates a Pod for the Node based on the underlying proto.
 func (n *Impl) CreatePod(ctx context.Context) error {
 	pb := n.Proto
-	log.Infof("Creating Pod:\n %+v", pb)
-	initContainerImage := pb.Config.InitImage
-	if initContainerImage == "" {
-		initContainerImage = DefaultInitContainerImage
+	n.Logger().Infof("Creating Pod:\n %+v", pb)
+	initContainers, err := InitContainers(pb)
+	if err != nil {
+		return err
 	}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -272,15 +708,7 @@ func (n *Impl) CreatePod(ctx context.Context) error {
 			},
 		},
 		Spec: corev1.PodSpec{
-			InitContainers: []corev1.Container{{
-				Name:  fmt.Sprintf("init-%s", pb.Name),
-				Image: initContainerImage,
-				Args: []string{
-					fmt.Sprintf("%d", len(n.Proto.Interfaces)+1),
-					fmt.Sprintf("%d", pb.Config.Sleep),
-				},
-				ImagePullPolicy: "IfNotPresent",
-			}},
+			InitContainers: initContainers,
 			Containers: []corev1.Container{{
 				Name:            pb.Name,
 				Image:           pb.Config.Image,
@@ -289,10 +717,9 @@ func (n *Impl) CreatePod(ctx context.Context) error {
 				Env:             ToEnvVar(pb.Config.Env),
 				Resources:       ToResourceRequirements(pb.Constraints),
 				ImagePullPolicy: "IfNotPresent",
-				SecurityContext: &corev1.SecurityContext{
-					Privileged: pointer.Bool(true),
-				},
+				SecurityContext: containerSecurityContext(pb),
 			}},
+			ImagePullSecrets:              ToImagePullSecrets(pb),
 			TerminationGracePeriodSeconds: pointer.Int64(0),
 			NodeSelector:                  map[string]string{},
 			Affinity: &corev1.Affinity{
@@ -314,6 +741,10 @@ func (n *Impl) CreatePod(ctx context.Context) error {
 			},
 		},
 	}
+	ApplyScheduling(&pod.Spec, pb)
+	if err := ApplyVolumes(&pod.Spec, pb); err != nil {
+		return err
+	}
 	if pb.Config.ConfigData != nil {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
 			Name: "startup-config-volume",
@@ -334,19 +765,31 @@ func (n *Impl) CreatePod(ctx context.Context) error {
 			})
 		}
 	}
+	if err := ApplySidecars(&pod.Spec, pb); err != nil {
+		return err
+	}
 	sPod, err := n.KubeClient.CoreV1().Pods(n.Namespace).Create(ctx, pod, metav1.CreateOptions{})
 	if err != nil {
 		return err
 	}
-	log.Debugf("Pod created:\n%+v\n", sPod)
+	n.Logger().Debugf("Pod created:\n%+v\n", sPod)
 	return nil
 }
 
+// containerSecurityContext returns the security context of the node
+// container, privileged unless set in the config of the node.
+func containerSecurityContext(pb *tpb.Node) *corev1.SecurityContext {
+	if sc := ToSecurityContext(pb.GetConfig().GetSecurityContext()); sc != nil {
+		return sc
+	}
+	return &corev1.SecurityContext{Privileged: pointer.Bool(true)}
+}
+
 // CreateService creates services for the node based on the underlying proto.
 func (n *Impl) CreateService(ctx context.Context) error {
 	var servicePorts []corev1.ServicePort
 	if len(n.Proto.Services) == 0 {
-		log.Info("no services found")
+		n.Logger().Info("no services found")
 		return nil
 	}
 	for k, v := range n.Proto.Services {
@@ -376,7 +819,8 @@ func (n *Impl) CreateService(ctx context.Context) error {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("service-%s", n.Name()),
 			Labels: map[string]string{
-				"pod": n.Name(),
+				"pod":  n.Name(),
+				"topo": n.Namespace,
 			},
 		},
 		Spec: corev1.ServiceSpec{
@@ -391,7 +835,7 @@ func (n *Impl) CreateService(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	log.Infof("Created Service:\n%v\n", sS)
+	n.Logger().Infof("Created Service:\n%v\n", sS)
 	return nil
 }
 
@@ -399,14 +843,14 @@ func (n *Impl) CreateService(ctx context.Context) error {
 func (n *Impl) Delete(ctx context.Context) error {
 	// Delete config maps for node
 	if err := n.DeleteConfig(ctx); err != nil {
-		log.Warnf("Error deleting config-map %q: %v", n.Name(), err)
+		n.Logger().Warnf("Error deleting config-map %q: %v", n.Name(), err)
 	}
 	if err := n.DeleteService(ctx); err != nil {
-		log.Warnf("Error deleting service %q: %v", n.Name(), err)
+		n.Logger().Warnf("Error deleting service %q: %v", n.Name(), err)
 	}
 	// Delete Resource for node
 	if err := n.DeleteResource(ctx); err != nil {
-		log.Warnf("Error deleting resource %q: %v", n.Name(), err)
+		n.Logger().Warnf("Error deleting resource %q: %v", n.Name(), err)
 	}
 	return nil
 }
@@ -428,42 +872,79 @@ func (n *Impl) DeleteService(ctx context.Context) error {
 
 // DeleteResource removes the resource definition for the Node.
 func (n *Impl) DeleteResource(ctx context.Context) error {
-	log.Infof("Deleting Resource for Pod:%s", n.Name())
+	n.Logger().Infof("Deleting Resource for Pod:%s", n.Name())
 	return n.KubeClient.CoreV1().Pods(n.Namespace).Delete(ctx, n.Name(), metav1.DeleteOptions{})
 }
 
-// Exec will make a connection via spdy transport to the Pod and execute the provided command.
-// It will wire up stdin, stdout, stderr to provided io channels.
-func (n *Impl) Exec(ctx context.Context, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+// execStream runs an exec session in the node pod. It is a variable for
+// testing.
+var execStream = func(ctx context.Context, n *Impl, opts *corev1.PodExecOptions, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
 	req := n.KubeClient.CoreV1().RESTClient().Post().Resource("pods").Name(n.Name()).Namespace(n.Namespace).SubResource("exec")
-	opts := &corev1.PodExecOptions{
-		Command:   cmd,
-		Container: n.Name(),
-		Stdin:     true,
-		Stdout:    true,
-		Stderr:    true,
-		TTY:       true,
-	}
-	if stdin == nil {
-		opts.Stdin = false
-	}
 	req.VersionedParams(
 		opts,
 		scheme.ParameterCodec,
 	)
-
 	exec, err := remotecommand.NewSPDYExecutor(n.RestConfig, "POST", req.URL())
 	if err != nil {
 		return err
 	}
-	log.Infof("Execing %s on %s", cmd, n.Name())
 	return exec.Stream(remotecommand.StreamOptions{
 		Stdin:  stdin,
 		Stdout: stdout,
 		Stderr: stderr,
+		Tty:    opts.TTY,
 	})
 }
 
+// Exec will make a connection via spdy transport to the Pod and execute the provided command.
+// It will wire up stdin, stdout, stderr to provided io channels. A TTY is only
+// allocated if stdin is provided, in which case stderr is merged into stdout.
+// The command is run in the node container, see ExecContainer.
+func (n *Impl) Exec(ctx context.Context, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	return n.ExecContainer(ctx, "", cmd, stdin, stdout, stderr)
+}
+
+// ExecContainer is like Exec but runs cmd in the named container of the node
+// pod. If container is empty the node container is used, see ContainerName.
+func (n *Impl) ExecContainer(ctx context.Context, container string, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	if container == "" {
+		container = n.Name()
+		if p, err := n.pod(ctx); err == nil {
+			container = ContainerName(p, n.Name())
+		}
+	}
+	opts := &corev1.PodExecOptions{
+		Command:   cmd,
+		Container: container,
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       true,
+	}
+	if stdin == nil {
+		opts.Stdin = false
+		opts.TTY = false
+	}
+	n.Logger().Infof("Execing %s on %s/%s", cmd, n.Name(), container)
+	return execStream(ctx, n, opts, stdin, stdout, stderr)
+}
+
+// ContainerName returns the name of the container of the node name in pod p.
+// Vendor controllers do not necessarily name the container after the node,
+// in which case the first container is used.
+func ContainerName(p *corev1.Pod, name string) string {
+	var container string
+	for _, c := range p.Spec.Containers {
+		if container == "" || c.Name == name {
+			container = c.Name
+		}
+	}
+	if container == "" {
+		return name
+	}
+	return container
+}
+
 // Status returns the current node state.
 func (n *Impl) Status(ctx context.Context) (Status, error) {
 	p, err := n.Pods(ctx)
@@ -473,17 +954,169 @@ func (n *Impl) Status(ctx context.Context) (Status, error) {
 	if len(p) != 1 {
 		return StatusUnknown, fmt.Errorf("expected exactly one pod for node %s", n.Name())
 	}
-	switch p[0].Status.Phase {
-	case corev1.PodFailed:
-		return StatusFailed, nil
-	case corev1.PodRunning:
-		for _, cond := range p[0].Status.Conditions {
-			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
-				return StatusRunning, nil
-			}
+	return n.bootStatus(podStatus(p[0]))
+}
+
+// bootTimer tracks since when a node is booting.
+type bootTimer struct {
+	mu    sync.Mutex
+	since time.Time
+}
+
+// bootStatus returns the node state s and error err, unless the node has been
+// booting for longer than its ready timeout, in which case it is reported as
+// failed.
+func (n *Impl) bootStatus(s Status, err error) (Status, error) {
+	n.booting.mu.Lock()
+	defer n.booting.mu.Unlock()
+	if s != StatusBooting {
+		n.booting.since = time.Time{}
+		return s, err
+	}
+	if n.booting.since.IsZero() {
+		n.booting.since = time.Now()
+	}
+	timeout := time.Duration(n.Proto.GetTimeouts().GetReady()) * time.Second
+	if timeout == 0 || time.Since(n.booting.since) <= timeout {
+		return s, err
+	}
+	return StatusFailed, &FailureError{
+		Reason: "ReadyTimeout",
+		Err:    fmt.Errorf("node %s not ready within %s of its pod running", n.Name(), timeout),
+	}
+}
+
+// podStatus returns the status of the node pod. If the node failed the
+// returned error provides the reason.
+func podStatus(p *corev1.Pod) (Status, error) {
+	switch p.Status.Phase {
+	case corev1.PodFailed, corev1.PodSucceeded:
+		err := fmt.Errorf("pod %s", p.Status.Phase)
+		if reason := strings.TrimSpace(p.Status.Reason + " " + p.Status.Message); reason != "" {
+			err = fmt.Errorf("pod %s: %s", p.Status.Phase, reason)
+		}
+		return StatusFailed, &FailureError{Reason: "Pod" + string(p.Status.Phase), Err: err}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...), p.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if w := cs.State.Waiting; w != nil && failedReasons[w.Reason] {
+			return StatusFailed, &FailureError{Reason: w.Reason, Err: fmt.Errorf("container %s: %s: %s", cs.Name, w.Reason, w.Message)}
+		}
+	}
+	if p.Status.Phase != corev1.PodRunning {
+		return StatusCreating, nil
+	}
+	for _, cond := range p.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			return StatusReady, nil
+		}
+	}
+	return StatusBooting, nil
+}
+
+var (
+	// ReadyCheckInterval is the minimum interval between readiness checks of
+	// a node, the result of the last check is returned in between.
+	ReadyCheckInterval = 5 * time.Second
+	// ReadyCheckMaxErrors is the number of consecutive readiness checks which
+	// fail to run, rather than just exit unsuccessfully, after which the node
+	// is reported as failed.
+	ReadyCheckMaxErrors = 6
+)
+
+// readyCheck is the state of the readiness checks of a node.
+type readyCheck struct {
+	mu      sync.Mutex
+	last    time.Time
+	status  Status
+	errors  int
+	lastErr error
+}
+
+// ReadyStatus returns the current node state like Status, however once the
+// pod is ready StatusReady is only returned if additionally cmd succeeds in
+// the node container and its output contains want. Vendor implementations use
+// it to report nodes whose software is not yet ready to be configured as
+// booting, as the pod becomes ready long before for several NOSes.
+//
+// The check is run at most every ReadyCheckInterval. A command exiting
+// unsuccessfully reports the node as booting, while failing to run the
+// command, e.g. as exec is not permitted or the command does not exist,
+// ReadyCheckMaxErrors times in a row reports the node as failed.
+func (n *Impl) ReadyStatus(ctx context.Context, cmd []string, want string) (Status, error) {
+	p, err := n.Pods(ctx)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if len(p) != 1 {
+		return StatusUnknown, fmt.Errorf("expected exactly one pod for node %s", n.Name())
+	}
+	if s, err := podStatus(p[0]); s != StatusReady {
+		return n.bootStatus(s, err)
+	}
+	return n.bootStatus(n.readyStatus(ctx, p[0], cmd, want))
+}
+
+// readyStatus returns the state of the node with the ready pod p, running the
+// readiness check unless it was run within ReadyCheckInterval.
+func (n *Impl) readyStatus(ctx context.Context, p *corev1.Pod, cmd []string, want string) (Status, error) {
+	n.ready.mu.Lock()
+	defer n.ready.mu.Unlock()
+	if n.ready.status != "" && time.Since(n.ready.last) < ReadyCheckInterval {
+		return n.ready.status, nil
+	}
+	n.ready.last = time.Now()
+	n.ready.status = n.readyCheck(ctx, p, cmd, want)
+	if n.ready.errors >= ReadyCheckMaxErrors {
+		// Later checks are run again in case the cause is fixed.
+		n.ready.status = ""
+		return StatusFailed, &FailureError{
+			Reason: "ReadinessCheckFailed",
+			Err:    fmt.Errorf("readiness check %v failed %d times: %w", cmd, n.ready.errors, n.ready.lastErr),
 		}
 	}
-	return StatusPending, nil
+	return n.ready.status, nil
+}
+
+// readyCheck runs the readiness check cmd in pod p and returns the resulting
+// state of the node. n.ready must be locked.
+func (n *Impl) readyCheck(ctx context.Context, p *corev1.Pod, cmd []string, want string) Status {
+	var out bytes.Buffer
+	opts := &corev1.PodExecOptions{
+		Command:   cmd,
+		Container: ContainerName(p, n.Name()),
+		Stdout:    true,
+		Stderr:    true,
+	}
+	err := execStream(ctx, n, opts, nil, &out, &out)
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+		n.ready.errors = 0
+	case errors.As(err, &exitErr):
+		n.ready.errors = 0
+		n.Logger().Debugf("Node %s readiness check %v failed: %v: %s", n.Name(), cmd, err, out.String())
+		return StatusBooting
+	default:
+		n.ready.errors++
+		n.ready.lastErr = err
+		n.Logger().Warnf("Node %s readiness check %v could not be run: %v", n.Name(), cmd, err)
+		return StatusBooting
+	}
+	if !strings.Contains(out.String(), want) {
+		n.Logger().Debugf("Node %s readiness check %v output does not contain %q: %s", n.Name(), cmd, want, out.String())
+		return StatusBooting
+	}
+	return StatusReady
+}
+
+// Logger returns a logger with the topology, namespace and node fields set.
+func (n *Impl) Logger() *log.Entry {
+	return log.WithFields(log.Fields{
+		"topology":  n.Namespace,
+		"namespace": n.Namespace,
+		"node":      n.Name(),
+	})
 }
 
 // Name returns the name of the node.
@@ -493,7 +1126,7 @@ func (n *Impl) Name() string {
 
 // Pod returns the pod definition for the node.
 func (n *Impl) Pods(ctx context.Context) ([]*corev1.Pod, error) {
-	p, err := n.KubeClient.CoreV1().Pods(n.Namespace).Get(ctx, n.Name(), metav1.GetOptions{})
+	p, err := n.pod(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -542,6 +1175,9 @@ func (n *Impl) PatchCLIConnOpen(bin string, cliCmd []string, opts []scrapliutil.
 	if n.Kubecfg != "" {
 		args = append(args, fmt.Sprintf("--kubeconfig=%s", n.Kubecfg))
 	}
+	if n.KubeContext != "" {
+		args = append(args, fmt.Sprintf("--context=%s", n.KubeContext))
+	}
 	args = append(args, "exec", "-it", "-n", n.GetNamespace(), n.Name(), "--")
 	args = append(args, cliCmd...)
 
@@ -566,24 +1202,91 @@ func (n *Impl) GetCLIConn(platform string, opts []scrapliutil.Option) (*scraplin
 			opts...,
 		)
 		if err != nil {
-			log.Errorf("failed to fetch platform instance for device %s; error: %+v\n", err, n.Name())
+			n.Logger().Errorf("failed to fetch platform instance for device %s; error: %+v\n", err, n.Name())
 			return nil, err
 		}
 
 		d, err := p.GetNetworkDriver()
 		if err != nil {
-			log.Errorf("failed to create driver for device %s; error: %+v\n", err, n.Name())
+			n.Logger().Errorf("failed to create driver for device %s; error: %+v\n", err, n.Name())
 			return nil, err
 		}
 
 		if err = d.Open(); err != nil {
-			log.Debugf("%s - Cli not ready (%s) - waiting.", n.Name(), err)
+			n.Logger().Debugf("%s - Cli not ready (%s) - waiting.", n.Name(), err)
 			time.Sleep(time.Second * 2)
 			continue
 		}
 
-		log.Debugf("%s - Cli ready.", n.Name())
+		n.Logger().Debugf("%s - Cli ready.", n.Name())
 
 		return d, nil
 	}
 }
+
+// RunCLI calls f, an operation on a CLI connection of the node, and returns
+// its error. If ctx is canceled first abort is called to unblock f, e.g. by
+// closing the transport of the connection, and the error of ctx is returned
+// once f returned, so f no longer uses the connection or writes its output.
+func (n *Impl) RunCLI(ctx context.Context, abort func(), f func() error) error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- f()
+	}()
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+	}
+	abort()
+	<-ch
+	return fmt.Errorf("%s - CLI operation aborted: %w", n.Name(), ctx.Err())
+}
+
+// AbortCLI returns a function force closing the transport of the CLI
+// connection d, failing the operation in progress on d, for RunCLI.
+func AbortCLI(d *scraplinetwork.Driver) func() {
+	return func() {
+		if err := d.Transport.Close(true); err != nil {
+			log.Warnf("Failed to close CLI transport: %v", err)
+		}
+	}
+}
+
+// DiagnosticsTimeout bounds the time taken to collect the diagnostics of a
+// node, as commands like show tech-support can take minutes.
+var DiagnosticsTimeout = 10 * time.Minute
+
+// WriteDiagnostics sends the diagnostic cmds over the CLI connection d and
+// writes the output of each command to w, closing d once done. Collection is
+// aborted after DiagnosticsTimeout or once ctx is done.
+func (n *Impl) WriteDiagnostics(ctx context.Context, d *scraplinetwork.Driver, cmds []string, w io.Writer) error {
+	defer d.Close()
+	ctx, cancel := context.WithTimeout(ctx, DiagnosticsTimeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	type result struct {
+		resp *scrapliresponse.MultiResponse
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		resp, err := d.SendCommands(cmds, scrapliopopts.WithTimeoutOps(time.Until(deadline)))
+		ch <- result{resp: resp, err: err}
+	}()
+	var r result
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("failed to collect diagnostics of %s: %w", n.Name(), ctx.Err())
+	case r = <-ch:
+	}
+	if r.err != nil {
+		return r.err
+	}
+	for _, resp := range r.resp.Responses {
+		if _, err := fmt.Fprintf(w, "# %s\n%s\n", resp.Input, resp.Result); err != nil {
+			return err
+		}
+	}
+	return r.resp.Failed
+}
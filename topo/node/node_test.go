@@ -2,16 +2,23 @@ package node
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/h-fam/errdiff"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/utils/pointer"
 
 	topopb "github.com/openconfig/kne/proto/topo"
 )
@@ -39,7 +46,7 @@ func NewR(impl *Impl) (Node, error) {
 func TestReset(t *testing.T) {
 	Register(topopb.Node_Type(1001), NewR)
 	Register(topopb.Node_Type(1002), NewNR)
-	n, err := New("test", &topopb.Node{Type: topopb.Node_Type(1001)}, nil, nil, "", "")
+	n, err := New("test", &topopb.Node{Type: topopb.Node_Type(1001)}, nil, nil, "", "", "")
 	if err != nil {
 		t.Fatalf("failed to create node: %v", err)
 	}
@@ -50,7 +57,7 @@ func TestReset(t *testing.T) {
 	if err := r.ResetCfg(context.Background()); err != nil {
 		t.Errorf("Resettable node failed to reset: %v", err)
 	}
-	nr, err := New("test", &topopb.Node{Type: topopb.Node_Type(1002)}, nil, nil, "", "")
+	nr, err := New("test", &topopb.Node{Type: topopb.Node_Type(1002)}, nil, nil, "", "", "")
 	if err != nil {
 		t.Fatalf("failed to create node: %v", err)
 	}
@@ -60,6 +67,521 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestApplyScheduling(t *testing.T) {
+	spread := corev1.WeightedPodAffinityTerm{
+		Weight: 100,
+		PodAffinityTerm: corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "topo", Operator: "In", Values: []string{"r1"}}},
+			},
+			TopologyKey: "kubernetes.io/hostname",
+		},
+	}
+	tests := []struct {
+		desc       string
+		scheduling *topopb.Scheduling
+		spec       corev1.PodSpec
+		want       corev1.PodSpec
+	}{{
+		desc: "none",
+		spec: corev1.PodSpec{NodeSelector: map[string]string{}},
+		want: corev1.PodSpec{NodeSelector: map[string]string{}},
+	}, {
+		desc: "node selector and tolerations",
+		scheduling: &topopb.Scheduling{
+			NodeSelector: map[string]string{"pool": "big"},
+			Tolerations: []*topopb.Toleration{
+				{Key: "dedicated", Value: "vm", Effect: "NoSchedule"},
+				{Key: "gpu", Operator: "Exists"},
+			},
+		},
+		spec: corev1.PodSpec{NodeSelector: map[string]string{}},
+		want: corev1.PodSpec{
+			NodeSelector: map[string]string{"pool": "big"},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Value: "vm", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "gpu", Operator: corev1.TolerationOpExists},
+			},
+		},
+	}, {
+		desc: "affinity",
+		scheduling: &topopb.Scheduling{
+			Affinity: &topopb.Affinity{
+				Required: []*topopb.LabelRequirement{
+					{Key: "kvm", Operator: "Exists"},
+					{Key: "cpu", Operator: "Gt", Values: []string{"16"}},
+				},
+				Preferred: []*topopb.LabelRequirement{
+					{Key: "zone", Operator: "In", Values: []string{"a"}, Weight: 50},
+					{Key: "ssd", Operator: "Exists"},
+				},
+				SpreadFrom: []string{"r2", "r3"},
+			},
+		},
+		spec: corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{spread},
+				},
+			},
+		},
+		want: corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "kvm", Operator: corev1.NodeSelectorOpExists},
+								{Key: "cpu", Operator: corev1.NodeSelectorOpGt, Values: []string{"16"}},
+							},
+						}},
+					},
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+						Weight: 50,
+						Preference: corev1.NodeSelectorTerm{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}},
+						},
+					}, {
+						Weight: 1,
+						Preference: corev1.NodeSelectorTerm{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "ssd", Operator: corev1.NodeSelectorOpExists}},
+						},
+					}},
+				},
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{spread, {
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "In", Values: []string{"r2", "r3"}}},
+							},
+							TopologyKey: "kubernetes.io/hostname",
+						},
+					}},
+				},
+			},
+		},
+	}, {
+		desc: "required added to existing terms",
+		scheduling: &topopb.Scheduling{
+			Affinity: &topopb.Affinity{
+				Required: []*topopb.LabelRequirement{{Key: "kvm", Operator: "Exists"}},
+			},
+		},
+		spec: corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "a", Operator: corev1.NodeSelectorOpExists}}},
+							{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "b", Operator: corev1.NodeSelectorOpExists}}},
+						},
+					},
+				},
+			},
+		},
+		want: corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "a", Operator: corev1.NodeSelectorOpExists}, {Key: "kvm", Operator: corev1.NodeSelectorOpExists}}},
+							{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "b", Operator: corev1.NodeSelectorOpExists}, {Key: "kvm", Operator: corev1.NodeSelectorOpExists}}},
+						},
+					},
+				},
+			},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			spec := tt.spec
+			ApplyScheduling(&spec, &topopb.Node{Name: "r1", Scheduling: tt.scheduling})
+			if s := cmp.Diff(tt.want, spec); s != "" {
+				t.Errorf("ApplyScheduling() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestApplyVolumes(t *testing.T) {
+	limit := resource.MustParse("1Gi")
+	tests := []struct {
+		desc    string
+		volumes []*topopb.Volume
+		want    corev1.PodSpec
+		wantErr string
+	}{{
+		desc: "none",
+		want: corev1.PodSpec{Containers: []corev1.Container{{Name: "r1"}}},
+	}, {
+		desc: "volumes",
+		volumes: []*topopb.Volume{{
+			Name:      "license",
+			Source:    &topopb.Volume_Secret{Secret: "license"},
+			MountPath: "/mnt/license",
+			ReadOnly:  true,
+		}, {
+			Name:      "scripts",
+			Source:    &topopb.Volume_ConfigMap{ConfigMap: "scripts"},
+			MountPath: "/etc/scripts/init.sh",
+			SubPath:   "init.sh",
+		}, {
+			Name:      "keys",
+			Source:    &topopb.Volume_HostPath{HostPath: "/home/lab/.ssh"},
+			MountPath: "/root/.ssh",
+		}, {
+			Name:   "shared",
+			Source: &topopb.Volume_EmptyDir{EmptyDir: &topopb.EmptyDirVolume{Medium: "Memory", SizeLimit: "1Gi"}},
+		}},
+		want: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "r1",
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "license", MountPath: "/mnt/license", ReadOnly: true},
+					{Name: "scripts", MountPath: "/etc/scripts/init.sh", SubPath: "init.sh"},
+					{Name: "keys", MountPath: "/root/.ssh"},
+				},
+			}},
+			Volumes: []corev1.Volume{
+				{Name: "license", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "license"}}},
+				{Name: "scripts", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "scripts"}}}},
+				{Name: "keys", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/home/lab/.ssh"}}},
+				{Name: "shared", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &limit}}},
+			},
+		},
+	}, {
+		desc:    "no name",
+		volumes: []*topopb.Volume{{Source: &topopb.Volume_Secret{Secret: "license"}}},
+		wantErr: "has no name",
+	}, {
+		desc:    "no source",
+		volumes: []*topopb.Volume{{Name: "license"}},
+		wantErr: `volume "license" of node "r1" has no source`,
+	}, {
+		desc: "invalid size limit",
+		volumes: []*topopb.Volume{{
+			Name:   "shared",
+			Source: &topopb.Volume_EmptyDir{EmptyDir: &topopb.EmptyDirVolume{SizeLimit: "big"}},
+		}},
+		wantErr: `invalid size limit "big"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "r1"}}}
+			err := ApplyVolumes(&spec, &topopb.Node{Name: "r1", Config: &topopb.Config{Volumes: tt.volumes}})
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("ApplyVolumes() unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if s := cmp.Diff(tt.want, spec); s != "" {
+				t.Errorf("ApplyVolumes() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestApplySidecars(t *testing.T) {
+	tests := []struct {
+		desc     string
+		sidecars []*topopb.Sidecar
+		want     []corev1.Container
+		wantErr  string
+	}{{
+		desc: "none",
+		want: []corev1.Container{{Name: "r1"}},
+	}, {
+		desc: "sidecars",
+		sidecars: []*topopb.Sidecar{{
+			Name:        "exporter",
+			Image:       "exporter:latest",
+			Args:        []string{"--port=9100"},
+			Env:         map[string]string{"TARGET": "localhost"},
+			Mounts:      []*topopb.VolumeMount{{Name: "shared", MountPath: "/data", ReadOnly: true}},
+			Constraints: map[string]string{"cpu": "100m", "memory": "64Mi"},
+		}, {
+			Name:       "capture",
+			Image:      "tcpdump:latest",
+			Command:    []string{"tcpdump", "-i", "eth1"},
+			Privileged: true,
+		}},
+		want: []corev1.Container{{Name: "r1"}, {
+			Name:            "exporter",
+			Image:           "exporter:latest",
+			Args:            []string{"--port=9100"},
+			Env:             []corev1.EnvVar{{Name: "TARGET", Value: "localhost"}},
+			ImagePullPolicy: "IfNotPresent",
+			VolumeMounts:    []corev1.VolumeMount{{Name: "shared", MountPath: "/data", ReadOnly: true}},
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			}},
+		}, {
+			Name:            "capture",
+			Image:           "tcpdump:latest",
+			Command:         []string{"tcpdump", "-i", "eth1"},
+			ImagePullPolicy: "IfNotPresent",
+			SecurityContext: &corev1.SecurityContext{Privileged: pointer.Bool(true)},
+		}},
+	}, {
+		desc:     "no image",
+		sidecars: []*topopb.Sidecar{{Name: "exporter"}},
+		wantErr:  `sidecar "exporter" of node "r1" must have a name and an image`,
+	}, {
+		desc:     "invalid constraint",
+		sidecars: []*topopb.Sidecar{{Name: "exporter", Image: "exporter:latest", Constraints: map[string]string{"cpu": "lots"}}},
+		wantErr:  `sidecar "exporter" of node "r1": invalid cpu constraint "lots"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "r1"}}}
+			err := ApplySidecars(&spec, &topopb.Node{Name: "r1", Config: &topopb.Config{Sidecars: tt.sidecars}})
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("ApplySidecars() unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if s := cmp.Diff(tt.want, spec.Containers); s != "" {
+				t.Errorf("ApplySidecars() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestInitContainers(t *testing.T) {
+	interfaces := map[string]*topopb.Interface{"eth1": {}, "eth2": {}}
+	restricted := ToSecurityContext(&topopb.SecurityContext{Restricted: true})
+	tests := []struct {
+		desc    string
+		config  *topopb.Config
+		want    []corev1.Container
+		wantErr string
+	}{{
+		desc:   "default",
+		config: &topopb.Config{Sleep: 5},
+		want: []corev1.Container{{
+			Name:            "init-r1",
+			Image:           DefaultInitContainerImage,
+			Args:            []string{"3", "5"},
+			ImagePullPolicy: "IfNotPresent",
+		}},
+	}, {
+		desc: "custom wait and init containers",
+		config: &topopb.Config{
+			InitImage: "registry.local/init-wait:ga",
+			InitWait:  &topopb.InitWait{Interfaces: 5},
+			InitContainers: []*topopb.Sidecar{{
+				Name:    "license",
+				Image:   "busybox",
+				Command: []string{"cp", "/license/key", "/shared/key"},
+				Mounts:  []*topopb.VolumeMount{{Name: "shared", MountPath: "/shared"}},
+			}},
+		},
+		want: []corev1.Container{{
+			Name:            "init-r1",
+			Image:           "registry.local/init-wait:ga",
+			Args:            []string{"5", "0"},
+			ImagePullPolicy: "IfNotPresent",
+		}, {
+			Name:            "license",
+			Image:           "busybox",
+			Command:         []string{"cp", "/license/key", "/shared/key"},
+			ImagePullPolicy: "IfNotPresent",
+			VolumeMounts:    []corev1.VolumeMount{{Name: "shared", MountPath: "/shared"}},
+		}},
+	}, {
+		desc: "wait disabled",
+		config: &topopb.Config{
+			InitWait:       &topopb.InitWait{Disabled: true},
+			InitContainers: []*topopb.Sidecar{{Name: "prepare", Image: "busybox"}},
+		},
+		want: []corev1.Container{{
+			Name:            "prepare",
+			Image:           "busybox",
+			ImagePullPolicy: "IfNotPresent",
+		}},
+	}, {
+		desc: "restricted",
+		config: &topopb.Config{
+			SecurityContext: &topopb.SecurityContext{Restricted: true, RunAsUser: 1000},
+			InitContainers: []*topopb.Sidecar{{
+				Name:            "prepare",
+				Image:           "busybox",
+				Privileged:      true,
+				SecurityContext: &topopb.SecurityContext{Restricted: true},
+			}},
+		},
+		want: []corev1.Container{{
+			Name:            "init-r1",
+			Image:           DefaultInitContainerImage,
+			Args:            []string{"3", "0"},
+			ImagePullPolicy: "IfNotPresent",
+			SecurityContext: restricted,
+		}, {
+			Name:            "prepare",
+			Image:           "busybox",
+			ImagePullPolicy: "IfNotPresent",
+			SecurityContext: restricted,
+		}},
+	}, {
+		desc:    "no image",
+		config:  &topopb.Config{InitContainers: []*topopb.Sidecar{{Name: "prepare"}}},
+		wantErr: `init container "prepare" of node "r1" must have a name and an image`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := InitContainers(&topopb.Node{Name: "r1", Interfaces: interfaces, Config: tt.config})
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("InitContainers() unexpected error: %s", s)
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("InitContainers() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestToSecurityContext(t *testing.T) {
+	tests := []struct {
+		desc string
+		sc   *topopb.SecurityContext
+		want *corev1.SecurityContext
+	}{{
+		desc: "unset",
+	}, {
+		desc: "unprivileged",
+		sc:   &topopb.SecurityContext{},
+		want: &corev1.SecurityContext{},
+	}, {
+		desc: "privileged",
+		sc:   &topopb.SecurityContext{Privileged: true, RunAsUser: 0},
+		want: &corev1.SecurityContext{Privileged: pointer.Bool(true)},
+	}, {
+		desc: "capabilities and user",
+		sc: &topopb.SecurityContext{
+			AddCapabilities:  []string{"NET_ADMIN", "NET_RAW"},
+			DropCapabilities: []string{"MKNOD"},
+			RunAsUser:        1000,
+			RunAsGroup:       2000,
+			RunAsNonRoot:     true,
+		},
+		want: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Add:  []corev1.Capability{"NET_ADMIN", "NET_RAW"},
+				Drop: []corev1.Capability{"MKNOD"},
+			},
+			RunAsUser:    pointer.Int64(1000),
+			RunAsGroup:   pointer.Int64(2000),
+			RunAsNonRoot: pointer.Bool(true),
+		},
+	}, {
+		desc: "restricted",
+		sc:   &topopb.SecurityContext{Restricted: true, AddCapabilities: []string{"NET_BIND_SERVICE"}, DropCapabilities: []string{"MKNOD"}},
+		want: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+				Drop: []corev1.Capability{"ALL"},
+			},
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			RunAsUser:                pointer.Int64(65534),
+			RunAsNonRoot:             pointer.Bool(true),
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	}, {
+		desc: "restricted user",
+		sc:   &topopb.SecurityContext{Restricted: true, RunAsUser: 1000},
+		want: &corev1.SecurityContext{
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			RunAsUser:                pointer.Int64(1000),
+			RunAsNonRoot:             pointer.Bool(true),
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if s := cmp.Diff(tt.want, ToSecurityContext(tt.sc)); s != "" {
+				t.Errorf("ToSecurityContext() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+	for _, cfg := range []*topopb.Config{
+		{SecurityContext: &topopb.SecurityContext{Privileged: true, Restricted: true}},
+		{SecurityContext: &topopb.SecurityContext{RunAsUser: -1}},
+		{Sidecars: []*topopb.Sidecar{{Name: "s", Image: "s", SecurityContext: &topopb.SecurityContext{Privileged: true, Restricted: true}}}},
+		{InitContainers: []*topopb.Sidecar{{Name: "i", Image: "i", SecurityContext: &topopb.SecurityContext{RunAsGroup: -1}}}},
+	} {
+		if _, err := New("test", &topopb.Node{Name: "r1", Type: topopb.Node_Type(1001), Config: cfg}, nil, nil, "", "", ""); err == nil {
+			t.Errorf("New() with config %v succeeded, want error", cfg)
+		}
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	tests := []struct {
+		desc        string
+		constraints map[string]string
+		want        corev1.ResourceRequirements
+		wantErr     string
+	}{{
+		desc: "none",
+		want: corev1.ResourceRequirements{Requests: corev1.ResourceList{}},
+	}, {
+		desc:        "cpu and memory",
+		constraints: map[string]string{"cpu": "4", "memory": "12Gi", "ephemeral-storage": "10Gi"},
+		want: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:              resource.MustParse("4"),
+			corev1.ResourceMemory:           resource.MustParse("12Gi"),
+			corev1.ResourceEphemeralStorage: resource.MustParse("10Gi"),
+		}},
+	}, {
+		desc: "hugepages and device plugins",
+		constraints: map[string]string{
+			"cpu":                       "4",
+			"hugepages-1Gi":             "8Gi",
+			"intel.com/sriov_netdevice": "2",
+			"devices.kubevirt.io/kvm":   "1",
+		},
+		want: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:          resource.MustParse("4"),
+				"hugepages-1Gi":             resource.MustParse("8Gi"),
+				"intel.com/sriov_netdevice": resource.MustParse("2"),
+				"devices.kubevirt.io/kvm":   resource.MustParse("1"),
+			},
+			Limits: corev1.ResourceList{
+				"hugepages-1Gi":             resource.MustParse("8Gi"),
+				"intel.com/sriov_netdevice": resource.MustParse("2"),
+				"devices.kubevirt.io/kvm":   resource.MustParse("1"),
+			},
+		},
+	}, {
+		desc:        "invalid",
+		constraints: map[string]string{"hugepages-2Mi": "lots"},
+		wantErr:     `invalid hugepages-2Mi constraint "lots"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ParseConstraints(tt.constraints)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("ParseConstraints() unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("ParseConstraints() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+	if _, err := New("test", &topopb.Node{Name: "r1", Type: topopb.Node_Type(1001), Constraints: map[string]string{"cpu": "four"}}, nil, nil, "", "", ""); err == nil {
+		t.Errorf("New() with invalid constraints succeeded, want error")
+	}
+}
+
 func TestService(t *testing.T) {
 	tests := []struct {
 		desc           string
@@ -94,7 +616,7 @@ func TestService(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "service-dev1",
 				Namespace: "test",
-				Labels:    map[string]string{"pod": "dev1"},
+				Labels:    map[string]string{"pod": "dev1", "topo": "test"},
 			},
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{{
@@ -135,7 +657,7 @@ func TestService(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "service-dev2",
 				Namespace: "test",
-				Labels:    map[string]string{"pod": "dev2"},
+				Labels:    map[string]string{"pod": "dev2", "topo": "test"},
 			},
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{{
@@ -209,3 +731,452 @@ func TestService(t *testing.T) {
 		})
 	}
 }
+
+func TestReadyStatus(t *testing.T) {
+	readyPod := func(phase corev1.PodPhase, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "dev1", Namespace: "test"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "init"}, {Name: "dev1"}},
+			},
+			Status: corev1.PodStatus{
+				Phase: phase,
+				Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodReady,
+					Status: ready,
+				}},
+			},
+		}
+	}
+	tests := []struct {
+		desc      string
+		pod       *corev1.Pod
+		out       string
+		execErr   error
+		want      Status
+		wantExec  bool
+		wantError string
+	}{{
+		desc:     "ready",
+		pod:      readyPod(corev1.PodRunning, corev1.ConditionTrue),
+		out:      "state running",
+		want:     StatusReady,
+		wantExec: true,
+	}, {
+		desc:     "check output mismatch",
+		pod:      readyPod(corev1.PodRunning, corev1.ConditionTrue),
+		out:      "state starting",
+		want:     StatusBooting,
+		wantExec: true,
+	}, {
+		desc:     "check failed",
+		pod:      readyPod(corev1.PodRunning, corev1.ConditionTrue),
+		execErr:  fmt.Errorf("command terminated with exit code 1"),
+		want:     StatusBooting,
+		wantExec: true,
+	}, {
+		desc: "pod booting",
+		pod:  readyPod(corev1.PodRunning, corev1.ConditionFalse),
+		want: StatusBooting,
+	}, {
+		desc: "pod creating",
+		pod:  readyPod(corev1.PodPending, corev1.ConditionFalse),
+		want: StatusCreating,
+	}, {
+		desc: "pod crash looping",
+		pod: func() *corev1.Pod {
+			p := readyPod(corev1.PodRunning, corev1.ConditionFalse)
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name: "dev1",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off restarting failed container"},
+				},
+			}}
+			return p
+		}(),
+		want:      StatusFailed,
+		wantError: "container dev1: CrashLoopBackOff",
+	}, {
+		desc: "init container image pull failure",
+		pod: func() *corev1.Pod {
+			p := readyPod(corev1.PodPending, corev1.ConditionFalse)
+			p.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+				Name: "init-dev1",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+				},
+			}}
+			return p
+		}(),
+		want:      StatusFailed,
+		wantError: "container init-dev1: ImagePullBackOff",
+	}, {
+		desc:      "pod failed",
+		pod:       readyPod(corev1.PodFailed, corev1.ConditionFalse),
+		want:      StatusFailed,
+		wantError: "pod Failed",
+	}, {
+		desc:      "no pod",
+		want:      StatusUnknown,
+		wantError: "not found",
+	}}
+	origExecStream := execStream
+	defer func() {
+		execStream = origExecStream
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kClient := kfake.NewSimpleClientset()
+			if tt.pod != nil {
+				kClient = kfake.NewSimpleClientset(tt.pod)
+			}
+			var gotOpts *corev1.PodExecOptions
+			execStream = func(_ context.Context, _ *Impl, opts *corev1.PodExecOptions, _ io.Reader, stdout io.Writer, _ io.Writer) error {
+				gotOpts = opts
+				fmt.Fprint(stdout, tt.out)
+				return tt.execErr
+			}
+			n := &Impl{
+				Namespace:  "test",
+				KubeClient: kClient,
+				Proto:      &topopb.Node{Name: "dev1"},
+			}
+			got, err := n.ReadyStatus(context.Background(), []string{"check"}, "running")
+			if s := errdiff.Check(err, tt.wantError); s != "" {
+				t.Fatalf("ReadyStatus() unexpected error: %s", s)
+			}
+			if got != tt.want {
+				t.Errorf("ReadyStatus() got %v, want %v", got, tt.want)
+			}
+			if !tt.wantExec {
+				if gotOpts != nil {
+					t.Errorf("ReadyStatus() unexpectedly ran readiness check")
+				}
+				return
+			}
+			want := &corev1.PodExecOptions{
+				Command:   []string{"check"},
+				Container: "dev1",
+				Stdout:    true,
+				Stderr:    true,
+			}
+			if s := cmp.Diff(want, gotOpts); s != "" {
+				t.Errorf("ReadyStatus() unexpected exec options diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestExecContainer(t *testing.T) {
+	pod := func(containers ...string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "dev1", Namespace: "test"}}
+		for _, c := range containers {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: c})
+		}
+		return p
+	}
+	tests := []struct {
+		desc      string
+		pod       *corev1.Pod
+		container string
+		want      string
+	}{{
+		desc: "node container",
+		pod:  pod("sidecar", "dev1"),
+		want: "dev1",
+	}, {
+		desc: "vendor container",
+		pod:  pod("srlinux", "sidecar"),
+		want: "srlinux",
+	}, {
+		desc:      "explicit container",
+		pod:       pod("dev1", "sidecar"),
+		container: "sidecar",
+		want:      "sidecar",
+	}, {
+		desc: "no pod",
+		want: "dev1",
+	}}
+	origExecStream := execStream
+	defer func() {
+		execStream = origExecStream
+	}()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kClient := kfake.NewSimpleClientset()
+			if tt.pod != nil {
+				kClient = kfake.NewSimpleClientset(tt.pod)
+			}
+			var got string
+			execStream = func(_ context.Context, _ *Impl, opts *corev1.PodExecOptions, _ io.Reader, _ io.Writer, _ io.Writer) error {
+				got = opts.Container
+				return nil
+			}
+			n := &Impl{
+				Namespace:  "test",
+				KubeClient: kClient,
+				Proto:      &topopb.Node{Name: "dev1"},
+			}
+			if err := n.ExecContainer(context.Background(), tt.container, []string{"ls"}, nil, io.Discard, io.Discard); err != nil {
+				t.Fatalf("ExecContainer() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExecContainer() ran in container %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadyStatusChecks(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev1", Namespace: "test"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "dev1"}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	origExecStream, origInterval, origMaxErrors := execStream, ReadyCheckInterval, ReadyCheckMaxErrors
+	defer func() {
+		execStream, ReadyCheckInterval, ReadyCheckMaxErrors = origExecStream, origInterval, origMaxErrors
+	}()
+	ReadyCheckMaxErrors = 3
+	tests := []struct {
+		desc      string
+		interval  time.Duration
+		execErr   error
+		checks    int
+		want      Status
+		wantExecs int
+		wantErr   string
+	}{{
+		desc:      "rate limited",
+		interval:  time.Hour,
+		checks:    5,
+		want:      StatusReady,
+		wantExecs: 1,
+	}, {
+		desc:      "command exits unsuccessfully",
+		execErr:   utilexec.CodeExitError{Err: fmt.Errorf("command terminated with non-zero exit code"), Code: 1},
+		checks:    5,
+		want:      StatusBooting,
+		wantExecs: 5,
+	}, {
+		desc:      "exec not permitted",
+		execErr:   fmt.Errorf(`pods "dev1" is forbidden: cannot create resource "pods/exec"`),
+		checks:    3,
+		want:      StatusFailed,
+		wantExecs: 3,
+		wantErr:   "failed 3 times: pods \"dev1\" is forbidden",
+	}, {
+		desc:      "transient exec error",
+		execErr:   fmt.Errorf("connection reset"),
+		checks:    2,
+		want:      StatusBooting,
+		wantExecs: 2,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ReadyCheckInterval = tt.interval
+			var execs int
+			execStream = func(_ context.Context, _ *Impl, _ *corev1.PodExecOptions, _ io.Reader, stdout io.Writer, _ io.Writer) error {
+				execs++
+				fmt.Fprint(stdout, "running")
+				return tt.execErr
+			}
+			n := &Impl{
+				Namespace:  "test",
+				KubeClient: kfake.NewSimpleClientset(pod),
+				Proto:      &topopb.Node{Name: "dev1"},
+			}
+			var got Status
+			var err error
+			for i := 0; i < tt.checks; i++ {
+				got, err = n.ReadyStatus(context.Background(), []string{"check"}, "running")
+			}
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("ReadyStatus() unexpected error: %s", s)
+			}
+			if got != tt.want {
+				t.Errorf("ReadyStatus() got %v, want %v", got, tt.want)
+			}
+			if execs != tt.wantExecs {
+				t.Errorf("ReadyStatus() ran %d checks, want %d", execs, tt.wantExecs)
+			}
+		})
+	}
+}
+
+func TestRunCLI(t *testing.T) {
+	n := &Impl{Proto: &topopb.Node{Name: "dev1"}}
+	tests := []struct {
+		desc string
+		// f is called with a channel closed by the abort function.
+		f           func(aborted <-chan struct{}) error
+		cancel      bool
+		wantAborted bool
+		wantErr     string
+	}{{
+		desc: "success",
+		f:    func(<-chan struct{}) error { return nil },
+	}, {
+		desc:    "failure",
+		f:       func(<-chan struct{}) error { return fmt.Errorf("send failed") },
+		wantErr: "send failed",
+	}, {
+		desc: "canceled",
+		f: func(aborted <-chan struct{}) error {
+			<-aborted
+			return fmt.Errorf("transport closed")
+		},
+		cancel:      true,
+		wantAborted: true,
+		wantErr:     "dev1 - CLI operation aborted: context canceled",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			aborted := make(chan struct{})
+			returned := false
+			err := n.RunCLI(ctx, func() { close(aborted) }, func() error {
+				defer func() { returned = true }()
+				return tt.f(aborted)
+			})
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Errorf("RunCLI() unexpected error: %s", s)
+			}
+			select {
+			case <-aborted:
+				if !tt.wantAborted {
+					t.Errorf("RunCLI() aborted the operation")
+				}
+			default:
+				if tt.wantAborted {
+					t.Errorf("RunCLI() did not abort the operation")
+				}
+			}
+			// RunCLI must not return before the operation.
+			if !returned {
+				t.Errorf("RunCLI() returned before the operation")
+			}
+		})
+	}
+}
+
+func TestReadyTimeout(t *testing.T) {
+	pod := func(ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "dev1", Namespace: "test"},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	tests := []struct {
+		desc        string
+		pod         *corev1.Pod
+		ready       uint32
+		booting     time.Duration
+		want        Status
+		wantBooting bool
+		wantErr     string
+	}{{
+		desc:        "booting within timeout",
+		pod:         pod(corev1.ConditionFalse),
+		ready:       60,
+		booting:     30 * time.Second,
+		want:        StatusBooting,
+		wantBooting: true,
+	}, {
+		desc:        "booting past timeout",
+		pod:         pod(corev1.ConditionFalse),
+		ready:       60,
+		booting:     90 * time.Second,
+		want:        StatusFailed,
+		wantBooting: true,
+		wantErr:     "node dev1 not ready within 1m0s of its pod running",
+	}, {
+		desc:        "booting without timeout",
+		pod:         pod(corev1.ConditionFalse),
+		booting:     time.Hour,
+		want:        StatusBooting,
+		wantBooting: true,
+	}, {
+		desc:        "starts booting",
+		pod:         pod(corev1.ConditionFalse),
+		ready:       60,
+		want:        StatusBooting,
+		wantBooting: true,
+	}, {
+		desc:    "ready resets timer",
+		pod:     pod(corev1.ConditionTrue),
+		ready:   60,
+		booting: 90 * time.Second,
+		want:    StatusReady,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			n := &Impl{
+				Namespace:  "test",
+				KubeClient: kfake.NewSimpleClientset(tt.pod),
+				Proto:      &topopb.Node{Name: "dev1", Timeouts: &topopb.Timeouts{Ready: tt.ready}},
+			}
+			if tt.booting > 0 {
+				n.booting.since = time.Now().Add(-tt.booting)
+			}
+			got, err := n.Status(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("Status() unexpected error: %s", s)
+			}
+			if got != tt.want {
+				t.Errorf("Status() got %v, want %v", got, tt.want)
+			}
+			var fe *FailureError
+			if tt.wantErr != "" && (!errors.As(err, &fe) || fe.Reason != "ReadyTimeout") {
+				t.Errorf("Status() got error %v, want failure reason ReadyTimeout", err)
+			}
+			if gotBooting := !n.booting.since.IsZero(); gotBooting != tt.wantBooting {
+				t.Errorf("Status() tracks booting %v, want %v", gotBooting, tt.wantBooting)
+			}
+		})
+	}
+}
+
+func TestRedirectCommands(t *testing.T) {
+	want := []string{
+		"ip link set dev eth1 up",
+		"tc qdisc del dev eth1 ingress 2>/dev/null || true",
+		"tc qdisc add dev eth1 ingress",
+		"tc filter add dev eth1 parent ffff: matchall action mirred egress redirect dev tap1",
+	}
+	if s := cmp.Diff(want, RedirectCommands("eth1", "tap1")); s != "" {
+		t.Errorf("RedirectCommands() unexpected diff (-want +got):\n%s", s)
+	}
+}
+
+func TestValidInterfaceName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "eth1", want: true},
+		{name: "e1-1.100", want: true},
+		{name: "xyz_0123456789a", want: true},
+		{name: "xyz_0123456789ab"},
+		{name: ""},
+		{name: "eth1; reboot"},
+		{name: "eth1/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidInterfaceName(tt.name); got != tt.want {
+				t.Errorf("ValidInterfaceName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
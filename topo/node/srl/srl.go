@@ -12,13 +12,11 @@ import (
 	scrapliopopts "github.com/scrapli/scrapligo/driver/opoptions"
 	scrapliopts "github.com/scrapli/scrapligo/driver/options"
 	scrapliutil "github.com/scrapli/scrapligo/util"
-	log "github.com/sirupsen/logrus"
 	srlclient "github.com/srl-labs/srl-controller/api/clientset/v1alpha1"
 	srltypes "github.com/srl-labs/srl-controller/api/types/v1alpha1"
 	"github.com/srl-labs/srlinux-scrapli"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
 )
 
 const (
@@ -54,9 +52,12 @@ type Node struct {
 
 // Add validations for interfaces the node provides
 var (
-	_ node.Certer       = (*Node)(nil)
-	_ node.Resetter     = (*Node)(nil)
-	_ node.ConfigPusher = (*Node)(nil)
+	_ node.Certer             = (*Node)(nil)
+	_ node.CertRotator        = (*Node)(nil)
+	_ node.Resetter           = (*Node)(nil)
+	_ node.Diagnoser          = (*Node)(nil)
+	_ node.ConfigPusher       = (*Node)(nil)
+	_ node.ConfigPushStreamer = (*Node)(nil)
 )
 
 // GenerateSelfSigned generates a self-signed TLS certificate using SR Linux tools command
@@ -64,81 +65,111 @@ var (
 func (n *Node) GenerateSelfSigned(ctx context.Context) error {
 	selfSigned := n.Proto.GetConfig().GetCert().GetSelfSigned()
 	if selfSigned == nil {
-		log.Infof("%s - no cert config", n.Name())
+		n.Logger().Infof("%s - no cert config", n.Name())
 		return nil
 	}
-	log.Infof("%s - generating self signed certs", n.Name())
-	log.Infof("%s - waiting for pod to be running", n.Name())
-	w, err := n.KubeClient.CoreV1().Pods(n.Namespace).Watch(ctx, metav1.ListOptions{
-		FieldSelector: fields.SelectorFromSet(
-			fields.Set{metav1.ObjectNameField: n.Name()},
-		).String(),
-	})
-	if err != nil {
+	n.Logger().Infof("%s - generating self signed certs", n.Name())
+	n.Logger().Infof("%s - waiting for pod to be running", n.Name())
+	if _, err := n.WaitForPod(ctx, func(p *corev1.Pod) bool { return p.Status.Phase == corev1.PodRunning }); err != nil {
 		return err
 	}
-	for e := range w.ResultChan() {
-		p := e.Object.(*corev1.Pod)
-		if p.Status.Phase == corev1.PodRunning {
-			break
-		}
-	}
-	log.Infof("%s - pod running.", n.Name())
+	n.Logger().Infof("%s - pod running.", n.Name())
 
 	if err := n.SpawnCLIConn(); err != nil {
 		return err
 	}
 
-	err = srlinux.AddSelfSignedServerTLSProfile(n.cliConn, selfSigned.CertName, false)
-	if err != nil {
+	if err := srlinux.AddSelfSignedServerTLSProfile(n.cliConn, selfSigned.CertName, false); err != nil {
 		return err
 	}
 
-	log.Infof("%s - finished cert generation", n.Name())
+	n.Logger().Infof("%s - finished cert generation", n.Name())
 
 	return n.cliConn.Close()
 }
 
+// RotateCert generates a new self-signed TLS certificate and replaces the one
+// of the server profile of the running node. The profile is updated in a
+// single commit, so the servers using it keep running.
+func (n *Node) RotateCert(ctx context.Context) error {
+	selfSigned := n.Proto.GetConfig().GetCert().GetSelfSigned()
+	if selfSigned == nil {
+		n.Logger().Infof("%s - no cert config", n.Name())
+		return nil
+	}
+	n.Logger().Infof("%s - rotating self signed certs", n.Name())
+
+	if err := n.SpawnCLIConn(); err != nil {
+		return err
+	}
+
+	defer n.cliConn.Close()
+
+	if err := n.RunCLI(ctx, node.AbortCLI(n.cliConn), func() error {
+		return srlinux.AddSelfSignedServerTLSProfile(n.cliConn, selfSigned.CertName, false)
+	}); err != nil {
+		return err
+	}
+
+	n.Logger().Infof("%s - finished cert rotation", n.Name())
+
+	return nil
+}
+
 // ConfigPush pushes config lines provided in r using scrapligo SendConfig
 func (n *Node) ConfigPush(ctx context.Context, r io.Reader) error {
-	log.Infof("%s - pushing config", n.Name())
+	return n.ConfigPushStream(ctx, r, nil)
+}
+
+// ConfigPushStream pushes config lines provided in r like ConfigPush, writing
+// the output of the device to w if not nil.
+func (n *Node) ConfigPushStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	n.Logger().Infof("%s - pushing config", n.Name())
 
 	cfg, err := io.ReadAll(r)
 	cfgs := string(cfg)
 
-	log.Debugf("config to push:\n%s", cfgs)
+	n.Logger().Debugf("config to push:\n%s", cfgs)
 
 	if err != nil {
 		return err
 	}
 
-	err = n.SpawnCLIConn()
+	var opts []scrapliutil.Option
+	if w != nil {
+		opts = append(opts, scrapliopts.WithChannelLog(w))
+	}
+	err = n.SpawnCLIConn(opts...)
 	if err != nil {
 		return err
 	}
 
 	defer n.cliConn.Close()
 
-	resp, err := n.cliConn.SendConfig(cfgs, scrapliopopts.WithStopOnFailed())
+	err = n.RunCLI(ctx, node.AbortCLI(n.cliConn), func() error {
+		resp, err := n.cliConn.SendConfig(cfgs, scrapliopopts.WithStopOnFailed())
+		if err != nil {
+			return err
+		}
+		return resp.Failed
+	})
 	if err != nil {
 		return err
 	}
 
-	if resp.Failed == nil {
-		log.Infof("%s - finshed config push", n.Impl.Proto.Name)
-	}
+	n.Logger().Infof("%s - finshed config push", n.Impl.Proto.Name)
 
-	return resp.Failed
+	return nil
 }
 
 // Create creates a Nokia SR Linux node by interfacing with srl-labs/srl-controller
 func (n *Node) Create(ctx context.Context) error {
-	log.Infof("Creating Srlinux node resource %s", n.Name())
+	n.Logger().Infof("Creating Srlinux node resource %s", n.Name())
 
 	if err := n.CreateConfig(ctx); err != nil {
 		return fmt.Errorf("node %s failed to create config-map %w", n.Name(), err)
 	}
-	log.Infof("Created SR Linux node %s configmap", n.Name())
+	n.Logger().Infof("Created SR Linux node %s configmap", n.Name())
 
 	srl := &srltypes.Srlinux{
 		TypeMeta: metav1.TypeMeta{
@@ -188,20 +219,11 @@ func (n *Node) Create(ctx context.Context) error {
 	}
 
 	// wait till srlinux pods are created in the cluster
-	w, err := n.KubeClient.CoreV1().Pods(n.Namespace).Watch(ctx, metav1.ListOptions{
-		FieldSelector: fields.SelectorFromSet(fields.Set{metav1.ObjectNameField: n.Name()}).String(),
-	})
-	if err != nil {
+	if _, err := n.WaitForPod(ctx, func(*corev1.Pod) bool { return true }); err != nil {
 		return err
 	}
-	for e := range w.ResultChan() {
-		p := e.Object.(*corev1.Pod)
-		if p.Status.Phase == corev1.PodPending {
-			break
-		}
-	}
 
-	log.Infof("Created Srlinux resource: %s", n.Name())
+	n.Logger().Infof("Created Srlinux resource: %s", n.Name())
 
 	if err := n.CreateService(ctx); err != nil {
 		return err
@@ -210,6 +232,16 @@ func (n *Node) Create(ctx context.Context) error {
 	return err
 }
 
+// readyCmd reports the state of the management server which serves gNMI and
+// the CLI, it is running once SR Linux is ready to be configured.
+var readyCmd = []string{"sr_cli", "-d", "info from state system app-management application mgmt_server state"}
+
+// Status returns the current node state. The node is only reported running
+// once the SR Linux management server is running.
+func (n *Node) Status(ctx context.Context) (node.Status, error) {
+	return n.ReadyStatus(ctx, readyCmd, "running")
+}
+
 func (n *Node) Delete(ctx context.Context) error {
 	c, err := srlclient.NewForConfig(n.RestConfig)
 	if err != nil {
@@ -219,14 +251,14 @@ func (n *Node) Delete(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	log.Infof("Deleted custom resource: %s", n.Name())
+	n.Logger().Infof("Deleted custom resource: %s", n.Name())
 	if err := n.DeleteService(ctx); err != nil {
 		return err
 	}
 	if err := n.DeleteConfig(ctx); err != nil {
 		return err
 	}
-	log.Infof("Deleted Srlinux node resource %s", n.Name())
+	n.Logger().Infof("Deleted Srlinux node resource %s", n.Name())
 	return nil
 }
 
@@ -271,7 +303,7 @@ func defaults(pb *topopb.Node) *topopb.Node {
 // Implement the resetter for SRL
 // Using load factory auto-commit to reset default configs
 func (n *Node) ResetCfg(ctx context.Context) error {
-	log.Infof("%s resetting config", n.Name())
+	n.Logger().Infof("%s resetting config", n.Name())
 
 	err := n.SpawnCLIConn()
 	if err != nil {
@@ -288,22 +320,41 @@ func (n *Node) ResetCfg(ctx context.Context) error {
 	if resp.Failed != nil {
 		return resp.Failed
 	}
-	log.Infof("%s - finished resetting config", n.Name())
+	n.Logger().Infof("%s - finished resetting config", n.Name())
 
 	return n.cliConn.Close()
 }
 
+// diagnosticCmds are the commands run to collect diagnostics from the node.
+var diagnosticCmds = []string{
+	"show version",
+	"show system application",
+	"info from state /system",
+}
+
+// Diagnostics writes the output of the vendor diagnostic commands to w.
+func (n *Node) Diagnostics(ctx context.Context, w io.Writer) error {
+	n.Logger().Infof("%s - collecting diagnostics", n.Name())
+
+	if err := n.SpawnCLIConn(); err != nil {
+		return err
+	}
+
+	return n.WriteDiagnostics(ctx, n.cliConn, diagnosticCmds, w)
+}
+
 // SpawnCLIConn spawns a CLI connection towards a Network OS using `kubectl exec` terminal and ensures CLI is ready
 // to accept inputs.
 // scrapligo options can be provided to this function for a caller to modify scrapligo platform.
 // For example, mock transport can be set via options
-func (n *Node) SpawnCLIConn() error {
+func (n *Node) SpawnCLIConn(extraOpts ...scrapliutil.Option) error {
 	opts := []scrapliutil.Option{
 		scrapliopts.WithAuthBypass(),
 		// jacked up terminal width to allow for long strings
 		// such as cert and key to not break the terminal
 		scrapliopts.WithTermWidth(5000),
 	}
+	opts = append(opts, extraOpts...)
 
 	// add options defined in test package
 	opts = append(opts, n.testOpts...)
@@ -14,8 +14,11 @@
 package srl
 
 import (
+	"bytes"
 	"context"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -47,6 +50,25 @@ func scrapliDebug() bool {
 	return set
 }
 
+// syncBuffer is a buffer safe for concurrent use, as the device output is
+// written by the read loop of the CLI connection.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 func (f *fakeWatch) Stop() {}
 
 func (f *fakeWatch) ResultChan() <-chan watch.Event {
@@ -226,6 +248,80 @@ func TestGenerateSelfSigned(t *testing.T) {
 	}
 }
 
+func TestRotateCert(t *testing.T) {
+	cert := &topopb.CertificateCfg{
+		Config: &topopb.CertificateCfg_SelfSigned{
+			SelfSigned: &topopb.SelfSignedCertCfg{
+				CertName: "test",
+				KeyName:  "my_key",
+				KeySize:  2048,
+			},
+		},
+	}
+
+	tests := []struct {
+		desc     string
+		wantErr  bool
+		cert     *topopb.CertificateCfg
+		testFile string
+	}{
+		{
+			// successfully replace certificate
+			desc:     "success",
+			cert:     cert,
+			testFile: "generate_certificate_success",
+		},
+		{
+			// device returns "Error: something bad happened" -- we expect to fail
+			desc:     "failure",
+			wantErr:  true,
+			cert:     cert,
+			testFile: "generate_certificate_failure",
+		},
+		{
+			// no cert config -- nothing to rotate
+			desc: "no cert",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			nImpl, err := New(&node.Impl{
+				KubeClient: fake.NewSimpleClientset(),
+				Namespace:  "test",
+				Proto: &topopb.Node{
+					Name:   "pod1",
+					Vendor: topopb.Vendor_NOKIA,
+					Config: &topopb.Config{Cert: tt.cert},
+				},
+			})
+
+			if err != nil {
+				t.Fatalf("failed creating kne srlinux node")
+			}
+
+			n, _ := nImpl.(*Node)
+
+			n.testOpts = []scrapliutil.Option{
+				scrapliopts.WithTransportType(scraplitransport.FileTransport),
+				scrapliopts.WithFileTransportFile(tt.testFile),
+				scrapliopts.WithTimeoutOps(2 * time.Second),
+				scrapliopts.WithTransportReadSize(1),
+				scrapliopts.WithReadDelay(0),
+				scrapliopts.WithDefaultLogger(),
+			}
+
+			// Rotating again replaces the cert of the profile again.
+			for i := 0; i < 2; i++ {
+				err = n.RotateCert(context.Background())
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("RotateCert() #%d got error %v, want error %v", i, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
 func TestResetCfg(t *testing.T) {
 	ki := fake.NewSimpleClientset(&corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -328,6 +424,8 @@ func TestConfigPush(t *testing.T) {
 		ni       *node.Impl
 		testFile string
 		cmdFile  string
+		// wantOutput is streamed device output, not streamed if empty.
+		wantOutput string
 	}{
 		{
 			// successfully configure certificate
@@ -337,6 +435,15 @@ func TestConfigPush(t *testing.T) {
 			testFile: "configpush_success",
 			cmdFile:  "configpush_success_cli.cfg",
 		},
+		{
+			// device output is streamed during the config push
+			desc:       "success streaming",
+			wantErr:    false,
+			ni:         ni,
+			testFile:   "configpush_success",
+			cmdFile:    "configpush_success_cli.cfg",
+			wantOutput: "Welcome to the srlinux CLI.",
+		},
 		{
 			// device returns an error during config push -- we expect to fail
 			desc:     "failure",
@@ -384,10 +491,21 @@ func TestConfigPush(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			err = n.ConfigPush(ctx, r)
+			if tt.wantOutput == "" {
+				err = n.ConfigPush(ctx, r)
+				if err != nil && !tt.wantErr {
+					t.Fatalf("config push failed, error: %+v\n", err)
+				}
+				return
+			}
+			w := &syncBuffer{}
+			err = n.ConfigPushStream(ctx, r, w)
 			if err != nil && !tt.wantErr {
 				t.Fatalf("config push failed, error: %+v\n", err)
 			}
+			if got := w.String(); !strings.Contains(got, tt.wantOutput) {
+				t.Errorf("config push streamed output %q, want containing %q", got, tt.wantOutput)
+			}
 		})
 	}
 }
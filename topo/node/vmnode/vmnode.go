@@ -0,0 +1,233 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vmnode implements nodes running a network OS VM with QEMU in a
+// vrnetlab style container, for vendors without a native container image.
+// The serial console of the VM is served by telnet on port 5000 of the pod,
+// the management NIC of the VM uses QEMU user networking with the services of
+// the node forwarded to it, and each interface eth<n> of the pod is connected
+// to the n-th data NIC of the VM.
+package vmnode
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+)
+
+const (
+	// ConsolePort is the port of the telnet server of the serial console.
+	ConsolePort = 5000
+	// guestIP is the address of the management NIC of the VM, the first
+	// address handed out by the DHCP server of QEMU user networking.
+	guestIP = "10.0.0.15"
+
+	defaultCPUs     = 2
+	defaultMemory   = 4096
+	defaultNICModel = "virtio-net-pci"
+)
+
+var (
+	// intfRe matches the interfaces of the pod connected to the VM.
+	intfRe = regexp.MustCompile(`^eth([1-9][0-9]*)$`)
+	// nicModelRe matches the QEMU device models which can be configured.
+	nicModelRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+func New(nodeImpl *node.Impl) (node.Node, error) {
+	if nodeImpl == nil {
+		return nil, fmt.Errorf("nodeImpl cannot be nil")
+	}
+	if nodeImpl.Proto == nil {
+		return nil, fmt.Errorf("nodeImpl.Proto cannot be nil")
+	}
+	cfg := defaults(nodeImpl.Proto)
+	if cfg.Config.Image == "" {
+		return nil, fmt.Errorf("node %q: the image of the VM must be set", cfg.GetName())
+	}
+	if len(cfg.Config.Command) == 0 {
+		script, err := launchScript(cfg)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Config.Command = []string{"/bin/sh", "-c", script}
+	}
+	nodeImpl.Proto = cfg
+	n := &Node{
+		Impl: nodeImpl,
+	}
+	return n, nil
+}
+
+type Node struct {
+	*node.Impl
+}
+
+func defaults(pb *tpb.Node) *tpb.Node {
+	if pb.Config == nil {
+		pb.Config = &tpb.Config{}
+	}
+	if pb.Config.Vm == nil {
+		pb.Config.Vm = &tpb.VirtualMachine{}
+	}
+	vm := pb.Config.Vm
+	if vm.Cpus == 0 {
+		vm.Cpus = defaultCPUs
+	}
+	if vm.Memory == 0 {
+		vm.Memory = defaultMemory
+	}
+	if vm.NicModel == "" {
+		vm.NicModel = defaultNICModel
+	}
+	if r := vm.GetKvmResource(); r != "" {
+		if pb.Constraints == nil {
+			pb.Constraints = map[string]string{}
+		}
+		if pb.Constraints[r] == "" {
+			pb.Constraints[r] = "1"
+		}
+	}
+	if pb.Services == nil {
+		pb.Services = map[uint32]*tpb.Service{
+			22: {
+				Name:   "ssh",
+				Inside: 22,
+			},
+		}
+	}
+	if pb.Config.EntryCommand == "" {
+		pb.Config.EntryCommand = fmt.Sprintf("kubectl exec -it %s -- telnet localhost %d", pb.Name, ConsolePort)
+	}
+	return pb
+}
+
+// launchScript returns the script of the node container starting the VM. The
+// data NICs of the VM are tap devices, the traffic of each interface of the
+// pod is redirected to its tap device and back. NICs are also added for the
+// interfaces missing before the last one so the numbering of the NICs matches
+// the interfaces. The image of the node must provide the qemu-system-x86_64,
+// ip and tc commands.
+func launchScript(pb *tpb.Node) (string, error) {
+	vm := pb.GetConfig().GetVm()
+	if !nicModelRe.MatchString(vm.GetNicModel()) {
+		return "", fmt.Errorf("node %q: invalid NIC model %q", pb.GetName(), vm.GetNicModel())
+	}
+	nics := 0
+	used := map[int]bool{}
+	for k := range pb.GetInterfaces() {
+		if !node.ValidInterfaceName(k) {
+			return "", fmt.Errorf("node %q: invalid interface name %q", pb.GetName(), k)
+		}
+		m := intfRe.FindStringSubmatch(k)
+		if m == nil {
+			return "", fmt.Errorf("node %q: interface %q is not named eth<n>", pb.GetName(), k)
+		}
+		i, err := strconv.Atoi(m[1])
+		if err != nil || i > 255 {
+			return "", fmt.Errorf("node %q: interface %q exceeds the 255 NICs of the VM", pb.GetName(), k)
+		}
+		used[i] = true
+		if i > nics {
+			nics = i
+		}
+	}
+	var ports []int
+	for _, s := range pb.GetServices() {
+		if s.GetInside() == ConsolePort {
+			return "", fmt.Errorf("node %q: service %q uses port %d of the serial console", pb.GetName(), s.GetName(), ConsolePort)
+		}
+		if s.GetInside() != 0 {
+			ports = append(ports, int(s.GetInside()))
+		}
+	}
+	sort.Ints(ports)
+
+	cmds := []string{"set -e"}
+	if d := vm.GetDisk(); d != "" {
+		cmds = append(cmds, "disk="+quote(d))
+	} else {
+		cmds = append(cmds,
+			"set -- /*.qcow2",
+			`if [ $# -ne 1 ] || [ ! -f "$1" ]; then echo "want one qcow2 disk in /, found: $*" >&2; exit 1; fi`,
+			`disk=$1`,
+		)
+	}
+	mgmt := "user,id=mgmt,net=10.0.0.0/24"
+	for _, p := range ports {
+		mgmt += fmt.Sprintf(",hostfwd=tcp::%d-%s:%d", p, guestIP, p)
+	}
+	qemu := []string{
+		"exec qemu-system-x86_64",
+		"-name", quote(pb.GetName()),
+		"-machine", "pc,accel=kvm:tcg",
+		"-cpu", "max",
+		"-smp", strconv.Itoa(int(vm.GetCpus())),
+		"-m", strconv.Itoa(int(vm.GetMemory())),
+		"-display", "none",
+		"-serial", fmt.Sprintf("telnet:0.0.0.0:%d,server,nowait", ConsolePort),
+		"-drive", `if=virtio,format=qcow2,file="$disk"`,
+		"-snapshot",
+		"-netdev", mgmt,
+		"-device", fmt.Sprintf("%s,netdev=mgmt,mac=%s", vm.GetNicModel(), macAddress(pb.GetName(), 0)),
+	}
+	for i := 1; i <= nics; i++ {
+		tap := fmt.Sprintf("tap%d", i)
+		// The tap device is persistent so it is already there if the container
+		// is restarted.
+		cmds = append(cmds,
+			fmt.Sprintf("ip link show dev %s >/dev/null 2>&1 || ip tuntap add dev %s mode tap", tap, tap),
+			fmt.Sprintf("ip link set dev %s up", tap),
+		)
+		if used[i] {
+			intf := fmt.Sprintf("eth%d", i)
+			cmds = append(cmds, node.RedirectCommands(intf, tap)...)
+			cmds = append(cmds, node.RedirectCommands(tap, intf)...)
+		}
+		qemu = append(qemu,
+			"-netdev", fmt.Sprintf("tap,id=net%d,ifname=%s,script=no,downscript=no", i, tap),
+			"-device", fmt.Sprintf("%s,netdev=net%d,mac=%s", vm.GetNicModel(), i, macAddress(pb.GetName(), i)),
+		)
+	}
+	for _, a := range vm.GetQemuArgs() {
+		qemu = append(qemu, quote(a))
+	}
+	cmds = append(cmds, strings.Join(qemu, " "))
+	return strings.Join(cmds, "\n"), nil
+}
+
+// macAddress returns the MAC address of the i-th NIC of the VM of the node.
+// The addresses differ between nodes, unlike the QEMU defaults, so directly
+// linked VMs do not use the same address.
+func macAddress(name string, i int) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	s := h.Sum32()
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", byte(s>>8), byte(s), i)
+}
+
+// quote returns s quoted for the shell.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	node.Vendor(tpb.Vendor_VM, New)
+}
@@ -0,0 +1,247 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vmnode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestNew(t *testing.T) {
+	script := strings.Join([]string{
+		"set -e",
+		"set -- /*.qcow2",
+		`if [ $# -ne 1 ] || [ ! -f "$1" ]; then echo "want one qcow2 disk in /, found: $*" >&2; exit 1; fi`,
+		`disk=$1`,
+		"ip link show dev tap1 >/dev/null 2>&1 || ip tuntap add dev tap1 mode tap",
+		"ip link set dev tap1 up",
+		"ip link set dev eth1 up",
+		"tc qdisc del dev eth1 ingress 2>/dev/null || true",
+		"tc qdisc add dev eth1 ingress",
+		"tc filter add dev eth1 parent ffff: matchall action mirred egress redirect dev tap1",
+		"ip link set dev tap1 up",
+		"tc qdisc del dev tap1 ingress 2>/dev/null || true",
+		"tc qdisc add dev tap1 ingress",
+		"tc filter add dev tap1 parent ffff: matchall action mirred egress redirect dev eth1",
+		"ip link show dev tap2 >/dev/null 2>&1 || ip tuntap add dev tap2 mode tap",
+		"ip link set dev tap2 up",
+		"ip link show dev tap3 >/dev/null 2>&1 || ip tuntap add dev tap3 mode tap",
+		"ip link set dev tap3 up",
+		"ip link set dev eth3 up",
+		"tc qdisc del dev eth3 ingress 2>/dev/null || true",
+		"tc qdisc add dev eth3 ingress",
+		"tc filter add dev eth3 parent ffff: matchall action mirred egress redirect dev tap3",
+		"ip link set dev tap3 up",
+		"tc qdisc del dev tap3 ingress 2>/dev/null || true",
+		"tc qdisc add dev tap3 ingress",
+		"tc filter add dev tap3 parent ffff: matchall action mirred egress redirect dev eth3",
+		"exec qemu-system-x86_64 -name 'vm1' -machine pc,accel=kvm:tcg -cpu max -smp 2 -m 4096 -display none" +
+			" -serial telnet:0.0.0.0:5000,server,nowait -drive if=virtio,format=qcow2,file=\"$disk\" -snapshot" +
+			" -netdev user,id=mgmt,net=10.0.0.0/24,hostfwd=tcp::22-10.0.0.15:22 -device virtio-net-pci,netdev=mgmt,mac=52:54:00:93:a7:00" +
+			" -netdev tap,id=net1,ifname=tap1,script=no,downscript=no -device virtio-net-pci,netdev=net1,mac=52:54:00:93:a7:01" +
+			" -netdev tap,id=net2,ifname=tap2,script=no,downscript=no -device virtio-net-pci,netdev=net2,mac=52:54:00:93:a7:02" +
+			" -netdev tap,id=net3,ifname=tap3,script=no,downscript=no -device virtio-net-pci,netdev=net3,mac=52:54:00:93:a7:03",
+	}, "\n")
+	tests := []struct {
+		desc    string
+		nImpl   *node.Impl
+		want    *tpb.Node
+		wantErr string
+	}{{
+		desc:    "nil impl",
+		wantErr: "nodeImpl cannot be nil",
+	}, {
+		desc:    "nil pb",
+		wantErr: "nodeImpl.Proto cannot be nil",
+		nImpl:   &node.Impl{},
+	}, {
+		desc: "no image",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{Name: "vm1"},
+		},
+		wantErr: "image of the VM must be set",
+	}, {
+		desc: "defaults",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{
+				Name: "vm1",
+				Interfaces: map[string]*tpb.Interface{
+					"eth1": {},
+					"eth3": {},
+				},
+				Config: &tpb.Config{Image: "vrnetlab/vr-veos:4.28"},
+			},
+		},
+		want: &tpb.Node{
+			Name: "vm1",
+			Interfaces: map[string]*tpb.Interface{
+				"eth1": {},
+				"eth3": {},
+			},
+			Services: map[uint32]*tpb.Service{
+				22: {Name: "ssh", Inside: 22},
+			},
+			Config: &tpb.Config{
+				Image:        "vrnetlab/vr-veos:4.28",
+				Command:      []string{"/bin/sh", "-c", script},
+				EntryCommand: "kubectl exec -it vm1 -- telnet localhost 5000",
+				Vm: &tpb.VirtualMachine{
+					Cpus:     2,
+					Memory:   4096,
+					NicModel: "virtio-net-pci",
+				},
+			},
+		},
+	}, {
+		desc: "provided command",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{
+				Name: "vm1",
+				Config: &tpb.Config{
+					Image:   "vrnetlab/vr-xrv:6.1",
+					Command: []string{"/launch.py", "--connection-mode", "tc"},
+					Vm:      &tpb.VirtualMachine{KvmResource: "devices.kubevirt.io/kvm"},
+				},
+				Services: map[uint32]*tpb.Service{
+					830: {Name: "netconf", Inside: 830},
+				},
+			},
+		},
+		want: &tpb.Node{
+			Name:        "vm1",
+			Constraints: map[string]string{"devices.kubevirt.io/kvm": "1"},
+			Services: map[uint32]*tpb.Service{
+				830: {Name: "netconf", Inside: 830},
+			},
+			Config: &tpb.Config{
+				Image:        "vrnetlab/vr-xrv:6.1",
+				Command:      []string{"/launch.py", "--connection-mode", "tc"},
+				EntryCommand: "kubectl exec -it vm1 -- telnet localhost 5000",
+				Vm: &tpb.VirtualMachine{
+					Cpus:        2,
+					Memory:      4096,
+					NicModel:    "virtio-net-pci",
+					KvmResource: "devices.kubevirt.io/kvm",
+				},
+			},
+		},
+	}, {
+		desc: "invalid interface",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{
+				Name:       "vm1",
+				Interfaces: map[string]*tpb.Interface{"Ethernet1": {}},
+				Config:     &tpb.Config{Image: "vm:latest"},
+			},
+		},
+		wantErr: `interface "Ethernet1" is not named eth<n>`,
+	}, {
+		desc: "invalid interface name",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{
+				Name:       "vm1",
+				Interfaces: map[string]*tpb.Interface{"eth1; reboot": {}},
+				Config:     &tpb.Config{Image: "vm:latest"},
+			},
+		},
+		wantErr: `invalid interface name "eth1; reboot"`,
+	}, {
+		desc: "too many interfaces",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{
+				Name:       "vm1",
+				Interfaces: map[string]*tpb.Interface{"eth256": {}},
+				Config:     &tpb.Config{Image: "vm:latest"},
+			},
+		},
+		wantErr: "exceeds the 255 NICs",
+	}, {
+		desc: "service on console port",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{
+				Name:     "vm1",
+				Services: map[uint32]*tpb.Service{5000: {Name: "console", Inside: 5000}},
+				Config:   &tpb.Config{Image: "vm:latest"},
+			},
+		},
+		wantErr: "port 5000 of the serial console",
+	}, {
+		desc: "invalid nic model",
+		nImpl: &node.Impl{
+			Proto: &tpb.Node{
+				Name:   "vm1",
+				Config: &tpb.Config{Image: "vm:latest", Vm: &tpb.VirtualMachine{NicModel: "e1000,romfile="}},
+			},
+		},
+		wantErr: "invalid NIC model",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			n, err := New(tt.nImpl)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("New() unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if s := cmp.Diff(tt.want, n.GetProto(), protocmp.Transform()); s != "" {
+				t.Fatalf("New() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestLaunchScript(t *testing.T) {
+	pb := defaults(&tpb.Node{
+		Name: "vm1",
+		Services: map[uint32]*tpb.Service{
+			830: {Name: "netconf", Inside: 830},
+			22:  {Name: "ssh", Inside: 22},
+		},
+		Config: &tpb.Config{
+			Image: "vm:latest",
+			Vm: &tpb.VirtualMachine{
+				Disk:     "/images/it's.qcow2",
+				Cpus:     4,
+				Memory:   8192,
+				NicModel: "e1000",
+				QemuArgs: []string{"-boot", "order=c"},
+			},
+		},
+	})
+	got, err := launchScript(pb)
+	if err != nil {
+		t.Fatalf("launchScript() unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`disk='/images/it'\''s.qcow2'`,
+		"-smp 4 -m 8192 ",
+		"-netdev user,id=mgmt,net=10.0.0.0/24,hostfwd=tcp::22-10.0.0.15:22,hostfwd=tcp::830-10.0.0.15:830 -device e1000,netdev=mgmt,",
+		" '-boot' 'order=c'",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("launchScript() got:\n%s\nmissing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "tap") {
+		t.Errorf("launchScript() got:\n%s\nwant no data NICs", got)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// podWatch is a shared informer caching the pods of a namespace.
+type podWatch struct {
+	refs   int
+	lister corelisters.PodLister
+	stop   chan struct{}
+
+	mu   sync.Mutex
+	subs map[chan struct{}]bool
+}
+
+type watchKey struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+var (
+	watchMu    sync.Mutex
+	podWatches = map[watchKey]*podWatch{}
+)
+
+// WatchPods starts a shared watch of the pods in namespace. Until the returned
+// stop function is called the nodes in namespace using kClient get their pods
+// from the cache of the watch and wait for pod changes using its events,
+// instead of querying the API server. Watches are reference counted, so
+// concurrent callers for the same namespace share a single watch.
+func WatchPods(ctx context.Context, kClient kubernetes.Interface, namespace string) (func(), error) {
+	watchMu.Lock()
+	defer watchMu.Unlock()
+	key := watchKey{client: kClient, namespace: namespace}
+	w, ok := podWatches[key]
+	if !ok {
+		f := informers.NewSharedInformerFactoryWithOptions(kClient, 0, informers.WithNamespace(namespace))
+		pods := f.Core().V1().Pods()
+		w = &podWatch{
+			lister: pods.Lister(),
+			stop:   make(chan struct{}),
+			subs:   map[chan struct{}]bool{},
+		}
+		informer := pods.Informer()
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { w.notify() },
+			UpdateFunc: func(interface{}, interface{}) { w.notify() },
+			DeleteFunc: func(interface{}) { w.notify() },
+		})
+		go informer.Run(w.stop)
+		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			close(w.stop)
+			return nil, fmt.Errorf("failed to sync pods of namespace %q: %w", namespace, ctx.Err())
+		}
+		podWatches[key] = w
+	}
+	w.refs++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			watchMu.Lock()
+			defer watchMu.Unlock()
+			if w.refs--; w.refs == 0 {
+				close(w.stop)
+				delete(podWatches, key)
+			}
+		})
+	}, nil
+}
+
+// notify wakes up all waiters for pod changes.
+func (w *podWatch) notify() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for c := range w.subs {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// subscribe returns a channel receiving a value after pod changes and a
+// function to unsubscribe.
+func (w *podWatch) subscribe() (<-chan struct{}, func()) {
+	c := make(chan struct{}, 1)
+	w.mu.Lock()
+	w.subs[c] = true
+	w.mu.Unlock()
+	return c, func() {
+		w.mu.Lock()
+		delete(w.subs, c)
+		w.mu.Unlock()
+	}
+}
+
+// sharedWatch returns the shared watch of the pods of the node, nil if there
+// is none.
+func (n *Impl) sharedWatch() *podWatch {
+	watchMu.Lock()
+	defer watchMu.Unlock()
+	return podWatches[watchKey{client: n.KubeClient, namespace: n.Namespace}]
+}
+
+// pod returns the pod of the node from the shared watch of its namespace if
+// there is one, otherwise from the API server. The API server is also queried
+// if the pod is not cached yet, as the event of a pod just created may not
+// have been received.
+func (n *Impl) pod(ctx context.Context) (*corev1.Pod, error) {
+	if w := n.sharedWatch(); w != nil {
+		p, err := w.lister.Pods(n.Namespace).Get(n.Name())
+		if err == nil {
+			return p.DeepCopy(), nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return n.KubeClient.CoreV1().Pods(n.Namespace).Get(ctx, n.Name(), metav1.GetOptions{})
+}
+
+// WaitForPod waits until cond is true for the pod of the node and returns the
+// pod. The shared watch of the namespace is used if there is one, otherwise a
+// watch of the pod is started.
+func (n *Impl) WaitForPod(ctx context.Context, cond func(*corev1.Pod) bool) (*corev1.Pod, error) {
+	if w := n.sharedWatch(); w != nil {
+		c, unsubscribe := w.subscribe()
+		defer unsubscribe()
+		for {
+			if p, err := w.lister.Pods(n.Namespace).Get(n.Name()); err == nil && cond(p) {
+				return p.DeepCopy(), nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("failed waiting for pod %s: %w", n.Name(), ctx.Err())
+			case <-c:
+			}
+		}
+	}
+	pw, err := n.KubeClient.CoreV1().Pods(n.Namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{metav1.ObjectNameField: n.Name()}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer pw.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed waiting for pod %s: %w", n.Name(), ctx.Err())
+		case e, ok := <-pw.ResultChan():
+			if !ok {
+				return nil, fmt.Errorf("watch of pod %s closed", n.Name())
+			}
+			if p, ok := e.Object.(*corev1.Pod); ok && cond(p) {
+				return p, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/h-fam/errdiff"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	ktest "k8s.io/client-go/testing"
+
+	topopb "github.com/openconfig/kne/proto/topo"
+)
+
+func TestWatchPods(t *testing.T) {
+	ctx := context.Background()
+	kClient := kfake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	n := &Impl{Namespace: "test", KubeClient: kClient, Proto: &topopb.Node{Name: "r1"}}
+	stop1, err := WatchPods(ctx, kClient, "test")
+	if err != nil {
+		t.Fatalf("WatchPods() unexpected error: %v", err)
+	}
+	stop2, err := WatchPods(ctx, kClient, "test")
+	if err != nil {
+		t.Fatalf("WatchPods() unexpected error: %v", err)
+	}
+	if n.sharedWatch() == nil {
+		t.Fatalf("WatchPods() did not register a shared watch")
+	}
+	// Pods are served from the cache while watched.
+	var gets int
+	kClient.PrependReactor("get", "pods", func(ktest.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	pods, err := n.Pods(ctx)
+	if err != nil {
+		t.Fatalf("Pods() unexpected error: %v", err)
+	}
+	if got := pods[0].Status.Phase; got != corev1.PodRunning {
+		t.Errorf("Pods() got phase %s, want %s", got, corev1.PodRunning)
+	}
+	if gets != 0 {
+		t.Errorf("Pods() queried the API server %d times while watched, want 0", gets)
+	}
+	// The watch is shared, it is only stopped once all callers stopped it.
+	stop1()
+	stop1()
+	if n.sharedWatch() == nil {
+		t.Fatalf("stop() stopped the shared watch while still in use")
+	}
+	stop2()
+	if n.sharedWatch() != nil {
+		t.Fatalf("stop() did not stop the shared watch")
+	}
+	if _, err := n.Pods(ctx); err != nil {
+		t.Fatalf("Pods() unexpected error: %v", err)
+	}
+	if gets != 1 {
+		t.Errorf("Pods() queried the API server %d times once unwatched, want 1", gets)
+	}
+}
+
+func TestWaitForPod(t *testing.T) {
+	running := func(p *corev1.Pod) bool { return p.Status.Phase == corev1.PodRunning }
+	tests := []struct {
+		desc    string
+		watched bool
+		timeout time.Duration
+		update  bool
+		wantErr string
+	}{{
+		desc:    "shared watch",
+		watched: true,
+		update:  true,
+	}, {
+		desc:   "pod watch",
+		update: true,
+	}, {
+		desc:    "shared watch timeout",
+		watched: true,
+		timeout: 100 * time.Millisecond,
+		wantErr: "failed waiting for pod r1",
+	}, {
+		desc:    "pod watch timeout",
+		timeout: 100 * time.Millisecond,
+		wantErr: "failed waiting for pod r1",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ctx := context.Background()
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+				Status:     corev1.PodStatus{Phase: corev1.PodPending},
+			}
+			kClient := kfake.NewSimpleClientset(pod)
+			n := &Impl{Namespace: "test", KubeClient: kClient, Proto: &topopb.Node{Name: "r1"}}
+			if tt.watched {
+				stop, err := WatchPods(ctx, kClient, "test")
+				if err != nil {
+					t.Fatalf("WatchPods() unexpected error: %v", err)
+				}
+				defer stop()
+			}
+			if tt.timeout != 0 {
+				var cancel func()
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if tt.update {
+				go func() {
+					time.Sleep(50 * time.Millisecond)
+					p := pod.DeepCopy()
+					p.Status.Phase = corev1.PodRunning
+					if _, err := kClient.CoreV1().Pods("test").UpdateStatus(context.Background(), p, metav1.UpdateOptions{}); err != nil {
+						t.Errorf("failed to update pod: %v", err)
+					}
+				}()
+			}
+			p, err := n.WaitForPod(ctx, running)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("WaitForPod() unexpected error: %s", s)
+			}
+			if err == nil && !running(p) {
+				t.Errorf("WaitForPod() got pod in phase %s, want %s", p.Status.Phase, corev1.PodRunning)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmi/errlist"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/pointer"
+)
+
+// DefaultPrepullConcurrency is the default maximum number of images pulled
+// concurrently by Prepull.
+const DefaultPrepullConcurrency = 4
+
+// prepullApp is the app label of the jobs and pods pulling images.
+const prepullApp = "kne-prepull"
+
+// ImagePull is the pull of an image onto a cluster node by Prepull.
+type ImagePull struct {
+	Image       string
+	ClusterNode string
+	// Present is true if the image was already on the cluster node, it is
+	// not pulled then.
+	Present  bool
+	Duration time.Duration
+	Err      error
+}
+
+// imageTarget is an image of the nodes of the topology, with the scheduling
+// and the image pull secrets of the nodes using it. Each node using the image
+// has a spec.
+type imageTarget struct {
+	image   string
+	specs   []*corev1.PodSpec
+	secrets map[string]bool
+}
+
+// WithPrepull pulls the images of the nodes onto the cluster nodes with
+// Prepull before the topology is created, at most concurrency pulls at a
+// time. Images are not pulled if concurrency is not positive. Failed pulls
+// are logged, the kubelet pulls the images again for the pods of the nodes.
+func WithPrepull(concurrency int) Option {
+	return func(m *Manager) {
+		m.prepull = concurrency
+	}
+}
+
+// Prepull pulls the images of the nodes of the topology onto the cluster
+// nodes their pods can be scheduled on, so the pods do not all pull the same
+// large images at once when the topology is created. At most concurrency
+// images are pulled at a time, DefaultPrepullConcurrency if not positive.
+// Each image is pulled by a job of the namespace of the topology with a pod
+// bound to the cluster node, images already on a cluster node are skipped. The pulls are
+// returned sorted by image and cluster node, the errors of failed pulls are
+// returned together.
+func (m *Manager) Prepull(ctx context.Context, concurrency int) ([]*ImagePull, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultPrepullConcurrency
+	}
+	nodes, err := m.kClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster nodes: %w", err)
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+	if err := m.createNamespace(ctx); err != nil {
+		return nil, err
+	}
+	// The targets are sorted by image, so the pulls are sorted by image and
+	// cluster node.
+	var pulls []*ImagePull
+	var secrets [][]corev1.LocalObjectReference
+	for _, t := range m.imageTargets() {
+		var refs []corev1.LocalObjectReference
+		for s := range t.secrets {
+			refs = append(refs, corev1.LocalObjectReference{Name: s})
+		}
+		sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+		for i := range nodes.Items {
+			n := &nodes.Items[i]
+			if !schedulable(n) || !canScheduleAny(t.specs, n) {
+				continue
+			}
+			p := &ImagePull{Image: t.image, ClusterNode: n.Name, Present: hasImage(n, t.image)}
+			pulls = append(pulls, p)
+			secrets = append(secrets, refs)
+		}
+	}
+	parallel(concurrency, len(pulls), func(i int) error {
+		p := pulls[i]
+		if p.Present {
+			m.logger().Infof("Image %q already on cluster node %q", p.Image, p.ClusterNode)
+			return nil
+		}
+		m.logger().Infof("Pulling image %q onto cluster node %q", p.Image, p.ClusterNode)
+		start := time.Now()
+		p.Err = m.pullImage(ctx, p.Image, p.ClusterNode, secrets[i])
+		p.Duration = time.Since(start)
+		// The error is returned with the errors of the other pulls in their
+		// order below, not as they fail.
+		if p.Err != nil {
+			return nil
+		}
+		m.logger().Infof("Pulled image %q onto cluster node %q in %s", p.Image, p.ClusterNode, p.Duration.Truncate(time.Second))
+		return nil
+	})
+	var errs errlist.List
+	for _, p := range pulls {
+		if p.Err != nil {
+			errs.Add(fmt.Errorf("failed to pull image %q onto cluster node %q: %w", p.Image, p.ClusterNode, p.Err))
+		}
+	}
+	return pulls, errs.Err()
+}
+
+// imageTargets returns the images of the containers of the pods of the nodes,
+// sorted by image.
+func (m *Manager) imageTargets() []*imageTarget {
+	targets := map[string]*imageTarget{}
+	for _, name := range m.nodeNames() {
+		pb := m.nodes[name].GetProto()
+		spec := &corev1.PodSpec{}
+		node.ApplyScheduling(spec, pb)
+		images := []string{pb.GetConfig().GetImage()}
+		if !pb.GetConfig().GetInitWait().GetDisabled() {
+			image := pb.GetConfig().GetInitImage()
+			if image == "" {
+				image = node.DefaultInitContainerImage
+			}
+			images = append(images, image)
+		}
+		for _, sc := range append(append([]*tpb.Sidecar{}, pb.GetConfig().GetSidecars()...), pb.GetConfig().GetInitContainers()...) {
+			images = append(images, sc.GetImage())
+		}
+		seen := map[string]bool{}
+		for _, image := range images {
+			if image == "" || seen[image] {
+				continue
+			}
+			seen[image] = true
+			t, ok := targets[image]
+			if !ok {
+				t = &imageTarget{image: image, secrets: map[string]bool{}}
+				targets[image] = t
+			}
+			t.specs = append(t.specs, spec)
+			if s := pb.GetConfig().GetImagePullSecret(); s != "" {
+				t.secrets[s] = true
+			}
+		}
+	}
+	var sorted []*imageTarget
+	for _, t := range targets {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].image < sorted[j].image })
+	return sorted
+}
+
+// canScheduleAny returns true if any of specs can be scheduled on n.
+func canScheduleAny(specs []*corev1.PodSpec, n *corev1.Node) bool {
+	for _, spec := range specs {
+		if canSchedule(spec, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasImage returns true if image is in the images reported by n.
+func hasImage(n *corev1.Node, image string) bool {
+	want := normalizeImage(image)
+	for _, ci := range n.Status.Images {
+		for _, name := range ci.Names {
+			if normalizeImage(name) == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeImage returns the fully qualified name of image as reported by the
+// cluster nodes, e.g. docker.io/library/alpine:latest for alpine.
+func normalizeImage(image string) string {
+	name, digest, hasDigest := strings.Cut(image, "@")
+	i := strings.IndexByte(name, '/')
+	if i < 0 || !strings.ContainsAny(name[:i], ".:") && name[:i] != "localhost" {
+		if i < 0 {
+			name = "library/" + name
+		}
+		name = "docker.io/" + name
+	}
+	if hasDigest {
+		return name + "@" + digest
+	}
+	if !strings.Contains(name[strings.LastIndexByte(name, '/')+1:], ":") {
+		name += ":latest"
+	}
+	return name
+}
+
+// prepullJobName returns the name of the job pulling image onto the cluster
+// node.
+func prepullJobName(image, clusterNode string) string {
+	h := fnv.New32a()
+	h.Write([]byte(image + "/" + clusterNode))
+	return fmt.Sprintf("%s-%08x", prepullApp, h.Sum32())
+}
+
+// Limits of the jobs pulling images. The pulls are bounded by the deadline and
+// the finished jobs deleted by the cluster after the TTL, so jobs left over by
+// an interrupted prepull, e.g. pods backing off the pull of a missing image,
+// are cleaned up without kne.
+const (
+	prepullDeadline = 30 * time.Minute
+	prepullTTL      = time.Minute
+)
+
+var (
+	// prepullBackOffTimeout is how long the pull of an image may fail before
+	// the pull is given up once the kubelet backs off pulling it.
+	prepullBackOffTimeout = 5 * time.Minute
+	// prepullCheckInterval is the interval between checks of the pull of an
+	// image while there are no pod changes, for prepullBackOffTimeout.
+	prepullCheckInterval = 10 * time.Second
+)
+
+// pullImage pulls image onto the cluster node with a job whose pod is bound
+// to it, the job is deleted once the image is pulled. The pod runs /bin/true
+// from the image, the image is pulled even if the image has no such command.
+func (m *Manager) pullImage(ctx context.Context, image, clusterNode string, secrets []corev1.LocalObjectReference) error {
+	ns := m.topo.GetName()
+	jobs := m.kClient.BatchV1().Jobs(ns)
+	name := prepullJobName(image, clusterNode)
+	// The pods of deleted jobs are deleted by the garbage collector.
+	prop := metav1.DeletePropagationBackground
+	deleteOpts := metav1.DeleteOptions{PropagationPolicy: &prop}
+	// A job left over by an interrupted pull may have failed.
+	if err := jobs.Delete(ctx, name, deleteOpts); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	w, err := m.kClient.CoreV1().Pods(ns).Watch(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"job-name": name}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	podLabels := map[string]string{
+		"app":  prepullApp,
+		"topo": ns,
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: podLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            pointer.Int32(0),
+			ActiveDeadlineSeconds:   pointer.Int64(int64(prepullDeadline.Seconds())),
+			TTLSecondsAfterFinished: pointer.Int32(int32(prepullTTL.Seconds())),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					NodeName:      clusterNode,
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:            "pull",
+						Image:           image,
+						Command:         []string{"/bin/true"},
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						SecurityContext: node.ToSecurityContext(&tpb.SecurityContext{Restricted: true}),
+					}},
+					ImagePullSecrets:              secrets,
+					Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					TerminationGracePeriodSeconds: pointer.Int64(0),
+				},
+			},
+		},
+	}
+	job, err = jobs.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The job is deleted even if ctx is canceled.
+		dctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := jobs.Delete(dctx, name, deleteOpts); err != nil && !apierrors.IsNotFound(err) {
+			m.logger().Warnf("Failed to delete job %q: %v", name, err)
+		}
+	}()
+	check := time.NewTicker(prepullCheckInterval)
+	defer check.Stop()
+	s := &pullState{}
+	var last *corev1.Pod
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-check.C:
+			if last == nil {
+				continue
+			}
+			if done, err := s.pulled(last, time.Now()); done {
+				return err
+			}
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch of pods of job %q closed", name)
+			}
+			p, ok := e.Object.(*corev1.Pod)
+			// Pods of jobs of an earlier pull have the same job name.
+			if !ok || p.Labels["controller-uid"] != string(job.UID) {
+				continue
+			}
+			if e.Type == watch.Deleted {
+				return fmt.Errorf("pod %q of job %q deleted", p.Name, name)
+			}
+			last = p
+			if done, err := s.pulled(p, time.Now()); done {
+				return err
+			}
+		}
+	}
+}
+
+// pullState is the state of the pull of an image by a pod.
+type pullState struct {
+	// failing is when the pull was first seen failing, zero if it was not.
+	failing time.Time
+}
+
+// pulled returns true once the pod p pulling an image is done, with an error
+// if the pull failed. The image is pulled when its container is started or
+// fails to start for reasons other than the pull, e.g. a missing command.
+// Failed pulls are retried by the kubelet, e.g. those rate limited by the
+// registry, so only invalid image names fail the pull right away, pulls the
+// kubelet backs off fail once they have been failing for
+// prepullBackOffTimeout.
+func (s *pullState) pulled(p *corev1.Pod, now time.Time) (bool, error) {
+	for _, cs := range p.Status.ContainerStatuses {
+		switch {
+		case cs.State.Running != nil, cs.State.Terminated != nil:
+			return true, nil
+		case cs.State.Waiting != nil:
+			w := cs.State.Waiting
+			switch w.Reason {
+			case "InvalidImageName":
+				return true, fmt.Errorf("%s: %s", w.Reason, w.Message)
+			case "ErrImagePull", "ImagePullBackOff":
+				if s.failing.IsZero() {
+					s.failing = now
+				}
+				if w.Reason == "ImagePullBackOff" && now.Sub(s.failing) >= prepullBackOffTimeout {
+					return true, fmt.Errorf("%s: %s", w.Reason, w.Message)
+				}
+			case "", "ContainerCreating":
+			default:
+				return true, nil
+			}
+		}
+	}
+	if p.Status.Phase == corev1.PodFailed {
+		return true, fmt.Errorf("pod failed: %s %s", p.Status.Reason, p.Status.Message)
+	}
+	return false, nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/h-fam/errdiff"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	ktest "k8s.io/client-go/testing"
+)
+
+func TestPrepull(t *testing.T) {
+	imageNode := func(name, image string, mods ...func(*tpb.Node)) node.Node {
+		pb := &tpb.Node{Name: name, Config: &tpb.Config{Image: image, InitWait: &tpb.InitWait{Disabled: true}}}
+		for _, m := range mods {
+			m(pb)
+		}
+		return &node.Impl{Proto: pb}
+	}
+	labeled := func(n *corev1.Node) { n.Labels = map[string]string{"pool": "big"} }
+	cordoned := func(n *corev1.Node) { n.Spec.Unschedulable = true }
+	alpine := func(n *corev1.Node) {
+		n.Status.Images = []corev1.ContainerImage{{Names: []string{"docker.io/library/alpine@sha256:1234", "docker.io/library/alpine:latest"}}}
+	}
+	tests := []struct {
+		desc    string
+		nodes   map[string]node.Node
+		objs    []runtime.Object
+		want    []*ImagePull
+		wantErr string
+	}{{
+		desc: "pulled",
+		nodes: map[string]node.Node{
+			"r1": imageNode("r1", "xrd:7.8"),
+			"r2": imageNode("r2", "xrd:7.8"),
+			"h1": imageNode("h1", "alpine"),
+		},
+		objs: []runtime.Object{
+			clusterNode("w2", "8", "32Gi"),
+			clusterNode("w1", "8", "32Gi", alpine),
+			clusterNode("w3", "8", "32Gi", cordoned),
+		},
+		want: []*ImagePull{
+			{Image: "alpine", ClusterNode: "w1", Present: true},
+			{Image: "alpine", ClusterNode: "w2"},
+			{Image: "xrd:7.8", ClusterNode: "w1"},
+			{Image: "xrd:7.8", ClusterNode: "w2"},
+		},
+	}, {
+		desc: "init and sidecar images",
+		nodes: map[string]node.Node{
+			"r1": imageNode("r1", "xrd:7.8", func(pb *tpb.Node) {
+				pb.Config.InitWait = nil
+				pb.Config.InitImage = "init:1"
+				pb.Config.Sidecars = []*tpb.Sidecar{{Name: "exporter", Image: "exporter:1"}}
+				pb.Config.InitContainers = []*tpb.Sidecar{{Name: "license", Image: "missing-command"}}
+			}),
+		},
+		objs: []runtime.Object{clusterNode("w1", "8", "32Gi")},
+		want: []*ImagePull{
+			{Image: "exporter:1", ClusterNode: "w1"},
+			{Image: "init:1", ClusterNode: "w1"},
+			{Image: "missing-command", ClusterNode: "w1"},
+			{Image: "xrd:7.8", ClusterNode: "w1"},
+		},
+	}, {
+		desc: "node selector",
+		nodes: map[string]node.Node{
+			"r1": imageNode("r1", "xrd:7.8", func(pb *tpb.Node) {
+				pb.Scheduling = &tpb.Scheduling{NodeSelector: map[string]string{"pool": "big"}}
+			}),
+			"h1": imageNode("h1", "alpine"),
+		},
+		objs: []runtime.Object{clusterNode("w1", "8", "32Gi", labeled), clusterNode("w2", "8", "32Gi")},
+		want: []*ImagePull{
+			{Image: "alpine", ClusterNode: "w1"},
+			{Image: "alpine", ClusterNode: "w2"},
+			{Image: "xrd:7.8", ClusterNode: "w1"},
+		},
+	}, {
+		desc: "failed pull",
+		nodes: map[string]node.Node{
+			"r1": imageNode("r1", "xrd:7.8"),
+			"r2": imageNode("r2", "Invalid:1"),
+		},
+		objs: []runtime.Object{clusterNode("w1", "8", "32Gi")},
+		want: []*ImagePull{
+			{Image: "Invalid:1", ClusterNode: "w1"},
+			{Image: "xrd:7.8", ClusterNode: "w1"},
+		},
+		wantErr: `failed to pull image "Invalid:1" onto cluster node "w1": InvalidImageName: invalid reference format`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kClient := kfake.NewSimpleClientset(tt.objs...)
+			// The pods of the jobs are created with the status of the
+			// pull, as the fake clientset has no job controller.
+			kClient.PrependReactor("create", "jobs", func(action ktest.Action) (bool, runtime.Object, error) {
+				j := action.(ktest.CreateAction).GetObject().(*batchv1.Job)
+				j.UID = types.UID("uid-" + j.Name)
+				cs := corev1.ContainerStatus{Name: "pull"}
+				switch j.Spec.Template.Spec.Containers[0].Image {
+				case "Invalid:1":
+					cs.State.Waiting = &corev1.ContainerStateWaiting{Reason: "InvalidImageName", Message: "invalid reference format"}
+				case "missing-command":
+					cs.State.Waiting = &corev1.ContainerStateWaiting{Reason: "RunContainerError"}
+				default:
+					cs.State.Terminated = &corev1.ContainerStateTerminated{}
+				}
+				p := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      j.Name + "-x",
+						Namespace: j.Namespace,
+						Labels:    map[string]string{"job-name": j.Name, "controller-uid": string(j.UID)},
+					},
+					Spec:   j.Spec.Template.Spec,
+					Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{cs}},
+				}
+				if err := kClient.Tracker().Create(corev1.SchemeGroupVersion.WithResource("pods"), p, action.GetNamespace()); err != nil {
+					return true, nil, err
+				}
+				return false, nil, nil
+			})
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				nodes:   tt.nodes,
+				kClient: kClient,
+			}
+			got, err := m.Prepull(context.Background(), 2)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("Prepull() unexpected error: %s", s)
+			}
+			if s := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(ImagePull{}, "Duration", "Err")); s != "" {
+				t.Errorf("Prepull() unexpected diff (-want +got):\n%s", s)
+			}
+			jobs, err := kClient.BatchV1().Jobs("test").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list jobs: %v", err)
+			}
+			if len(jobs.Items) != 0 {
+				t.Errorf("Prepull() left %d jobs, want 0", len(jobs.Items))
+			}
+		})
+	}
+}
+
+func TestPulled(t *testing.T) {
+	origTimeout := prepullBackOffTimeout
+	defer func() {
+		prepullBackOffTimeout = origTimeout
+	}()
+	prepullBackOffTimeout = time.Minute
+	waiting := func(reason string) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: "too many requests"}},
+		}}}}
+	}
+	running := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}}}}
+	type update struct {
+		pod *corev1.Pod
+		// after is the time since the first update.
+		after    time.Duration
+		wantDone bool
+		wantErr  string
+	}
+	tests := []struct {
+		desc    string
+		updates []update
+	}{{
+		desc: "pulled",
+		updates: []update{
+			{pod: waiting("ContainerCreating")},
+			{pod: running, wantDone: true},
+		},
+	}, {
+		desc: "pulled after retries",
+		updates: []update{
+			{pod: waiting("ErrImagePull")},
+			{pod: waiting("ImagePullBackOff"), after: 10 * time.Second},
+			{pod: waiting("ErrImagePull"), after: 30 * time.Second},
+			{pod: running, after: 40 * time.Second, wantDone: true},
+		},
+	}, {
+		desc: "back off timeout",
+		updates: []update{
+			{pod: waiting("ErrImagePull")},
+			{pod: waiting("ImagePullBackOff"), after: 10 * time.Second},
+			{pod: waiting("ErrImagePull"), after: 2 * time.Minute},
+			{pod: waiting("ImagePullBackOff"), after: 2 * time.Minute, wantDone: true, wantErr: "ImagePullBackOff: too many requests"},
+		},
+	}, {
+		desc: "invalid image name",
+		updates: []update{
+			{pod: waiting("InvalidImageName"), wantDone: true, wantErr: "InvalidImageName"},
+		},
+	}, {
+		desc: "missing command",
+		updates: []update{
+			{pod: waiting("RunContainerError"), wantDone: true},
+		},
+	}, {
+		desc: "pod failed",
+		updates: []update{
+			{pod: &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "DeadlineExceeded"}}, wantDone: true, wantErr: "pod failed: DeadlineExceeded"},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := &pullState{}
+			start := time.Now()
+			for i, u := range tt.updates {
+				done, err := s.pulled(u.pod, start.Add(u.after))
+				if s := errdiff.Substring(err, u.wantErr); s != "" {
+					t.Errorf("pulled() of update %d unexpected error: %s", i, s)
+				}
+				if done != u.wantDone {
+					t.Errorf("pulled() of update %d got done %v, want %v", i, done, u.wantDone)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"alpine", "docker.io/library/alpine:latest"},
+		{"alpine:3.17", "docker.io/library/alpine:3.17"},
+		{"hfam/gobgp", "docker.io/hfam/gobgp:latest"},
+		{"docker.io/library/alpine:latest", "docker.io/library/alpine:latest"},
+		{"localhost/xrd", "localhost/xrd:latest"},
+		{"registry:5000/xrd", "registry:5000/xrd:latest"},
+		{"us-west1-docker.pkg.dev/p/r/ceos:4.28", "us-west1-docker.pkg.dev/p/r/ceos:4.28"},
+		{"alpine@sha256:1234", "docker.io/library/alpine@sha256:1234"},
+	}
+	for _, tt := range tests {
+		if got := normalizeImage(tt.image); got != tt.want {
+			t.Errorf("normalizeImage(%q) got %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
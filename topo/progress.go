@@ -0,0 +1,176 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Image pull states reported in NodeProgress. Failed pulls are reported with
+// the reason of the waiting container, e.g. ErrImagePull.
+const (
+	ImagePullPending = "Pending"
+	ImagePullPulling = "Pulling"
+	ImagePullPulled  = "Pulled"
+)
+
+// imagePullFailures are the waiting container reasons of failed image pulls.
+var imagePullFailures = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+	"InvalidImageName": true,
+}
+
+// NodeProgress is a progress update of a node while the topology is created.
+type NodeProgress struct {
+	Node  string
+	Phase node.Status
+	// ImagePull is the image pull state of the node pods, empty if unknown.
+	ImagePull string
+	// Reason is set if the node failed.
+	Reason string
+	// Elapsed is the time since the creation of the topology started.
+	Elapsed time.Duration
+}
+
+// MarshalJSON encodes the update with the elapsed time in seconds.
+func (p *NodeProgress) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Node      string  `json:"node"`
+		Phase     string  `json:"phase"`
+		ImagePull string  `json:"image_pull,omitempty"`
+		Reason    string  `json:"reason,omitempty"`
+		Elapsed   float64 `json:"elapsed_seconds"`
+	}{p.Node, string(p.Phase), p.ImagePull, p.Reason, p.Elapsed.Seconds()})
+}
+
+// ProgressFunc is called on every change of the progress of a node.
+type ProgressFunc func(*NodeProgress)
+
+// WithProgress reports the progress of the nodes during Create to f.
+func WithProgress(f ProgressFunc) Option {
+	return func(m *Manager) {
+		m.progress = f
+	}
+}
+
+// progressPollInterval is the minimum interval between polls of the pods of a
+// node for its image pull state.
+var progressPollInterval = time.Second
+
+// progressTracker tracks the last reported progress of each node so only
+// changes are reported.
+type progressTracker struct {
+	mu     sync.Mutex
+	f      ProgressFunc
+	start  time.Time
+	last   map[string]NodeProgress
+	polled map[string]time.Time
+}
+
+func newProgressTracker(f ProgressFunc) *progressTracker {
+	return &progressTracker{
+		f:      f,
+		start:  time.Now(),
+		last:   map[string]NodeProgress{},
+		polled: map[string]time.Time{},
+	}
+}
+
+// report reports the phase of n if it or the image pull state of n changed.
+// The pods of n are only polled for the image pull state while it is being
+// created. Nothing is reported if the tracker is nil.
+func (t *progressTracker) report(ctx context.Context, n node.Node, phase node.Status, reason error) {
+	if t == nil {
+		return
+	}
+	name := n.Name()
+	t.mu.Lock()
+	last, seen := t.last[name]
+	poll := false
+	if phase == node.StatusCreating || phase == node.StatusFailed {
+		if !seen || last.Phase != phase || time.Since(t.polled[name]) >= progressPollInterval {
+			poll = true
+			t.polled[name] = time.Now()
+		}
+	}
+	t.mu.Unlock()
+
+	// The pods are fetched and the update is reported without holding the lock
+	// so slow API calls or callbacks do not block the progress of other nodes.
+	imagePull, known := "", false
+	switch phase {
+	case node.StatusCreating, node.StatusFailed:
+		if poll {
+			if pods, err := n.Pods(ctx); err == nil {
+				imagePull, known = imagePullState(pods), true
+			}
+		}
+	case node.StatusBooting, node.StatusConfigPushing, node.StatusReady:
+		// The containers are running so their images have been pulled.
+		imagePull, known = ImagePullPulled, true
+	}
+
+	t.mu.Lock()
+	last, seen = t.last[name]
+	p := NodeProgress{Node: name, Phase: phase, ImagePull: last.ImagePull}
+	if known {
+		p.ImagePull = imagePull
+	}
+	if reason != nil {
+		p.Reason = reason.Error()
+	}
+	if seen && last.Phase == p.Phase && last.ImagePull == p.ImagePull && last.Reason == p.Reason {
+		t.mu.Unlock()
+		return
+	}
+	t.last[name] = p
+	p.Elapsed = time.Since(t.start)
+	t.mu.Unlock()
+	t.f(&p)
+}
+
+// imagePullState returns the aggregate image pull state of the containers of
+// pods.
+func imagePullState(pods []*corev1.Pod) string {
+	if len(pods) == 0 {
+		return ""
+	}
+	state := ImagePullPulled
+	for _, p := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...), p.Status.ContainerStatuses...)
+		if len(statuses) == 0 {
+			if state == ImagePullPulled {
+				state = ImagePullPending
+			}
+			continue
+		}
+		for _, cs := range statuses {
+			if w := cs.State.Waiting; w != nil && imagePullFailures[w.Reason] {
+				return w.Reason
+			}
+			if cs.ImageID == "" && state == ImagePullPulled {
+				state = ImagePullPulling
+			}
+		}
+	}
+	return state
+}
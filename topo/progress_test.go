@@ -0,0 +1,142 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podded is a fake node returning pods.
+type podded struct {
+	*node.Impl
+	pods []*corev1.Pod
+}
+
+func (p *podded) Pods(context.Context) ([]*corev1.Pod, error) {
+	return p.pods, nil
+}
+
+func podWithStatuses(statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: statuses}}
+}
+
+func TestImagePullState(t *testing.T) {
+	pulled := corev1.ContainerStatus{ImageID: "sha256:1234"}
+	creating := corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}}
+	backoff := corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}}
+	tests := []struct {
+		desc string
+		pods []*corev1.Pod
+		want string
+	}{{
+		desc: "no pods",
+	}, {
+		desc: "not scheduled",
+		pods: []*corev1.Pod{{}},
+		want: ImagePullPending,
+	}, {
+		desc: "pulling",
+		pods: []*corev1.Pod{podWithStatuses(pulled, creating)},
+		want: ImagePullPulling,
+	}, {
+		desc: "pulled",
+		pods: []*corev1.Pod{podWithStatuses(pulled), podWithStatuses(pulled)},
+		want: ImagePullPulled,
+	}, {
+		desc: "pull failed",
+		pods: []*corev1.Pod{podWithStatuses(creating), podWithStatuses(pulled, backoff)},
+		want: "ImagePullBackOff",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := imagePullState(tt.pods); got != tt.want {
+				t.Errorf("imagePullState() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressTracker(t *testing.T) {
+	origInterval := progressPollInterval
+	defer func() {
+		progressPollInterval = origInterval
+	}()
+	progressPollInterval = 0
+	ctx := context.Background()
+	n := &podded{
+		Impl: &node.Impl{Proto: &tpb.Node{Name: "r1"}},
+		pods: []*corev1.Pod{{}},
+	}
+	var got []NodeProgress
+	pt := newProgressTracker(func(p *NodeProgress) {
+		got = append(got, *p)
+	})
+	pt.report(ctx, n, node.StatusCreating, nil)
+	// Unchanged progress is not reported.
+	pt.report(ctx, n, node.StatusCreating, nil)
+	n.pods = []*corev1.Pod{podWithStatuses(corev1.ContainerStatus{})}
+	pt.report(ctx, n, node.StatusCreating, nil)
+	pt.report(ctx, n, node.StatusBooting, nil)
+	pt.report(ctx, n, node.StatusReady, nil)
+	pt.report(ctx, n, node.StatusReady, nil)
+	want := []NodeProgress{
+		{Node: "r1", Phase: node.StatusCreating, ImagePull: ImagePullPending},
+		{Node: "r1", Phase: node.StatusCreating, ImagePull: ImagePullPulling},
+		{Node: "r1", Phase: node.StatusBooting, ImagePull: ImagePullPulled},
+		{Node: "r1", Phase: node.StatusReady, ImagePull: ImagePullPulled},
+	}
+	if s := cmp.Diff(want, got, cmpopts.IgnoreFields(NodeProgress{}, "Elapsed")); s != "" {
+		t.Errorf("report() unexpected progress diff (-want +got):\n%s", s)
+	}
+	// A nil tracker does not report.
+	var nilTracker *progressTracker
+	nilTracker.report(ctx, n, node.StatusReady, nil)
+}
+
+func TestProgressTrackerReentrant(t *testing.T) {
+	ctx := context.Background()
+	r1 := &podded{Impl: &node.Impl{Proto: &tpb.Node{Name: "r1"}}}
+	r2 := &podded{Impl: &node.Impl{Proto: &tpb.Node{Name: "r2"}}}
+	var got []string
+	var pt *progressTracker
+	pt = newProgressTracker(func(p *NodeProgress) {
+		got = append(got, p.Node)
+		// The lock of the tracker is not held while reporting.
+		if p.Node == "r1" {
+			pt.report(ctx, r2, node.StatusReady, nil)
+		}
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pt.report(ctx, r1, node.StatusReady, nil)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("report() deadlocked reporting from the progress callback")
+	}
+	if s := cmp.Diff([]string{"r1", "r2"}, got); s != "" {
+		t.Errorf("report() unexpected nodes diff (-want +got):\n%s", s)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openconfig/gnmi/errlist"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ReplicaNames returns the names of n replicas of the topology named name,
+// name-0 to name-<n-1>, which are also their namespaces.
+func ReplicaNames(name string, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s-%d", name, i)
+	}
+	return names
+}
+
+// ReplicateTopology returns n copies of pb named by ReplicaNames. The copies
+// are isolated in their own namespaces, so the only resources they could
+// conflict on are cluster wide: the node ports set by the services of the
+// nodes are cleared so each replica is allocated its own, and nodes with
+// external interfaces, which bridge to fixed VLANs and VXLANs of the cluster
+// nodes, cannot be replicated.
+func ReplicateTopology(pb *tpb.Topology, n int) ([]*tpb.Topology, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of replicas %d, must be at least 1", n)
+	}
+	var errs errlist.List
+	for _, node := range pb.GetNodes() {
+		for name, intf := range node.GetInterfaces() {
+			if intf.GetExternal() != nil {
+				errs.Add(fmt.Errorf("node %q: external interface %q cannot be replicated", node.GetName(), name))
+			}
+		}
+	}
+	names := ReplicaNames(pb.GetName(), n)
+	// The last name is the longest.
+	if msgs := validation.IsDNS1123Label(names[n-1]); len(msgs) != 0 {
+		errs.Add(fmt.Errorf("invalid replica namespace %q: %s", names[n-1], strings.Join(msgs, ", ")))
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	replicas := make([]*tpb.Topology, n)
+	for i, name := range names {
+		r := proto.Clone(pb).(*tpb.Topology)
+		r.Name = name
+		for _, node := range r.GetNodes() {
+			for _, svc := range node.GetServices() {
+				svc.NodePort = 0
+			}
+		}
+		replicas[i] = r
+	}
+	return replicas, nil
+}
+
+// Replicas manages isolated copies of a topology, e.g. one per shard of a
+// test run.
+type Replicas struct {
+	name     string
+	managers []*Manager
+}
+
+// NewReplicas returns managers of n replicas of the topology pb created with
+// opts, see ReplicateTopology. The progress of the nodes is reported with the
+// nodes named replica/node.
+func NewReplicas(pb *tpb.Topology, n int, opts ...Option) (*Replicas, error) {
+	pbs, err := ReplicateTopology(pb, n)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replicas{name: pb.GetName()}
+	for _, rpb := range pbs {
+		m, err := New(rpb, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("replica %q: %w", rpb.GetName(), err)
+		}
+		if f := m.progress; f != nil {
+			name := rpb.GetName()
+			m.progress = func(p *NodeProgress) {
+				rp := *p
+				rp.Node = name + "/" + p.Node
+				f(&rp)
+			}
+		}
+		r.managers = append(r.managers, m)
+	}
+	return r, nil
+}
+
+// Create creates the replicas concurrently and returns their endpoints in
+// order. The capacity of the cluster is checked once for the nodes of all
+// replicas, and the images are pulled once for all replicas if prepulling is
+// enabled, before any replica is created. The errors of all failed replicas
+// are returned together, the replicas created are not deleted.
+func (r *Replicas) Create(ctx context.Context, timeout time.Duration) ([]*TopologyEndpoints, error) {
+	first := r.managers[0]
+	if !first.skipCapacityCheck {
+		var all []*nodeRequest
+		for _, m := range r.managers {
+			requests, err := m.nodeRequests()
+			if err != nil {
+				return nil, err
+			}
+			for _, nr := range requests {
+				nr.name = m.topo.GetName() + "/" + nr.name
+			}
+			all = append(all, requests...)
+		}
+		if err := first.checkRequests(ctx, fmt.Sprintf("%d replicas of topology %q", len(r.managers), r.name), all); err != nil {
+			return nil, err
+		}
+	}
+	if first.prepull > 0 {
+		if _, err := first.Prepull(ctx, first.prepull); err != nil {
+			first.logger().Warnf("Failed to prepull images: %v", err)
+		}
+	}
+	err := parallel(len(r.managers), len(r.managers), func(i int) error {
+		m := r.managers[i]
+		// The capacity was checked and the images were pulled for all
+		// replicas above.
+		m.skipCapacityCheck, m.prepull = true, 0
+		if err := m.Create(ctx, timeout); err != nil {
+			return fmt.Errorf("replica %q: %w", m.topo.GetName(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoints := make([]*TopologyEndpoints, len(r.managers))
+	for i, m := range r.managers {
+		e, err := m.Endpoints(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("replica %q: %w", m.topo.GetName(), err)
+		}
+		endpoints[i] = e
+	}
+	return endpoints, nil
+}
+
+// Delete deletes the replicas concurrently and returns the errors of all
+// failed deletions together.
+func (r *Replicas) Delete(ctx context.Context) error {
+	return parallel(len(r.managers), len(r.managers), func(i int) error {
+		m := r.managers[i]
+		if err := m.Delete(ctx); err != nil {
+			return fmt.Errorf("replica %q: %w", m.topo.GetName(), err)
+		}
+		return nil
+	})
+}
+
+// TopologyEndpoints is the endpoints of the services of the nodes of a
+// topology, which is also the namespace of the nodes.
+type TopologyEndpoints struct {
+	Name  string           `json:"name"`
+	Nodes []*NodeEndpoints `json:"nodes"`
+}
+
+// NodeEndpoints is the endpoints of the services of a node.
+type NodeEndpoints struct {
+	Node     string           `json:"node"`
+	Services []*ServiceHealth `json:"services,omitempty"`
+}
+
+// Endpoints returns the endpoints of the services of the nodes of the
+// topology in the order of the nodes of the topology.
+func (m *Manager) Endpoints(ctx context.Context) (*TopologyEndpoints, error) {
+	h, err := m.Health(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e := &TopologyEndpoints{Name: h.Name}
+	for _, nh := range h.Nodes {
+		e.Nodes = append(e.Nodes, &NodeEndpoints{Node: nh.Node, Services: nh.Services})
+	}
+	return e, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	ktest "k8s.io/client-go/testing"
+)
+
+func TestReplicateTopology(t *testing.T) {
+	pb := &tpb.Topology{
+		Name: "lab",
+		Nodes: []*tpb.Node{{
+			Name:     "r1",
+			Services: map[uint32]*tpb.Service{22: {Name: "ssh", Inside: 22, NodePort: 30022}},
+		}},
+	}
+	replica := func(name string) *tpb.Topology {
+		return &tpb.Topology{
+			Name: name,
+			Nodes: []*tpb.Node{{
+				Name:     "r1",
+				Services: map[uint32]*tpb.Service{22: {Name: "ssh", Inside: 22}},
+			}},
+		}
+	}
+	tests := []struct {
+		desc    string
+		pb      *tpb.Topology
+		n       int
+		want    []*tpb.Topology
+		wantErr string
+	}{{
+		desc: "replicas",
+		pb:   pb,
+		n:    2,
+		want: []*tpb.Topology{replica("lab-0"), replica("lab-1")},
+	}, {
+		desc:    "no replicas",
+		pb:      pb,
+		n:       0,
+		wantErr: "invalid number of replicas 0",
+	}, {
+		desc:    "invalid namespace",
+		pb:      &tpb.Topology{Name: strings.Repeat("a", 62)},
+		n:       10,
+		wantErr: "invalid replica namespace",
+	}, {
+		desc: "external interface",
+		pb: &tpb.Topology{
+			Name: "lab",
+			Nodes: []*tpb.Node{{
+				Name: "ext",
+				Interfaces: map[string]*tpb.Interface{
+					"eth1": {External: &tpb.ExternalInterface{Device: "eth1", Encap: &tpb.ExternalInterface_Vlan{Vlan: 100}}},
+				},
+			}},
+		},
+		n:       2,
+		wantErr: `node "ext": external interface "eth1" cannot be replicated`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ReplicateTopology(tt.pb, tt.n)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("ReplicateTopology() unexpected error: %s", s)
+			}
+			if s := cmp.Diff(tt.want, got, protocmp.Transform()); s != "" {
+				t.Errorf("ReplicateTopology() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+	if got := pb.GetNodes()[0].GetServices()[22].GetNodePort(); got != 30022 {
+		t.Errorf("ReplicateTopology() changed the node port of the topology to %d", got)
+	}
+}
+
+func TestReplicas(t *testing.T) {
+	ctx := context.Background()
+	node.Register(tpb.Node_Type(1015), NewConfigurable)
+	pb := &tpb.Topology{
+		Name: "lab",
+		Nodes: []*tpb.Node{{
+			Name:        "r1",
+			Type:        tpb.Node_Type(1015),
+			Services:    map[uint32]*tpb.Service{22: {Name: "ssh", Inside: 22}},
+			Config:      &tpb.Config{},
+			Constraints: map[string]string{"cpu": "2"},
+		}},
+	}
+	tests := []struct {
+		desc    string
+		n       int
+		cpu     string
+		want    []*TopologyEndpoints
+		wantErr string
+	}{{
+		desc: "created",
+		n:    2,
+		cpu:  "4",
+		want: []*TopologyEndpoints{{
+			Name:  "lab-0",
+			Nodes: []*NodeEndpoints{{Node: "r1", Services: []*ServiceHealth{{Name: "ssh", Inside: 22, Outside: 22}}}},
+		}, {
+			Name:  "lab-1",
+			Nodes: []*NodeEndpoints{{Node: "r1", Services: []*ServiceHealth{{Name: "ssh", Inside: 22, Outside: 22}}}},
+		}},
+	}, {
+		desc:    "capacity of all replicas",
+		n:       3,
+		cpu:     "4",
+		wantErr: `insufficient cluster capacity for 3 replicas of topology "lab": 1 of 3 nodes cannot be scheduled`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			kf := kfake.NewSimpleClientset(clusterNode("w1", tt.cpu, "16Gi"))
+			kf.PrependReactor("create", "pods", func(action ktest.Action) (bool, runtime.Object, error) {
+				p := action.(ktest.CreateAction).GetObject().(*corev1.Pod)
+				p.Status.Phase = corev1.PodRunning
+				p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+				return false, nil, nil
+			})
+			var (
+				mu         sync.Mutex
+				progressed []string
+			)
+			r, err := NewReplicas(pb, tt.n, WithClusterConfig(&rest.Config{}), WithKubeClient(kf), WithTopoClient(tf), WithWorkers(1), WithProgress(func(p *NodeProgress) {
+				mu.Lock()
+				defer mu.Unlock()
+				progressed = append(progressed, p.Node)
+			}))
+			if err != nil {
+				t.Fatalf("NewReplicas() unexpected error: %v", err)
+			}
+			got, err := r.Create(ctx, 0)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("Create() unexpected error: %s", s)
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("Create() unexpected diff (-want +got):\n%s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			for _, p := range progressed {
+				if p != "lab-0/r1" && p != "lab-1/r1" {
+					t.Errorf("Create() reported progress of node %q, want lab-0/r1 or lab-1/r1", p)
+				}
+			}
+			if err := r.Delete(ctx); err != nil {
+				t.Fatalf("Delete() unexpected error: %v", err)
+			}
+			for _, name := range ReplicaNames("lab", tt.n) {
+				if _, err := kf.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{}); err == nil {
+					t.Errorf("Delete() did not delete namespace %q", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"sort"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceReport is a summary of the resources the nodes of a topology
+// request from the cluster, e.g. for capacity planning.
+type ResourceReport struct {
+	Name  string `json:"name"`
+	Nodes int    `json:"nodes"`
+	// Requests is the total of the constraints of the nodes, including their
+	// sidecars.
+	Requests corev1.ResourceList `json:"requests"`
+	Vendors  []*VendorResources  `json:"vendors"`
+	Images   []*ImageResources   `json:"images"`
+	// Cluster is the comparison with the cluster, nil if not compared.
+	Cluster *ClusterResources `json:"cluster,omitempty"`
+}
+
+// VendorResources is the resources requested by the nodes of a vendor.
+type VendorResources struct {
+	Vendor   string              `json:"vendor"`
+	Nodes    int                 `json:"nodes"`
+	Requests corev1.ResourceList `json:"requests"`
+}
+
+// ImageResources is an image of the nodes of a topology.
+type ImageResources struct {
+	Image string `json:"image"`
+	Nodes int    `json:"nodes"`
+	// SizeBytes is the size of the image as reported by the cluster nodes, 0
+	// if unknown. Sizes are only known if the report is compared with the
+	// cluster and a cluster node has the image.
+	SizeBytes int64 `json:"size_bytes,omitempty"`
+}
+
+// ClusterResources is the comparison of the resources requested by the nodes
+// of a topology with the schedulable capacity of the cluster.
+type ClusterResources struct {
+	// Nodes is the number of ready and uncordoned cluster nodes.
+	Nodes int `json:"nodes"`
+	// Free is the free capacity of the cluster nodes for the resources
+	// requested by the topology.
+	Free corev1.ResourceList `json:"free"`
+	// Unschedulable is the sorted names of the nodes of the topology not
+	// fitting into the free capacity.
+	Unschedulable []string `json:"unschedulable,omitempty"`
+}
+
+// ResourceReport returns a summary of the resources requested by the nodes of
+// the topology, in total and per vendor, and the images of the nodes. If
+// compare is true the requests are compared with the schedulable capacity of
+// the cluster as by the capacity check of Create, and the sizes of the images
+// are taken from the cluster nodes.
+func (m *Manager) ResourceReport(ctx context.Context, compare bool) (*ResourceReport, error) {
+	requests, err := m.nodeRequests()
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]*nodeRequest{}
+	for _, r := range requests {
+		byName[r.name] = r
+	}
+	r := &ResourceReport{Name: m.topo.GetName(), Nodes: len(m.nodes), Requests: corev1.ResourceList{}}
+	vendors := map[string]*VendorResources{}
+	for _, name := range m.nodeNames() {
+		v := vendorName(m.nodes[name].GetProto())
+		vr, ok := vendors[v]
+		if !ok {
+			vr = &VendorResources{Vendor: v, Requests: corev1.ResourceList{}}
+			vendors[v] = vr
+			r.Vendors = append(r.Vendors, vr)
+		}
+		vr.Nodes++
+		if nr := byName[name]; nr != nil {
+			addResources(vr.Requests, nr.requests)
+			addResources(r.Requests, nr.requests)
+		}
+	}
+	sort.Slice(r.Vendors, func(i, j int) bool { return r.Vendors[i].Vendor < r.Vendors[j].Vendor })
+	for _, t := range m.imageTargets() {
+		r.Images = append(r.Images, &ImageResources{Image: t.image, Nodes: len(t.specs)})
+	}
+	if !compare {
+		return r, nil
+	}
+	cluster, err := m.schedulableCapacity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := &ClusterResources{Nodes: len(cluster), Free: corev1.ResourceList{}}
+	for _, cc := range cluster {
+		for res := range r.Requests {
+			addResources(c.Free, corev1.ResourceList{res: cc.free[res]})
+		}
+		for _, ci := range cc.node.Status.Images {
+			for _, name := range ci.Names {
+				for _, ir := range r.Images {
+					if normalizeImage(name) == normalizeImage(ir.Image) && ci.SizeBytes > ir.SizeBytes {
+						ir.SizeBytes = ci.SizeBytes
+					}
+				}
+			}
+		}
+	}
+	for name := range place(requests, cluster) {
+		c.Unschedulable = append(c.Unschedulable, name)
+	}
+	sort.Strings(c.Unschedulable)
+	r.Cluster = c
+	return r, nil
+}
+
+// vendorName returns the vendor of the node, or its type for nodes of an
+// unknown vendor.
+func vendorName(pb *tpb.Node) string {
+	if pb.GetVendor() == tpb.Vendor_UNKNOWN && pb.GetType() != tpb.Node_UNKNOWN {
+		return pb.GetType().String()
+	}
+	return pb.GetVendor().String()
+}
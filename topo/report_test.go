@@ -0,0 +1,132 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResourceReport(t *testing.T) {
+	vendorNode := func(name string, vendor tpb.Vendor, image, cpu, memory string, mods ...func(*tpb.Node)) node.Node {
+		n := constrainedNode(name, cpu, memory)
+		pb := n.GetProto()
+		pb.Vendor = vendor
+		pb.Config = &tpb.Config{Image: image, InitWait: &tpb.InitWait{Disabled: true}}
+		for _, m := range mods {
+			m(pb)
+		}
+		return n
+	}
+	hugepages := func(pb *tpb.Node) { pb.Constraints["hugepages-1Gi"] = "4Gi" }
+	exporter := func(pb *tpb.Node) {
+		pb.Config.Sidecars = []*tpb.Sidecar{{Name: "exporter", Image: "exporter:1", Constraints: map[string]string{"cpu": "500m"}}}
+	}
+	nodes := map[string]node.Node{
+		"r1": vendorNode("r1", tpb.Vendor_CISCO, "xrd:7.8", "4", "12Gi", hugepages),
+		"r2": vendorNode("r2", tpb.Vendor_CISCO, "xrd:7.8", "4", "12Gi", hugepages, exporter),
+		"h1": vendorNode("h1", tpb.Vendor_HOST, "alpine", "", ""),
+		"l1": &node.Impl{Proto: &tpb.Node{Name: "l1", Type: tpb.Node_LEMMING, Constraints: map[string]string{"cpu": "500m", "memory": "1Gi"}}},
+	}
+	withImage := func(name string, size int64) func(*corev1.Node) {
+		return func(n *corev1.Node) {
+			n.Status.Images = append(n.Status.Images, corev1.ContainerImage{Names: []string{name}, SizeBytes: size})
+		}
+	}
+	q := resource.MustParse
+	want := func() *ResourceReport {
+		return &ResourceReport{
+			Name:     "test",
+			Nodes:    4,
+			Requests: corev1.ResourceList{"cpu": q("9"), "memory": q("25Gi"), "hugepages-1Gi": q("8Gi")},
+			Vendors: []*VendorResources{
+				{Vendor: "CISCO", Nodes: 2, Requests: corev1.ResourceList{"cpu": q("8500m"), "memory": q("24Gi"), "hugepages-1Gi": q("8Gi")}},
+				{Vendor: "HOST", Nodes: 1, Requests: corev1.ResourceList{}},
+				{Vendor: "LEMMING", Nodes: 1, Requests: corev1.ResourceList{"cpu": q("500m"), "memory": q("1Gi")}},
+			},
+			Images: []*ImageResources{
+				{Image: "alpine", Nodes: 1},
+				{Image: "exporter:1", Nodes: 1},
+				{Image: "us-west1-docker.pkg.dev/kne-external/kne/networkop/init-wait:ga", Nodes: 1},
+				{Image: "xrd:7.8", Nodes: 2},
+			},
+		}
+	}
+	fits := want()
+	fits.Images[0].SizeBytes = 3 << 20
+	fits.Images[3].SizeBytes = 2 << 30
+	fits.Cluster = &ClusterResources{
+		Nodes: 2,
+		Free:  corev1.ResourceList{"cpu": q("16"), "memory": q("64Gi"), "hugepages-1Gi": q("16Gi")},
+	}
+	short := want()
+	short.Cluster = &ClusterResources{
+		Nodes:         1,
+		Free:          corev1.ResourceList{"cpu": q("8"), "memory": q("32Gi"), "hugepages-1Gi": q("8Gi")},
+		Unschedulable: []string{"r1"},
+	}
+	worker := func(name, cpu, memory string, mods ...func(*corev1.Node)) *corev1.Node {
+		return clusterNode(name, cpu, memory, append([]func(*corev1.Node){func(n *corev1.Node) {
+			n.Status.Allocatable["hugepages-1Gi"] = q("8Gi")
+		}}, mods...)...)
+	}
+	tests := []struct {
+		desc    string
+		compare bool
+		objs    []runtime.Object
+		want    *ResourceReport
+	}{{
+		desc: "not compared",
+		objs: []runtime.Object{worker("w1", "8", "32Gi")},
+		want: want(),
+	}, {
+		desc:    "fits",
+		compare: true,
+		objs: []runtime.Object{
+			worker("w1", "8", "32Gi", withImage("docker.io/library/alpine:latest", 3<<20), withImage("docker.io/library/xrd:7.8", 2<<30)),
+			worker("w2", "8", "32Gi", withImage("docker.io/library/xrd:7.8", 1<<30)),
+		},
+		want: fits,
+	}, {
+		desc:    "does not fit",
+		compare: true,
+		objs:    []runtime.Object{worker("w1", "8", "32Gi")},
+		want:    short,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				nodes:   nodes,
+				kClient: kfake.NewSimpleClientset(tt.objs...),
+			}
+			got, err := m.ResourceReport(context.Background(), tt.compare)
+			if err != nil {
+				t.Fatalf("ResourceReport() unexpected error: %v", err)
+			}
+			if s := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })); s != "" {
+				t.Errorf("ResourceReport() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
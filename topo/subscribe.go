@@ -0,0 +1,290 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	topologyclientv1 "github.com/openconfig/kne/api/clientset/v1beta1"
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+)
+
+// Kinds of the events of a topology sent by Subscribe.
+const (
+	WatchNodePhase = "NodePhase"
+	WatchServiceIP = "ServiceIP"
+	WatchLinks     = "Links"
+)
+
+// WatchEvent is a change of the state of a topology.
+type WatchEvent struct {
+	Kind string    `json:"kind"`
+	Node string    `json:"node"`
+	Time time.Time `json:"time"`
+	// Phase is the phase of the node of NodePhase events, Reason is set if the
+	// node failed or its phase could not be determined.
+	Phase  node.Status `json:"phase,omitempty"`
+	Reason string      `json:"reason,omitempty"`
+	// Service is the name of the service of ServiceIP events and IP its
+	// external IP, empty if the service lost its IP.
+	Service string `json:"service,omitempty"`
+	IP      string `json:"ip,omitempty"`
+	// Action is the change of the meshnet topology of the node of Links
+	// events, ADDED, MODIFIED or DELETED. Links is the operational state of its
+	// links.
+	Action      string                  `json:"action,omitempty"`
+	Links       []topologyv1.LinkStatus `json:"links,omitempty"`
+	Established int                     `json:"established_links,omitempty"`
+	Skipped     int                     `json:"skipped_links,omitempty"`
+}
+
+func (e *WatchEvent) String() string {
+	switch e.Kind {
+	case WatchNodePhase:
+		if e.Reason != "" {
+			return fmt.Sprintf("node %s: %s: %s", e.Node, e.Phase, e.Reason)
+		}
+		return fmt.Sprintf("node %s: %s", e.Node, e.Phase)
+	case WatchServiceIP:
+		if e.IP == "" {
+			return fmt.Sprintf("node %s: service %s has no external IP", e.Node, e.Service)
+		}
+		return fmt.Sprintf("node %s: service %s external IP %s", e.Node, e.Service, e.IP)
+	case WatchLinks:
+		return fmt.Sprintf("node %s: links %s, %d established, %d skipped", e.Node, e.Action, e.Established, e.Skipped)
+	}
+	return fmt.Sprintf("node %s: %s", e.Node, e.Kind)
+}
+
+// Subscribe streams the events of the topology in the cluster: changes of the
+// phases of the nodes, assignments of the external IPs of their services and
+// updates of their meshnet topologies. The current state is sent first, as
+// events for the existing resources. The returned channel is closed once ctx
+// is canceled, a slow receiver delays the events but none are dropped.
+func (m *Manager) Subscribe(ctx context.Context) (<-chan *WatchEvent, error) {
+	ns := m.topo.GetName()
+	s := &subscription{
+		m:      m,
+		ctx:    ctx,
+		ch:     make(chan *WatchEvent),
+		phases: map[string]string{},
+		ips:    map[string]string{},
+		links:  map[string]topologyv1.TopologyStatus{},
+		specs:  map[string]topologyv1.TopologySpec{},
+	}
+	f := informers.NewSharedInformerFactoryWithOptions(m.kClient, 0, informers.WithNamespace(ns))
+	pods := f.Core().V1().Pods().Informer()
+	pods.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    s.podChanged,
+		UpdateFunc: func(_, obj interface{}) { s.podChanged(obj) },
+		DeleteFunc: s.podChanged,
+	})
+	services := f.Core().V1().Services().Informer()
+	services.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { s.serviceChanged(obj, false) },
+		UpdateFunc: func(_, obj interface{}) { s.serviceChanged(obj, false) },
+		DeleteFunc: func(obj interface{}) { s.serviceChanged(obj, true) },
+	})
+	topos := topologyclientv1.NewTopologyInformer(m.tClient, ns, 0).Informer()
+	topos.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { s.topologyChanged(obj, watch.Added) },
+		UpdateFunc: func(_, obj interface{}) { s.topologyChanged(obj, watch.Modified) },
+		DeleteFunc: func(obj interface{}) { s.topologyChanged(obj, watch.Deleted) },
+	})
+	stop := make(chan struct{})
+	for _, i := range []cache.SharedIndexInformer{pods, services, topos} {
+		go i.Run(stop)
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), pods.HasSynced, services.HasSynced, topos.HasSynced) {
+		close(stop)
+		return nil, fmt.Errorf("failed to sync the resources of topology %q: %w", ns, ctx.Err())
+	}
+	go func() {
+		<-ctx.Done()
+		close(stop)
+		s.close()
+	}()
+	return s.ch, nil
+}
+
+// Watch prints the events of the topology to stdout until ctx is canceled.
+func (m *Manager) Watch(ctx context.Context) error {
+	ch, err := m.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	for e := range ch {
+		fmt.Println(e)
+	}
+	return nil
+}
+
+// subscription tracks the last sent state of the nodes of a topology so only
+// changes are sent.
+type subscription struct {
+	m   *Manager
+	ctx context.Context
+
+	sendMu sync.Mutex
+	ch     chan *WatchEvent
+	closed bool
+
+	mu     sync.Mutex
+	phases map[string]string
+	ips    map[string]string
+	links  map[string]topologyv1.TopologyStatus
+	specs  map[string]topologyv1.TopologySpec
+}
+
+// send sends e unless the subscription is closed.
+func (s *subscription) send(e *WatchEvent) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if s.closed {
+		return
+	}
+	e.Time = time.Now()
+	select {
+	case s.ch <- e:
+	case <-s.ctx.Done():
+	}
+}
+
+func (s *subscription) close() {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.closed = true
+	close(s.ch)
+}
+
+// deleted returns the object of obj, unwrapping the last known state of
+// objects deleted while the watch was disconnected.
+func deleted(obj interface{}) interface{} {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return d.Obj
+	}
+	return obj
+}
+
+// podChanged sends the phase of the node of the pod obj if it changed.
+func (s *subscription) podChanged(obj interface{}) {
+	p, ok := deleted(obj).(*corev1.Pod)
+	if !ok {
+		return
+	}
+	name := p.Labels["app"]
+	if _, ok := s.m.nodes[name]; !ok {
+		name = p.Name
+	}
+	s.phaseChanged(name)
+}
+
+// phaseChanged sends the phase of the node name if it changed.
+func (s *subscription) phaseChanged(name string) {
+	n, ok := s.m.nodes[name]
+	if !ok {
+		return
+	}
+	phase, err := s.m.nodeStatus(s.ctx, n)
+	e := &WatchEvent{Kind: WatchNodePhase, Node: name, Phase: phase}
+	if err != nil {
+		e.Reason = err.Error()
+	}
+	state := string(e.Phase) + "/" + e.Reason
+	s.mu.Lock()
+	last, seen := s.phases[name]
+	s.phases[name] = state
+	s.mu.Unlock()
+	if seen && last == state {
+		return
+	}
+	s.send(e)
+}
+
+// serviceChanged sends the external IP of the service obj if it changed.
+func (s *subscription) serviceChanged(obj interface{}, del bool) {
+	svc, ok := deleted(obj).(*corev1.Service)
+	if !ok {
+		return
+	}
+	name := svc.Labels["pod"]
+	if name == "" {
+		name = strings.TrimPrefix(svc.Name, "service-")
+	}
+	if _, ok := s.m.nodes[name]; !ok {
+		return
+	}
+	var ip string
+	if ing := svc.Status.LoadBalancer.Ingress; !del && len(ing) > 0 {
+		ip = ing[0].IP
+		if ip == "" {
+			ip = ing[0].Hostname
+		}
+	}
+	s.mu.Lock()
+	last := s.ips[svc.Name]
+	s.ips[svc.Name] = ip
+	s.mu.Unlock()
+	if last == ip {
+		return
+	}
+	s.send(&WatchEvent{Kind: WatchServiceIP, Node: name, Service: svc.Name, IP: ip})
+}
+
+// topologyChanged sends the links of the meshnet topology obj of a node if
+// they changed. The phase of the node is updated as it depends on the state
+// recorded in the meshnet topology.
+func (s *subscription) topologyChanged(obj interface{}, action watch.EventType) {
+	t, ok := deleted(obj).(*topologyv1.Topology)
+	if !ok {
+		return
+	}
+	if _, ok := s.m.nodes[t.Name]; !ok {
+		return
+	}
+	s.mu.Lock()
+	changed := action != watch.Modified ||
+		!reflect.DeepEqual(s.specs[t.Name], t.Spec) ||
+		!reflect.DeepEqual(s.links[t.Name].Links, t.Status.Links) ||
+		s.links[t.Name].EstablishedLinks != t.Status.EstablishedLinks ||
+		s.links[t.Name].SkippedLinks != t.Status.SkippedLinks
+	s.specs[t.Name] = t.Spec
+	s.links[t.Name] = t.Status
+	s.mu.Unlock()
+	if changed {
+		s.send(&WatchEvent{
+			Kind:        WatchLinks,
+			Node:        t.Name,
+			Action:      string(action),
+			Links:       t.Status.Links,
+			Established: t.Status.EstablishedLinks,
+			Skipped:     t.Status.SkippedLinks,
+		})
+	}
+	if action == watch.Modified {
+		s.phaseChanged(t.Name)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
+	"github.com/openconfig/kne/topo/node"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+func TestSubscribe(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test", Labels: map[string]string{"app": "r1"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-r1", Namespace: "test", Labels: map[string]string{"pod": "r1"}},
+	}
+	mt := &topologyv1.Topology{
+		ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+		Spec:       topologyv1.TopologySpec{Links: []topologyv1.Link{{UID: 1, LocalIntf: "eth1", PeerIntf: "eth1", PeerPod: "r2"}}},
+	}
+	kClient := kfake.NewSimpleClientset(pod, svc)
+	tClient, err := tfake.NewSimpleClientset(mt)
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	m := &Manager{
+		topo:    &tpb.Topology{Name: "test"},
+		kClient: kClient,
+		tClient: tClient,
+		nodes: map[string]node.Node{
+			"r1": &configurable{Impl: &node.Impl{Namespace: "test", KubeClient: kClient, Proto: &tpb.Node{Name: "r1"}}},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := m.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	next := func() *WatchEvent {
+		t.Helper()
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				t.Fatalf("Subscribe() channel closed")
+			}
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Subscribe() timed out waiting for event")
+		}
+		return nil
+	}
+	ignoreTime := cmpopts.IgnoreFields(WatchEvent{}, "Time")
+	sortEvents := cmpopts.SortSlices(func(a, b *WatchEvent) bool { return a.Kind < b.Kind })
+
+	// The current state is sent first, in any order.
+	want := []*WatchEvent{
+		{Kind: WatchLinks, Node: "r1", Action: "ADDED"},
+		{Kind: WatchNodePhase, Node: "r1", Phase: node.StatusCreating},
+	}
+	got := []*WatchEvent{next(), next()}
+	if s := cmp.Diff(want, got, ignoreTime, sortEvents); s != "" {
+		t.Fatalf("Subscribe() unexpected initial events (-want +got):\n%s", s)
+	}
+
+	pod.Status = corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+	}
+	if _, err := kClient.CoreV1().Pods("test").UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("cannot update pod: %v", err)
+	}
+	if s := cmp.Diff(&WatchEvent{Kind: WatchNodePhase, Node: "r1", Phase: node.StatusReady}, next(), ignoreTime); s != "" {
+		t.Errorf("Subscribe() unexpected phase event (-want +got):\n%s", s)
+	}
+
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.168.18.100"}}
+	if _, err := kClient.CoreV1().Services("test").UpdateStatus(ctx, svc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("cannot update service: %v", err)
+	}
+	if s := cmp.Diff(&WatchEvent{Kind: WatchServiceIP, Node: "r1", Service: "service-r1", IP: "192.168.18.100"}, next(), ignoreTime); s != "" {
+		t.Errorf("Subscribe() unexpected service event (-want +got):\n%s", s)
+	}
+
+	mt.Status.Links = []topologyv1.LinkStatus{{UID: 1, LocalIntf: "eth1", PeerPod: "r2", PeerIntf: "eth1", State: topologyv1.LinkEstablished}}
+	mt.Status.EstablishedLinks = 1
+	if _, err := tClient.Topology("test").Update(ctx, mt, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("cannot update meshnet topology: %v", err)
+	}
+	wantLinks := &WatchEvent{Kind: WatchLinks, Node: "r1", Action: "MODIFIED", Links: mt.Status.Links, Established: 1}
+	if s := cmp.Diff(wantLinks, next(), ignoreTime); s != "" {
+		t.Errorf("Subscribe() unexpected links event (-want +got):\n%s", s)
+	}
+
+	if err := kClient.CoreV1().Services("test").Delete(ctx, "service-r1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("cannot delete service: %v", err)
+	}
+	if s := cmp.Diff(&WatchEvent{Kind: WatchServiceIP, Node: "r1", Service: "service-r1"}, next(), ignoreTime); s != "" {
+		t.Errorf("Subscribe() unexpected service event (-want +got):\n%s", s)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/openconfig/kne/topo/node"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+var (
+	// defaultConfigPushBackoff is the delay before the first retry of a
+	// failed config push if the node does not set it.
+	defaultConfigPushBackoff = 5 * time.Second
+)
+
+// defaultTimeouts sets the unset timeouts of the node to the timeouts of the
+// topology.
+func (m *Manager) defaultTimeouts(pb *tpb.Node) {
+	def := m.topo.GetTimeouts()
+	if def == nil {
+		return
+	}
+	if pb.Timeouts == nil {
+		pb.Timeouts = proto.Clone(def).(*tpb.Timeouts)
+		return
+	}
+	t := pb.Timeouts
+	if t.Boot == 0 {
+		t.Boot = def.GetBoot()
+	}
+	if t.ServiceIp == 0 {
+		t.ServiceIp = def.GetServiceIp()
+	}
+	if t.ConfigPushRetries == 0 {
+		t.ConfigPushRetries = def.GetConfigPushRetries()
+	}
+	if t.ConfigPushBackoff == 0 {
+		t.ConfigPushBackoff = def.GetConfigPushBackoff()
+	}
+	if t.Ready == 0 {
+		t.Ready = def.GetReady()
+	}
+}
+
+// seconds returns s seconds as a duration.
+func seconds(s uint32) time.Duration {
+	return time.Duration(s) * time.Second
+}
+
+// waitServiceIPs waits for the external IPs of the services of the nodes with
+// a service_ip timeout.
+func (m *Manager) waitServiceIPs(ctx context.Context) error {
+	return m.forEachNode(m.nodeNames(), func(name string, n node.Node) error {
+		timeout := seconds(n.GetProto().GetTimeouts().GetServiceIp())
+		if timeout == 0 || len(n.GetProto().GetServices()) == 0 {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		svc := fmt.Sprintf("service-%s", name)
+		selector := fields.OneTermEqualSelector(metav1.ObjectNameField, svc).String()
+		services := m.kClient.CoreV1().Services(m.topo.GetName())
+		lw := &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = selector
+				return services.List(ctx, opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = selector
+				return services.Watch(ctx, opts)
+			},
+		}
+		e, err := watchtools.UntilWithSync(ctx, lw, &corev1.Service{}, nil, func(e watch.Event) (bool, error) {
+			s, ok := e.Object.(*corev1.Service)
+			return ok && e.Type != watch.Deleted && s.Name == svc && len(s.Status.LoadBalancer.Ingress) > 0, nil
+		})
+		if err != nil {
+			return fmt.Errorf("node %q: service %q not assigned an external IP within %s: %w", name, svc, timeout, err)
+		}
+		m.logger().WithField("node", name).Infof("Service %q of node %q assigned %v", svc, name, e.Object.(*corev1.Service).Status.LoadBalancer.Ingress)
+		return nil
+	})
+}
+
+// configPush pushes the config read from r to the node using push, retrying
+// failed pushes with exponential backoff up to the config_push_retries of the
+// node.
+func (m *Manager) configPush(ctx context.Context, n node.Node, push func(context.Context, io.Reader) error, r io.Reader) error {
+	t := n.GetProto().GetTimeouts()
+	retries := t.GetConfigPushRetries()
+	if retries == 0 {
+		return push(ctx, r)
+	}
+	// The config is read once so it can be pushed again on retries.
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	backoff := seconds(t.GetConfigPushBackoff())
+	if backoff == 0 {
+		backoff = defaultConfigPushBackoff
+	}
+	for attempt := uint32(0); ; attempt++ {
+		err = push(ctx, bytes.NewReader(b))
+		if err == nil || attempt == retries {
+			return err
+		}
+		m.logger().WithField("node", n.Name()).Warnf("Config push to node %q failed, retrying in %s (%d/%d): %v", n.Name(), backoff, attempt+1, retries, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v: %w", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
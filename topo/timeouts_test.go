@@ -0,0 +1,296 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	tfake "github.com/openconfig/kne/api/clientset/v1beta1/fake"
+	"github.com/openconfig/kne/topo/node"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+func TestDefaultTimeouts(t *testing.T) {
+	tests := []struct {
+		desc string
+		topo *tpb.Timeouts
+		node *tpb.Timeouts
+		want *tpb.Timeouts
+	}{{
+		desc: "none",
+	}, {
+		desc: "topology",
+		topo: &tpb.Timeouts{Boot: 300, ConfigPushRetries: 2},
+		want: &tpb.Timeouts{Boot: 300, ConfigPushRetries: 2},
+	}, {
+		desc: "node",
+		node: &tpb.Timeouts{Boot: 30},
+		want: &tpb.Timeouts{Boot: 30},
+	}, {
+		desc: "node overrides topology",
+		topo: &tpb.Timeouts{Boot: 300, ServiceIp: 60, ConfigPushRetries: 2, ConfigPushBackoff: 10},
+		node: &tpb.Timeouts{Boot: 30, ConfigPushRetries: 5},
+		want: &tpb.Timeouts{Boot: 30, ServiceIp: 60, ConfigPushRetries: 5, ConfigPushBackoff: 10},
+	}, {
+		desc: "node ready overrides topology",
+		topo: &tpb.Timeouts{Boot: 600, Ready: 30},
+		node: &tpb.Timeouts{Ready: 300},
+		want: &tpb.Timeouts{Boot: 600, Ready: 300},
+	}, {
+		desc: "topology ready",
+		topo: &tpb.Timeouts{Ready: 30},
+		node: &tpb.Timeouts{Boot: 600},
+		want: &tpb.Timeouts{Boot: 600, Ready: 30},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := &Manager{topo: &tpb.Topology{Name: "test", Timeouts: tt.topo}}
+			pb := &tpb.Node{Name: "r1", Timeouts: tt.node}
+			m.defaultTimeouts(pb)
+			if s := cmp.Diff(tt.want, pb.GetTimeouts(), protocmp.Transform()); s != "" {
+				t.Errorf("defaultTimeouts() unexpected diff (-want +got):\n%s", s)
+			}
+			if tt.topo != nil && pb.GetTimeouts() == tt.topo {
+				t.Errorf("defaultTimeouts() shares the topology timeouts with the node")
+			}
+		})
+	}
+}
+
+func TestBootTimeout(t *testing.T) {
+	node.Register(tpb.Node_Type(1013), NewConfigurable)
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	m, err := New(&tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name:     "r1",
+			Type:     tpb.Node_Type(1013),
+			Config:   &tpb.Config{},
+			Timeouts: &tpb.Timeouts{Boot: 1},
+		}},
+	}, WithClusterConfig(&rest.Config{}), WithKubeClient(kfake.NewSimpleClientset()), WithTopoClient(tf))
+	if err != nil {
+		t.Fatalf("New() failed to create new topology manager: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = m.Create(ctx, 0)
+	if s := errdiff.Check(err, `Node "r1": not ready within 1s, Status CREATING`); s != "" {
+		t.Errorf("Create() unexpected error: %s", s)
+	}
+}
+
+// slowCreate is a node whose creation never completes, e.g. as its operator
+// does not create its pod.
+type slowCreate struct {
+	*node.Impl
+}
+
+func (s *slowCreate) Create(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestBootTimeoutCreate(t *testing.T) {
+	node.Register(tpb.Node_Type(1014), func(impl *node.Impl) (node.Node, error) {
+		return &slowCreate{Impl: impl}, nil
+	})
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	m, err := New(&tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{{
+			Name:     "r1",
+			Type:     tpb.Node_Type(1014),
+			Timeouts: &tpb.Timeouts{Boot: 1},
+		}},
+	}, WithClusterConfig(&rest.Config{}), WithKubeClient(kfake.NewSimpleClientset()), WithTopoClient(tf))
+	if err != nil {
+		t.Fatalf("New() failed to create new topology manager: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = m.Create(ctx, 0)
+	if s := errdiff.Check(err, "failed to create node r1: context deadline exceeded"); s != "" {
+		t.Errorf("Create() unexpected error: %s", s)
+	}
+}
+
+func TestWaitServiceIPs(t *testing.T) {
+	service := func(name string, ips ...string) *corev1.Service {
+		s := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "service-" + name, Namespace: "test"}}
+		for _, ip := range ips {
+			s.Status.LoadBalancer.Ingress = append(s.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: ip})
+		}
+		return s
+	}
+	withServices := func(name string, t *tpb.Timeouts) node.Node {
+		return &node.Impl{Proto: &tpb.Node{Name: name, Services: map[uint32]*tpb.Service{22: {Name: "ssh"}}, Timeouts: t}}
+	}
+	tests := []struct {
+		desc     string
+		nodes    map[string]node.Node
+		services []*corev1.Service
+		// assign is updated while waiting.
+		assign  *corev1.Service
+		wantErr string
+	}{{
+		desc: "not waited for",
+		nodes: map[string]node.Node{
+			"r1": withServices("r1", nil),
+			"r2": &node.Impl{Proto: &tpb.Node{Name: "r2", Timeouts: &tpb.Timeouts{ServiceIp: 1}}},
+		},
+	}, {
+		desc: "assigned",
+		nodes: map[string]node.Node{
+			"r1": withServices("r1", &tpb.Timeouts{ServiceIp: 1}),
+		},
+		services: []*corev1.Service{service("r1", "192.168.18.100")},
+	}, {
+		desc: "assigned while waiting",
+		nodes: map[string]node.Node{
+			"r1": withServices("r1", &tpb.Timeouts{ServiceIp: 5}),
+		},
+		services: []*corev1.Service{service("r1")},
+		assign:   service("r1", "192.168.18.100"),
+	}, {
+		desc: "not assigned",
+		nodes: map[string]node.Node{
+			"r1": withServices("r1", &tpb.Timeouts{ServiceIp: 1}),
+			"r2": withServices("r2", &tpb.Timeouts{ServiceIp: 1}),
+		},
+		services: []*corev1.Service{service("r1", "192.168.18.100"), service("r2")},
+		wantErr:  `node "r2": service "service-r2" not assigned an external IP within 1s`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kf := kfake.NewSimpleClientset()
+			for _, s := range tt.services {
+				if _, err := kf.CoreV1().Services("test").Create(context.Background(), s, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create service: %v", err)
+				}
+			}
+			if tt.assign != nil {
+				go func() {
+					time.Sleep(100 * time.Millisecond)
+					if _, err := kf.CoreV1().Services("test").UpdateStatus(context.Background(), tt.assign, metav1.UpdateOptions{}); err != nil {
+						t.Errorf("failed to update service: %v", err)
+					}
+				}()
+			}
+			m := &Manager{topo: &tpb.Topology{Name: "test"}, nodes: tt.nodes, kClient: kf}
+			err := m.waitServiceIPs(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Errorf("waitServiceIPs() unexpected error: %s", s)
+			}
+		})
+	}
+}
+
+// flaky is a node failing the first failures config pushes.
+type flaky struct {
+	*node.Impl
+	failures int
+	pushes   []string
+}
+
+func (f *flaky) ConfigPush(_ context.Context, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	f.pushes = append(f.pushes, string(b))
+	if len(f.pushes) <= f.failures {
+		return fmt.Errorf("push %d failed", len(f.pushes))
+	}
+	return nil
+}
+
+func TestConfigPushRetries(t *testing.T) {
+	orig := defaultConfigPushBackoff
+	defer func() { defaultConfigPushBackoff = orig }()
+	defaultConfigPushBackoff = time.Millisecond
+	tests := []struct {
+		desc       string
+		retries    uint32
+		failures   int
+		wantPushes int
+		wantErr    string
+	}{{
+		desc:       "no retries",
+		failures:   1,
+		wantPushes: 1,
+		wantErr:    "push 1 failed",
+	}, {
+		desc:       "succeeds on retry",
+		retries:    3,
+		failures:   2,
+		wantPushes: 3,
+	}, {
+		desc:       "retries exhausted",
+		retries:    2,
+		failures:   5,
+		wantPushes: 3,
+		wantErr:    "push 3 failed",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			f := &flaky{
+				Impl:     &node.Impl{Proto: &tpb.Node{Name: "r1", Timeouts: &tpb.Timeouts{ConfigPushRetries: tt.retries}}},
+				failures: tt.failures,
+			}
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				nodes:   map[string]node.Node{"r1": f},
+				kClient: kfake.NewSimpleClientset(),
+				tClient: tf,
+			}
+			err = m.ConfigPush(context.Background(), "r1", strings.NewReader("hostname r1"))
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Errorf("ConfigPush() unexpected error: %s", s)
+			}
+			if len(f.pushes) != tt.wantPushes {
+				t.Fatalf("ConfigPush() pushed %d times, want %d", len(f.pushes), tt.wantPushes)
+			}
+			for i, p := range f.pushes {
+				if p != "hostname r1" {
+					t.Errorf("ConfigPush() push %d got config %q, want %q", i, p, "hostname r1")
+				}
+			}
+		})
+	}
+}
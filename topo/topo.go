@@ -15,27 +15,36 @@
 package topo
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"path"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/ghodss/yaml"
-	"github.com/kr/pretty"
+	"github.com/openconfig/gnmi/errlist"
 	cpb "github.com/openconfig/kne/proto/controller"
+	"github.com/openconfig/kne/topo/metrics"
 	"github.com/openconfig/kne/topo/node"
+	"github.com/openconfig/kne/topo/topofile"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/encoding/prototext"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/retry"
 
 	topologyclientv1 "github.com/openconfig/kne/api/clientset/v1beta1"
 	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
@@ -44,27 +53,37 @@ import (
 	_ "github.com/openconfig/kne/topo/node/ceos"
 	_ "github.com/openconfig/kne/topo/node/cisco"
 	_ "github.com/openconfig/kne/topo/node/cptx"
+	_ "github.com/openconfig/kne/topo/node/external"
 	_ "github.com/openconfig/kne/topo/node/gobgp"
 	_ "github.com/openconfig/kne/topo/node/host"
 	_ "github.com/openconfig/kne/topo/node/ixia"
 	_ "github.com/openconfig/kne/topo/node/lemming"
 	_ "github.com/openconfig/kne/topo/node/srl"
+	_ "github.com/openconfig/kne/topo/node/vmnode"
 )
 
-var protojsonUnmarshaller = protojson.UnmarshalOptions{
-	AllowPartial:   true,
-	DiscardUnknown: false,
-}
+// defaultWorkers is the default maximum number of nodes operated on
+// concurrently.
+const defaultWorkers = 16
 
 // Manager is a topology manager for a cluster instance.
 type Manager struct {
 	topo     *tpb.Topology
 	nodes    map[string]node.Node
 	kubecfg  string
+	context  string
 	kClient  kubernetes.Interface
 	tClient  topologyclientv1.Interface
 	rCfg     *rest.Config
 	basePath string
+	progress ProgressFunc
+	workers  int
+	qps      float32
+	burst    int
+	images   map[string]string
+	prepull  int
+
+	skipCapacityCheck bool
 }
 
 type Option func(m *Manager)
@@ -75,6 +94,15 @@ func WithKubecfg(k string) Option {
 	}
 }
 
+// WithContext sets the context of the kubeconfig used for the cluster config,
+// instead of its current context. The in-cluster config is not tried if a
+// context is set.
+func WithContext(c string) Option {
+	return func(m *Manager) {
+		m.context = c
+	}
+}
+
 func WithKubeClient(c kubernetes.Interface) Option {
 	return func(m *Manager) {
 		m.kClient = c
@@ -99,10 +127,40 @@ func WithBasePath(s string) Option {
 	}
 }
 
+// WithWorkers sets the maximum number of nodes created concurrently, the
+// default is used if n is not positive.
+func WithWorkers(n int) Option {
+	return func(m *Manager) {
+		m.workers = n
+	}
+}
+
+// WithRateLimit sets the client side rate limit of the requests to the API
+// server, qps requests per second with bursts of up to burst requests. The
+// client-go defaults are used for zero values. The limit is shared by all
+// clients created by the manager. It does not apply to clients passed with
+// WithKubeClient or WithTopoClient.
+func WithRateLimit(qps float32, burst int) Option {
+	return func(m *Manager) {
+		m.qps = qps
+		m.burst = burst
+	}
+}
+
+// WithImageMap rewrites the images of the nodes, including their init
+// container images, found in images to the mapped image, e.g. to images
+// preloaded into the cluster or pushed to a local registry.
+func WithImageMap(images map[string]string) Option {
+	return func(m *Manager) {
+		m.images = images
+	}
+}
+
 // New creates a new Manager based on the provided topology. The cluster config
 // passed from the WithClusterConfig option overrides the determined in-cluster
 // config. If neither of these configurations can be used then the kubecfg passed
-// from the WithKubecfg option will be used to determine the cluster config.
+// from the WithKubecfg option will be used to determine the cluster config, with
+// the context passed from the WithContext option if set.
 func New(topo *tpb.Topology, opts ...Option) (*Manager, error) {
 	if topo == nil {
 		return nil, fmt.Errorf("topology cannot be nil")
@@ -114,71 +172,142 @@ func New(topo *tpb.Topology, opts ...Option) (*Manager, error) {
 	for _, o := range opts {
 		o(m)
 	}
+	if err := m.initClients(); err != nil {
+		return nil, err
+	}
+	if err := m.load(); err != nil {
+		return nil, fmt.Errorf("failed to load topology: %w", err)
+	}
+	m.logger().Infof("Created manager for topology:\n%v", prototext.Format(m.topo))
+	return m, nil
+}
+
+// initClients sets the cluster config and the clients of the manager not set
+// by options.
+func (m *Manager) initClients() error {
+	if m.rCfg == nil && m.context != "" {
+		m.logger().Infof("Using context %q of kubeconfig: %q", m.context, m.kubecfg)
+		rCfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: m.kubecfg},
+			&clientcmd.ConfigOverrides{CurrentContext: m.context},
+		).ClientConfig()
+		if err != nil {
+			return err
+		}
+		m.rCfg = rCfg
+	}
 	if m.rCfg == nil {
-		log.Infof("Trying in-cluster configuration")
+		m.logger().Infof("Trying in-cluster configuration")
 		rCfg, err := rest.InClusterConfig()
 		if err != nil {
-			log.Infof("Falling back to kubeconfig: %q", m.kubecfg)
+			m.logger().Infof("Falling back to kubeconfig: %q", m.kubecfg)
 			rCfg, err = clientcmd.BuildConfigFromFlags("", m.kubecfg)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		m.rCfg = rCfg
 	}
+	if m.qps > 0 || m.burst > 0 {
+		// A single rate limiter is shared by all clients, otherwise each
+		// client created from the config would have a limit of its own.
+		m.rCfg = rest.CopyConfig(m.rCfg)
+		m.rCfg.QPS, m.rCfg.Burst = rest.DefaultQPS, rest.DefaultBurst
+		if m.qps > 0 {
+			m.rCfg.QPS = m.qps
+		}
+		if m.burst > 0 {
+			m.rCfg.Burst = m.burst
+		}
+		m.rCfg.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(m.rCfg.QPS, m.rCfg.Burst)
+	}
 	if m.kClient == nil {
 		kClient, err := kubernetes.NewForConfig(m.rCfg)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		m.kClient = kClient
 	}
 	if m.tClient == nil {
 		tClient, err := topologyclientv1.NewForConfig(m.rCfg)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		m.tClient = tClient
 	}
-	if err := m.load(); err != nil {
-		return nil, fmt.Errorf("failed to load topology: %w", err)
-	}
-	log.Infof("Created manager for topology:\n%v", prototext.Format(m.topo))
-	return m, nil
+	return nil
+}
+
+// logger returns a logger with the topology and namespace fields set.
+func (m *Manager) logger() *log.Entry {
+	return log.WithFields(log.Fields{
+		"topology":  m.topo.GetName(),
+		"namespace": m.topo.GetName(),
+	})
 }
 
 // Create creates the topology in the cluster.
 func (m *Manager) Create(ctx context.Context, timeout time.Duration) error {
-	log.Infof("Topology:\n%v", prototext.Format(m.topo))
-	if err := m.push(ctx); err != nil {
+	m.logger().Infof("Topology:\n%v", prototext.Format(m.topo))
+	if !m.skipCapacityCheck {
+		if err := m.checkCapacity(ctx); err != nil {
+			return err
+		}
+	}
+	if m.prepull > 0 {
+		if _, err := m.Prepull(ctx, m.prepull); err != nil {
+			m.logger().Warnf("Failed to prepull images: %v", err)
+		}
+	}
+	// The nodes get their pods from a shared watch of the namespace rather
+	// than querying the API server while waiting for them.
+	if stop, err := node.WatchPods(ctx, m.kClient, m.topo.GetName()); err != nil {
+		m.logger().Warnf("Failed to watch pods, querying them instead: %v", err)
+	} else {
+		defer stop()
+	}
+	var pt *progressTracker
+	if m.progress != nil {
+		pt = newProgressTracker(m.progress)
+	}
+	if err := m.push(ctx, pt); err != nil {
 		return err
 	}
-	if err := m.checkNodeStatus(ctx, timeout); err != nil {
+	if err := m.checkNodeStatus(ctx, timeout, pt); err != nil {
+		metrics.Failed(metrics.OpNodeStatus, err)
 		return err
 	}
-	log.Infof("Topology %q created", m.topo.GetName())
+	if err := m.waitServiceIPs(ctx); err != nil {
+		return err
+	}
+	m.recordLinkStates(ctx)
+	metrics.ActiveTopologies.WithLabelValues(m.topo.GetName()).Set(1)
+	m.logger().Infof("Topology %q created", m.topo.GetName())
+	m.recordTopologyEvent(ctx, corev1.EventTypeNormal, EventTopologyCreated, "Topology %q created with %d nodes", m.topo.GetName(), len(m.nodes))
 	return nil
 }
 
 // Delete deletes the topology from the cluster.
 func (m *Manager) Delete(ctx context.Context) error {
-	log.Infof("Topology:\n%v", prototext.Format(m.topo))
+	m.logger().Infof("Topology:\n%v", prototext.Format(m.topo))
 	if _, err := m.kClient.CoreV1().Namespaces().Get(ctx, m.topo.Name, metav1.GetOptions{}); err != nil {
 		return fmt.Errorf("topology %q does not exist in cluster", m.topo.Name)
 	}
 
 	// Delete topology nodes
-	for _, n := range m.nodes {
-		// Delete Service for node
+	m.forEachNode(m.nodeNames(), func(name string, n node.Node) error {
 		if err := n.Delete(ctx); err != nil {
-			log.Warnf("Error deleting node %q: %v", n.Name(), err)
+			metrics.Failed(metrics.OpDeleteNode, err)
+			m.logger().WithField("node", name).Warnf("Error deleting node %q: %v", name, err)
 		}
-	}
+		return nil
+	})
 
 	if err := m.deleteMeshnetTopologies(ctx); err != nil {
 		return err
 	}
 
+	metrics.ActiveTopologies.DeleteLabelValues(m.topo.GetName())
 	// Delete namespace
 	prop := metav1.DeletePropagationForeground
 	return m.kClient.CoreV1().Namespaces().Delete(ctx, m.topo.Name, metav1.DeleteOptions{PropagationPolicy: &prop})
@@ -186,7 +315,7 @@ func (m *Manager) Delete(ctx context.Context) error {
 
 // Show returns the topology information including services and node health.
 func (m *Manager) Show(ctx context.Context) (*cpb.ShowTopologyResponse, error) {
-	log.Infof("Topology:\n%v", prototext.Format(m.topo))
+	m.logger().Infof("Topology:\n%v", prototext.Format(m.topo))
 	r, err := m.Resources(ctx)
 	if err != nil {
 		return nil, err
@@ -206,33 +335,142 @@ func (m *Manager) Show(ctx context.Context) (*cpb.ShowTopologyResponse, error) {
 		}
 	}
 	stateMap := &stateMap{}
+	nodes := map[string]*cpb.NodeStatus{}
 	for _, n := range m.nodes {
-		phase, _ := n.Status(ctx)
+		phase, err := m.nodeStatus(ctx, n)
 		stateMap.setNodeState(n.Name(), phase)
+		ns := &cpb.NodeStatus{State: nodeState(phase)}
+		if err != nil {
+			ns.Reason = err.Error()
+		}
+		nodes[n.Name()] = ns
 	}
 	return &cpb.ShowTopologyResponse{
 		State:    stateMap.topologyState(),
 		Topology: m.topo,
+		Nodes:    nodes,
 	}, nil
 }
 
-func (m *Manager) Watch(ctx context.Context) error {
-	watcher, err := m.tClient.Topology(m.topo.Name).Watch(ctx, metav1.ListOptions{})
+// Nodes returns a map of node names to implementations in the current topology.
+func (m *Manager) Nodes() map[string]node.Node {
+	return m.nodes
+}
+
+// InterfaceNames returns the vendor names of the interfaces of the node name
+// of the topology keyed by their names in the topology, e.g. eth1. The
+// cluster is not accessed.
+func InterfaceNames(pb *tpb.Topology, name string) (map[string]string, error) {
+	m := &Manager{
+		topo:  pb,
+		nodes: map[string]node.Node{},
+	}
+	if err := m.load(); err != nil {
+		return nil, fmt.Errorf("failed to load topology: %w", err)
+	}
+	n, ok := m.nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("node %q not found", name)
+	}
+	return n.InterfaceNames()
+}
+
+// SelectNodes returns the subset of nodes in the current topology matching
+// the provided name patterns and label selector. A node is selected if its
+// name matches any of the patterns (shell globs as understood by path.Match)
+// and its labels match the selector (k8s label selector syntax, e.g.
+// "vendor=CISCO,role in (leaf)"). The vendor, type, model, os and version
+// fields of the node are available as labels unless the node explicitly sets
+// a label with the same key. Empty patterns or an empty selector match all
+// nodes. An error is returned if a pattern without wildcards names a node not
+// in the topology or if no nodes are selected.
+func (m *Manager) SelectNodes(patterns []string, selector string) (map[string]node.Node, error) {
+	pbs := map[string]*tpb.Node{}
+	for name, n := range m.nodes {
+		pbs[name] = n.GetProto()
+	}
+	selected, err := selectNodes(pbs, patterns, selector)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	ch := watcher.ResultChan()
-	for e := range ch {
-		fmt.Println(e.Type)
-		pretty.Print(e.Object)
-		fmt.Println("")
+	nodes := map[string]node.Node{}
+	for name := range selected {
+		nodes[name] = m.nodes[name]
 	}
-	return nil
+	return nodes, nil
 }
 
-// Nodes returns a map of node names to implementations in the current topology.
-func (m *Manager) Nodes() map[string]node.Node {
-	return m.nodes
+// SelectNodeProtos returns the nodes of the topology t matching the provided
+// name patterns and label selector as SelectNodes does.
+func SelectNodeProtos(t *tpb.Topology, patterns []string, selector string) (map[string]*tpb.Node, error) {
+	pbs := map[string]*tpb.Node{}
+	for _, n := range t.GetNodes() {
+		pbs[n.GetName()] = n
+	}
+	return selectNodes(pbs, patterns, selector)
+}
+
+// selectNodes returns the nodes of pbs, keyed by name, matching patterns and
+// selector.
+func selectNodes(pbs map[string]*tpb.Node, patterns []string, selector string) (map[string]*tpb.Node, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", selector, err)
+	}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid node pattern %q: %w", p, err)
+		}
+		if strings.ContainsAny(p, `*?[\`) {
+			continue
+		}
+		if _, ok := pbs[p]; !ok {
+			return nil, fmt.Errorf("node %q not found", p)
+		}
+	}
+	nodes := map[string]*tpb.Node{}
+	for name, pb := range pbs {
+		if !matchName(name, patterns) {
+			continue
+		}
+		if !sel.Matches(nodeLabels(pb)) {
+			continue
+		}
+		nodes[name] = pb
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes matched patterns %q and selector %q", patterns, selector)
+	}
+	return nodes, nil
+}
+
+// matchName returns true if name matches any of the patterns or if there are
+// no patterns.
+func matchName(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeLabels returns the labels of the node used for selection.
+func nodeLabels(pb *tpb.Node) labels.Set {
+	l := labels.Set{
+		"vendor":  pb.GetVendor().String(),
+		"type":    pb.GetType().String(),
+		"model":   pb.GetModel(),
+		"os":      pb.GetOs(),
+		"version": pb.GetVersion(),
+	}
+	for k, v := range pb.GetLabels() {
+		l[k] = v
+	}
+	return l
 }
 
 // load populates the internal fields of the topology proto.
@@ -251,7 +489,7 @@ func (m *Manager) load() error {
 	}
 	uid := 0
 	for _, l := range m.topo.Links {
-		log.Infof("Adding Link: %s:%s %s:%s", l.ANode, l.AInt, l.ZNode, l.ZInt)
+		m.logger().Infof("Adding Link: %s:%s %s:%s", l.ANode, l.AInt, l.ZNode, l.ZInt)
 		aNode, ok := nMap[l.ANode]
 		if !ok {
 			return fmt.Errorf("invalid topology: missing node %q", l.ANode)
@@ -288,17 +526,115 @@ func (m *Manager) load() error {
 		zInt.Uid = int64(uid)
 		uid++
 	}
+	if err := assignLinkAddresses(m.topo, nMap); err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
 	for k, n := range nMap {
-		log.Infof("Adding Node: %s:%s:%s", n.Name, n.Vendor, n.Type)
-		nn, err := node.New(m.topo.Name, n, m.kClient, m.rCfg, m.basePath, m.kubecfg)
+		m.logger().Infof("Adding Node: %s:%s:%s", n.Name, n.Vendor, n.Type)
+		nn, err := node.New(m.topo.Name, n, m.kClient, m.rCfg, m.basePath, m.kubecfg, m.context)
 		if err != nil {
 			return fmt.Errorf("failed to load topology: %w", err)
 		}
+		m.rewriteImages(nn.GetProto())
+		m.defaultImagePullSecret(nn.GetProto())
+		m.defaultTimeouts(nn.GetProto())
 		m.nodes[k] = nn
 	}
 	return nil
 }
 
+// rewriteImages rewrites the images of the node, its sidecars and init
+// containers with the image map of the manager.
+func (m *Manager) rewriteImages(pb *tpb.Node) {
+	if len(m.images) == 0 || pb.GetConfig() == nil {
+		return
+	}
+	if image, ok := m.images[pb.Config.Image]; ok {
+		m.logger().Infof("Rewriting image of node %q: %q to %q", pb.Name, pb.Config.Image, image)
+		pb.Config.Image = image
+	}
+	initImage := pb.Config.InitImage
+	if initImage == "" {
+		initImage = node.DefaultInitContainerImage
+	}
+	if image, ok := m.images[initImage]; ok {
+		m.logger().Infof("Rewriting init image of node %q: %q to %q", pb.Name, initImage, image)
+		pb.Config.InitImage = image
+	}
+	for _, sc := range append(append([]*tpb.Sidecar{}, pb.Config.Sidecars...), pb.Config.InitContainers...) {
+		if image, ok := m.images[sc.Image]; ok {
+			m.logger().Infof("Rewriting image of container %q of node %q: %q to %q", sc.Name, pb.Name, sc.Image, image)
+			sc.Image = image
+		}
+	}
+}
+
+// defaultImagePullSecret sets the image pull secret of the node to the one of
+// the topology, unless the node has its own.
+func (m *Manager) defaultImagePullSecret(pb *tpb.Node) {
+	secret := m.topo.GetImagePullSecret()
+	if secret == "" || pb.GetConfig() == nil || pb.Config.ImagePullSecret != "" {
+		return
+	}
+	pb.Config.ImagePullSecret = secret
+}
+
+// attachImagePullSecrets adds the image pull secrets of the nodes to the
+// default service account of the namespace of the topology, so the pods
+// created by vendor operators rather than KNE can pull their images too.
+func (m *Manager) attachImagePullSecrets(ctx context.Context) error {
+	secrets := map[string]bool{}
+	for _, n := range m.nodes {
+		if s := n.GetProto().GetConfig().GetImagePullSecret(); s != "" {
+			secrets[s] = true
+		}
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	sas := m.kClient.CoreV1().ServiceAccounts(m.topo.GetName())
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		sa, err := sas.Get(ctx, "default", metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			// The service account controller may not have created the
+			// account of a new namespace yet.
+			sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		case err != nil:
+			return err
+		}
+		missing := map[string]bool{}
+		for s := range secrets {
+			missing[s] = true
+		}
+		for _, ref := range sa.ImagePullSecrets {
+			delete(missing, ref.Name)
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		var names []string
+		for s := range missing {
+			names = append(names, s)
+		}
+		sort.Strings(names)
+		for _, s := range names {
+			sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: s})
+		}
+		m.logger().Infof("Adding image pull secrets %v to default service account", names)
+		if sa.ResourceVersion == "" {
+			_, err = sas.Create(ctx, sa, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Retry with the account created concurrently.
+				return apierrors.NewConflict(corev1.Resource("serviceaccounts"), sa.Name, err)
+			}
+			return err
+		}
+		_, err = sas.Update(ctx, sa, metav1.UpdateOptions{})
+		return err
+	})
+}
+
 // setLinkPeer finds the peer pod name and peer interface name for a given interface.
 func setLinkPeer(nodeName string, podName string, link *topologyv1.Link, peerSpecs []*topologyv1.Topology) error {
 	for _, peerSpec := range peerSpecs {
@@ -322,13 +658,13 @@ func (m *Manager) topologySpecs(ctx context.Context) ([]*topologyv1.Topology, er
 
 	// get topology specs from all nodes
 	for _, n := range m.nodes {
-		log.Infof("Getting topology specs for node %s", n.Name())
+		m.logger().Infof("Getting topology specs for node %s", n.Name())
 		specs, err := n.TopologySpecs(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("could not fetch topology specs for node %s: %v", n.Name(), err)
 		}
 
-		log.Tracef("Topology specs for node %s: %+v", n.Name(), specs)
+		m.logger().Tracef("Topology specs for node %s: %+v", n.Name(), specs)
 		nodeSpecs[n.Name()] = specs
 	}
 
@@ -346,6 +682,12 @@ func (m *Manager) topologySpecs(ctx context.Context) ([]*topologyv1.Topology, er
 					return nil, err
 				}
 			}
+			// The label allows selecting the resources of the topology, like
+			// the label of the node pods.
+			if spec.ObjectMeta.Labels == nil {
+				spec.ObjectMeta.Labels = map[string]string{}
+			}
+			spec.ObjectMeta.Labels["topo"] = m.topo.Name
 			topos = append(topos, spec)
 		}
 	}
@@ -353,113 +695,364 @@ func (m *Manager) topologySpecs(ctx context.Context) ([]*topologyv1.Topology, er
 	return topos, nil
 }
 
-// push deploys the topology to the cluster.
-func (m *Manager) push(ctx context.Context) error {
+// push deploys the topology to the cluster, reporting the nodes created to pt.
+func (m *Manager) push(ctx context.Context, pt *progressTracker) error {
+	if err := m.createNamespace(ctx); err != nil {
+		return err
+	}
+
+	if err := m.attachImagePullSecrets(ctx); err != nil {
+		return fmt.Errorf("failed to attach image pull secrets: %w", err)
+	}
+
+	if err := m.createMeshnetTopologies(ctx); err != nil {
+		return err
+	}
+
+	m.logger().Infof("Creating Node Pods")
+	if err := m.forEachNode(m.nodeNames(), func(name string, n node.Node) error {
+		start := time.Now()
+		cctx := ctx
+		if boot := seconds(n.GetProto().GetTimeouts().GetBoot()); boot > 0 {
+			var cancel context.CancelFunc
+			cctx, cancel = context.WithTimeout(ctx, boot)
+			defer cancel()
+		}
+		if err := n.Create(cctx); err != nil {
+			metrics.Failed(metrics.OpCreateNode, err)
+			pt.report(ctx, n, node.StatusFailed, err)
+			return fmt.Errorf("failed to create node %s: %w", name, err)
+		}
+		pt.report(ctx, n, node.StatusCreating, nil)
+		metrics.Since(metrics.NodeCreateDuration.WithLabelValues(n.GetProto().GetVendor().String()), start)
+		m.logger().WithField("node", name).Infof("Node %q resource created", name)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return m.forEachNode(m.nodeNames(), func(name string, n node.Node) error {
+		err := m.GenerateSelfSigned(ctx, name)
+		switch {
+		default:
+			return fmt.Errorf("failed to generate cert for node %s: %w", name, err)
+		case err == nil, status.Code(err) == codes.Unimplemented:
+		}
+		return nil
+	})
+}
+
+// createNamespace creates the namespace of the topology if it does not exist.
+func (m *Manager) createNamespace(ctx context.Context) error {
 	if _, err := m.kClient.CoreV1().Namespaces().Get(ctx, m.topo.Name, metav1.GetOptions{}); err != nil {
-		log.Infof("Creating namespace for topology: %q", m.topo.Name)
+		m.logger().Infof("Creating namespace for topology: %q", m.topo.Name)
 		ns := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: m.topo.Name,
+				Labels: map[string]string{
+					"topo": m.topo.Name,
+				},
 			},
 		}
 		sNs, err := m.kClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 		if err != nil {
 			return err
 		}
-		log.Infof("Server Namespace: %+v", sNs)
+		m.logger().Infof("Server Namespace: %+v", sNs)
 	}
+	return nil
+}
 
-	if err := m.createMeshnetTopologies(ctx); err != nil {
-		return err
+// nodeNames returns the sorted names of the nodes of the topology.
+func (m *Manager) nodeNames() []string {
+	names := make([]string, 0, len(m.nodes))
+	for name := range m.nodes {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	log.Infof("Creating Node Pods")
-	for k, n := range m.nodes {
-		if err := n.Create(ctx); err != nil {
-			return err
-		}
-		log.Infof("Node %q resource created", k)
+// forEachNode calls fn for each of the named nodes, running at most
+// m.workers calls concurrently. All calls are made even if some fail, the
+// errors of the failed calls are returned together.
+func (m *Manager) forEachNode(names []string, fn func(name string, n node.Node) error) error {
+	return m.parallelize(len(names), func(i int) error {
+		return fn(names[i], m.nodes[names[i]])
+	})
+}
+
+// parallelize calls fn for 0 <= i < n, running at most m.workers calls
+// concurrently. All calls are made even if some fail, the errors of the
+// failed calls are returned together.
+func (m *Manager) parallelize(n int, fn func(i int) error) error {
+	workers := m.workers
+	if workers <= 0 {
+		workers = defaultWorkers
 	}
-	for _, n := range m.nodes {
-		err := m.GenerateSelfSigned(ctx, n.Name())
-		switch {
-		default:
-			return fmt.Errorf("failed to generate cert for node %s: %w", n.Name(), err)
-		case err == nil, status.Code(err) == codes.Unimplemented:
-		}
+	return parallel(workers, n, fn)
+}
+
+// parallel calls fn for 0 <= i < n, running at most workers calls
+// concurrently, and returns the errors of the failed calls together.
+func parallel(workers, n int, fn func(i int) error) error {
+	sem := make(chan struct{}, workers)
+	var mu sync.Mutex
+	var errs errlist.List
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(i); err != nil {
+				mu.Lock()
+				errs.Add(err)
+				mu.Unlock()
+			}
+		}(i)
 	}
-	return nil
+	wg.Wait()
+	return errs.Err()
 }
 
 // createMeshnetTopologies creates meshnet resources for all available nodes.
+// There is no batch create in the API, the resources are created with
+// concurrent requests instead, limited by the number of workers and the
+// client side rate limit.
 func (m *Manager) createMeshnetTopologies(ctx context.Context) error {
-	log.Infof("Getting topology specs for namespace %s", m.topo.Name)
+	m.logger().Infof("Getting topology specs for namespace %s", m.topo.Name)
 	topologies, err := m.topologySpecs(ctx)
 	if err != nil {
 		return fmt.Errorf("could not get meshnet topologies: %v", err)
 	}
-	log.Tracef("Got topology specs for namespace %s: %+v", m.topo.Name, topologies)
-	for _, t := range topologies {
-		log.Infof("Creating topology for meshnet node %s", t.ObjectMeta.Name)
+	m.logger().Tracef("Got topology specs for namespace %s: %+v", m.topo.Name, topologies)
+	return m.parallelize(len(topologies), func(i int) error {
+		t := topologies[i]
+		m.logger().WithField("node", t.ObjectMeta.Name).Infof("Creating topology for meshnet node %s", t.ObjectMeta.Name)
 		sT, err := m.tClient.Topology(m.topo.Name).Create(ctx, t, metav1.CreateOptions{})
 		if err != nil {
 			return fmt.Errorf("could not create topology for meshnet node %s: %v", t.ObjectMeta.Name, err)
 		}
-		log.Infof("Meshnet Node:\n%+v\n", sT)
-	}
-	return nil
+		m.logger().WithField("node", t.ObjectMeta.Name).Infof("Meshnet Node:\n%+v\n", sT)
+		return nil
+	})
 }
 
 // deleteMeshnetTopologies deletes meshnet resources for all available nodes.
 func (m *Manager) deleteMeshnetTopologies(ctx context.Context) error {
 	nodes, err := m.topologyResources(ctx)
 	if err == nil {
-		for _, n := range nodes {
-			if err := m.tClient.Topology(m.topo.Name).Delete(ctx, n.ObjectMeta.Name, metav1.DeleteOptions{}); err != nil {
-				log.Warnf("Error meshnet node %q: %v", n.ObjectMeta.Name, err)
+		m.parallelize(len(nodes), func(i int) error {
+			if err := m.tClient.Topology(m.topo.Name).Delete(ctx, nodes[i].ObjectMeta.Name, metav1.DeleteOptions{}); err != nil {
+				m.logger().Warnf("Error meshnet node %q: %v", nodes[i].ObjectMeta.Name, err)
 			}
-		}
+			return nil
+		})
 	} else {
 		// no need to return warning as deleting meshnet namespace shall delete the resources too
-		log.Warnf("Error getting meshnet nodes: %v", err)
+		m.logger().Warnf("Error getting meshnet nodes: %v", err)
 	}
 
 	return nil
 }
 
-// checkNodeStatus reports node status, ignores for unimplemented nodes.
-func (m *Manager) checkNodeStatus(ctx context.Context, timeout time.Duration) error {
-	foundAll := false
-	processed := make(map[string]bool)
+// checkNodeStatus reports node status, ignores for unimplemented nodes. The
+// progress of the nodes is reported to pt. The status of the nodes is checked
+// concurrently and the errors of all failed nodes are returned.
+func (m *Manager) checkNodeStatus(ctx context.Context, timeout time.Duration, pt *progressTracker) error {
+	var mu sync.Mutex
+	pending := m.nodeNames()
+	states := map[string]node.Status{}
 
 	// Check until end state or timeout sec expired
 	start := time.Now()
-	for (timeout == 0 || time.Since(start) < timeout) && !foundAll {
-		foundAll = true
-		for name, n := range m.nodes {
-			if _, ok := processed[name]; ok {
-				continue
-			}
-
+	for (timeout == 0 || time.Since(start) < timeout) && len(pending) > 0 {
+		var notReady []string
+		if err := m.forEachNode(pending, func(name string, n node.Node) error {
 			phase, err := n.Status(ctx)
-			if err != nil || phase == node.StatusFailed {
-				return fmt.Errorf("Node %q: Status %s Reason %v", name, phase, err)
+			mu.Lock()
+			changed := phase != states[name]
+			states[name] = phase
+			mu.Unlock()
+			if changed {
+				m.recordNodeState(ctx, name, phase, err)
 			}
-			if phase == node.StatusRunning {
-				log.Infof("Node %q: Status %s", name, phase)
-				processed[name] = true
-			} else {
-				foundAll = false
+			pt.report(ctx, n, phase, err)
+			if err != nil {
+				return fmt.Errorf("Node %q: Status %s Reason %w", name, phase, err)
+			}
+			if phase == node.StatusFailed {
+				return fmt.Errorf("Node %q: Status %s", name, phase)
+			}
+			if phase == node.StatusReady {
+				m.logger().WithField("node", name).Infof("Node %q: Status %s", name, phase)
+				return nil
+			}
+			if boot := seconds(n.GetProto().GetTimeouts().GetBoot()); boot > 0 && time.Since(start) > boot {
+				err := fmt.Errorf("Node %q: not ready within %s, Status %s", name, boot, phase)
+				m.recordNodeState(ctx, name, node.StatusFailed, err)
+				pt.report(ctx, n, node.StatusFailed, err)
+				return err
 			}
+			mu.Lock()
+			notReady = append(notReady, name)
+			mu.Unlock()
+			return nil
+		}); err != nil {
+			return err
 		}
+		sort.Strings(notReady)
+		pending = notReady
 		time.Sleep(100 * time.Millisecond)
 	}
-	if !foundAll {
-		log.Warnf("Failed to determine status of some node resources in %d sec", timeout)
+	if len(pending) > 0 {
+		m.logger().Warnf("Failed to determine status of some node resources in %d sec", timeout)
 	}
 	return nil
 }
 
+// recordNodeState records the lifecycle state of the node in the status of its
+// meshnet Topology resource, making it visible to cluster tooling. The status
+// is also written by meshnet, so only the state and reason are patched.
+// Recording is best effort, failures are only logged.
+func (m *Manager) recordNodeState(ctx context.Context, name string, state node.Status, reason error) {
+	var msg string
+	if reason != nil {
+		msg = reason.Error()
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]string{
+			"state":  string(state),
+			"reason": msg,
+		},
+	})
+	if err == nil {
+		_, err = m.tClient.Topology(m.topo.GetName()).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	}
+	if err != nil {
+		m.logger().WithField("node", name).Warnf("Failed to record state %s of node %q: %v", state, name, err)
+	}
+}
+
+// recordConfigPush records the result of the last config push to the node
+// in the status of its meshnet Topology resource, where it outlives the
+// events of the push. Recording is best effort, failures are only logged.
+func (m *Manager) recordConfigPush(ctx context.Context, name string, pushErr error) {
+	result, msg := ConfigPushDone, ""
+	if pushErr != nil {
+		result, msg = ConfigPushFailed, pushErr.Error()
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]string{
+			"config_push":        result,
+			"config_push_reason": msg,
+		},
+	})
+	if err == nil {
+		_, err = m.tClient.Topology(m.topo.GetName()).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	}
+	if err != nil {
+		m.logger().WithField("node", name).Warnf("Failed to record config push of node %q: %v", name, err)
+	}
+}
+
+// recordLinkStates records the operational state of the links of the nodes in
+// the status of their meshnet Topology resources, along with the number of
+// established and skipped links. Only these fields are patched as the status
+// is also written by meshnet. Recording is best effort, failures are only
+// logged.
+func (m *Manager) recordLinkStates(ctx context.Context) {
+	topos, err := m.topologyResources(ctx)
+	if err != nil {
+		m.logger().Warnf("Failed to record link states: %v", err)
+		return
+	}
+	links := linkStatuses(topos)
+	m.parallelize(len(topos), func(i int) error {
+		name := topos[i].Name
+		var established, skipped int
+		for _, l := range links[name] {
+			if l.State == topologyv1.LinkEstablished {
+				established++
+			} else {
+				skipped++
+			}
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"status": map[string]interface{}{
+				"links":             links[name],
+				"established_links": established,
+				"skipped_links":     skipped,
+			},
+		})
+		if err == nil {
+			_, err = m.tClient.Topology(m.topo.GetName()).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		}
+		if err != nil {
+			m.logger().WithField("node", name).Warnf("Failed to record link states of node %q: %v", name, err)
+		}
+		return nil
+	})
+}
+
+// linkStatuses returns the operational state of the links of the meshnet
+// Topology resources by resource name. A link is established once the pods of
+// both ends are running, i.e. have their source IP set, and neither end
+// skipped the other.
+func linkStatuses(topos []*topologyv1.Topology) map[string][]topologyv1.LinkStatus {
+	byName := map[string]*topologyv1.Topology{}
+	for _, t := range topos {
+		byName[t.Name] = t
+	}
+	skipped := func(t *topologyv1.Topology, peer string) bool {
+		for _, s := range t.Status.Skipped {
+			if s == peer {
+				return true
+			}
+		}
+		return false
+	}
+	links := map[string][]topologyv1.LinkStatus{}
+	for _, t := range topos {
+		for _, l := range t.Spec.Links {
+			state := topologyv1.LinkSkipped
+			if p, ok := byName[l.PeerPod]; ok && t.Status.SrcIP != "" && p.Status.SrcIP != "" && !skipped(t, p.Name) && !skipped(p, t.Name) {
+				state = topologyv1.LinkEstablished
+			}
+			links[t.Name] = append(links[t.Name], topologyv1.LinkStatus{
+				UID:       l.UID,
+				LocalIntf: l.LocalIntf,
+				PeerPod:   l.PeerPod,
+				PeerIntf:  l.PeerIntf,
+				State:     state,
+			})
+		}
+	}
+	return links
+}
+
+// nodeStatus returns the lifecycle state of the node. This is the state
+// reported by the node itself, unless config is being pushed to a ready node
+// which is only known from the state recorded in its meshnet Topology
+// resource.
+func (m *Manager) nodeStatus(ctx context.Context, n node.Node) (node.Status, error) {
+	s, err := n.Status(ctx)
+	if s != node.StatusReady {
+		return s, err
+	}
+	t, err := m.tClient.Topology(m.topo.GetName()).Get(ctx, n.Name(), metav1.GetOptions{})
+	if err != nil {
+		return s, nil
+	}
+	if rs := node.Status(t.Status.State); rs == node.StatusConfigPushing {
+		return rs, nil
+	}
+	return s, nil
+}
+
 type Resources struct {
 	Services   map[string][]*corev1.Service
 	Pods       map[string][]*corev1.Pod
@@ -518,16 +1111,55 @@ func (m *Manager) topologyResources(ctx context.Context) ([]*topologyv1.Topology
 
 // ConfigPush will push config to the provided node. If the node does
 // not fulfill ConfigPusher then status.Unimplemented error will be returned.
+// Failed pushes are retried as set by the timeouts of the node.
 func (m *Manager) ConfigPush(ctx context.Context, nodeName string, r io.Reader) error {
+	return m.ConfigPushStream(ctx, nodeName, r, nil)
+}
+
+// ConfigPushStream pushes config to the provided node as ConfigPush does,
+// writing the output of the device to w as it is received. The output is not
+// streamed if w is nil or the node does not fulfill ConfigPushStreamer.
+func (m *Manager) ConfigPushStream(ctx context.Context, nodeName string, r io.Reader, w io.Writer) error {
+	n, push, err := m.configPusher(nodeName, w)
+	if err != nil {
+		return err
+	}
+	m.recordNodeState(ctx, nodeName, node.StatusConfigPushing, nil)
+	start := time.Now()
+	if err := m.configPush(ctx, n, push, r); err != nil {
+		metrics.Failed(metrics.OpConfigPush, err)
+		m.recordNodeState(ctx, nodeName, node.StatusFailed, fmt.Errorf("config push failed: %w", err))
+		m.recordConfigPush(ctx, nodeName, err)
+		m.recordNodeEvent(ctx, nodeName, corev1.EventTypeWarning, EventConfigPushFailed, "Config push failed: %v", err)
+		return err
+	}
+	metrics.Since(metrics.ConfigPushDuration.WithLabelValues(n.GetProto().GetVendor().String()), start)
+	m.recordNodeState(ctx, nodeName, node.StatusReady, nil)
+	m.recordConfigPush(ctx, nodeName, nil)
+	m.recordNodeEvent(ctx, nodeName, corev1.EventTypeNormal, EventConfigPushed, "Config pushed")
+	return nil
+}
+
+// configPusher returns the node named nodeName and the function pushing config
+// to it, streaming the output of the device to w if w is not nil.
+func (m *Manager) configPusher(nodeName string, w io.Writer) (node.Node, func(context.Context, io.Reader) error, error) {
 	n, ok := m.nodes[nodeName]
 	if !ok {
-		return fmt.Errorf("node %q not found", nodeName)
+		return nil, nil, fmt.Errorf("node %q not found", nodeName)
 	}
 	cp, ok := n.(node.ConfigPusher)
 	if !ok {
-		return status.Errorf(codes.Unimplemented, "node %q does not implement ConfigPusher interface", nodeName)
+		return nil, nil, status.Errorf(codes.Unimplemented, "node %q does not implement ConfigPusher interface, %s nodes do not support config pushes, streamed or async", nodeName, vendorName(n.GetProto()))
+	}
+	if s, ok := n.(node.ConfigPushStreamer); ok && w != nil {
+		return n, func(ctx context.Context, r io.Reader) error {
+			return s.ConfigPushStream(ctx, r, w)
+		}, nil
+	}
+	if w != nil {
+		m.logger().WithField("node", nodeName).Warnf("Node %q does not stream config push output, pushing config without it", nodeName)
 	}
-	return cp.ConfigPush(ctx, r)
+	return n, cp.ConfigPush, nil
 }
 
 // ResetCfg will reset the config for the provided node. If the node does
@@ -541,7 +1173,183 @@ func (m *Manager) ResetCfg(ctx context.Context, nodeName string) error {
 	if !ok {
 		return status.Errorf(codes.Unimplemented, "node %q does not implement Resetter interface", nodeName)
 	}
-	return r.ResetCfg(ctx)
+	m.recordNodeState(ctx, nodeName, node.StatusConfigPushing, nil)
+	if err := r.ResetCfg(ctx); err != nil {
+		metrics.Failed(metrics.OpResetConfig, err)
+		m.recordNodeState(ctx, nodeName, node.StatusFailed, fmt.Errorf("config reset failed: %w", err))
+		m.recordNodeEvent(ctx, nodeName, corev1.EventTypeWarning, EventResetFailed, "Config reset failed: %v", err)
+		return err
+	}
+	m.recordNodeState(ctx, nodeName, node.StatusReady, nil)
+	m.recordNodeEvent(ctx, nodeName, corev1.EventTypeNormal, EventConfigReset, "Config reset to vendor default")
+	return nil
+}
+
+// logStream identifies a single container log stream of a node.
+type logStream struct {
+	prefix    string
+	pod       string
+	container string
+}
+
+// Logs streams the logs of the NOS container of the provided nodes to w with
+// each line prefixed by the node name, or of all their containers if
+// allContainers is true. For nodes with multiple pods or containers streamed
+// the prefix also includes the pod and container name. If no nodes are
+// provided the logs of all nodes in the topology are streamed. If follow is
+// true the logs are streamed until ctx is canceled.
+func (m *Manager) Logs(ctx context.Context, w io.Writer, follow, allContainers bool, nodeNames ...string) error {
+	if len(nodeNames) == 0 {
+		for name := range m.nodes {
+			nodeNames = append(nodeNames, name)
+		}
+		sort.Strings(nodeNames)
+	}
+	var streams []logStream
+	for _, name := range nodeNames {
+		n, ok := m.nodes[name]
+		if !ok {
+			return fmt.Errorf("node %q not found", name)
+		}
+		pods, err := n.Pods(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get pods for node %q: %w", name, err)
+		}
+		var ps []*corev1.Pod
+		for _, p := range pods {
+			if p != nil {
+				ps = append(ps, p)
+			}
+		}
+		for _, p := range ps {
+			containers := []string{node.ContainerName(p, name)}
+			if allContainers {
+				containers = nil
+				for _, c := range p.Spec.Containers {
+					containers = append(containers, c.Name)
+				}
+			}
+			for _, c := range containers {
+				prefix := name
+				if len(ps) > 1 {
+					prefix += "/" + p.Name
+				}
+				if len(containers) > 1 {
+					prefix += "/" + c
+				}
+				streams = append(streams, logStream{prefix: prefix, pod: p.Name, container: c})
+			}
+		}
+	}
+	lw := &lineWriter{w: w}
+	var mu sync.Mutex
+	var errs errlist.List
+	var wg sync.WaitGroup
+	for _, s := range streams {
+		wg.Add(1)
+		go func(s logStream) {
+			defer wg.Done()
+			if err := m.streamLogs(ctx, lw, s, follow); err != nil {
+				mu.Lock()
+				errs.Add(err)
+				mu.Unlock()
+			}
+		}(s)
+	}
+	wg.Wait()
+	return errs.Err()
+}
+
+// streamLogs copies the logs of a single container to lw.
+func (m *Manager) streamLogs(ctx context.Context, lw *lineWriter, s logStream, follow bool) error {
+	opts := &corev1.PodLogOptions{
+		Container: s.container,
+		Follow:    follow,
+	}
+	rc, err := m.kClient.CoreV1().Pods(m.topo.Name).GetLogs(s.pod, opts).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to stream logs for %q: %w", s.prefix, err)
+	}
+	defer rc.Close()
+	// Lines are read without a length limit as NOS logs can contain very
+	// long lines.
+	br := bufio.NewReader(rc)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if err := lw.writeLine(s.prefix, strings.TrimSuffix(line, "\n")); err != nil {
+				return err
+			}
+		}
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil && ctx.Err() == nil:
+			return fmt.Errorf("failed to read logs for %q: %w", s.prefix, err)
+		case err != nil:
+			return nil
+		}
+	}
+}
+
+// lineWriter serializes prefixed lines from multiple log streams to w.
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lineWriter) writeLine(prefix, line string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := fmt.Fprintf(l.w, "[%s] %s\n", prefix, line)
+	return err
+}
+
+// Console opens an interactive session on the provided node by running the
+// entry command of the node in its pod.
+func (m *Manager) Console(ctx context.Context, nodeName string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	n, ok := m.nodes[nodeName]
+	if !ok {
+		return fmt.Errorf("node %q not found", nodeName)
+	}
+	container, cmd := entryCommand(n.GetProto().GetConfig().GetEntryCommand())
+	if len(cmd) == 0 {
+		return fmt.Errorf("node %q has no entry command", nodeName)
+	}
+	if container == "" {
+		return n.Exec(ctx, cmd, stdin, stdout, stderr)
+	}
+	ce, ok := n.(node.ContainerExecer)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "node %q does not implement ContainerExecer interface", nodeName)
+	}
+	return ce.ExecContainer(ctx, container, cmd, stdin, stdout, stderr)
+}
+
+// entryCommand returns the container and the command to run in the node pod
+// for the provided entry command. Entry commands are typically of the form
+// "kubectl exec -it <node> [-c <container>] -- <cmd>", in which case the
+// container, if any, and <cmd> are returned. Serial consoles of VM based nodes
+// are reached through their entry command as well, e.g. a telnet to the serial
+// port exposed in the pod.
+func entryCommand(s string) (string, []string) {
+	before, after, ok := strings.Cut(s, " -- ")
+	if !ok {
+		return "", strings.Fields(s)
+	}
+	var container string
+	args := strings.Fields(before)
+	for i, a := range args {
+		switch {
+		case (a == "-c" || a == "--container") && i+1 < len(args):
+			container = args[i+1]
+		case strings.HasPrefix(a, "-c="):
+			container = strings.TrimPrefix(a, "-c=")
+		case strings.HasPrefix(a, "--container="):
+			container = strings.TrimPrefix(a, "--container=")
+		}
+	}
+	return container, strings.Fields(after)
 }
 
 // GenerateSelfSigned will create self signed certs on the provided node.
@@ -553,14 +1361,57 @@ func (m *Manager) GenerateSelfSigned(ctx context.Context, nodeName string) error
 		return fmt.Errorf("node %q not found", nodeName)
 	}
 	if n.GetProto().GetConfig().GetCert() == nil {
-		log.Debugf("No cert info for %q, skipping cert generation", nodeName)
+		m.logger().WithField("node", nodeName).Debugf("No cert info for %q, skipping cert generation", nodeName)
 		return nil
 	}
 	c, ok := n.(node.Certer)
 	if !ok {
 		return status.Errorf(codes.Unimplemented, "node %q does not implement Certer interface", nodeName)
 	}
-	return c.GenerateSelfSigned(ctx)
+	if err := c.GenerateSelfSigned(ctx); err != nil {
+		metrics.Failed(metrics.OpGenerateCert, err)
+		m.recordNodeEvent(ctx, nodeName, corev1.EventTypeWarning, EventCertFailed, "Self signed cert generation failed: %v", err)
+		return err
+	}
+	m.recordNodeEvent(ctx, nodeName, corev1.EventTypeNormal, EventCertInstalled, "Self signed cert %q installed", n.GetProto().GetConfig().GetCert().GetSelfSigned().GetCertName())
+	return nil
+}
+
+// RotateCert re-generates and installs the cert of the provided running node.
+// Nodes fulfilling CertRotator rotate the cert in place, the cert of other
+// nodes fulfilling Certer is replaced by generating a new self signed cert. If
+// the node does not have cert info then it is a noop. If the node fulfills
+// neither then status.Unimplemented error will be returned.
+//
+// Certs are not rotated over gNOI, so only SR Linux nodes rotate their certs.
+// The generation fallback must be safe to repeat on a running node: cEOS and
+// lemming nodes do not generate certs and return status.Unimplemented each
+// time, cptx and XRd nodes do not fulfill Certer.
+func (m *Manager) RotateCert(ctx context.Context, nodeName string) error {
+	n, ok := m.nodes[nodeName]
+	if !ok {
+		return fmt.Errorf("node %q not found", nodeName)
+	}
+	if n.GetProto().GetConfig().GetCert() == nil {
+		m.logger().WithField("node", nodeName).Debugf("No cert info for %q, skipping cert rotation", nodeName)
+		return nil
+	}
+	var rotate func(context.Context) error
+	switch c := n.(type) {
+	case node.CertRotator:
+		rotate = c.RotateCert
+	case node.Certer:
+		rotate = c.GenerateSelfSigned
+	default:
+		return status.Errorf(codes.Unimplemented, "node %q does not implement CertRotator or Certer interface, %s nodes do not support cert rotation", nodeName, vendorName(n.GetProto()))
+	}
+	if err := rotate(ctx); err != nil {
+		metrics.Failed(metrics.OpRotateCert, err)
+		m.recordNodeEvent(ctx, nodeName, corev1.EventTypeWarning, EventCertFailed, "Cert rotation failed: %v", err)
+		return err
+	}
+	m.recordNodeEvent(ctx, nodeName, corev1.EventTypeNormal, EventCertRotated, "Cert %q rotated", n.GetProto().GetConfig().GetCert().GetSelfSigned().GetCertName())
+	return nil
 }
 
 // populateServiceMap modifies m to contain the full service info.
@@ -619,35 +1470,33 @@ func (s *stateMap) topologyState() cpb.TopologyState {
 	switch {
 	default:
 		return cpb.TopologyState_TOPOLOGY_STATE_UNSPECIFIED
-	case counts[node.StatusRunning] == s.size():
+	case counts[node.StatusReady] == s.size():
 		return cpb.TopologyState_TOPOLOGY_STATE_RUNNING
 	case counts[node.StatusFailed] > 0:
 		return cpb.TopologyState_TOPOLOGY_STATE_ERROR
-	case counts[node.StatusPending] > 0:
+	case counts[node.StatusCreating] > 0, counts[node.StatusBooting] > 0, counts[node.StatusConfigPushing] > 0:
 		return cpb.TopologyState_TOPOLOGY_STATE_CREATING
 	}
 }
 
-// Load loads a Topology from path.
-func Load(path string) (*tpb.Topology, error) {
-	b, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// nodeState returns the proto representation of the node status.
+func nodeState(s node.Status) cpb.NodeState {
+	switch s {
+	case node.StatusCreating:
+		return cpb.NodeState_NODE_STATE_CREATING
+	case node.StatusBooting:
+		return cpb.NodeState_NODE_STATE_BOOTING
+	case node.StatusConfigPushing:
+		return cpb.NodeState_NODE_STATE_CONFIG_PUSHING
+	case node.StatusReady:
+		return cpb.NodeState_NODE_STATE_READY
+	case node.StatusFailed:
+		return cpb.NodeState_NODE_STATE_FAILED
 	}
-	t := &tpb.Topology{}
-	switch {
-	case strings.HasSuffix(path, ".yaml"), strings.HasSuffix(path, ".yml"):
-		jsonBytes, err := yaml.YAMLToJSON(b)
-		if err != nil {
-			return nil, fmt.Errorf("could not parse yaml: %v", err)
-		}
-		if err := protojsonUnmarshaller.Unmarshal(jsonBytes, t); err != nil {
-			return nil, fmt.Errorf("could not parse json: %v", err)
-		}
-	default:
-		if err := prototext.Unmarshal(b, t); err != nil {
-			return nil, err
-		}
-	}
-	return t, nil
+	return cpb.NodeState_NODE_STATE_UNSPECIFIED
+}
+
+// Load loads a Topology from path, in the format of its extension.
+func Load(path string) (*tpb.Topology, error) {
+	return topofile.Load(path)
 }
@@ -19,6 +19,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,11 +33,16 @@ import (
 	topologyv1 "github.com/openconfig/kne/api/types/v1beta1"
 	cpb "github.com/openconfig/kne/proto/controller"
 	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/metrics"
 	"github.com/openconfig/kne/topo/node"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kfake "k8s.io/client-go/kubernetes/fake"
@@ -60,6 +69,13 @@ func TestLoad(t *testing.T) {
 		desc:    "yaml invalid",
 		path:    "testdata/invalid_topo.yaml",
 		wantErr: true,
+	}, {
+		desc: "json",
+		path: "testdata/valid_topo.json",
+	}, {
+		desc:    "json invalid",
+		path:    "testdata/invalid_topo.json",
+		wantErr: true,
 	}}
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
@@ -127,6 +143,21 @@ func (c *certable) GenerateSelfSigned(_ context.Context) error {
 	return nil
 }
 
+// rotatable is a node rotating its certs in place.
+type rotatable struct {
+	*certable
+	rErr    string
+	rotated bool
+}
+
+func (r *rotatable) RotateCert(_ context.Context) error {
+	r.rotated = true
+	if r.rErr != "" {
+		return fmt.Errorf(r.rErr)
+	}
+	return nil
+}
+
 type notCertable struct {
 	*node.Impl
 	proto *tpb.Node
@@ -370,12 +401,18 @@ func TestCreate(t *testing.T) {
 		t.Fatalf("cannot create fake topology clientset: %v", err)
 	}
 	kf := kfake.NewSimpleClientset()
-	kf.PrependReactor("get", "pods", func(action ktest.Action) (bool, runtime.Object, error) {
-		gAction, ok := action.(ktest.GetAction)
+	// The pods are watched by the manager, so they are stored in the tracker
+	// with their status set when created.
+	kf.PrependReactor("create", "pods", func(action ktest.Action) (bool, runtime.Object, error) {
+		cAction, ok := action.(ktest.CreateAction)
+		if !ok {
+			return false, nil, nil
+		}
+		p, ok := cAction.GetObject().(*corev1.Pod)
 		if !ok {
 			return false, nil, nil
 		}
-		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: gAction.GetName()}}
+		p = p.DeepCopy()
 		switch p.Name {
 		default:
 			p.Status.Phase = corev1.PodRunning
@@ -385,6 +422,9 @@ func TestCreate(t *testing.T) {
 		case "hanging":
 			p.Status.Phase = corev1.PodPending
 		}
+		if err := kf.Tracker().Create(cAction.GetResource(), p, cAction.GetNamespace()); err != nil {
+			return true, nil, err
+		}
 		return true, p, nil
 	})
 	opts := []Option{
@@ -493,6 +533,561 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestForEachNode(t *testing.T) {
+	nodes := map[string]node.Node{}
+	var names []string
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("r%d", i)
+		nodes[name] = &node.Impl{Proto: &tpb.Node{Name: name}}
+		names = append(names, name)
+	}
+	tests := []struct {
+		desc     string
+		workers  int
+		fail     map[string]bool
+		wantMax  int
+		wantErrs []string
+	}{{
+		desc:    "bounded",
+		workers: 3,
+		wantMax: 3,
+	}, {
+		desc:    "default",
+		wantMax: defaultWorkers,
+	}, {
+		desc:     "errors aggregated",
+		workers:  5,
+		fail:     map[string]bool{"r3": true, "r17": true},
+		wantMax:  5,
+		wantErrs: []string{"r3 failed", "r17 failed"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := &Manager{topo: &tpb.Topology{Name: "test"}, nodes: nodes, workers: tt.workers}
+			var mu sync.Mutex
+			var running, maxRunning int
+			called := map[string]bool{}
+			err := m.forEachNode(names, func(name string, n node.Node) error {
+				mu.Lock()
+				called[name] = true
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+				if tt.fail[name] {
+					return fmt.Errorf("%s failed", n.Name())
+				}
+				return nil
+			})
+			if len(called) != len(names) {
+				t.Errorf("forEachNode() called %d nodes, want %d", len(called), len(names))
+			}
+			if maxRunning > tt.wantMax {
+				t.Errorf("forEachNode() ran %d concurrently, want at most %d", maxRunning, tt.wantMax)
+			}
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("forEachNode() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("forEachNode() got no error, want %v", tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("forEachNode() got error %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	tests := []struct {
+		desc      string
+		qps       float32
+		burst     int
+		wantQPS   float32
+		wantBurst int
+	}{{
+		desc: "client defaults",
+	}, {
+		desc:      "qps and burst",
+		qps:       50,
+		burst:     100,
+		wantQPS:   50,
+		wantBurst: 100,
+	}, {
+		desc:      "qps only",
+		qps:       20,
+		wantQPS:   20,
+		wantBurst: rest.DefaultBurst,
+	}, {
+		desc:      "burst only",
+		burst:     30,
+		wantQPS:   rest.DefaultQPS,
+		wantBurst: 30,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rCfg := &rest.Config{}
+			m, err := New(&tpb.Topology{Name: "test"},
+				WithClusterConfig(rCfg),
+				WithKubeClient(kfake.NewSimpleClientset()),
+				WithTopoClient(tf),
+				WithRateLimit(tt.qps, tt.burst),
+			)
+			if err != nil {
+				t.Fatalf("New() failed to create new topology manager: %v", err)
+			}
+			if m.rCfg.QPS != tt.wantQPS || m.rCfg.Burst != tt.wantBurst {
+				t.Errorf("New() got QPS %v burst %d, want QPS %v burst %d", m.rCfg.QPS, m.rCfg.Burst, tt.wantQPS, tt.wantBurst)
+			}
+			if rCfg.QPS != 0 || rCfg.Burst != 0 || rCfg.RateLimiter != nil {
+				t.Errorf("New() modified the passed cluster config")
+			}
+			switch {
+			case tt.qps == 0 && tt.burst == 0:
+				if m.rCfg.RateLimiter != nil {
+					t.Errorf("New() unexpectedly set a rate limiter")
+				}
+			case m.rCfg.RateLimiter == nil:
+				t.Errorf("New() did not set a shared rate limiter")
+			case m.rCfg.RateLimiter.QPS() != tt.wantQPS:
+				t.Errorf("New() got rate limiter QPS %v, want %v", m.rCfg.RateLimiter.QPS(), tt.wantQPS)
+			}
+		})
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	node.Register(tpb.Node_Type(1010), NewConfigurable)
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	kubecfg := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubecfg, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: a
+  cluster:
+    server: https://a.example.com
+- name: b
+  cluster:
+    server: https://b.example.com
+contexts:
+- name: a
+  context:
+    cluster: a
+- name: b
+  context:
+    cluster: b
+current-context: a
+`), 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	tests := []struct {
+		desc     string
+		context  string
+		wantHost string
+		wantErr  string
+	}{{
+		desc:     "context",
+		context:  "b",
+		wantHost: "https://b.example.com",
+	}, {
+		desc:    "missing context",
+		context: "c",
+		wantErr: `context "c" does not exist`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m, err := New(&tpb.Topology{
+				Name:  "test",
+				Nodes: []*tpb.Node{{Name: "r1", Type: tpb.Node_Type(1010)}},
+			},
+				WithKubecfg(kubecfg),
+				WithContext(tt.context),
+				WithKubeClient(kfake.NewSimpleClientset()),
+				WithTopoClient(tf),
+			)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("unexpected error: %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if m.rCfg.Host != tt.wantHost {
+				t.Errorf("New() got host %q, want %q", m.rCfg.Host, tt.wantHost)
+			}
+			if got := m.nodes["r1"].(*configurable).KubeContext; got != tt.context {
+				t.Errorf("New() got node context %q, want %q", got, tt.context)
+			}
+		})
+	}
+}
+
+func TestWithImageMap(t *testing.T) {
+	node.Register(tpb.Node_Type(1009), NewConfigurable)
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	images := map[string]string{
+		"r1:latest":                    "local/r1:v1",
+		node.DefaultInitContainerImage: "local/init-wait:ga",
+		"init:custom":                  "local/init:custom",
+		"exporter:latest":              "local/exporter:v1",
+	}
+	tests := []struct {
+		desc          string
+		images        map[string]string
+		config        *tpb.Config
+		wantImage     string
+		wantInitImage string
+		wantSidecar   string
+	}{{
+		desc:          "no image map",
+		config:        &tpb.Config{Image: "r1:latest"},
+		wantImage:     "r1:latest",
+		wantInitImage: "",
+	}, {
+		desc:          "default init image",
+		images:        images,
+		config:        &tpb.Config{Image: "r1:latest"},
+		wantImage:     "local/r1:v1",
+		wantInitImage: "local/init-wait:ga",
+	}, {
+		desc:          "custom init image",
+		images:        images,
+		config:        &tpb.Config{Image: "r1:latest", InitImage: "init:custom"},
+		wantImage:     "local/r1:v1",
+		wantInitImage: "local/init:custom",
+	}, {
+		desc:          "not mapped",
+		images:        images,
+		config:        &tpb.Config{Image: "r2:latest", InitImage: "init:other"},
+		wantImage:     "r2:latest",
+		wantInitImage: "init:other",
+	}, {
+		desc:          "sidecar",
+		images:        images,
+		config:        &tpb.Config{Image: "r1:latest", Sidecars: []*tpb.Sidecar{{Name: "exporter", Image: "exporter:latest"}}},
+		wantImage:     "local/r1:v1",
+		wantInitImage: "local/init-wait:ga",
+		wantSidecar:   "local/exporter:v1",
+	}, {
+		desc:          "init container",
+		images:        images,
+		config:        &tpb.Config{Image: "r1:latest", InitContainers: []*tpb.Sidecar{{Name: "prepare", Image: "exporter:latest"}}},
+		wantImage:     "local/r1:v1",
+		wantInitImage: "local/init-wait:ga",
+		wantSidecar:   "local/exporter:v1",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m, err := New(&tpb.Topology{
+				Name:  "test",
+				Nodes: []*tpb.Node{{Name: "r1", Type: tpb.Node_Type(1009), Config: tt.config}},
+			},
+				WithClusterConfig(&rest.Config{}),
+				WithKubeClient(kfake.NewSimpleClientset()),
+				WithTopoClient(tf),
+				WithImageMap(tt.images),
+			)
+			if err != nil {
+				t.Fatalf("New() failed to create new topology manager: %v", err)
+			}
+			c := m.nodes["r1"].GetProto().GetConfig()
+			if c.GetImage() != tt.wantImage || c.GetInitImage() != tt.wantInitImage {
+				t.Errorf("New() got image %q init image %q, want image %q init image %q", c.GetImage(), c.GetInitImage(), tt.wantImage, tt.wantInitImage)
+			}
+			if got := append(c.GetSidecars(), c.GetInitContainers()...); tt.wantSidecar != "" && (len(got) != 1 || got[0].GetImage() != tt.wantSidecar) {
+				t.Errorf("New() got containers %v, want image %q", got, tt.wantSidecar)
+			}
+		})
+	}
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	node.Register(tpb.Node_Type(1012), NewConfigurable)
+	tests := []struct {
+		desc     string
+		secret   string
+		configs  map[string]*tpb.Config
+		sa       *corev1.ServiceAccount
+		wantPods map[string][]corev1.LocalObjectReference
+		wantSA   []corev1.LocalObjectReference
+		wantNoSA bool
+	}{{
+		desc:     "none",
+		configs:  map[string]*tpb.Config{"r1": {}},
+		wantPods: map[string][]corev1.LocalObjectReference{"r1": nil},
+		wantNoSA: true,
+	}, {
+		desc:    "topology default with node override",
+		secret:  "registry",
+		configs: map[string]*tpb.Config{"r1": {}, "r2": {ImagePullSecret: "vendor"}},
+		wantPods: map[string][]corev1.LocalObjectReference{
+			"r1": {{Name: "registry"}},
+			"r2": {{Name: "vendor"}},
+		},
+		wantSA: []corev1.LocalObjectReference{{Name: "registry"}, {Name: "vendor"}},
+	}, {
+		desc:    "existing service account",
+		configs: map[string]*tpb.Config{"r1": {ImagePullSecret: "vendor"}},
+		sa: &corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "test", ResourceVersion: "1"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "other"}, {Name: "vendor"}},
+		},
+		wantPods: map[string][]corev1.LocalObjectReference{"r1": {{Name: "vendor"}}},
+		wantSA:   []corev1.LocalObjectReference{{Name: "other"}, {Name: "vendor"}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tf, err := tfake.NewSimpleClientset()
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			kf := kfake.NewSimpleClientset()
+			if tt.sa != nil {
+				kf = kfake.NewSimpleClientset(tt.sa)
+			}
+			tp := &tpb.Topology{Name: "test", ImagePullSecret: tt.secret}
+			for name, c := range tt.configs {
+				tp.Nodes = append(tp.Nodes, &tpb.Node{Name: name, Type: tpb.Node_Type(1012), Config: c})
+			}
+			m, err := New(tp, WithClusterConfig(&rest.Config{}), WithKubeClient(kf), WithTopoClient(tf))
+			if err != nil {
+				t.Fatalf("New() failed to create new topology manager: %v", err)
+			}
+			if err := m.Create(context.Background(), 100*time.Millisecond); err != nil {
+				t.Fatalf("Create() unexpected error: %v", err)
+			}
+			for name, want := range tt.wantPods {
+				p, err := kf.CoreV1().Pods("test").Get(context.Background(), name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("failed to get pod %q: %v", name, err)
+				}
+				if s := cmp.Diff(want, p.Spec.ImagePullSecrets); s != "" {
+					t.Errorf("Create() unexpected image pull secrets of pod %q (-want +got):\n%s", name, s)
+				}
+			}
+			sa, err := kf.CoreV1().ServiceAccounts("test").Get(context.Background(), "default", metav1.GetOptions{})
+			if tt.wantNoSA {
+				if err == nil {
+					t.Errorf("Create() unexpectedly created service account: %v", sa)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get service account: %v", err)
+			}
+			if s := cmp.Diff(tt.wantSA, sa.ImagePullSecrets); s != "" {
+				t.Errorf("Create() unexpected image pull secrets of service account (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestCreateMeshnetTopologies(t *testing.T) {
+	ctx := context.Background()
+	nodes := map[string]node.Node{}
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("r%d", i)
+		nodes[name] = &configurable{Impl: &node.Impl{Proto: &tpb.Node{Name: name}}}
+	}
+	tests := []struct {
+		desc     string
+		existing []string
+		wantErrs []string
+	}{{
+		desc: "all created",
+	}, {
+		desc:     "errors aggregated",
+		existing: []string{"r2", "r6"},
+		wantErrs: []string{"meshnet node r2", "meshnet node r6"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var objs []runtime.Object
+			for _, name := range tt.existing {
+				objs = append(objs, &topologyv1.Topology{
+					TypeMeta:   metav1.TypeMeta{Kind: "Topology", APIVersion: "networkop.co.uk/v1beta1"},
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+				})
+			}
+			tf, err := tfake.NewSimpleClientset(objs...)
+			if err != nil {
+				t.Fatalf("cannot create fake topology clientset: %v", err)
+			}
+			m := &Manager{topo: &tpb.Topology{Name: "test"}, nodes: nodes, tClient: tf, workers: 3}
+			err = m.createMeshnetTopologies(ctx)
+			for _, want := range tt.wantErrs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("createMeshnetTopologies() got error %v, want it to contain %q", err, want)
+				}
+			}
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Fatalf("createMeshnetTopologies() unexpected error: %v", err)
+			}
+			got, err := tf.Topology("test").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list topologies: %v", err)
+			}
+			if len(got.Items) != len(nodes) {
+				t.Errorf("createMeshnetTopologies() got %d topologies, want %d", len(got.Items), len(nodes))
+			}
+			labeled, err := tf.Topology("test").ListByLabels(ctx, labels.SelectorFromSet(labels.Set{"topo": "test"}))
+			if err != nil {
+				t.Fatalf("failed to list topologies by labels: %v", err)
+			}
+			if want := len(nodes) - len(tt.existing); len(labeled.Items) != want {
+				t.Errorf("createMeshnetTopologies() got %d labeled topologies, want %d", len(labeled.Items), want)
+			}
+		})
+	}
+}
+
+func TestRecordNodeState(t *testing.T) {
+	ctx := context.Background()
+	tf, err := tfake.NewSimpleClientset(&topologyv1.Topology{
+		TypeMeta:   metav1.TypeMeta{Kind: "Topology", APIVersion: "networkop.co.uk/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+		Status:     topologyv1.TopologyStatus{SrcIP: "10.0.0.1", State: string(node.StatusCreating)},
+		Spec:       topologyv1.TopologySpec{Links: []topologyv1.Link{{LocalIntf: "eth1", PeerPod: "r2", PeerIntf: "eth1"}}},
+	})
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	m := &Manager{topo: &tpb.Topology{Name: "test"}, tClient: tf}
+	m.recordNodeState(ctx, "r1", node.StatusFailed, fmt.Errorf("container r1: CrashLoopBackOff"))
+	// A missing resource is only logged.
+	m.recordNodeState(ctx, "r2", node.StatusFailed, nil)
+	got, err := tf.Topology("test").Get(ctx, "r1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get topology: %v", err)
+	}
+	want := topologyv1.TopologyStatus{SrcIP: "10.0.0.1", State: string(node.StatusFailed), Reason: "container r1: CrashLoopBackOff"}
+	if s := cmp.Diff(want, got.Status); s != "" {
+		t.Errorf("recordNodeState() unexpected status diff (-want +got):\n%s", s)
+	}
+	if len(got.Spec.Links) != 1 {
+		t.Errorf("recordNodeState() modified the links of the topology: %v", got.Spec.Links)
+	}
+}
+
+func TestLinkStatuses(t *testing.T) {
+	topology := func(name, srcIP string, skipped []string, peers ...string) *topologyv1.Topology {
+		t := &topologyv1.Topology{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Status:     topologyv1.TopologyStatus{SrcIP: srcIP, Skipped: skipped},
+		}
+		for i, p := range peers {
+			t.Spec.Links = append(t.Spec.Links, topologyv1.Link{UID: i, LocalIntf: "eth1", PeerPod: p, PeerIntf: "eth1"})
+		}
+		return t
+	}
+	link := func(uid int, peer, state string) topologyv1.LinkStatus {
+		return topologyv1.LinkStatus{UID: uid, LocalIntf: "eth1", PeerPod: peer, PeerIntf: "eth1", State: state}
+	}
+	tests := []struct {
+		desc  string
+		topos []*topologyv1.Topology
+		want  map[string][]topologyv1.LinkStatus
+	}{{
+		desc: "established",
+		topos: []*topologyv1.Topology{
+			topology("r1", "10.0.0.1", nil, "r2"),
+			topology("r2", "10.0.0.2", nil, "r1"),
+		},
+		want: map[string][]topologyv1.LinkStatus{
+			"r1": {link(0, "r2", topologyv1.LinkEstablished)},
+			"r2": {link(0, "r1", topologyv1.LinkEstablished)},
+		},
+	}, {
+		desc: "skipped by peer",
+		topos: []*topologyv1.Topology{
+			topology("r1", "10.0.0.1", nil, "r2", "r3"),
+			topology("r2", "10.0.0.2", []string{"r1"}, "r1"),
+			topology("r3", "10.0.0.3", nil, "r1"),
+		},
+		want: map[string][]topologyv1.LinkStatus{
+			"r1": {link(0, "r2", topologyv1.LinkSkipped), link(1, "r3", topologyv1.LinkEstablished)},
+			"r2": {link(0, "r1", topologyv1.LinkSkipped)},
+			"r3": {link(0, "r1", topologyv1.LinkEstablished)},
+		},
+	}, {
+		desc: "peer not running",
+		topos: []*topologyv1.Topology{
+			topology("r1", "10.0.0.1", nil, "r2"),
+			topology("r2", "", nil, "r1"),
+		},
+		want: map[string][]topologyv1.LinkStatus{
+			"r1": {link(0, "r2", topologyv1.LinkSkipped)},
+			"r2": {link(0, "r1", topologyv1.LinkSkipped)},
+		},
+	}, {
+		desc: "missing peer",
+		topos: []*topologyv1.Topology{
+			topology("r1", "10.0.0.1", nil, "r2"),
+		},
+		want: map[string][]topologyv1.LinkStatus{
+			"r1": {link(0, "r2", topologyv1.LinkSkipped)},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if s := cmp.Diff(tt.want, linkStatuses(tt.topos)); s != "" {
+				t.Errorf("linkStatuses() unexpected links diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestRecordLinkStates(t *testing.T) {
+	ctx := context.Background()
+	var objs []runtime.Object
+	for _, n := range [][2]string{{"r1", "r2"}, {"r2", "r1"}} {
+		objs = append(objs, &topologyv1.Topology{
+			TypeMeta:   metav1.TypeMeta{Kind: "Topology", APIVersion: "networkop.co.uk/v1beta1"},
+			ObjectMeta: metav1.ObjectMeta{Name: n[0], Namespace: "test"},
+			Status:     topologyv1.TopologyStatus{SrcIP: "10.0.0.1", NetNS: "/run/netns/" + n[0]},
+			Spec:       topologyv1.TopologySpec{Links: []topologyv1.Link{{UID: 1, LocalIntf: "eth1", PeerPod: n[1], PeerIntf: "eth1"}}},
+		})
+	}
+	tf, err := tfake.NewSimpleClientset(objs...)
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
+	m := &Manager{topo: &tpb.Topology{Name: "test"}, tClient: tf}
+	m.recordLinkStates(ctx)
+	got, err := tf.Topology("test").Get(ctx, "r1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get topology: %v", err)
+	}
+	want := topologyv1.TopologyStatus{
+		SrcIP:            "10.0.0.1",
+		NetNS:            "/run/netns/r1",
+		Links:            []topologyv1.LinkStatus{{UID: 1, LocalIntf: "eth1", PeerPod: "r2", PeerIntf: "eth1", State: topologyv1.LinkEstablished}},
+		EstablishedLinks: 1,
+	}
+	if s := cmp.Diff(want, got.Status); s != "" {
+		t.Errorf("recordLinkStates() unexpected status diff (-want +got):\n%s", s)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	ctx := context.Background()
 	node.Register(tpb.Node_Type(1003), NewConfigurable)
@@ -754,6 +1349,10 @@ func TestShow(t *testing.T) {
 		want: &cpb.ShowTopologyResponse{
 			State:    cpb.TopologyState_TOPOLOGY_STATE_RUNNING,
 			Topology: wantTopo,
+			Nodes: map[string]*cpb.NodeStatus{
+				"r1": {State: cpb.NodeState_NODE_STATE_READY},
+				"r2": {State: cpb.NodeState_NODE_STATE_READY},
+			},
 		},
 	}, {
 		desc: "success with remapped ports",
@@ -841,6 +1440,10 @@ func TestShow(t *testing.T) {
 		want: &cpb.ShowTopologyResponse{
 			State:    cpb.TopologyState_TOPOLOGY_STATE_RUNNING,
 			Topology: wantTopoRemapPorts,
+			Nodes: map[string]*cpb.NodeStatus{
+				"r1": {State: cpb.NodeState_NODE_STATE_READY},
+				"r2": {State: cpb.NodeState_NODE_STATE_READY},
+			},
 		},
 	}, {
 		desc: "no pods",
@@ -977,6 +1580,10 @@ func TestShow(t *testing.T) {
 		want: &cpb.ShowTopologyResponse{
 			State:    cpb.TopologyState_TOPOLOGY_STATE_CREATING,
 			Topology: wantTopo,
+			Nodes: map[string]*cpb.NodeStatus{
+				"r1": {State: cpb.NodeState_NODE_STATE_CREATING},
+				"r2": {State: cpb.NodeState_NODE_STATE_READY},
+			},
 		},
 	}, {
 		desc: "success - unhealthy",
@@ -1061,6 +1668,10 @@ func TestShow(t *testing.T) {
 		want: &cpb.ShowTopologyResponse{
 			State:    cpb.TopologyState_TOPOLOGY_STATE_ERROR,
 			Topology: wantTopo,
+			Nodes: map[string]*cpb.NodeStatus{
+				"r1": {State: cpb.NodeState_NODE_STATE_FAILED, Reason: "pod Failed"},
+				"r2": {State: cpb.NodeState_NODE_STATE_READY},
+			},
 		},
 	}}
 	for _, tt := range tests {
@@ -1314,31 +1925,216 @@ func TestNodes(t *testing.T) {
 	}
 }
 
+func TestSelectNodes(t *testing.T) {
+	newNode := func(pb *tpb.Node) node.Node {
+		return &configurable{Impl: &node.Impl{Proto: pb}}
+	}
+	nodes := map[string]node.Node{
+		"leaf1":  newNode(&tpb.Node{Name: "leaf1", Vendor: tpb.Vendor_CISCO, Labels: map[string]string{"role": "leaf"}}),
+		"leaf2":  newNode(&tpb.Node{Name: "leaf2", Vendor: tpb.Vendor_ARISTA, Labels: map[string]string{"role": "leaf"}}),
+		"spine1": newNode(&tpb.Node{Name: "spine1", Vendor: tpb.Vendor_CISCO, Model: "8201", Labels: map[string]string{"role": "spine"}}),
+		"host1":  newNode(&tpb.Node{Name: "host1", Type: tpb.Node_HOST, Labels: map[string]string{"vendor": "custom"}}),
+	}
+	m := &Manager{nodes: nodes}
+	tests := []struct {
+		desc     string
+		patterns []string
+		selector string
+		want     []string
+		wantErr  string
+	}{{
+		desc: "all nodes",
+		want: []string{"host1", "leaf1", "leaf2", "spine1"},
+	}, {
+		desc:     "names",
+		patterns: []string{"leaf1", "spine1"},
+		want:     []string{"leaf1", "spine1"},
+	}, {
+		desc:     "glob",
+		patterns: []string{"leaf*"},
+		want:     []string{"leaf1", "leaf2"},
+	}, {
+		desc:     "vendor selector",
+		selector: "vendor=CISCO",
+		want:     []string{"leaf1", "spine1"},
+	}, {
+		desc:     "label selector",
+		selector: "role in (spine)",
+		want:     []string{"spine1"},
+	}, {
+		desc:     "model selector",
+		selector: "model=8201",
+		want:     []string{"spine1"},
+	}, {
+		desc:     "type selector",
+		selector: "type=HOST",
+		want:     []string{"host1"},
+	}, {
+		desc:     "explicit label overrides vendor",
+		selector: "vendor=custom",
+		want:     []string{"host1"},
+	}, {
+		desc:     "glob and selector",
+		patterns: []string{"leaf*"},
+		selector: "vendor=CISCO,role=leaf",
+		want:     []string{"leaf1"},
+	}, {
+		desc:     "node not found",
+		patterns: []string{"leaf3"},
+		wantErr:  `node "leaf3" not found`,
+	}, {
+		desc:     "invalid pattern",
+		patterns: []string{"leaf["},
+		wantErr:  "invalid node pattern",
+	}, {
+		desc:     "invalid selector",
+		selector: "vendor in (CISCO",
+		wantErr:  "invalid label selector",
+	}, {
+		desc:     "no matches",
+		patterns: []string{"spine*"},
+		selector: "role=leaf",
+		wantErr:  "no nodes matched",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := m.SelectNodes(tt.patterns, tt.selector)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("SelectNodes() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			var gotNames []string
+			for name := range got {
+				gotNames = append(gotNames, name)
+			}
+			if s := cmp.Diff(tt.want, gotNames, cmpopts.SortSlices(func(a, b string) bool { return a < b })); s != "" {
+				t.Errorf("SelectNodes() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestLogs(t *testing.T) {
+	kClient := kfake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "r1", Namespace: "test"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "r1"}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "r2", Namespace: "test"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "nos"}, {Name: "sidecar"}},
+			},
+		},
+	)
+	newNode := func(name string) node.Node {
+		return &configurable{Impl: &node.Impl{
+			Namespace:  "test",
+			KubeClient: kClient,
+			Proto:      &tpb.Node{Name: name},
+		}}
+	}
+	m := &Manager{
+		topo:    &tpb.Topology{Name: "test"},
+		kClient: kClient,
+		nodes: map[string]node.Node{
+			"r1": newNode("r1"),
+			"r2": newNode("r2"),
+		},
+	}
+	tests := []struct {
+		desc          string
+		nodes         []string
+		allContainers bool
+		want          []string
+		wantErr       string
+	}{{
+		desc:  "single container",
+		nodes: []string{"r1"},
+		want:  []string{"[r1] fake logs"},
+	}, {
+		desc:  "multiple containers",
+		nodes: []string{"r2"},
+		want:  []string{"[r2] fake logs"},
+	}, {
+		desc:          "multiple containers all streamed",
+		nodes:         []string{"r2"},
+		allContainers: true,
+		want:          []string{"[r2/nos] fake logs", "[r2/sidecar] fake logs"},
+	}, {
+		desc: "all nodes",
+		want: []string{"[r1] fake logs", "[r2] fake logs"},
+	}, {
+		desc:    "node not found",
+		nodes:   []string{"dne"},
+		wantErr: `node "dne" not found`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := m.Logs(context.Background(), &buf, false, tt.allContainers, tt.nodes...)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("Logs() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if s := cmp.Diff(tt.want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); s != "" {
+				t.Errorf("Logs() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
 func TestConfigPush(t *testing.T) {
+	tf, err := tfake.NewSimpleClientset(&topologyv1.Topology{
+		ObjectMeta: metav1.ObjectMeta{Name: "configurable", Namespace: "test"},
+	})
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
 	m := &Manager{
+		topo:    &tpb.Topology{Name: "test"},
+		kClient: kfake.NewSimpleClientset(),
+		tClient: tf,
 		nodes: map[string]node.Node{
-			"configurable":     &configurable{},
-			"not_configurable": &notConfigurable{},
+			"configurable":     &configurable{Impl: &node.Impl{Proto: &tpb.Node{Name: "configurable", Vendor: tpb.Vendor_ARISTA}}},
+			"not_configurable": &notConfigurable{Impl: &node.Impl{Proto: &tpb.Node{Name: "not_configurable", Vendor: tpb.Vendor_CISCO}}},
 		},
 	}
 	tests := []struct {
-		desc    string
-		name    string
-		cfg     io.Reader
-		wantErr string
+		desc         string
+		name         string
+		cfg          io.Reader
+		wantErr      string
+		wantState    node.Status
+		wantReason   string
+		wantPush     topologyv1.TopologyStatus
+		wantFailures float64
 	}{{
-		desc: "configurable good config",
-		name: "configurable",
-		cfg:  bytes.NewReader([]byte("good config")),
+		desc:      "configurable good config",
+		name:      "configurable",
+		cfg:       bytes.NewReader([]byte("good config")),
+		wantState: node.StatusReady,
+		wantPush:  topologyv1.TopologyStatus{ConfigPush: ConfigPushDone},
 	}, {
-		desc:    "configurable bad config",
-		name:    "configurable",
-		cfg:     bytes.NewReader([]byte("error")),
-		wantErr: "error",
+		desc:         "configurable bad config",
+		name:         "configurable",
+		cfg:          bytes.NewReader([]byte("error")),
+		wantErr:      "error",
+		wantState:    node.StatusFailed,
+		wantReason:   "config push failed: error",
+		wantPush:     topologyv1.TopologyStatus{ConfigPush: ConfigPushFailed, ConfigPushReason: "error"},
+		wantFailures: 1,
 	}, {
 		desc:    "not configurable",
 		name:    "not_configurable",
-		wantErr: "does not implement ConfigPusher interface",
+		wantErr: "does not implement ConfigPusher interface, CISCO nodes do not support config pushes",
 	}, {
 		desc:    "node not found",
 		name:    "dne",
@@ -1346,16 +2142,41 @@ func TestConfigPush(t *testing.T) {
 	}}
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
+			failures := metrics.OperationFailures.WithLabelValues(metrics.OpConfigPush, "Unknown")
+			before := testutil.ToFloat64(failures)
 			err := m.ConfigPush(context.Background(), tt.name, tt.cfg)
 			if s := errdiff.Check(err, tt.wantErr); s != "" {
 				t.Errorf("ConfigPush() unexpected error: %s", s)
 			}
+			if tt.wantState == "" {
+				return
+			}
+			if got := testutil.ToFloat64(failures) - before; got != tt.wantFailures {
+				t.Errorf("ConfigPush() recorded %v failures, want %v", got, tt.wantFailures)
+			}
+			got, err := tf.Topology("test").Get(context.Background(), tt.name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get topology %q: %v", tt.name, err)
+			}
+			if node.Status(got.Status.State) != tt.wantState || got.Status.Reason != tt.wantReason {
+				t.Errorf("ConfigPush() recorded state %q reason %q, want %q reason %q", got.Status.State, got.Status.Reason, tt.wantState, tt.wantReason)
+			}
+			if got.Status.ConfigPush != tt.wantPush.ConfigPush || got.Status.ConfigPushReason != tt.wantPush.ConfigPushReason {
+				t.Errorf("ConfigPush() recorded push %q reason %q, want %q reason %q", got.Status.ConfigPush, got.Status.ConfigPushReason, tt.wantPush.ConfigPush, tt.wantPush.ConfigPushReason)
+			}
 		})
 	}
 }
 
 func TestResetCfg(t *testing.T) {
+	tf, err := tfake.NewSimpleClientset()
+	if err != nil {
+		t.Fatalf("cannot create fake topology clientset: %v", err)
+	}
 	m := &Manager{
+		topo:    &tpb.Topology{Name: "test"},
+		kClient: kfake.NewSimpleClientset(),
+		tClient: tf,
 		nodes: map[string]node.Node{
 			"resettable":     &resettable{},
 			"resettable_err": &resettable{rErr: "failed to reset"},
@@ -1392,8 +2213,112 @@ func TestResetCfg(t *testing.T) {
 	}
 }
 
+type execable struct {
+	*node.Impl
+	proto        *tpb.Node
+	gotCmd       []string
+	gotContainer string
+}
+
+func (e *execable) GetProto() *tpb.Node {
+	return e.proto
+}
+
+func (e *execable) Exec(_ context.Context, cmd []string, _ io.Reader, stdout io.Writer, _ io.Writer) error {
+	e.gotCmd = cmd
+	if cmd[0] == "error" {
+		return fmt.Errorf("exec failed")
+	}
+	_, err := fmt.Fprint(stdout, "ok")
+	return err
+}
+
+func (e *execable) ExecContainer(ctx context.Context, container string, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	e.gotContainer = container
+	return e.Exec(ctx, cmd, stdin, stdout, stderr)
+}
+
+func TestConsole(t *testing.T) {
+	newExecable := func(entry string) *execable {
+		return &execable{proto: &tpb.Node{Config: &tpb.Config{EntryCommand: entry}}}
+	}
+	tests := []struct {
+		desc          string
+		node          node.Node
+		name          string
+		wantCmd       []string
+		wantContainer string
+		wantErr       string
+	}{{
+		desc:    "kubectl entry command",
+		node:    newExecable("kubectl exec -it r1 -- Cli"),
+		wantCmd: []string{"Cli"},
+	}, {
+		desc:          "kubectl entry command with container",
+		node:          newExecable("kubectl exec -it r1 -c srlinux -- sr_cli"),
+		wantCmd:       []string{"sr_cli"},
+		wantContainer: "srlinux",
+	}, {
+		desc:          "kubectl entry command with long container flag",
+		node:          newExecable("kubectl exec -it r1 --container=vr -- telnet localhost 5000"),
+		wantCmd:       []string{"telnet", "localhost", "5000"},
+		wantContainer: "vr",
+	}, {
+		desc:    "kubectl entry command with args",
+		node:    newExecable("kubectl exec -it r1 -- sr_cli -d"),
+		wantCmd: []string{"sr_cli", "-d"},
+	}, {
+		desc:    "plain entry command",
+		node:    newExecable("/bin/bash"),
+		wantCmd: []string{"/bin/bash"},
+	}, {
+		desc:    "exec failure",
+		node:    newExecable("kubectl exec -it r1 -- error"),
+		wantCmd: []string{"error"},
+		wantErr: "exec failed",
+	}, {
+		desc:    "no entry command",
+		node:    newExecable(""),
+		wantErr: "has no entry command",
+	}, {
+		desc:    "node not found",
+		node:    newExecable("sh"),
+		name:    "dne",
+		wantErr: "not found",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			name := "r1"
+			if tt.name != "" {
+				name = tt.name
+			}
+			m := &Manager{nodes: map[string]node.Node{"r1": tt.node}}
+			var out bytes.Buffer
+			err := m.Console(context.Background(), name, nil, &out, &out)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("Console() unexpected error: %s", s)
+			}
+			e, ok := tt.node.(*execable)
+			if !ok {
+				return
+			}
+			if s := cmp.Diff(tt.wantCmd, e.gotCmd); s != "" {
+				t.Errorf("Console() unexpected command diff (-want +got):\n%s", s)
+			}
+			if e.gotContainer != tt.wantContainer {
+				t.Errorf("Console() got container %q, want %q", e.gotContainer, tt.wantContainer)
+			}
+			if tt.wantErr == "" && out.String() != "ok" {
+				t.Errorf("Console() unexpected output: got %q, want %q", out.String(), "ok")
+			}
+		})
+	}
+}
+
 func TestGenerateSelfSigned(t *testing.T) {
 	m := &Manager{
+		topo:    &tpb.Topology{Name: "test"},
+		kClient: kfake.NewSimpleClientset(),
 		nodes: map[string]node.Node{
 			"certable": &certable{
 				proto: &tpb.Node{
@@ -1459,6 +2384,127 @@ func TestGenerateSelfSigned(t *testing.T) {
 	}
 }
 
+func TestRotateCert(t *testing.T) {
+	certCfg := &tpb.Config{
+		Cert: &tpb.CertificateCfg{
+			Config: &tpb.CertificateCfg_SelfSigned{},
+		},
+	}
+	tests := []struct {
+		desc        string
+		name        string
+		wantRotated bool
+		wantErr     string
+	}{{
+		desc:        "rotated in place",
+		name:        "rotatable",
+		wantRotated: true,
+	}, {
+		desc:        "rotation failure",
+		name:        "rotatable_err",
+		wantRotated: true,
+		wantErr:     "failed to rotate certs",
+	}, {
+		desc: "replaced by generation",
+		name: "certable",
+	}, {
+		desc:    "generation failure",
+		name:    "certable_err",
+		wantErr: "failed to generate certs",
+	}, {
+		desc:    "not certable",
+		name:    "not_certable",
+		wantErr: "does not implement CertRotator or Certer interface",
+	}, {
+		desc: "no cert info",
+		name: "no_info",
+	}, {
+		desc:    "node not found",
+		name:    "dne",
+		wantErr: "not found",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rotator := &rotatable{certable: &certable{proto: &tpb.Node{Config: certCfg}}}
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				kClient: kfake.NewSimpleClientset(),
+				nodes: map[string]node.Node{
+					"rotatable":     rotator,
+					"rotatable_err": &rotatable{certable: &certable{proto: &tpb.Node{Config: certCfg}}, rErr: "failed to rotate certs"},
+					"certable":      &certable{proto: &tpb.Node{Config: certCfg}},
+					"certable_err":  &certable{proto: &tpb.Node{Config: certCfg}, gErr: "failed to generate certs"},
+					"not_certable":  &notCertable{proto: &tpb.Node{Config: certCfg}},
+					"no_info":       &rotatable{certable: &certable{}},
+				},
+			}
+			err := m.RotateCert(context.Background(), tt.name)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Errorf("RotateCert() unexpected error: %s", s)
+			}
+			if r, ok := m.nodes[tt.name].(*rotatable); ok && r.rotated != tt.wantRotated {
+				t.Errorf("RotateCert() rotated %v, want %v", r.rotated, tt.wantRotated)
+			}
+		})
+	}
+}
+
+// TestRotateCertVendors checks that repeated cert rotations of the vendors not
+// rotating their certs in place fail the same way each time.
+func TestRotateCertVendors(t *testing.T) {
+	certCfg := &tpb.Config{
+		Cert: &tpb.CertificateCfg{
+			Config: &tpb.CertificateCfg_SelfSigned{
+				SelfSigned: &tpb.SelfSignedCertCfg{CertName: "grpc-cert", KeyName: "grpc-key", KeySize: 2048},
+			},
+		},
+	}
+	tests := []struct {
+		desc    string
+		pb      *tpb.Node
+		wantErr string
+	}{{
+		desc:    "ceos",
+		pb:      &tpb.Node{Name: "r1", Vendor: tpb.Vendor_ARISTA, Config: certCfg},
+		wantErr: "To configure a certificate on a cEOS-lab device",
+	}, {
+		desc:    "cptx",
+		pb:      &tpb.Node{Name: "r1", Vendor: tpb.Vendor_JUNIPER, Model: "cptx", Config: certCfg},
+		wantErr: "JUNIPER nodes do not support cert rotation",
+	}, {
+		desc:    "xrd",
+		pb:      &tpb.Node{Name: "r1", Vendor: tpb.Vendor_CISCO, Model: "xrd", Config: certCfg},
+		wantErr: "CISCO nodes do not support cert rotation",
+	}, {
+		desc:    "lemming",
+		pb:      &tpb.Node{Name: "r1", Vendor: tpb.Vendor_OPENCONFIG, Config: certCfg},
+		wantErr: "certificate generation is not supported",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			kClient := kfake.NewSimpleClientset()
+			n, err := node.New("test", tt.pb, kClient, nil, "", "", "")
+			if err != nil {
+				t.Fatalf("node.New() unexpected error: %v", err)
+			}
+			m := &Manager{
+				topo:    &tpb.Topology{Name: "test"},
+				kClient: kClient,
+				nodes:   map[string]node.Node{"r1": n},
+			}
+			for i := 0; i < 2; i++ {
+				err := m.RotateCert(context.Background(), "r1")
+				if s := errdiff.Check(err, tt.wantErr); s != "" {
+					t.Fatalf("RotateCert() #%d unexpected error: %s", i, s)
+				}
+				if got := status.Code(err); got != codes.Unimplemented {
+					t.Fatalf("RotateCert() #%d returned code %v, want %v", i, got, codes.Unimplemented)
+				}
+			}
+		})
+	}
+}
+
 func TestStateMap(t *testing.T) {
 	type nodeInfo struct {
 		name  string
@@ -1476,23 +2522,23 @@ func TestStateMap(t *testing.T) {
 		desc: "one node failed",
 		nodes: []*nodeInfo{
 			{"n1", node.StatusFailed},
-			{"n2", node.StatusRunning},
-			{"n3", node.StatusRunning},
+			{"n2", node.StatusReady},
+			{"n3", node.StatusReady},
 		},
 		want: cpb.TopologyState_TOPOLOGY_STATE_ERROR,
 	}, {
 		desc: "one node failed with one node pending",
 		nodes: []*nodeInfo{
 			{"n1", node.StatusFailed},
-			{"n2", node.StatusRunning},
-			{"n3", node.StatusRunning},
+			{"n2", node.StatusReady},
+			{"n3", node.StatusReady},
 		},
 		want: cpb.TopologyState_TOPOLOGY_STATE_ERROR,
 	}, {
 		desc: "one node failed, one node pending, one node unknown",
 		nodes: []*nodeInfo{
 			{"n1", node.StatusFailed},
-			{"n2", node.StatusPending},
+			{"n2", node.StatusCreating},
 			{"n3", node.StatusUnknown},
 		},
 		want: cpb.TopologyState_TOPOLOGY_STATE_ERROR,
@@ -1507,11 +2553,26 @@ func TestStateMap(t *testing.T) {
 	}, {
 		desc: "one node pending",
 		nodes: []*nodeInfo{
-			{"n1", node.StatusPending},
-			{"n2", node.StatusRunning},
-			{"n3", node.StatusRunning},
+			{"n1", node.StatusCreating},
+			{"n2", node.StatusReady},
+			{"n3", node.StatusReady},
 		},
 		want: cpb.TopologyState_TOPOLOGY_STATE_CREATING,
+	}, {
+		desc: "one node booting, one node pushing config",
+		nodes: []*nodeInfo{
+			{"n1", node.StatusBooting},
+			{"n2", node.StatusConfigPushing},
+			{"n3", node.StatusReady},
+		},
+		want: cpb.TopologyState_TOPOLOGY_STATE_CREATING,
+	}, {
+		desc: "all nodes ready",
+		nodes: []*nodeInfo{
+			{"n1", node.StatusReady},
+			{"n2", node.StatusReady},
+		},
+		want: cpb.TopologyState_TOPOLOGY_STATE_RUNNING,
 	},
 	}
 
@@ -1528,3 +2589,57 @@ func TestStateMap(t *testing.T) {
 		})
 	}
 }
+
+func TestInterfaceNames(t *testing.T) {
+	pb := &tpb.Topology{
+		Name: "test",
+		Nodes: []*tpb.Node{
+			{Name: "r1", Vendor: tpb.Vendor_ARISTA},
+			{Name: "r2", Vendor: tpb.Vendor_HOST},
+		},
+		Links: []*tpb.Link{
+			{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"},
+		},
+	}
+	tests := []struct {
+		desc    string
+		topo    *tpb.Topology
+		node    string
+		want    map[string]string
+		wantErr string
+	}{{
+		desc: "vendor names",
+		topo: pb,
+		node: "r1",
+		want: map[string]string{"eth1": "Ethernet1"},
+	}, {
+		desc: "topology names",
+		topo: pb,
+		node: "r2",
+		want: map[string]string{"eth1": "eth1"},
+	}, {
+		desc:    "missing node",
+		topo:    pb,
+		node:    "r3",
+		wantErr: `node "r3" not found`,
+	}, {
+		desc: "invalid topology",
+		topo: &tpb.Topology{
+			Name:  "test",
+			Links: []*tpb.Link{{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"}},
+		},
+		node:    "r1",
+		wantErr: `missing node "r1"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := InterfaceNames(proto.Clone(tt.topo).(*tpb.Topology), tt.node)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("InterfaceNames() unexpected error: %s", s)
+			}
+			if s := cmp.Diff(tt.want, got); s != "" {
+				t.Errorf("InterfaceNames() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
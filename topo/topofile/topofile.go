@@ -0,0 +1,136 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package topofile loads and writes topology files, without the node vendors
+// of package topo.
+package topofile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+// CurrentVersion is the version of the topology schema of this release.
+const CurrentVersion = 1
+
+// Formats of topology files. The JSON and YAML formats are the JSON mapping
+// of the topology proto.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+var protojsonUnmarshaller = protojson.UnmarshalOptions{
+	AllowPartial:   true,
+	DiscardUnknown: false,
+}
+
+// FormatOf returns the format of the topology file at path by its extension,
+// the text format for unknown extensions.
+func FormatOf(path string) string {
+	switch filepath.Ext(path) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatText
+}
+
+// Load loads a Topology from path, in the format of its extension.
+func Load(path string) (*tpb.Topology, error) {
+	return LoadFormat(path, "")
+}
+
+// LoadFormat loads a Topology from path in format, or in the format of its
+// extension if empty. Topologies of a version newer than CurrentVersion are
+// rejected.
+func LoadFormat(path, format string) (*tpb.Topology, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = FormatOf(path)
+	}
+	t, err := Parse(b, format)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckVersion(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// CheckVersion returns an error if t is of a version newer than
+// CurrentVersion.
+func CheckVersion(t *tpb.Topology) error {
+	if v := t.GetVersion(); v > CurrentVersion {
+		return fmt.Errorf("topology version %d is newer than the supported version %d, upgrade kne", v, CurrentVersion)
+	}
+	return nil
+}
+
+// Parse parses a Topology from b in format.
+func Parse(b []byte, format string) (*tpb.Topology, error) {
+	t := &tpb.Topology{}
+	switch format {
+	case FormatYAML:
+		jsonBytes, err := yaml.YAMLToJSON(b)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse yaml: %v", err)
+		}
+		if err := protojsonUnmarshaller.Unmarshal(jsonBytes, t); err != nil {
+			return nil, fmt.Errorf("could not parse json: %v", err)
+		}
+	case FormatJSON:
+		if err := protojsonUnmarshaller.Unmarshal(b, t); err != nil {
+			return nil, fmt.Errorf("could not parse json: %v", err)
+		}
+	case FormatText:
+		if err := prototext.Unmarshal(b, t); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid topology format %q, must be text, json or yaml", format)
+	}
+	return t, nil
+}
+
+// Marshal returns the topology t in format.
+func Marshal(t *tpb.Topology, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML, FormatJSON:
+		b, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		if format == FormatJSON {
+			return append(b, '\n'), nil
+		}
+		return yaml.JSONToYAML(b)
+	case FormatText:
+		return prototext.MarshalOptions{Multiline: true}.Marshal(t)
+	}
+	return nil, fmt.Errorf("invalid topology format %q, must be text, json or yaml", format)
+}
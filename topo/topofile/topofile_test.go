@@ -0,0 +1,96 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topofile
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/h-fam/errdiff"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestLoadFormat(t *testing.T) {
+	want, err := Load("../testdata/valid_topo.yaml")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	tests := []struct {
+		desc    string
+		path    string
+		format  string
+		wantErr string
+	}{{
+		desc: "by extension",
+		path: "../testdata/valid_topo.json",
+	}, {
+		desc:   "json as yaml",
+		path:   "../testdata/valid_topo.json",
+		format: FormatYAML,
+	}, {
+		desc:    "text as json",
+		path:    "../testdata/valid_topo.pb.txt",
+		format:  FormatJSON,
+		wantErr: "could not parse json",
+	}, {
+		desc:    "invalid format",
+		path:    "../testdata/valid_topo.pb.txt",
+		format:  "xml",
+		wantErr: `invalid topology format "xml"`,
+	}, {
+		desc:    "newer version",
+		path:    "../testdata/newer_topo.pb.txt",
+		wantErr: "topology version 1000 is newer",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := LoadFormat(tt.path, tt.format)
+			if s := errdiff.Substring(err, tt.wantErr); s != "" {
+				t.Fatalf("LoadFormat() unexpected error: %s", s)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if s := cmp.Diff(want, got, protocmp.Transform()); s != "" {
+				t.Errorf("LoadFormat() unexpected diff (-want +got):\n%s", s)
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	want, err := Load("../testdata/valid_topo.pb.txt")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	for _, format := range []string{FormatText, FormatJSON, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			b, err := Marshal(want, format)
+			if err != nil {
+				t.Fatalf("Marshal() failed: %v", err)
+			}
+			got, err := Parse(b, format)
+			if err != nil {
+				t.Fatalf("Parse() of marshaled topology failed: %v\n%s", err, b)
+			}
+			if s := cmp.Diff(want, got, protocmp.Transform()); s != "" {
+				t.Errorf("Marshal() did not round trip (-want +got):\n%s", s)
+			}
+		})
+	}
+	if _, err := Marshal(want, "xml"); err == nil {
+		t.Errorf("Marshal() of invalid format did not fail")
+	}
+}
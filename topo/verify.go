@@ -0,0 +1,117 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+)
+
+// linkLocalScope is the scope of link-local addresses in /proc/net/if_inet6.
+const linkLocalScope = "20"
+
+// verifyPingCount is the number of pings sent across each link.
+var verifyPingCount = 3
+
+// LinkResult is the result of verifying a single link of the topology.
+type LinkResult struct {
+	Link *tpb.Link
+	// Err is nil if the link is functional.
+	Err error
+}
+
+// String returns the link in the form a_node:a_int <-> z_node:z_int.
+func (r *LinkResult) String() string {
+	l := r.Link
+	return fmt.Sprintf("%s:%s <-> %s:%s", l.GetANode(), l.GetAInt(), l.GetZNode(), l.GetZInt())
+}
+
+// Verify checks every link of the topology is functional by pinging the IPv6
+// link-local address of the z side interface from the a side interface. The
+// link-local addresses are assigned automatically by the kernel of the node
+// pods so no configuration of the nodes is required. A result is returned for
+// each link in the order of the topology proto. At most the number of workers
+// of the manager links are verified concurrently.
+func (m *Manager) Verify(ctx context.Context) ([]*LinkResult, error) {
+	links := m.topo.GetLinks()
+	if len(links) == 0 {
+		return nil, fmt.Errorf("topology %q has no links", m.topo.GetName())
+	}
+	results := make([]*LinkResult, len(links))
+	for i, l := range links {
+		results[i] = &LinkResult{Link: l}
+	}
+	// Failures are reported in the results rather than returned.
+	_ = m.parallelize(len(results), func(i int) error {
+		r := results[i]
+		r.Err = m.verifyLink(ctx, r.Link)
+		if r.Err != nil {
+			m.logger().Warnf("Link %s failed verification: %v", r, r.Err)
+			return nil
+		}
+		m.logger().Infof("Link %s verified", r)
+		return nil
+	})
+	return results, nil
+}
+
+func (m *Manager) verifyLink(ctx context.Context, l *tpb.Link) error {
+	aNode, ok := m.nodes[l.GetANode()]
+	if !ok {
+		return fmt.Errorf("node %q not found", l.GetANode())
+	}
+	zNode, ok := m.nodes[l.GetZNode()]
+	if !ok {
+		return fmt.Errorf("node %q not found", l.GetZNode())
+	}
+	var stdout, stderr bytes.Buffer
+	if err := zNode.Exec(ctx, []string{"cat", "/proc/net/if_inet6"}, nil, &stdout, &stderr); err != nil {
+		return fmt.Errorf("failed to get addresses of %s:%s: %w: %s", l.GetZNode(), l.GetZInt(), err, strings.TrimSpace(stderr.String()))
+	}
+	addr, err := linkLocalAddr(stdout.String(), l.GetZInt())
+	if err != nil {
+		return fmt.Errorf("%s:%s: %w", l.GetZNode(), l.GetZInt(), err)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	cmd := []string{"ping", "-c", fmt.Sprint(verifyPingCount), "-W", "1", fmt.Sprintf("%s%%%s", addr, l.GetAInt())}
+	if err := aNode.Exec(ctx, cmd, nil, &stdout, &stderr); err != nil {
+		return fmt.Errorf("ping from %s:%s to %s failed: %w: %s", l.GetANode(), l.GetAInt(), addr, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// linkLocalAddr returns the link-local address of intf from the contents of
+// /proc/net/if_inet6.
+func linkLocalAddr(ifInet6, intf string) (string, error) {
+	for _, line := range strings.Split(ifInet6, "\n") {
+		f := strings.Fields(line)
+		if len(f) != 6 || f[5] != intf || f[3] != linkLocalScope || len(f[0]) != 32 {
+			continue
+		}
+		b, err := hex.DecodeString(f[0])
+		if err != nil {
+			return "", fmt.Errorf("invalid address %q: %w", f[0], err)
+		}
+		return net.IP(b).String(), nil
+	}
+	return "", fmt.Errorf("no link-local address found, interface may not be connected")
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/h-fam/errdiff"
+	tpb "github.com/openconfig/kne/proto/topo"
+	"github.com/openconfig/kne/topo/node"
+)
+
+const (
+	r1IfInet6 = `00000000000000000000000000000001 01 80 10 80       lo
+fe800000000000000000000000000011 02 40 20 80     eth1
+20010db8000000000000000000000011 02 40 00 80     eth1
+`
+	r2IfInet6 = `00000000000000000000000000000001 01 80 10 80       lo
+fe800000000000000000000000000021 02 40 20 80     eth1
+`
+)
+
+// pingable is a fake node which supports reading its addresses and pinging
+// the addresses in reachable.
+type pingable struct {
+	*node.Impl
+	ifInet6   string
+	reachable map[string]bool
+}
+
+func (p *pingable) Exec(_ context.Context, cmd []string, _ io.Reader, stdout io.Writer, _ io.Writer) error {
+	switch cmd[0] {
+	case "cat":
+		_, err := fmt.Fprint(stdout, p.ifInet6)
+		return err
+	case "ping":
+		if !p.reachable[cmd[len(cmd)-1]] {
+			return fmt.Errorf("100%% packet loss")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown command %v", cmd)
+}
+
+func TestLinkLocalAddr(t *testing.T) {
+	tests := []struct {
+		desc    string
+		intf    string
+		want    string
+		wantErr string
+	}{{
+		desc: "found",
+		intf: "eth1",
+		want: "fe80::11",
+	}, {
+		desc:    "not found",
+		intf:    "eth2",
+		wantErr: "no link-local address",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := linkLocalAddr(r1IfInet6, tt.intf)
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("linkLocalAddr() unexpected error: %s", s)
+			}
+			if got != tt.want {
+				t.Errorf("linkLocalAddr() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		desc    string
+		links   []*tpb.Link
+		nodes   map[string]node.Node
+		want    []string
+		wantErr string
+	}{{
+		desc: "all links up",
+		links: []*tpb.Link{
+			{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"},
+		},
+		nodes: map[string]node.Node{
+			"r1": &pingable{ifInet6: r1IfInet6, reachable: map[string]bool{"fe80::21%eth1": true}},
+			"r2": &pingable{ifInet6: r2IfInet6},
+		},
+		want: []string{""},
+	}, {
+		desc: "broken links",
+		links: []*tpb.Link{
+			{ANode: "r1", AInt: "eth1", ZNode: "r2", ZInt: "eth1"},
+			{ANode: "r2", AInt: "eth1", ZNode: "r1", ZInt: "eth1"},
+			{ANode: "r1", AInt: "eth2", ZNode: "r2", ZInt: "eth2"},
+			{ANode: "r1", AInt: "eth3", ZNode: "dne", ZInt: "eth1"},
+		},
+		nodes: map[string]node.Node{
+			"r1": &pingable{ifInet6: r1IfInet6, reachable: map[string]bool{"fe80::21%eth1": true}},
+			"r2": &pingable{ifInet6: r2IfInet6},
+		},
+		want: []string{"", "packet loss", "no link-local address", `node "dne" not found`},
+	}, {
+		desc:    "no links",
+		nodes:   map[string]node.Node{},
+		wantErr: "has no links",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := &Manager{
+				topo:  &tpb.Topology{Name: "test", Links: tt.links},
+				nodes: tt.nodes,
+			}
+			got, err := m.Verify(context.Background())
+			if s := errdiff.Check(err, tt.wantErr); s != "" {
+				t.Fatalf("Verify() unexpected error: %s", s)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Verify() got %d results, want %d", len(got), len(tt.want))
+			}
+			for i, r := range got {
+				if r.Link != tt.links[i] {
+					t.Errorf("Verify() result %d is for link %s, want %v", i, r, tt.links[i])
+				}
+				if tt.want[i] == "" {
+					if r.Err != nil {
+						t.Errorf("Verify() link %s unexpected error: %v", r, r.Err)
+					}
+					continue
+				}
+				if r.Err == nil || !strings.Contains(r.Err.Error(), tt.want[i]) {
+					t.Errorf("Verify() link %s got error %v, want %q", r, r.Err, tt.want[i])
+				}
+			}
+		})
+	}
+}